@@ -0,0 +1,77 @@
+// Package authz checks the permissions in the access tokens of callers. The
+// auth middleware of a service verifies the token and calls SetClaims; routes
+// then require permissions with RequirePermission.
+//
+// Permissions are granted to roles in user-service, which puts the
+// permissions of the role of a user in every access token it issues.
+package authz
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+)
+
+// Context keys set by SetClaims
+const (
+	ContextClaims      = "claims"
+	ContextPermissions = "permissions"
+	ContextAuthMethods = "amr"
+)
+
+// Permissions checked by the services. user-service seeds them and grants
+// all of them to the admin role.
+const (
+	ProductWrite   = "product:write"
+	OrderRead      = "order:read"
+	OrderWrite     = "order:write"
+	ShipmentWrite  = "shipment:write"
+	ReturnWrite    = "return:write"
+	PromotionWrite = "promotion:write"
+	OutboxWrite    = "outbox:write"
+	UserRead       = "user:read"
+	UserWrite      = "user:write"
+	RoleWrite      = "role:write"
+)
+
+// SetClaims stores the claims of a verified token in the context
+func SetClaims(c *gin.Context, claims *token.Claims) {
+	c.Set(ContextClaims, claims)
+	c.Set(ContextPermissions, claims.Permissions)
+	c.Set(ContextAuthMethods, claims.AuthMethods)
+}
+
+// RequirePermission rejects callers that were not granted every one of
+// permissions
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := make(map[string]bool)
+		for _, permission := range c.GetStringSlice(ContextPermissions) {
+			granted[permission] = true
+		}
+		for _, permission := range permissions {
+			if !granted[permission] {
+				c.JSON(http.StatusForbidden, gin.H{"error": "forbidden: missing permission " + permission})
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireAuthMethod rejects callers whose session did not start with method,
+// e.g. token.AuthMethodMFA
+func RequireAuthMethod(method string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, m := range c.GetStringSlice(ContextAuthMethods) {
+			if m == method {
+				c.Next()
+				return
+			}
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden: log in with " + method})
+		c.Abort()
+	}
+}
@@ -0,0 +1,57 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+)
+
+// serve runs a request of a caller with claims through handlers and returns
+// the response status
+func serve(claims *token.Claims, handlers ...gin.HandlerFunc) int {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handlers = append([]gin.HandlerFunc{func(c *gin.Context) { SetClaims(c, claims) }}, handlers...)
+	handlers = append(handlers, func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/", handlers...)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec.Code
+}
+
+func TestRequirePermission(t *testing.T) {
+	customer := &token.Claims{UserID: 1, Role: "customer"}
+	admin := &token.Claims{UserID: 2, Role: "admin", Permissions: []string{OrderRead, OrderWrite}}
+	tests := []struct {
+		claims      *token.Claims
+		permissions []string
+		want        int
+	}{
+		{customer, []string{OrderRead}, http.StatusForbidden},
+		{admin, []string{OrderRead}, http.StatusOK},
+		{admin, []string{OrderRead, OrderWrite}, http.StatusOK},
+		{admin, []string{OrderRead, OutboxWrite}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		if got := serve(tt.claims, RequirePermission(tt.permissions...)); got != tt.want {
+			t.Errorf("%s with %v: status = %d, want %d", tt.claims.Role, tt.permissions, got, tt.want)
+		}
+	}
+}
+
+func TestRequireAuthMethod(t *testing.T) {
+	password := &token.Claims{AuthMethods: []string{token.AuthMethodPassword}}
+	mfa := &token.Claims{AuthMethods: []string{token.AuthMethodPassword, token.AuthMethodOTP, token.AuthMethodMFA}}
+
+	if got := serve(password, RequireAuthMethod(token.AuthMethodMFA)); got != http.StatusForbidden {
+		t.Errorf("password session: status = %d, want %d", got, http.StatusForbidden)
+	}
+	if got := serve(mfa, RequireAuthMethod(token.AuthMethodMFA)); got != http.StatusOK {
+		t.Errorf("mfa session: status = %d, want %d", got, http.StatusOK)
+	}
+}
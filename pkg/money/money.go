@@ -0,0 +1,282 @@
+// Package money represents amounts of money as integer minor units (satang,
+// cents) with an ISO 4217 currency code.
+//
+// Rounding rules: prices are stored in minor units, so line subtotals
+// (unit price x quantity) and totals (sum of subtotals) are exact and never
+// rounded. Rounding only happens when
+//
+//   - a float major amount is converted with FromMajor, which rounds half away
+//     from zero. Use it only for legacy float inputs.
+//   - a rate such as a tax or discount percentage is applied with MulRate, which
+//     rounds the result with the RoundingMode given by the caller.
+//
+// Amounts of different currencies are never combined, Add and Sub return
+// ErrCurrencyMismatch instead.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of amounts that were stored without one
+const DefaultCurrency = "THB"
+
+// ErrCurrencyMismatch is returned when amounts of different currencies are combined
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// RoundingMode decides how a fraction of a minor unit is rounded
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest minor unit, halves away from zero
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest minor unit, halves to the even neighbour
+	RoundHalfEven
+	// RoundDown drops the fraction, rounding towards zero
+	RoundDown
+	// RoundUp rounds any fraction away from zero
+	RoundUp
+)
+
+// Money is an amount in minor units of a currency. The GORM tags make it usable
+// as an embedded struct, e.g. `gorm:"embedded;embeddedPrefix:price_"` stores
+// price_amount and price_currency columns.
+type Money struct {
+	Amount   int64  `gorm:"not null;default:0" json:"amount" example:"4590000"`
+	Currency string `gorm:"type:varchar(3);not null;default:'THB'" json:"currency" example:"THB"`
+}
+
+// New returns amount minor units of currency
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// Zero returns a zero amount of currency
+func Zero(currency string) Money {
+	return New(0, currency)
+}
+
+// FromMajor converts a float amount in major units, e.g. 12.345 THB, to minor
+// units rounding half away from zero. The shortest decimal form of the float is
+// rounded, so 1.005 becomes 1.01 although 1.005 * 100 is 100.4999... in float64.
+func FromMajor(major float64, currency string) Money {
+	decimal := strconv.FormatFloat(major, 'f', -1, 64)
+	negative := strings.HasPrefix(decimal, "-")
+	decimal = strings.TrimPrefix(decimal, "-")
+
+	whole, fraction, _ := strings.Cut(decimal, ".")
+	exponent := Exponent(currency)
+	for len(fraction) <= exponent {
+		fraction += "0"
+	}
+
+	amount, _ := strconv.ParseInt(whole+fraction[:exponent], 10, 64)
+	if fraction[exponent] >= '5' {
+		amount++
+	}
+	if negative {
+		amount = -amount
+	}
+	return New(amount, currency)
+}
+
+// Exponent returns the number of minor unit digits of a currency
+func Exponent(currency string) int {
+	switch strings.ToUpper(currency) {
+	case "BIF", "CLP", "DJF", "GNF", "ISK", "JPY", "KMF", "KRW", "PYG", "RWF", "UGX", "VND", "VUV", "XAF", "XOF", "XPF":
+		return 0
+	case "BHD", "IQD", "JOD", "KWD", "LYD", "OMR", "TND":
+		return 3
+	default:
+		return 2
+	}
+}
+
+// ValidCurrency reports whether code looks like an ISO 4217 code
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Major returns the amount in major units. Use it only for display and for
+// legacy float fields, never for arithmetic.
+func (m Money) Major() float64 {
+	return float64(m.Amount) / math.Pow10(Exponent(m.Currency))
+}
+
+// String formats the amount with its currency, e.g. "1234.50 THB"
+func (m Money) String() string {
+	exponent := Exponent(m.Currency)
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if exponent == 0 {
+		return fmt.Sprintf("%s%d %s", sign, amount, m.Currency)
+	}
+	factor := int64(math.Pow10(exponent))
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/factor, exponent, amount%factor, m.Currency)
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// IsNegative reports whether the amount is less than zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// SameCurrency reports whether both amounts have the same currency
+func (m Money) SameCurrency(other Money) bool {
+	return m.Currency == other.Currency
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Mul returns m x quantity. The result is exact.
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// MulRate returns m x numerator / denominator rounded with mode, e.g.
+// MulRate(7, 100, RoundHalfUp) for 7% VAT
+func (m Money) MulRate(numerator, denominator int64, mode RoundingMode) Money {
+	if denominator == 0 {
+		panic("money: MulRate with zero denominator")
+	}
+	return Money{Amount: divRound(m.Amount*numerator, denominator, mode), Currency: m.Currency}
+}
+
+// Cmp compares two amounts of the same currency and returns -1, 0 or +1
+func (m Money) Cmp(other Money) (int, error) {
+	if !m.SameCurrency(other) {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Allocate splits m into parts proportional to weights. The parts always add up
+// to m: minor units left over by rounding down go to the first parts.
+func (m Money) Allocate(weights []int64) []Money {
+	parts := make([]Money, len(weights))
+	var total int64
+	for _, weight := range weights {
+		total += weight
+	}
+	if total == 0 {
+		for i := range parts {
+			parts[i] = Zero(m.Currency)
+		}
+		return parts
+	}
+
+	remainder := m.Amount
+	for i, weight := range weights {
+		parts[i] = Money{Amount: m.Amount * weight / total, Currency: m.Currency}
+		remainder -= parts[i].Amount
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		if weights[i] == 0 {
+			continue
+		}
+		parts[i].Amount += step
+		remainder -= step
+	}
+	return parts
+}
+
+// Sum adds amounts of currency. An empty list sums to zero.
+func Sum(currency string, amounts ...Money) (Money, error) {
+	total := Zero(currency)
+	for _, amount := range amounts {
+		var err error
+		if total, err = total.Add(amount); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// divRound divides a by b and rounds the quotient with mode
+func divRound(a, b int64, mode RoundingMode) int64 {
+	if b < 0 {
+		a, b = -a, -b
+	}
+	quotient, remainder := a/b, a%b
+	if remainder == 0 {
+		return quotient
+	}
+
+	sign := int64(1)
+	if a < 0 {
+		sign = -1
+		remainder = -remainder
+	}
+
+	switch mode {
+	case RoundDown:
+		return quotient
+	case RoundUp:
+		return quotient + sign
+	case RoundHalfEven:
+		switch {
+		case remainder*2 > b:
+			return quotient + sign
+		case remainder*2 == b && quotient%2 != 0:
+			return quotient + sign
+		default:
+			return quotient
+		}
+	default:
+		if remainder*2 >= b {
+			return quotient + sign
+		}
+		return quotient
+	}
+}
@@ -0,0 +1,110 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFromMajor(t *testing.T) {
+	tests := []struct {
+		major    float64
+		currency string
+		want     int64
+	}{
+		{45900, "THB", 4590000},
+		{19.99, "THB", 1999},
+		{0.1 + 0.2, "THB", 30},
+		{2.675, "USD", 268},
+		{-1.005, "USD", -101},
+		{1500, "JPY", 1500},
+		{1.2345, "KWD", 1235},
+	}
+
+	for _, tt := range tests {
+		if got := FromMajor(tt.major, tt.currency); got.Amount != tt.want {
+			t.Errorf("FromMajor(%v, %s) = %d, want %d", tt.major, tt.currency, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestAddRejectsCurrencyMismatch(t *testing.T) {
+	if _, err := New(100, "THB").Add(New(100, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+
+	sum, err := New(100, "THB").Add(New(250, "thb"))
+	if err != nil || sum.Amount != 350 {
+		t.Errorf("Add() = %v, %v, want 350 THB", sum, err)
+	}
+}
+
+func TestSumIsExact(t *testing.T) {
+	// Summing 0.10 a thousand times drifts with float64
+	amounts := make([]Money, 1000)
+	for i := range amounts {
+		amounts[i] = New(10, "THB")
+	}
+
+	total, err := Sum("THB", amounts...)
+	if err != nil || total.Amount != 10000 {
+		t.Errorf("Sum() = %v, %v, want 100.00 THB", total, err)
+	}
+}
+
+func TestMulRate(t *testing.T) {
+	tests := []struct {
+		amount int64
+		mode   RoundingMode
+		want   int64
+	}{
+		{150, RoundHalfUp, 11},   // 10.5
+		{150, RoundHalfEven, 10}, // 10.5
+		{250, RoundHalfEven, 18}, // 17.5
+		{150, RoundDown, 10},
+		{143, RoundUp, 11}, // 10.01
+		{-150, RoundHalfUp, -11},
+		{-150, RoundDown, -10},
+	}
+
+	for _, tt := range tests {
+		if got := New(tt.amount, "THB").MulRate(7, 100, tt.mode); got.Amount != tt.want {
+			t.Errorf("MulRate(%d, 7%%, %d) = %d, want %d", tt.amount, tt.mode, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	parts := New(100, "THB").Allocate([]int64{1, 1, 1})
+	want := []int64{34, 33, 33}
+	for i, part := range parts {
+		if part.Amount != want[i] {
+			t.Errorf("part %d = %d, want %d", i, part.Amount, want[i])
+		}
+	}
+
+	parts = New(1000, "THB").Allocate([]int64{0, 3, 1})
+	want = []int64{0, 750, 250}
+	for i, part := range parts {
+		if part.Amount != want[i] {
+			t.Errorf("part %d = %d, want %d", i, part.Amount, want[i])
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{New(123450, "THB"), "1234.50 THB"},
+		{New(-5, "THB"), "-0.05 THB"},
+		{New(1500, "JPY"), "1500 JPY"},
+		{New(1235, "KWD"), "1.235 KWD"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Package token verifies the access tokens issued by user-service. Tokens are
+// signed with RS256 or EdDSA and name their signing key in the kid header; the
+// public keys are published by user-service as a JWK set at JWKSPath.
+package token
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Issuer is the iss claim of every access token
+const Issuer = "user-service"
+
+// JWKSPath is where user-service publishes its public signing keys
+const JWKSPath = "/.well-known/jwks.json"
+
+// Authentication methods of the amr claim, from RFC 8176
+const (
+	// AuthMethodPassword is a login with a password
+	AuthMethodPassword = "pwd"
+	// AuthMethodOTP is a one-time password, such as a TOTP or recovery code
+	AuthMethodOTP = "otp"
+	// AuthMethodMFA is set when more than one factor was used
+	AuthMethodMFA = "mfa"
+)
+
+// Claims are the claims of an access token
+type Claims struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+	// Permissions are the permissions granted to the role of the user
+	Permissions []string `json:"permissions,omitempty"`
+	// SessionID is the refresh token family the token was issued for
+	SessionID string `json:"sid,omitempty"`
+	// AuthMethods are how the user authenticated when the session started
+	AuthMethods []string `json:"amr,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// HasAuthMethod reports whether the user authenticated with method
+func (c *Claims) HasAuthMethod(method string) bool {
+	for _, m := range c.AuthMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether the role of the user was granted permission
+func (c *Claims) HasPermission(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package token
+
+// Revoked access tokens are kept in Redis by user-service until they expire.
+// Services that verify tokens locally read the same keys to reject them.
+
+// DenylistTokenKey is the Redis key that revokes one access token by its jti
+func DenylistTokenKey(tokenID string) string {
+	return "auth:denylist:jti:" + tokenID
+}
+
+// DenylistSessionKey is the Redis key that revokes every access token of a
+// session by its sid
+func DenylistSessionKey(sessionID string) string {
+	return "auth:denylist:sid:" + sessionID
+}
+
+// DenylistKeys returns the keys to check for an access token; it is revoked
+// when any of them exists
+func DenylistKeys(tokenID, sessionID string) []string {
+	keys := []string{DenylistTokenKey(tokenID)}
+	if sessionID != "" {
+		keys = append(keys, DenylistSessionKey(sessionID))
+	}
+	return keys
+}
@@ -0,0 +1,141 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWK is a public key in JSON Web Key format (RFC 7517). Only RSA and Ed25519
+// keys are supported.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet is the document served at JWKSPath
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// VerificationKey is a public key with the algorithm it verifies
+type VerificationKey struct {
+	ID        string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
+// Algorithm returns the JWS algorithm used with a key
+func Algorithm(key crypto.PublicKey) (string, error) {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256.Alg(), nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA.Alg(), nil
+	default:
+		return "", fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// NewJWK encodes a public key as a JWK. The key ID is the RFC 7638 thumbprint
+// of the key, so the same key always gets the same ID.
+func NewJWK(key crypto.PublicKey) (JWK, error) {
+	alg, err := Algorithm(key)
+	if err != nil {
+		return JWK{}, err
+	}
+
+	jwk := JWK{Use: "sig", Alg: alg}
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	}
+	jwk.Kid = jwk.Thumbprint()
+	return jwk, nil
+}
+
+// Thumbprint returns the RFC 7638 SHA-256 thumbprint of the key
+func (k JWK) Thumbprint() string {
+	var members interface{}
+	switch k.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	}
+	// Marshalling a struct of strings cannot fail
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerificationKey decodes the public key of k
+func (k JWK) VerificationKey() (VerificationKey, error) {
+	if k.Kid == "" {
+		return VerificationKey{}, errors.New("jwk has no kid")
+	}
+
+	var key crypto.PublicKey
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return VerificationKey{}, fmt.Errorf("invalid jwk %s: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return VerificationKey{}, fmt.Errorf("invalid jwk %s: %w", k.Kid, err)
+		}
+		key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return VerificationKey{}, fmt.Errorf("invalid jwk %s: unsupported curve %q", k.Kid, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return VerificationKey{}, fmt.Errorf("invalid jwk %s: bad Ed25519 key", k.Kid)
+		}
+		key = ed25519.PublicKey(x)
+	default:
+		return VerificationKey{}, fmt.Errorf("invalid jwk %s: unsupported key type %q", k.Kid, k.Kty)
+	}
+
+	alg, err := Algorithm(key)
+	if err != nil {
+		return VerificationKey{}, err
+	}
+	if k.Alg != "" && k.Alg != alg {
+		return VerificationKey{}, fmt.Errorf("invalid jwk %s: algorithm %s does not match key type %s", k.Kid, k.Alg, k.Kty)
+	}
+	return VerificationKey{ID: k.Kid, Algorithm: alg, Key: key}, nil
+}
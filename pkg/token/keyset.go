@@ -0,0 +1,171 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// KeySet looks up the key an access token was signed with by its kid
+type KeySet interface {
+	Key(ctx context.Context, kid string) (VerificationKey, error)
+}
+
+// minRefreshInterval limits how often tokens with an unknown kid can make a
+// RemoteKeySet fetch the JWK set again
+const minRefreshInterval = 30 * time.Second
+
+// RemoteKeySet is a KeySet fetched from a JWKS URL and cached for maxAge. A
+// kid that is not in the cache triggers a refresh, so keys added by a
+// rotation are picked up without waiting for the cache to expire. When the
+// JWKS URL cannot be reached the cached keys are kept.
+//
+// The JWK set is fetched without holding the lock: expired keys are served
+// while a refresh runs in the background, and callers that wait for an
+// unknown kid share one fetch.
+type RemoteKeySet struct {
+	url    string
+	maxAge time.Duration
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]VerificationKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	fetching    *keySetFetch
+}
+
+// keySetFetch is a running fetch of the JWK set, done is closed when it ends
+type keySetFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewRemoteKeySet creates a key set for the JWK set at url
+func NewRemoteKeySet(url string, maxAge time.Duration) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:    url,
+		maxAge: maxAge,
+		client: &http.Client{Timeout: 5 * time.Second},
+		keys:   make(map[string]VerificationKey),
+	}
+}
+
+// Key returns the key with ID kid
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (VerificationKey, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	fresh := time.Since(s.fetchedAt) < s.maxAge
+	s.mu.RUnlock()
+	if ok && fresh {
+		return key, nil
+	}
+
+	fetch := s.startFetch(false)
+	if ok {
+		return key, nil
+	}
+	if fetch != nil {
+		select {
+		case <-fetch.done:
+		case <-ctx.Done():
+			return VerificationKey{}, ctx.Err()
+		}
+		s.mu.RLock()
+		key, ok = s.keys[kid]
+		s.mu.RUnlock()
+	}
+	if !ok {
+		return VerificationKey{}, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// Refresh fetches the JWK set now, e.g. to warm the cache at startup
+func (s *RemoteKeySet) Refresh(ctx context.Context) error {
+	fetch := s.startFetch(true)
+	select {
+	case <-fetch.done:
+		return fetch.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startFetch starts fetching the JWK set and returns the fetch, or the one
+// that is already running. Unless force is set it returns nil within
+// minRefreshInterval of the last attempt. Failures of fetches that are not
+// forced are logged, forced ones are returned to the caller of Refresh.
+func (s *RemoteKeySet) startFetch(force bool) *keySetFetch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fetching != nil {
+		return s.fetching
+	}
+	if !force && time.Since(s.attemptedAt) < minRefreshInterval {
+		return nil
+	}
+	s.attemptedAt = time.Now()
+	fetch := &keySetFetch{done: make(chan struct{})}
+	s.fetching = fetch
+
+	// The fetch is shared, so it is not bound to the context of one caller
+	go func() {
+		keys, err := s.fetch(context.Background())
+		if err != nil && !force {
+			log.Printf("Failed to refresh JWKS from %s: %v", s.url, err)
+		}
+
+		s.mu.Lock()
+		if err == nil {
+			s.keys = keys
+			s.fetchedAt = time.Now()
+		}
+		s.fetching = nil
+		s.mu.Unlock()
+
+		fetch.err = err
+		close(fetch.done)
+	}()
+	return fetch
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) (map[string]VerificationKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]VerificationKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.VerificationKey()
+		if err != nil {
+			log.Printf("Skipping JWK from %s: %v", s.url, err)
+			continue
+		}
+		keys[key.ID] = key
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,243 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// staticKeySet is a KeySet of fixed keys
+type staticKeySet map[string]VerificationKey
+
+func (s staticKeySet) Key(ctx context.Context, kid string) (VerificationKey, error) {
+	if key, ok := s[kid]; ok {
+		return key, nil
+	}
+	return VerificationKey{}, errors.New("unknown signing key")
+}
+
+// revokedTokens revokes the token IDs in its map
+type revokedTokens map[string]bool
+
+func (r revokedTokens) IsRevoked(ctx context.Context, tokenID, sessionID string) (bool, error) {
+	return r[tokenID], nil
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims *Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	return signed
+}
+
+func validClaims(id string) *Claims {
+	return &Claims{
+		UserID: 1,
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Issuer:    Issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+}
+
+func TestThumbprint(t *testing.T) {
+	// Example from RFC 7638 section 3.1
+	jwk := JWK{
+		Kty: "RSA",
+		N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:   "AQAB",
+	}
+	if got, want := jwk.Thumbprint(), "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; got != want {
+		t.Errorf("Thumbprint() = %s, want %s", got, want)
+	}
+}
+
+func TestJWKRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, public := range []interface{}{&rsaKey.PublicKey, edPublic} {
+		jwk, err := NewJWK(public)
+		if err != nil {
+			t.Fatalf("NewJWK(%T) error = %v", public, err)
+		}
+		key, err := jwk.VerificationKey()
+		if err != nil {
+			t.Fatalf("VerificationKey() error = %v", err)
+		}
+		if key.ID != jwk.Kid || key.Algorithm != jwk.Alg {
+			t.Errorf("VerificationKey() = %s/%s, want %s/%s", key.ID, key.Algorithm, jwk.Kid, jwk.Alg)
+		}
+		if !key.Key.(interface{ Equal(crypto.PublicKey) bool }).Equal(public) {
+			t.Errorf("VerificationKey() of %T does not round trip", public)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	keys := staticKeySet{"ed": {ID: "ed", Algorithm: "EdDSA", Key: public}}
+	verifier := NewVerifier(keys, revokedTokens{"revoked": true})
+
+	wrongIssuer := validClaims("a")
+	wrongIssuer.Issuer = "someone-else"
+	expired := validClaims("b")
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"valid", sign(t, jwt.SigningMethodEdDSA, "ed", private, validClaims("ok")), true},
+		{"unknown kid", sign(t, jwt.SigningMethodEdDSA, "other", private, validClaims("ok")), false},
+		{"wrong issuer", sign(t, jwt.SigningMethodEdDSA, "ed", private, wrongIssuer), false},
+		{"expired", sign(t, jwt.SigningMethodEdDSA, "ed", private, expired), false},
+		{"missing jti", sign(t, jwt.SigningMethodEdDSA, "ed", private, validClaims("")), false},
+		{"revoked", sign(t, jwt.SigningMethodEdDSA, "ed", private, validClaims("revoked")), false},
+		// The public key must not be usable as an HMAC secret
+		{"hmac", sign(t, jwt.SigningMethodHS256, "ed", []byte(public), validClaims("ok")), false},
+	}
+
+	for _, tt := range tests {
+		_, err := verifier.Verify(context.Background(), tt.token)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: Verify() error = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestRemoteKeySetPicksUpRotatedKeys(t *testing.T) {
+	first, _, _ := ed25519.GenerateKey(rand.Reader)
+	second, _, _ := ed25519.GenerateKey(rand.Reader)
+	firstJWK, _ := NewJWK(first)
+	secondJWK, _ := NewJWK(second)
+
+	set := JWKSet{Keys: []JWK{firstJWK}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer server.Close()
+
+	keys := NewRemoteKeySet(server.URL, time.Hour)
+	if _, err := keys.Key(context.Background(), firstJWK.Kid); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+
+	// A new kid refreshes the cache, but not more often than minRefreshInterval
+	set.Keys = append(set.Keys, secondJWK)
+	if _, err := keys.Key(context.Background(), secondJWK.Kid); err == nil {
+		t.Fatal("Key() refreshed within minRefreshInterval")
+	}
+	keys.attemptedAt = time.Now().Add(-minRefreshInterval)
+	if _, err := keys.Key(context.Background(), secondJWK.Kid); err != nil {
+		t.Fatalf("Key() of rotated key error = %v", err)
+	}
+
+	// Cached keys survive an unreachable JWKS URL
+	server.Close()
+	keys.fetchedAt = time.Now().Add(-2 * time.Hour)
+	keys.attemptedAt = time.Time{}
+	if _, err := keys.Key(context.Background(), firstJWK.Kid); err != nil {
+		t.Errorf("Key() with unreachable JWKS error = %v", err)
+	}
+}
+
+func TestRemoteKeySetServesCachedKeysDuringFetch(t *testing.T) {
+	first, _, _ := ed25519.GenerateKey(rand.Reader)
+	second, _, _ := ed25519.GenerateKey(rand.Reader)
+	firstJWK, _ := NewJWK(first)
+	secondJWK, _ := NewJWK(second)
+
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every fetch after the first hangs until released
+		if requests.Add(1) > 1 {
+			<-release
+		}
+		json.NewEncoder(w).Encode(JWKSet{Keys: []JWK{firstJWK, secondJWK}})
+	}))
+	defer server.Close()
+	defer close(release)
+
+	keys := NewRemoteKeySet(server.URL, time.Hour)
+	if err := keys.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	keys.mu.Lock()
+	delete(keys.keys, secondJWK.Kid)
+	keys.fetchedAt = time.Now().Add(-2 * time.Hour)
+	keys.attemptedAt = time.Time{}
+	keys.mu.Unlock()
+
+	// Callers waiting for the unknown kid share one fetch
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keys.Key(context.Background(), secondJWK.Kid)
+			errs <- err
+		}()
+	}
+
+	// The expired key is served while the fetch hangs
+	done := make(chan error, 1)
+	go func() {
+		_, err := keys.Key(context.Background(), firstJWK.Kid)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Key() of cached key error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Key() of cached key blocked on the fetch")
+	}
+
+	// A caller can give up on the fetch
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := keys.Key(ctx, secondJWK.Kid); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Key() with expired context error = %v, want DeadlineExceeded", err)
+	}
+
+	release <- struct{}{}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Key() of rotated key error = %v", err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", got)
+	}
+}
@@ -0,0 +1,74 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RevocationChecker reports whether an access token was revoked before it
+// expired, see DenylistTokenKey
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, tokenID, sessionID string) (bool, error)
+}
+
+// Verifier verifies access tokens locally against a KeySet
+type Verifier struct {
+	keys        KeySet
+	revocations RevocationChecker
+	parser      *jwt.Parser
+}
+
+// NewVerifier creates a verifier. revocations may be nil when revoked tokens
+// are checked elsewhere.
+func NewVerifier(keys KeySet, revocations RevocationChecker) *Verifier {
+	return &Verifier{
+		keys:        keys,
+		revocations: revocations,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+			jwt.WithIssuer(Issuer),
+			jwt.WithExpirationRequired(),
+		),
+	}
+}
+
+// Verify checks the signature, issuer and expiry of an access token and,
+// when the verifier has a RevocationChecker, that it was not revoked. It fails
+// closed when revocations cannot be checked.
+func (v *Verifier) Verify(ctx context.Context, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := v.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("token has no kid")
+		}
+		key, err := v.keys.Key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if key.Algorithm != token.Method.Alg() {
+			return nil, fmt.Errorf("algorithm %s does not match key %s", token.Method.Alg(), kid)
+		}
+		return key.Key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if claims.ID == "" {
+		return nil, errors.New("invalid token: missing token id")
+	}
+
+	if v.revocations != nil {
+		revoked, err := v.revocations.IsRevoked(ctx, claims.ID, claims.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if revoked {
+			return nil, errors.New("token has been revoked")
+		}
+	}
+	return claims, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
@@ -11,13 +12,22 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "github.com/ploezy/ecommerce-platform/order-service/docs" // Swagger docs
 	"github.com/ploezy/ecommerce-platform/order-service/config"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/cart"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/correlation"
 	"github.com/ploezy/ecommerce-platform/order-service/internal/grpc/client"
 	"github.com/ploezy/ecommerce-platform/order-service/internal/handler"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/idempotency"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/middleware"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/outbox"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/pricing"
 	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/scheduler"
 	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
 	"github.com/ploezy/ecommerce-platform/order-service/pkg/database"
 	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
 	"github.com/ploezy/ecommerce-platform/order-service/pkg/redis"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
 )
 // @title Order Service API
 // @version 1.0
@@ -39,7 +49,10 @@ import (
 // @description Type "Bearer" followed by a space and JWT token.
 func main() {
 	// Load configuration
-	cfg := config.LoadConfig()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
 
 	log.Println("===== Order Service Configuration =====")
 	log.Printf("Server Port: %s\n", cfg.ServerPort)
@@ -47,13 +60,14 @@ func main() {
 	log.Printf("Database: %s@%s:%s/%s\n", cfg.DBUser, cfg.DBHost, cfg.DBPort, cfg.DBName)
 	log.Printf("Redis: %s:%s (DB: %d)\n", cfg.RedisHost, cfg.RedisPort, cfg.RedisDB)
 	log.Printf("Kafka Brokers: %s\n", cfg.KafkaBrokers)
+	log.Printf("Kafka Consumer Group: %s\n", cfg.KafkaConsumerGroup)
 	log.Printf("User Service gRPC: %s\n", cfg.UserServiceGRPCURL)
 	log.Printf("Product Service gRPC: %s\n", cfg.ProductServiceGRPCURL)
 	log.Println("========================================")
 
 	// Connect to database
 	log.Println("\nConnecting to database...")
-	err := database.ConnectDatabase(cfg)
+	err = database.ConnectDatabase(cfg)
 	if err != nil {
 		log.Fatalf("Database connection failed: %v", err)
 	}
@@ -96,6 +110,24 @@ func main() {
 	}
 	defer productClient.Close()
 
+	// Initialize Payment Service gRPC Client
+	log.Println("Connecting to Payment Service gRPC...")
+	paymentClient, err := client.NewPaymentClient(cfg.PaymentServiceGRPCURL)
+	if err != nil {
+		log.Fatalf("Payment Service gRPC connection failed: %v", err)
+	}
+	defer paymentClient.Close()
+
+	// Access tokens are verified locally against the JWKS of User Service.
+	// Revoked tokens are read from the denylist User Service keeps in Redis.
+	jwks := token.NewRemoteKeySet(cfg.JWKSURL, cfg.JWKSCacheTTL)
+	if err := jwks.Refresh(context.Background()); err != nil {
+		log.Printf("Warning: failed to fetch JWKS from %s, retrying on first request: %v", cfg.JWKSURL, err)
+	}
+	denylistClient := redis.NewClient(cfg, cfg.TokenDenylistRedisDB)
+	defer denylistClient.Close()
+	verifier := token.NewVerifier(jwks, middleware.NewRedisRevocationChecker(denylistClient))
+
 	log.Println("\nAll connections successful!")
 
 	// Get database instance
@@ -103,12 +135,71 @@ func main() {
 
 	// Initialize layers: Repository -> Service -> Handler
 	orderRepo := repository.NewOrderRepository(db)
-	orderService := service.NewOrderService(orderRepo, db, userClient, productClient, kafkaProducer)
-	orderHandler := handler.NewOrderHandler(orderService)
+	sagaRepo := repository.NewSagaRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	historyRepo := repository.NewOrderStatusHistoryRepository(db)
+	orderMachine := statemachine.NewOrderMachine(db, orderRepo, historyRepo, outboxRepo)
+	promotionRepo := repository.NewPromotionRepository(db)
+	pricingConfig, err := pricing.LoadConfig(cfg.PricingConfigFile)
+	if err != nil {
+		log.Fatalf("Pricing config failed to load: %v", err)
+	}
+	pricer := pricing.NewCalculator(pricingConfig)
+	orderService := service.NewOrderService(orderRepo, sagaRepo, outboxRepo, promotionRepo, pricer, orderMachine, db, userClient, productClient, paymentClient, cfg.StockReservationTTL)
+	idempotencyStore := idempotency.NewStore(redis.GetClient(), cfg.IdempotencyKeyTTL, cfg.IdempotencyLockTTL)
+	orderHandler := handler.NewOrderHandler(orderService, idempotencyStore)
+	outboxService := service.NewOutboxService(outboxRepo)
+	outboxHandler := handler.NewOutboxHandler(outboxService)
+	returnRepo := repository.NewReturnRepository(db)
+	returnService := service.NewReturnService(returnRepo, orderRepo, outboxRepo, orderMachine, paymentClient)
+	returnHandler := handler.NewReturnHandler(returnService)
+	shipmentRepo := repository.NewShipmentRepository(db)
+	shipmentService := service.NewShipmentService(shipmentRepo, orderRepo, outboxRepo, orderMachine)
+	shipmentHandler := handler.NewShipmentHandler(shipmentService)
+	paymentEventHandler := handler.NewPaymentEventHandler(orderService)
+	cartStore := cart.NewStore(redis.GetClient(), cfg.CartTTL)
+	cartService := service.NewCartService(cartStore, orderService, productClient, cfg.CartMaxItems)
+	cartHandler := handler.NewCartHandler(cartService)
+	promotionService := service.NewPromotionService(promotionRepo)
+	promotionHandler := handler.NewPromotionHandler(promotionService)
+
+	// Start outbox relay in goroutine
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relay := outbox.NewRelay(db, outboxRepo, kafkaProducer, outbox.RelayConfig{
+		PollInterval: cfg.OutboxPollInterval,
+		BatchSize:    cfg.OutboxBatchSize,
+		MaxAttempts:  cfg.OutboxMaxAttempts,
+		BaseBackoff:  cfg.OutboxBaseBackoff,
+		MaxBackoff:   cfg.OutboxMaxBackoff,
+	})
+	relay.Handle(statemachine.TopicRestock, statemachine.RestockHandler(productClient))
+	relay.Handle(statemachine.TopicRefund, statemachine.RefundHandler(paymentClient))
+	go relay.Start(relayCtx)
+
+	// Start payment event consumer in goroutine
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	paymentConsumer := kafka.NewConsumer(cfg, kafka.TopicPaymentCaptured)
+	defer paymentConsumer.Close()
+	go paymentConsumer.Run(consumerCtx, paymentEventHandler.HandlePaymentCaptured)
+
+	// Start unpaid order expiry in goroutine
+	expiryCtx, stopExpiry := context.WithCancel(context.Background())
+	orderExpiry := scheduler.NewOrderExpiry(orderService, redis.GetClient(), scheduler.OrderExpiryConfig{
+		Timeout:   cfg.PendingOrderTimeout,
+		Interval:  cfg.OrderExpiryInterval,
+		BatchSize: cfg.OrderExpiryBatchSize,
+	})
+	go orderExpiry.Start(expiryCtx)
+
+	// Resume sagas left in flight by a previous run or waiting for a retry
+	resumerCtx, stopResumer := context.WithCancel(context.Background())
+	sagaResumer := scheduler.NewSagaResumer(orderService, cfg.SagaResumeInterval, cfg.SagaResumeStaleAfter)
+	go sagaResumer.Start(resumerCtx)
 
 	// Setup Gin router
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
+	router.Use(correlation.Middleware())
 
 	// Swagger documentation with custom config
 	url := ginSwagger.URL("http://localhost:8083/swagger/doc.json")
@@ -127,25 +218,14 @@ func main() {
 	})
 
 	// API v1 routes
-	v1 := router.Group("/api/v1")
-	{
-		// Order routes (Protected - require JWT)
-		orders := v1.Group("/orders")
-		orders.Use(AuthMiddleware(userClient)) // JWT Middleware
-		{
-			orders.POST("", orderHandler.CreateOrder)            // Create order
-			orders.GET("", orderHandler.GetOrders)               // Get user orders (pagination)
-			orders.GET("/:id", orderHandler.GetOrderByID)        // Get order by ID
-			orders.POST("/:id/cancel", orderHandler.CancelOrder) // Cancel order
-		}
-
-		// Admin routes (Protected - require JWT)
-		admin := v1.Group("/admin/orders")
-		admin.Use(AuthMiddleware(userClient)) // JWT Middleware
-		{
-			admin.PUT("/:id/status", orderHandler.UpdateOrderStatus) // Update order status
-		}
-	}
+	registerRoutes(router, verifier, routeHandlers{
+		order:     orderHandler,
+		cart:      cartHandler,
+		ret:       returnHandler,
+		shipment:  shipmentHandler,
+		promotion: promotionHandler,
+		outbox:    outboxHandler,
+	})
 
 	// Start server in goroutine
 	serverAddr := ":" + cfg.ServerPort
@@ -163,46 +243,9 @@ func main() {
 	<-quit
 
 	log.Println("\nShutting down Order Service...")
+	stopRelay()
+	stopConsumer()
+	stopExpiry()
+	stopResumer()
 	log.Println("Order Service stopped")
 }
-
-// AuthMiddleware validates JWT token via User Service
-func AuthMiddleware(userClient *client.UserClient) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Get token from Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(401, gin.H{"error": "authorization header required"})
-			c.Abort()
-			return
-		}
-
-		// Extract token (format: "Bearer <token>")
-		token := ""
-		if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-			token = authHeader[7:]
-		} else {
-			c.JSON(401, gin.H{"error": "invalid authorization format"})
-			c.Abort()
-			return
-		}
-
-		// Validate token via User Service gRPC
-		resp, err := userClient.ValidateToken(c.Request.Context(), token)
-		if err != nil {
-			c.JSON(401, gin.H{"error": "invalid or expired token"})
-			c.Abort()
-			return
-		}
-
-		if !resp.Valid {
-			c.JSON(401, gin.H{"error": "invalid token"})
-			c.Abort()
-			return
-		}
-
-		// Set user_id in context for handlers
-		c.Set("user_id", uint(resp.UserId))
-		c.Next()
-	}
-}
\ No newline at end of file
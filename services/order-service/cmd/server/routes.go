@@ -0,0 +1,106 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/handler"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/middleware"
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
+)
+
+// routeHandlers are the handlers served under /api/v1
+type routeHandlers struct {
+	order     *handler.OrderHandler
+	cart      *handler.CartHandler
+	ret       *handler.ReturnHandler
+	shipment  *handler.ShipmentHandler
+	promotion *handler.PromotionHandler
+	outbox    *handler.OutboxHandler
+}
+
+// registerRoutes adds the API v1 routes to router. Tokens are verified by
+// verifier; admin routes also require the permission of their area, which
+// user-service grants to the admin role and to any role given it.
+func registerRoutes(router *gin.Engine, verifier middleware.TokenVerifier, h routeHandlers) {
+	auth := middleware.AuthMiddleware(verifier)
+	canWriteOrders := authz.RequirePermission(authz.OrderWrite)
+
+	v1 := router.Group("/api/v1")
+	{
+		// Order routes (Protected - require JWT)
+		orders := v1.Group("/orders")
+		orders.Use(auth) // JWT Middleware
+		{
+			orders.POST("", h.order.CreateOrder)                       // Create order
+			orders.POST("/quote", h.order.QuoteOrder)                  // Price an order without creating it
+			orders.GET("", h.order.GetOrders)                          // Get user orders (pagination)
+			orders.GET("/:id", h.order.GetOrderByID)                   // Get order by ID
+			orders.GET("/:id/history", h.order.GetOrderHistory)        // Get order status history
+			orders.POST("/:id/cancel", h.order.CancelOrder)            // Cancel order
+			orders.POST("/:id/returns", h.ret.RequestReturn)           // Request a return
+			orders.GET("/:id/returns", h.ret.GetOrderReturns)          // Get order returns
+			orders.GET("/:id/shipments", h.shipment.GetOrderShipments) // Get order shipments
+		}
+
+		// Cart routes (Guests use X-Cart-ID, checkout and merge require JWT)
+		carts := v1.Group("/cart")
+		carts.Use(middleware.OptionalAuthMiddleware(verifier)) // JWT Middleware if a token is sent
+		{
+			carts.GET("", h.cart.GetCart)                             // Get cart
+			carts.DELETE("", h.cart.ClearCart)                        // Clear cart
+			carts.POST("/items", h.cart.AddCartItem)                  // Add item to cart
+			carts.PATCH("/items/:product_id", h.cart.UpdateCartItem)  // Update item quantity
+			carts.DELETE("/items/:product_id", h.cart.RemoveCartItem) // Remove item from cart
+			carts.POST("/merge", h.cart.MergeCart)                    // Merge guest cart after login
+			carts.POST("/checkout", h.cart.Checkout)                  // Create order from cart
+		}
+
+		// Admin routes (Protected - require JWT and a permission)
+		admin := v1.Group("/admin/orders")
+		admin.Use(auth, authz.RequirePermission(authz.OrderRead))
+		{
+			admin.GET("", h.order.ListOrders)                                         // List orders (filters, cursor pagination)
+			admin.POST("/bulk-status", canWriteOrders, h.order.BulkUpdateOrderStatus) // Update the status of several orders
+			admin.GET("/:id", h.order.GetOrderByIDAdmin)                              // Get any order by ID
+			admin.PUT("/:id/status", canWriteOrders, h.order.UpdateOrderStatus)       // Update order status
+			admin.POST("/:id/shipments", canWriteOrders, h.shipment.CreateShipment)   // Ship order items
+		}
+
+		// Admin shipment routes (Protected - require JWT and a permission)
+		adminShipments := v1.Group("/admin/shipments")
+		adminShipments.Use(auth, authz.RequirePermission(authz.ShipmentWrite))
+		{
+			adminShipments.POST("/tracking", h.shipment.RecordTracking) // Record carrier tracking update
+			adminShipments.GET("/:id", h.shipment.GetShipment)          // Get shipment
+		}
+
+		// Admin return routes (Protected - require JWT and a permission)
+		adminReturns := v1.Group("/admin/returns")
+		adminReturns.Use(auth, authz.RequirePermission(authz.ReturnWrite))
+		{
+			adminReturns.GET("/:id", h.ret.GetReturn)              // Get return request
+			adminReturns.POST("/:id/approve", h.ret.ApproveReturn) // Approve return
+			adminReturns.POST("/:id/reject", h.ret.RejectReturn)   // Reject return
+			adminReturns.POST("/:id/receive", h.ret.ReceiveReturn) // Receive returned goods
+			adminReturns.POST("/:id/refund", h.ret.RefundReturn)   // Refund return
+		}
+
+		// Admin promotion routes (Protected - require JWT and a permission)
+		adminPromotions := v1.Group("/admin/promotions")
+		adminPromotions.Use(auth, authz.RequirePermission(authz.PromotionWrite))
+		{
+			adminPromotions.POST("", h.promotion.CreatePromotion)    // Create promotion
+			adminPromotions.GET("", h.promotion.ListPromotions)      // List promotions
+			adminPromotions.GET("/:id", h.promotion.GetPromotion)    // Get promotion
+			adminPromotions.PUT("/:id", h.promotion.UpdatePromotion) // Replace promotion
+		}
+
+		// Admin outbox routes (Protected - require JWT and a permission)
+		adminOutbox := v1.Group("/admin/outbox")
+		adminOutbox.Use(auth, authz.RequirePermission(authz.OutboxWrite))
+		{
+			adminOutbox.GET("", h.outbox.ListOutboxEvents)              // List outbox events
+			adminOutbox.GET("/:id", h.outbox.GetOutboxEvent)            // Get outbox event
+			adminOutbox.POST("/:id/replay", h.outbox.ReplayOutboxEvent) // Replay failed outbox event
+		}
+	}
+}
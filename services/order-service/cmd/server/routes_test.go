@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/handler"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/middleware"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+)
+
+type fakeVerifier map[string]*token.Claims
+
+func (v fakeVerifier) Verify(ctx context.Context, accessToken string) (*token.Claims, error) {
+	claims, ok := v[accessToken]
+	if !ok {
+		return nil, errors.New("token rejected")
+	}
+	return claims, nil
+}
+
+// testRouter registers the API routes with handlers that have no services.
+// Requests that get past the middleware panic in the handler and are answered
+// with 500, which is enough to tell them apart from 401 and 403.
+func testRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+
+	verifier := fakeVerifier{
+		"customer": {UserID: 1, Role: middleware.RoleCustomer},
+		"admin": {UserID: 2, Role: middleware.RoleAdmin, Permissions: []string{
+			"order:read", "order:write", "shipment:write", "return:write", "promotion:write", "outbox:write",
+		}},
+		"support": {UserID: 3, Role: "support", Permissions: []string{
+			"order:read", "order:write", "shipment:write", "return:write", "promotion:write",
+		}},
+	}
+	registerRoutes(router, verifier, routeHandlers{
+		order:     handler.NewOrderHandler(nil, nil),
+		cart:      handler.NewCartHandler(nil),
+		ret:       handler.NewReturnHandler(nil),
+		shipment:  handler.NewShipmentHandler(nil),
+		promotion: handler.NewPromotionHandler(nil),
+		outbox:    handler.NewOutboxHandler(nil),
+	})
+	return router
+}
+
+func request(router *gin.Engine, method, path, token string) int {
+	path = strings.NewReplacer(":id", "1", ":product_id", "1").Replace(path)
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestAdminRoutesRequireAdmin(t *testing.T) {
+	router := testRouter()
+
+	checked := 0
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/admin/") {
+			continue
+		}
+		checked++
+
+		if got := request(router, route.Method, route.Path, ""); got != http.StatusUnauthorized {
+			t.Errorf("%s %s without token: status = %d, want %d", route.Method, route.Path, got, http.StatusUnauthorized)
+		}
+		if got := request(router, route.Method, route.Path, "customer"); got != http.StatusForbidden {
+			t.Errorf("%s %s as customer: status = %d, want %d", route.Method, route.Path, got, http.StatusForbidden)
+		}
+		if got := request(router, route.Method, route.Path, "admin"); got == http.StatusUnauthorized || got == http.StatusForbidden {
+			t.Errorf("%s %s as admin: status = %d, want access", route.Method, route.Path, got)
+		}
+	}
+	if checked == 0 {
+		t.Fatal("Expected admin routes to be registered")
+	}
+}
+
+func TestOutboxRoutesRequireOutboxPermission(t *testing.T) {
+	router := testRouter()
+
+	routes := map[string]bool{}
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/admin/outbox") {
+			continue
+		}
+		routes[route.Method+" "+route.Path] = true
+
+		if got := request(router, route.Method, route.Path, "support"); got != http.StatusForbidden {
+			t.Errorf("%s %s without outbox:write: status = %d, want %d", route.Method, route.Path, got, http.StatusForbidden)
+		}
+	}
+
+	for _, want := range []string{
+		"GET /api/v1/admin/outbox",
+		"GET /api/v1/admin/outbox/:id",
+		"POST /api/v1/admin/outbox/:id/replay",
+	} {
+		if !routes[want] {
+			t.Errorf("Expected route %s to be registered", want)
+		}
+	}
+}
+
+func TestCustomerRoutesAllowCustomers(t *testing.T) {
+	router := testRouter()
+
+	for _, route := range router.Routes() {
+		if strings.HasPrefix(route.Path, "/api/v1/admin/") {
+			continue
+		}
+
+		if got := request(router, route.Method, route.Path, "customer"); got == http.StatusUnauthorized || got == http.StatusForbidden {
+			t.Errorf("%s %s as customer: status = %d, want access", route.Method, route.Path, got)
+		}
+		if strings.HasPrefix(route.Path, "/api/v1/orders") {
+			if got := request(router, route.Method, route.Path, ""); got != http.StatusUnauthorized {
+				t.Errorf("%s %s without token: status = %d, want %d", route.Method, route.Path, got, http.StatusUnauthorized)
+			}
+		}
+	}
+}
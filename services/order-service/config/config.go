@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -30,16 +33,49 @@ type Config struct {
 	KafkaTopicOrderCreated       string
 	KafkaTopicOrderStatusChanged string
 	KafkaTopicOrderCancelled     string
+	KafkaConsumerGroup           string
 
 	// gRPC Services
 	UserServiceGRPCURL    string
 	ProductServiceGRPCURL string
-
-	// JWT
-	JWTSecret string
+	PaymentServiceGRPCURL string
+
+	// JWT, access tokens are verified against the JWKS of user-service
+	JWKSURL      string
+	JWKSCacheTTL time.Duration
+	// TokenDenylistRedisDB is the Redis DB where user-service keeps revoked tokens
+	TokenDenylistRedisDB int
+
+	// Saga
+	StockReservationTTL  time.Duration
+	SagaResumeInterval   time.Duration
+	SagaResumeStaleAfter time.Duration
+
+	// Outbox
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+	OutboxMaxAttempts  int
+	OutboxBaseBackoff  time.Duration
+	OutboxMaxBackoff   time.Duration
+
+	// Idempotency
+	IdempotencyKeyTTL  time.Duration
+	IdempotencyLockTTL time.Duration
+
+	// Order expiry
+	PendingOrderTimeout  time.Duration
+	OrderExpiryInterval  time.Duration
+	OrderExpiryBatchSize int
+
+	// Cart
+	CartTTL      time.Duration
+	CartMaxItems int
+
+	// Pricing
+	PricingConfigFile string
 }
 
-func LoadConfig() *Config {
+func LoadConfig() (*Config, error) {
 	// Load .env file
 	err := godotenv.Load()
 	if err != nil {
@@ -69,16 +105,76 @@ func LoadConfig() *Config {
 		KafkaTopicOrderCreated:       getEnv("KAFKA_TOPIC_ORDER_CREATED", "order.created"),
 		KafkaTopicOrderStatusChanged: getEnv("KAFKA_TOPIC_ORDER_STATUS_CHANGED", "order.status_changed"),
 		KafkaTopicOrderCancelled:     getEnv("KAFKA_TOPIC_ORDER_CANCELLED", "order.cancelled"),
+		KafkaConsumerGroup:           getEnv("KAFKA_CONSUMER_GROUP", "order-service"),
 
 		// gRPC Services
 		UserServiceGRPCURL:    getEnv("USER_SERVICE_GRPC_URL", "localhost:50052"),
 		ProductServiceGRPCURL: getEnv("PRODUCT_SERVICE_GRPC_URL", "localhost:50053"),
+		PaymentServiceGRPCURL: getEnv("PAYMENT_SERVICE_GRPC_URL", "localhost:9094"),
 
 		// JWT
-		JWTSecret: getEnv("JWT_SECRET", "your-super-secret-key"),
+		JWKSURL:              getEnv("JWKS_URL", "http://localhost:8081/.well-known/jwks.json"),
+		JWKSCacheTTL:         getDurationEnv("JWKS_CACHE_TTL", 5*time.Minute),
+		TokenDenylistRedisDB: getIntEnv("TOKEN_DENYLIST_REDIS_DB", 0),
+
+		// Saga
+		StockReservationTTL:  getDurationEnv("STOCK_RESERVATION_TTL", 15*time.Minute),
+		SagaResumeInterval:   getDurationEnv("SAGA_RESUME_INTERVAL", 30*time.Second),
+		SagaResumeStaleAfter: getDurationEnv("SAGA_RESUME_STALE_AFTER", time.Minute),
+
+		// Outbox
+		OutboxPollInterval: getDurationEnv("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:    getIntEnv("OUTBOX_BATCH_SIZE", 100),
+		OutboxMaxAttempts:  getIntEnv("OUTBOX_MAX_ATTEMPTS", 10),
+		OutboxBaseBackoff:  getDurationEnv("OUTBOX_BASE_BACKOFF", time.Second),
+		OutboxMaxBackoff:   getDurationEnv("OUTBOX_MAX_BACKOFF", 5*time.Minute),
+
+		// Idempotency
+		IdempotencyKeyTTL:  getDurationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		IdempotencyLockTTL: getDurationEnv("IDEMPOTENCY_LOCK_TTL", time.Minute),
+
+		// Order expiry
+		PendingOrderTimeout:  getDurationEnv("PENDING_ORDER_TIMEOUT", 30*time.Minute),
+		OrderExpiryInterval:  getDurationEnv("ORDER_EXPIRY_INTERVAL", time.Minute),
+		OrderExpiryBatchSize: getIntEnv("ORDER_EXPIRY_BATCH_SIZE", 100),
+
+		// Cart
+		CartTTL:      getDurationEnv("CART_TTL", 7*24*time.Hour),
+		CartMaxItems: getIntEnv("CART_MAX_ITEMS", 50),
+
+		// Pricing
+		PricingConfigFile: getEnv("PRICING_CONFIG_FILE", ""),
+	}
+
+	if err := requirePositive(map[string]time.Duration{
+		"JWKS_CACHE_TTL":          config.JWKSCacheTTL,
+		"STOCK_RESERVATION_TTL":   config.StockReservationTTL,
+		"SAGA_RESUME_INTERVAL":    config.SagaResumeInterval,
+		"SAGA_RESUME_STALE_AFTER": config.SagaResumeStaleAfter,
+		"OUTBOX_POLL_INTERVAL":    config.OutboxPollInterval,
+		"OUTBOX_BASE_BACKOFF":     config.OutboxBaseBackoff,
+		"OUTBOX_MAX_BACKOFF":      config.OutboxMaxBackoff,
+		"IDEMPOTENCY_KEY_TTL":     config.IdempotencyKeyTTL,
+		"IDEMPOTENCY_LOCK_TTL":    config.IdempotencyLockTTL,
+		"PENDING_ORDER_TIMEOUT":   config.PendingOrderTimeout,
+		"ORDER_EXPIRY_INTERVAL":   config.OrderExpiryInterval,
+		"CART_TTL":                config.CartTTL,
+	}); err != nil {
+		return nil, err
 	}
 
-	return config
+	return config, nil
+}
+
+// requirePositive rejects durations that are zero or negative: intervals
+// drive tickers, which panic on them, and TTLs would expire at once
+func requirePositive(durations map[string]time.Duration) error {
+	for key, duration := range durations {
+		if duration <= 0 {
+			return fmt.Errorf("%s must be positive, got %v", key, duration)
+		}
+	}
+	return nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -88,3 +184,29 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: invalid duration for %s, using default %v", key, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid integer for %s, using default %d", key, defaultValue)
+		return defaultValue
+	}
+	return number
+}
@@ -0,0 +1,193 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// GuestHeaderName is the header that carries the ID of a guest cart
+const GuestHeaderName = "X-Cart-ID"
+
+// ErrInvalidGuestID is returned for guest cart IDs that are not UUIDs
+var ErrInvalidGuestID = errors.New("invalid cart id")
+
+// Owner identifies a cart. Signed-in users own one cart each, guests are
+// identified by a random cart ID kept by the client.
+type Owner struct {
+	UserID  uint
+	GuestID string
+}
+
+// UserOwner returns the owner of the cart of a signed-in user
+func UserOwner(userID uint) Owner {
+	return Owner{UserID: userID}
+}
+
+// GuestOwner returns the owner of a guest cart
+func GuestOwner(guestID string) (Owner, error) {
+	if _, err := uuid.Parse(guestID); err != nil {
+		return Owner{}, ErrInvalidGuestID
+	}
+	return Owner{GuestID: guestID}, nil
+}
+
+// NewGuestID returns a new random guest cart ID
+func NewGuestID() string {
+	return uuid.NewString()
+}
+
+// IsGuest reports whether the cart belongs to a guest
+func (o Owner) IsGuest() bool {
+	return o.UserID == 0
+}
+
+// key returns the Redis key of the cart
+func (o Owner) key() string {
+	if o.IsGuest() {
+		return "cart:guest:" + o.GuestID
+	}
+	return fmt.Sprintf("cart:user:%d", o.UserID)
+}
+
+// Store keeps carts in Redis as a hash of product ID to quantity. Every write
+// extends the expiry of the cart by ttl.
+type Store struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewStore creates a new cart store
+func NewStore(client *redis.Client, ttl time.Duration) *Store {
+	return &Store{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// Items returns the quantities in a cart keyed by product ID. A missing or
+// expired cart is empty.
+func (s *Store) Items(ctx context.Context, owner Owner) (map[uint]int, error) {
+	values, err := s.client.HGetAll(ctx, owner.key()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	items := make(map[uint]int, len(values))
+	for field, value := range values {
+		productID, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			continue
+		}
+		quantity, err := strconv.Atoi(value)
+		if err != nil || quantity <= 0 {
+			continue
+		}
+		items[uint(productID)] = quantity
+	}
+	return items, nil
+}
+
+// SetQuantity sets the quantity of a product in a cart
+func (s *Store) SetQuantity(ctx context.Context, owner Owner, productID uint, quantity int) error {
+	key := owner.key()
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, productField(productID), quantity)
+		pipe.Expire(ctx, key, s.ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update cart: %w", err)
+	}
+	return nil
+}
+
+// RemoveItem removes a product from a cart
+func (s *Store) RemoveItem(ctx context.Context, owner Owner, productID uint) error {
+	if err := s.client.HDel(ctx, owner.key(), productField(productID)).Err(); err != nil {
+		return fmt.Errorf("failed to update cart: %w", err)
+	}
+	return nil
+}
+
+// Clear deletes a cart
+func (s *Store) Clear(ctx context.Context, owner Owner) error {
+	if err := s.client.Del(ctx, owner.key()).Err(); err != nil {
+		return fmt.Errorf("failed to clear cart: %w", err)
+	}
+	return nil
+}
+
+// mergeScript moves products of the from cart (KEYS[1]) into the into cart
+// (KEYS[2]). ARGV[1] is the cart TTL in milliseconds, ARGV[2] the most
+// products the into cart may hold, followed by pairs of product field and the
+// most of that product the into cart may hold. Quantities are added and
+// clamped to the limit, a quantity already above it is kept. Moved products
+// are removed from the from cart, including those over the limits; products
+// without a limit stay in it.
+var mergeScript = redis.NewScript(`
+local max_products = tonumber(ARGV[2])
+local count = redis.call('HLEN', KEYS[2])
+local merged = false
+for i = 3, #ARGV, 2 do
+  local field = ARGV[i]
+  local limit = tonumber(ARGV[i + 1])
+  local quantity = tonumber(redis.call('HGET', KEYS[1], field))
+  if quantity then
+    local current = tonumber(redis.call('HGET', KEYS[2], field))
+    if current then
+      local total = math.min(current + quantity, limit)
+      if total > current then
+        redis.call('HSET', KEYS[2], field, total)
+        merged = true
+      end
+    elseif count < max_products and limit > 0 then
+      redis.call('HSET', KEYS[2], field, math.min(quantity, limit))
+      count = count + 1
+      merged = true
+    end
+    redis.call('HDEL', KEYS[1], field)
+  end
+end
+if merged then
+  redis.call('PEXPIRE', KEYS[2], ARGV[1])
+end
+return 0
+`)
+
+// Merge adds the quantities of the from cart to the into cart and removes them
+// from the from cart, atomically, so a repeated or concurrent merge cannot add
+// them twice. limits holds the most of each product the into cart may hold,
+// e.g. its stock; products without a limit are left in the from cart.
+// maxProducts is the most products the into cart may hold, products that do
+// not fit are dropped.
+func (s *Store) Merge(ctx context.Context, from, into Owner, limits map[uint]int, maxProducts int) error {
+	productIDs := make([]uint, 0, len(limits))
+	for productID := range limits {
+		productIDs = append(productIDs, productID)
+	}
+	// products with lower IDs win when the into cart is full
+	sort.Slice(productIDs, func(i, j int) bool { return productIDs[i] < productIDs[j] })
+
+	args := make([]interface{}, 0, 2+2*len(productIDs))
+	args = append(args, s.ttl.Milliseconds(), maxProducts)
+	for _, productID := range productIDs {
+		args = append(args, productField(productID), limits[productID])
+	}
+
+	if err := mergeScript.Run(ctx, s.client, []string{from.key(), into.key()}, args...).Err(); err != nil {
+		return fmt.Errorf("failed to merge cart: %w", err)
+	}
+	return nil
+}
+
+// productField returns the hash field of a product
+func productField(productID uint) string {
+	return strconv.FormatUint(uint64(productID), 10)
+}
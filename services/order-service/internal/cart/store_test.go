@@ -0,0 +1,152 @@
+package cart
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewStore(client, time.Hour), server
+}
+
+func fill(t *testing.T, store *Store, owner Owner, items map[uint]int) {
+	t.Helper()
+	for productID, quantity := range items {
+		if err := store.SetQuantity(context.Background(), owner, productID, quantity); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func items(t *testing.T, store *Store, owner Owner) map[uint]int {
+	t.Helper()
+	items, err := store.Items(context.Background(), owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func TestGuestOwner(t *testing.T) {
+	if _, err := GuestOwner("not-a-uuid"); err != ErrInvalidGuestID {
+		t.Errorf("Expected ErrInvalidGuestID, got %v", err)
+	}
+
+	id := NewGuestID()
+	owner, err := GuestOwner(id)
+	if err != nil {
+		t.Fatalf("Expected new guest ID to be valid, got %v", err)
+	}
+	if !owner.IsGuest() {
+		t.Error("Expected guest owner")
+	}
+	if got, want := owner.key(), "cart:guest:"+id; got != want {
+		t.Errorf("key() = %s, want %s", got, want)
+	}
+}
+
+func TestUserOwnerKey(t *testing.T) {
+	owner := UserOwner(42)
+	if owner.IsGuest() {
+		t.Error("Expected user owner")
+	}
+	if got, want := owner.key(), "cart:user:42"; got != want {
+		t.Errorf("key() = %s, want %s", got, want)
+	}
+}
+
+func TestCartExpiresAfterLastWrite(t *testing.T) {
+	store, server := newTestStore(t)
+	owner := UserOwner(1)
+	fill(t, store, owner, map[uint]int{10: 1})
+
+	// Every write extends the expiry
+	server.FastForward(50 * time.Minute)
+	fill(t, store, owner, map[uint]int{11: 2})
+	server.FastForward(50 * time.Minute)
+	if got := items(t, store, owner); len(got) != 2 {
+		t.Fatalf("Items() = %v, want both products before the TTL", got)
+	}
+
+	server.FastForward(11 * time.Minute)
+	if got := items(t, store, owner); len(got) != 0 {
+		t.Errorf("Items() = %v, want an empty cart after the TTL", got)
+	}
+}
+
+func TestMergeAddsOnceAndClamps(t *testing.T) {
+	store, server := newTestStore(t)
+	ctx := context.Background()
+	guest, _ := GuestOwner(NewGuestID())
+	user := UserOwner(1)
+	fill(t, store, guest, map[uint]int{10: 2, 11: 5, 12: 1, 13: 1})
+	fill(t, store, user, map[uint]int{10: 1})
+
+	// 11 is clamped to its stock, 12 is gone, 13 has no limit yet
+	limits := map[uint]int{10: 10, 11: 3, 12: 0}
+	for i := 0; i < 2; i++ {
+		if err := store.Merge(ctx, guest, user, limits, 50); err != nil {
+			t.Fatalf("Merge() attempt %d error = %v", i+1, err)
+		}
+	}
+
+	if got, want := fmt.Sprint(items(t, store, user)), fmt.Sprint(map[uint]int{10: 3, 11: 3}); got != want {
+		t.Errorf("user cart = %s, want %s", got, want)
+	}
+	if got, want := fmt.Sprint(items(t, store, guest)), fmt.Sprint(map[uint]int{13: 1}); got != want {
+		t.Errorf("guest cart = %s, want %s with the product that had no limit", got, want)
+	}
+	if ttl := server.TTL(user.key()); ttl != time.Hour {
+		t.Errorf("user cart TTL = %v, want %v", ttl, time.Hour)
+	}
+}
+
+func TestMergeIntoFullCart(t *testing.T) {
+	store, _ := newTestStore(t)
+	guest, _ := GuestOwner(NewGuestID())
+	user := UserOwner(1)
+	fill(t, store, guest, map[uint]int{10: 1, 11: 1, 12: 1})
+	fill(t, store, user, map[uint]int{20: 1, 21: 1})
+
+	// Products already in the cart still merge when it is full
+	fill(t, store, guest, map[uint]int{20: 1})
+	if err := store.Merge(context.Background(), guest, user, map[uint]int{10: 5, 11: 5, 12: 5, 20: 5}, 3); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fmt.Sprint(items(t, store, user)), fmt.Sprint(map[uint]int{10: 1, 20: 2, 21: 1}); got != want {
+		t.Errorf("user cart = %s, want %s", got, want)
+	}
+}
+
+func TestConcurrentMergesAddOnce(t *testing.T) {
+	store, _ := newTestStore(t)
+	guest, _ := GuestOwner(NewGuestID())
+	user := UserOwner(1)
+	fill(t, store, guest, map[uint]int{10: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.Merge(context.Background(), guest, user, map[uint]int{10: 100}, 50); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := items(t, store, user)[10]; got != 2 {
+		t.Errorf("quantity after concurrent merges = %d, want 2", got)
+	}
+}
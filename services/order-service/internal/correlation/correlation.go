@@ -0,0 +1,39 @@
+package correlation
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HeaderName is the HTTP header that carries the correlation ID
+const HeaderName = "X-Correlation-ID"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the correlation ID
+func NewContext(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, correlationID)
+}
+
+// FromContext returns the correlation ID stored in ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(contextKey{}).(string)
+	return correlationID
+}
+
+// Middleware reads the correlation ID from the request, or creates one, and
+// stores it in the request context and the response header
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		correlationID := c.GetHeader(HeaderName)
+		if correlationID == "" || len(correlationID) > 100 {
+			correlationID = uuid.NewString()
+		}
+
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), correlationID))
+		c.Header(HeaderName, correlationID)
+		c.Next()
+	}
+}
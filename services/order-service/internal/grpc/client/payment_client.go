@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	pb "github.com/ploezy/ecommerce-platform/proto/payment"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type PaymentClient struct {
+	client pb.PaymentServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewPaymentClient creates a new gRPC client for Payment Service
+func NewPaymentClient(address string) (*PaymentClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(
+		ctx,
+		address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to payment service: %w", err)
+	}
+
+	log.Printf("Payment Service gRPC client connected to %s", address)
+	return &PaymentClient{
+		client: pb.NewPaymentServiceClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// RefundPayment refunds amount minor units of a captured payment intent, 0
+// refunds everything left. A retry with the same idempotency key does not
+// refund again.
+func (c *PaymentClient) RefundPayment(ctx context.Context, intentID string, amount int64, reason, idempotencyKey string) (*pb.PaymentIntentResponse, error) {
+	req := &pb.RefundPaymentRequest{
+		Id:             intentID,
+		Amount:         amount,
+		Reason:         reason,
+		IdempotencyKey: idempotencyKey,
+	}
+
+	resp, err := c.client.RefundPayment(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	return resp, nil
+}
+
+// VoidOrderPayments voids the payment intents of an order that were not
+// captured. The response has Captured set when one already was.
+func (c *PaymentClient) VoidOrderPayments(ctx context.Context, orderID uint) (*pb.VoidOrderPaymentsResponse, error) {
+	req := &pb.VoidOrderPaymentsRequest{
+		OrderId: uint32(orderID),
+	}
+
+	resp, err := c.client.VoidOrderPayments(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to void order payments: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Close closes the gRPC connection
+func (c *PaymentClient) Close() error {
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
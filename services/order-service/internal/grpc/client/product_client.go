@@ -6,7 +6,8 @@ import (
 	"time"
 	"log"
 	
-	pb "github.com/ploezy/ecommerce-platform/order-service/proto/product"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+	pb "github.com/ploezy/ecommerce-platform/proto/product"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -55,7 +56,7 @@ func GetProductClient() *ProductClient {
 // GetProduct retrieves product information by product ID
 func (c *ProductClient) GetProduct(ctx context.Context, productID uint32) (*pb.ProductResponse, error) {
 	req := &pb.GetProductRequest{
-		ProductId: productID,
+		Id: productID,
 	}
 
 	resp, err := c.client.GetProduct(ctx, req)
@@ -66,6 +67,15 @@ func (c *ProductClient) GetProduct(ctx context.Context, productID uint32) (*pb.P
 	return resp, nil
 }
 
+// UnitPrice returns the price of a product. Product services that do not send
+// unit_price yet report the deprecated float price in the default currency.
+func UnitPrice(product *pb.Product) money.Money {
+	if product.UnitPrice != nil {
+		return money.New(product.UnitPrice.Amount, product.UnitPrice.Currency)
+	}
+	return money.FromMajor(product.Price, money.DefaultCurrency) //nolint:staticcheck // fallback for old product-service
+}
+
 // CheckStock checks if product has enough stock
 func (c *ProductClient) CheckStock(ctx context.Context, productID uint32, quantity int32) (*pb.CheckStockResponse, error) {
 	req := &pb.CheckStockRequest{
@@ -96,6 +106,77 @@ func (c *ProductClient) UpdateStock(ctx context.Context, productID uint32, quant
 	return resp, nil
 }
 
+// ReserveStock reserves stock for all items in one call, quantities are keyed by product ID
+func (c *ProductClient) ReserveStock(ctx context.Context, items map[uint32]int32, ttl time.Duration, reference string) (*pb.ReserveStockResponse, error) {
+	req := &pb.ReserveStockRequest{
+		Items:      make([]*pb.StockItem, 0, len(items)),
+		TtlSeconds: int32(ttl.Seconds()),
+		Reference:  reference,
+	}
+	for productID, quantity := range items {
+		req.Items = append(req.Items, &pb.StockItem{
+			ProductId: productID,
+			Quantity:  quantity,
+		})
+	}
+
+	resp, err := c.client.ReserveStock(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve stock: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ReleaseStock releases a stock reservation
+func (c *ProductClient) ReleaseStock(ctx context.Context, reservationID string) (*pb.ReleaseStockResponse, error) {
+	req := &pb.ReleaseStockRequest{
+		ReservationId: reservationID,
+	}
+
+	resp, err := c.client.ReleaseStock(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release stock: %w", err)
+	}
+
+	return resp, nil
+}
+
+// CommitReservation commits a stock reservation
+func (c *ProductClient) CommitReservation(ctx context.Context, reservationID string) (*pb.CommitReservationResponse, error) {
+	req := &pb.CommitReservationRequest{
+		ReservationId: reservationID,
+	}
+
+	resp, err := c.client.CommitReservation(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit reservation: %w", err)
+	}
+
+	return resp, nil
+}
+
+// RestockItems gives stock back to products once per reference, quantities are keyed by product ID
+func (c *ProductClient) RestockItems(ctx context.Context, reference string, items map[uint32]int32) (*pb.RestockItemsResponse, error) {
+	req := &pb.RestockItemsRequest{
+		Reference: reference,
+		Items:     make([]*pb.StockItem, 0, len(items)),
+	}
+	for productID, quantity := range items {
+		req.Items = append(req.Items, &pb.StockItem{
+			ProductId: productID,
+			Quantity:  quantity,
+		})
+	}
+
+	resp, err := c.client.RestockItems(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restock items: %w", err)
+	}
+
+	return resp, nil
+}
+
 // Close closes the gRPC connection
 func (c *ProductClient) Close() error {
 	if c.conn != nil {
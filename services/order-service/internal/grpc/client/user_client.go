@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"log"
 	"time"
-	pb "github.com/ploezy/ecommerce-platform/order-service/proto/user"
+	pb "github.com/ploezy/ecommerce-platform/proto/user"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
 type UserClient struct {
@@ -51,13 +52,13 @@ func GetUserClient() *UserClient{
 	return userClient
 }
 
-// GetUSer retrieves user information by user ID
-func (c *UserClient) GetUser(ctx context.Context,userID uint32) (*pb.GetUserResponse, error) {
-	req := &pb.GetUserRequest{
-		UserId: userID,
+// GetUser retrieves user information by user ID
+func (c *UserClient) GetUser(ctx context.Context,userID uint32) (*pb.UserResponse, error) {
+	req := &pb.GetUserByIDRequest{
+		Id: userID,
 	}
 
-	resp, err := c.client.GetUser(ctx, req)
+	resp, err := c.client.GetUserByID(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -77,6 +78,38 @@ func (c *UserClient) ValidateToken(ctx context.Context, token string) (*pb.Valid
 	return resp, nil
 }
 
+type accessTokenKey struct{}
+
+// WithAccessToken returns a context whose address requests to user-service
+// are made with the access token of the signed in user
+func WithAccessToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, accessTokenKey{}, token)
+}
+
+// withUserToken sends the access token of WithAccessToken, user-service only
+// serves the addresses of the user of the token
+func withUserToken(ctx context.Context) context.Context {
+	if token, ok := ctx.Value(accessTokenKey{}).(string); ok && token != "" {
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+	return ctx
+}
+
+// GetAddress retrieves an address of a user, or the default address when addressID is 0.
+// The gRPC status is kept so that callers can tell a missing address from a failure.
+func (c *UserClient) GetAddress(ctx context.Context, userID, addressID uint32) (*pb.Address, error) {
+	req := &pb.GetAddressRequest{
+		UserId:    userID,
+		AddressId: addressID,
+	}
+	return c.client.GetAddress(withUserToken(ctx), req)
+}
+
+// ValidateAddress checks and normalizes an address entered at checkout
+func (c *UserClient) ValidateAddress(ctx context.Context, address *pb.Address) (*pb.Address, error) {
+	return c.client.ValidateAddress(ctx, &pb.ValidateAddressRequest{Address: address})
+}
+
 // Close close the gRPC connection
 func(c *UserClient) Close() error {
 	if c.conn != nil {
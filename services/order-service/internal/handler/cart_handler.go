@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/cart"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+type CartHandler struct {
+	service service.CartService
+}
+
+func NewCartHandler(service service.CartService) *CartHandler {
+	return &CartHandler{
+		service: service,
+	}
+}
+
+// cartErrorStatus maps cart service errors to HTTP status codes
+func cartErrorStatus(err error) int {
+	errorMessage := err.Error()
+	switch {
+	case contains(errorMessage, "not found"):
+		return http.StatusNotFound
+	case contains(errorMessage, "invalid") || contains(errorMessage, "insufficient"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// cartOwner returns the cart of the signed-in user, or the guest cart named by
+// the X-Cart-ID header. A signed-in user who still sends X-Cart-ID, e.g. on the
+// first cart call after login, gets the guest cart merged into theirs first.
+// With create set, a guest without a cart gets a new cart ID in the X-Cart-ID
+// response header.
+func (h *CartHandler) cartOwner(c *gin.Context, create bool) (cart.Owner, bool) {
+	guestID := c.GetHeader(cart.GuestHeaderName)
+	if userID, ok := c.Get("user_id"); ok {
+		if guestID != "" {
+			if err := h.service.MergeGuestCart(c.Request.Context(), userID.(uint), guestID); err != nil {
+				c.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+				c.Abort()
+				return cart.Owner{}, false
+			}
+		}
+		return cart.UserOwner(userID.(uint)), true
+	}
+
+	if guestID == "" {
+		if !create {
+			return cart.Owner{}, false
+		}
+		guestID = cart.NewGuestID()
+	}
+
+	owner, err := cart.GuestOwner(guestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Abort()
+		return cart.Owner{}, false
+	}
+	c.Header(cart.GuestHeaderName, guestID)
+	return owner, true
+}
+
+// GetCart godoc
+// @Summary Get cart
+// @Description Get the cart of the signed-in user, or the guest cart named by X-Cart-ID, priced with current prices and stock
+// @Tags cart
+// @Produce json
+// @Param X-Cart-ID header string false "Guest cart ID, merged into the cart of a signed-in user"
+// @Success 200 {object} map[string]interface{} "Cart retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid cart ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /cart [get]
+func (h *CartHandler) GetCart(c *gin.Context) {
+	owner, ok := h.cartOwner(c, false)
+	if c.IsAborted() {
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "cart retrieved successfully",
+			"data":    models.CartResponse{Items: []models.CartItemResponse{}, Total: money.Zero(money.DefaultCurrency), Valid: true},
+		})
+		return
+	}
+
+	resp, err := h.service.GetCart(c.Request.Context(), owner)
+	if err != nil {
+		c.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "cart retrieved successfully",
+		"data":    resp,
+	})
+}
+
+// AddCartItem godoc
+// @Summary Add item to cart
+// @Description Add a product to the cart. Guests without X-Cart-ID get a new cart ID in the X-Cart-ID response header.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-ID header string false "Guest cart ID, merged into the cart of a signed-in user"
+// @Param item body models.AddCartItemRequest true "Product and quantity"
+// @Success 200 {object} map[string]interface{} "Item added successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request or insufficient stock"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Product not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /cart/items [post]
+func (h *CartHandler) AddCartItem(c *gin.Context) {
+	var req models.AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	owner, _ := h.cartOwner(c, true)
+	if c.IsAborted() {
+		return
+	}
+
+	resp, err := h.service.AddItem(c.Request.Context(), owner, &req)
+	if err != nil {
+		c.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "item added successfully",
+		"data":    resp,
+	})
+}
+
+// UpdateCartItem godoc
+// @Summary Update cart item
+// @Description Set the quantity of a product in the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-ID header string false "Guest cart ID, merged into the cart of a signed-in user"
+// @Param product_id path int true "Product ID"
+// @Param item body models.UpdateCartItemRequest true "New quantity"
+// @Success 200 {object} map[string]interface{} "Item updated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request or insufficient stock"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Item not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /cart/items/{product_id} [patch]
+func (h *CartHandler) UpdateCartItem(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product id"})
+		return
+	}
+
+	var req models.UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	owner, ok := h.cartOwner(c, false)
+	if c.IsAborted() {
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "cart item not found"})
+		return
+	}
+
+	resp, err := h.service.UpdateItem(c.Request.Context(), owner, uint(productID), req.Quantity)
+	if err != nil {
+		c.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "item updated successfully",
+		"data":    resp,
+	})
+}
+
+// RemoveCartItem godoc
+// @Summary Remove cart item
+// @Description Remove a product from the cart
+// @Tags cart
+// @Produce json
+// @Param X-Cart-ID header string false "Guest cart ID, merged into the cart of a signed-in user"
+// @Param product_id path int true "Product ID"
+// @Success 200 {object} map[string]interface{} "Item removed successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /cart/items/{product_id} [delete]
+func (h *CartHandler) RemoveCartItem(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("product_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product id"})
+		return
+	}
+
+	owner, ok := h.cartOwner(c, false)
+	if c.IsAborted() {
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "item removed successfully",
+			"data":    models.CartResponse{Items: []models.CartItemResponse{}, Total: money.Zero(money.DefaultCurrency), Valid: true},
+		})
+		return
+	}
+
+	resp, err := h.service.RemoveItem(c.Request.Context(), owner, uint(productID))
+	if err != nil {
+		c.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "item removed successfully",
+		"data":    resp,
+	})
+}
+
+// ClearCart godoc
+// @Summary Clear cart
+// @Description Remove every item from the cart
+// @Tags cart
+// @Produce json
+// @Param X-Cart-ID header string false "Guest cart ID, merged into the cart of a signed-in user"
+// @Success 200 {object} map[string]interface{} "Cart cleared successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid cart ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /cart [delete]
+func (h *CartHandler) ClearCart(c *gin.Context) {
+	owner, ok := h.cartOwner(c, false)
+	if c.IsAborted() {
+		return
+	}
+	if ok {
+		if err := h.service.ClearCart(c.Request.Context(), owner); err != nil {
+			c.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "cart cleared successfully",
+	})
+}
+
+// MergeCart godoc
+// @Summary Merge guest cart
+// @Description Move the items of the guest cart named by X-Cart-ID into the cart of the signed-in user. Quantities are added and clamped to the stock, merging again adds nothing. Other cart calls of a signed-in user that send X-Cart-ID merge it as well.
+// @Tags cart
+// @Produce json
+// @Param X-Cart-ID header string true "Guest cart ID"
+// @Success 200 {object} map[string]interface{} "Cart merged successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid cart ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /cart/merge [post]
+func (h *CartHandler) MergeCart(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	guestID := c.GetHeader(cart.GuestHeaderName)
+	if guestID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": cart.GuestHeaderName + " header required"})
+		return
+	}
+
+	if err := h.service.MergeGuestCart(c.Request.Context(), userID.(uint), guestID); err != nil {
+		c.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.GetCart(c.Request.Context(), cart.UserOwner(userID.(uint)))
+	if err != nil {
+		c.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "cart merged successfully",
+		"data":    resp,
+	})
+}
+
+// Checkout godoc
+// @Summary Checkout cart
+// @Description Create an order from the cart of the signed-in user and clear the cart. The body is optional.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-ID header string false "Guest cart ID, merged into the cart of a signed-in user"
+// @Param request body models.CheckoutRequest false "Coupon code and shipping address"
+// @Success 201 {object} map[string]interface{} "Order created successfully"
+// @Failure 400 {object} map[string]interface{} "Cart is empty or has unavailable items"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /cart/checkout [post]
+func (h *CartHandler) Checkout(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	// merges a guest cart the user filled before signing in
+	h.cartOwner(c, false)
+	if c.IsAborted() {
+		return
+	}
+
+	var req models.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	order, err := h.service.Checkout(c.Request.Context(), userID.(uint), &req)
+	if err != nil {
+		c.JSON(cartErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "order created successfully",
+		"data":    order,
+	})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/cart"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+)
+
+// fakeCartService records guest cart merges and the owners of cart reads
+type fakeCartService struct {
+	service.CartService
+	merged []string
+	read   []cart.Owner
+}
+
+func (s *fakeCartService) MergeGuestCart(ctx context.Context, userID uint, guestID string) error {
+	s.merged = append(s.merged, guestID)
+	return nil
+}
+
+func (s *fakeCartService) GetCart(ctx context.Context, owner cart.Owner) (*models.CartResponse, error) {
+	s.read = append(s.read, owner)
+	return &models.CartResponse{}, nil
+}
+
+func getCart(carts *fakeCartService, userID uint, guestID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	h := NewCartHandler(carts)
+	router.GET("/cart", func(c *gin.Context) {
+		if userID != 0 {
+			c.Set("user_id", userID)
+		}
+		h.GetCart(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/cart", nil)
+	if guestID != "" {
+		req.Header.Set(cart.GuestHeaderName, guestID)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSignedInCartCallMergesGuestCart(t *testing.T) {
+	carts := &fakeCartService{}
+	guestID := cart.NewGuestID()
+
+	rec := getCart(carts, 7, guestID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if len(carts.merged) != 1 || carts.merged[0] != guestID {
+		t.Errorf("merged %v, want the guest cart %s", carts.merged, guestID)
+	}
+	if len(carts.read) != 1 || carts.read[0] != cart.UserOwner(7) {
+		t.Errorf("read carts %v, want the cart of user 7", carts.read)
+	}
+
+	// Guests only read their own cart
+	carts = &fakeCartService{}
+	if rec := getCart(carts, 0, guestID); rec.Code != http.StatusOK || len(carts.merged) != 0 {
+		t.Errorf("guest call = %d and merged %v, want 200 without a merge", rec.Code, carts.merged)
+	}
+}
@@ -1,22 +1,33 @@
 package handler
 
 import (
+    "errors"
+    "fmt"
+    "log"
     "net/http"
     "strconv"
     "strings"
     
     "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "github.com/ploezy/ecommerce-platform/order-service/internal/idempotency"
     "github.com/ploezy/ecommerce-platform/order-service/internal/models"
+    "github.com/ploezy/ecommerce-platform/order-service/internal/saga"
     "github.com/ploezy/ecommerce-platform/order-service/internal/service"
 )
 
+// maxIdempotencyKeyLength limits the size of the Idempotency-Key header
+const maxIdempotencyKeyLength = 255
+
 type OrderHandler struct {
-    service service.OrderService
+    service     service.OrderService
+    idempotency *idempotency.Store
 }
 
-func NewOrderHandler(service service.OrderService) *OrderHandler {
+func NewOrderHandler(service service.OrderService, idempotencyStore *idempotency.Store) *OrderHandler {
     return &OrderHandler{
-        service: service,
+        service:     service,
+        idempotency: idempotencyStore,
     }
 }
 
@@ -48,15 +59,22 @@ func contains(str, substr string) bool {
 
 // CreateOrder godoc
 // @Summary Create a new order
-// @Description Create a new order with items
+// @Description Create a new order with items. Send an Idempotency-Key header to make retries safe:
+// @Description a retry with the same key and body returns the original response. When the first attempt failed
+// @Description with a server error, the retry returns the order if it was created after all, or creates it.
+// @Description The order ships to shipping_address_id from the address book, to an inline shipping_address,
+// @Description or to the default address of the user. The address is copied onto the order and sets the shipping fee and tax.
 // @Tags orders
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Unique key for this order attempt"
 // @Param order body models.CreateOrderRequest true "Order data"
 // @Success 201 {object} map[string]interface{} "Order created successfully"
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 404 {object} map[string]interface{} "Product not found"
+// @Failure 409 {object} map[string]interface{} "Request with this key is still in progress"
+// @Failure 422 {object} map[string]interface{} "Idempotency key reused with a different request"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Security BearerAuth
 // @Router /orders [post]
@@ -88,33 +106,204 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
         return
     }
     
+    // Claim the idempotency key, or replay the response of an earlier attempt.
+    // The key is bound to the saga of the attempt that holds it.
+    ctx := c.Request.Context()
+    var idempotencyKey, requestHash, sagaID string
+    if key := c.GetHeader(idempotency.HeaderName); key != "" {
+        if len(key) > maxIdempotencyKeyLength {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "idempotency key is too long",
+            })
+            return
+        }
+        
+        requestHash, err = idempotency.HashRequest(req)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error": err.Error(),
+            })
+            return
+        }
+        
+        // Keys are scoped per user so different users cannot collide
+        idempotencyKey = fmt.Sprintf("idempotency:orders:%d:%s", userID, key)
+        sagaID = uuid.NewString()
+        record, started, err := h.idempotency.Begin(ctx, idempotencyKey, requestHash, sagaID)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error": err.Error(),
+            })
+            return
+        }
+        
+        if !started {
+            if record.RequestHash != requestHash {
+                c.JSON(http.StatusUnprocessableEntity, gin.H{
+                    "error": "idempotency key was already used with a different request",
+                })
+                return
+            }
+            if record.Status == idempotency.StatusCompleted {
+                c.Header("Idempotent-Replayed", "true")
+                c.Data(record.StatusCode, "application/json; charset=utf-8", record.Body)
+                return
+            }
+            if h.idempotency.Running(record) {
+                c.JSON(http.StatusConflict, gin.H{
+                    "error": "a request with this idempotency key is still being processed",
+                })
+                return
+            }
+            if !h.settleEarlierAttempt(c, idempotencyKey, userID, record, sagaID) {
+                return
+            }
+        }
+        ctx = saga.WithID(ctx, sagaID)
+    }
 
-    order, err := h.service.CreateOrder(c.Request.Context(), userID, &req)
+    order, err := h.service.CreateOrder(ctx, userID, &req)
     if err != nil {
         // Check error type for appropriate status code
         statusCode := http.StatusInternalServerError
         errorMessage := err.Error()
         
         // Handle specific errors
-        if contains(errorMessage, "insufficient stock") {
+        if contains(errorMessage, "insufficient stock") || contains(errorMessage, "invalid") {
             statusCode = http.StatusBadRequest
         } else if contains(errorMessage, "not found") {
             statusCode = http.StatusNotFound
         }
         
-        c.JSON(statusCode, gin.H{
+        h.respondIdempotent(c, idempotencyKey, requestHash, sagaID, statusCode, gin.H{
             "error": errorMessage,
         })
         return
     }
     
     // Return success response
-    c.JSON(http.StatusCreated, gin.H{
+    h.respondIdempotent(c, idempotencyKey, requestHash, sagaID, http.StatusCreated, gin.H{
         "message": "order created successfully",
         "data": order,
     })
 }
 
+// settleEarlierAttempt answers a retry of a request that failed with a server
+// error or never finished, using what the saga of that attempt did. It returns
+// true when the saga created nothing and the key was claimed again for sagaID.
+func (h *OrderHandler) settleEarlierAttempt(c *gin.Context, key string, userID uint, record *idempotency.Record, sagaID string) bool {
+    order, err := h.service.GetOrderBySaga(c.Request.Context(), record.SagaID, userID)
+    switch {
+    case err == nil:
+        c.Header("Idempotent-Replayed", "true")
+        h.respondIdempotent(c, key, record.RequestHash, record.SagaID, http.StatusCreated, gin.H{
+            "message": "order created successfully",
+            "data": order,
+        })
+        return false
+    case errors.Is(err, service.ErrOrderInProgress):
+        c.JSON(http.StatusConflict, gin.H{
+            "error": "a request with this idempotency key is still being processed",
+        })
+        return false
+    case errors.Is(err, service.ErrOrderNotCreated):
+        retaken, err := h.idempotency.Retake(c.Request.Context(), key, record, sagaID)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error": err.Error(),
+            })
+            return false
+        }
+        if !retaken {
+            c.JSON(http.StatusConflict, gin.H{
+                "error": "a request with this idempotency key is still being processed",
+            })
+            return false
+        }
+        return true
+    default:
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error": err.Error(),
+        })
+        return false
+    }
+}
+
+// respondIdempotent writes the response and stores it under the idempotency key.
+// After a server error the outcome of the saga is unknown, so the key is kept
+// bound to the saga and a retry looks it up instead of creating a second order.
+func (h *OrderHandler) respondIdempotent(c *gin.Context, key, requestHash, sagaID string, statusCode int, body gin.H) {
+    if key != "" {
+        var err error
+        if statusCode >= http.StatusInternalServerError {
+            err = h.idempotency.MarkUnknown(c.Request.Context(), key, requestHash, sagaID)
+        } else {
+            err = h.idempotency.Complete(c.Request.Context(), key, requestHash, statusCode, body)
+        }
+        if err != nil {
+            log.Printf("Failed to record idempotent response for %s: %v", key, err)
+        }
+    }
+    
+    c.JSON(statusCode, body)
+}
+
+// QuoteOrder godoc
+// @Summary Quote an order
+// @Description Price a basket with the current prices, automatic promotions, an optional coupon,
+// @Description the shipping fee and tax exactly like creating the order would, without creating it or reserving stock
+// @Description Without a shipping address the shipping fee and tax are estimated for shipping_country and shipping_region.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param order body models.CreateOrderRequest true "Order data"
+// @Success 200 {object} map[string]interface{} "Order quoted successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request or coupon cannot be used"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Product not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /orders/quote [post]
+func (h *OrderHandler) QuoteOrder(c *gin.Context) {
+    userID, err := h.getUserIDFromContext(c)
+    if err != nil {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error": "unauthorized",
+        })
+        return
+    }
+    
+    var req models.CreateOrderRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "invalid request body",
+            "details": err.Error(),
+        })
+        return
+    }
+    
+    quote, err := h.service.QuoteOrder(c.Request.Context(), userID, &req)
+    if err != nil {
+        statusCode := http.StatusInternalServerError
+        errorMessage := err.Error()
+        if contains(errorMessage, "invalid") || contains(errorMessage, "must have") {
+            statusCode = http.StatusBadRequest
+        } else if contains(errorMessage, "not found") {
+            statusCode = http.StatusNotFound
+        }
+        
+        c.JSON(statusCode, gin.H{
+            "error": errorMessage,
+        })
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "message": "order quoted successfully",
+        "data": quote,
+    })
+}
+
 // GetOrders godoc
 // @Summary Get user orders
 // @Description Get all orders for the authenticated user with pagination
@@ -227,9 +416,69 @@ func (h *OrderHandler) GetOrderByID(c *gin.Context) {
     })
 }
 
+// GetOrderHistory godoc
+// @Summary Get order status history
+// @Description Get every status change of an order with its actor, reason and time, oldest first
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} map[string]interface{} "Order history retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid order ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /orders/{id}/history [get]
+func (h *OrderHandler) GetOrderHistory(c *gin.Context) {
+    // ดึง user ID จาก JWT context
+    userID, err := h.getUserIDFromContext(c)
+    if err != nil {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error": "unauthorized",
+        })
+        return
+    }
+    
+    // Parse order ID from URL parameter
+    orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "invalid order id",
+        })
+        return
+    }
+    
+    history, err := h.service.GetOrderHistory(c.Request.Context(), uint(orderID), userID)
+    if err != nil {
+        statusCode := http.StatusInternalServerError
+        errorMessage := err.Error()
+        
+        // Handle specific errors
+        if contains(errorMessage, "not found") {
+            statusCode = http.StatusNotFound
+        } else if contains(errorMessage, "unauthorized") {
+            statusCode = http.StatusForbidden
+        }
+        
+        c.JSON(statusCode, gin.H{
+            "error": errorMessage,
+        })
+        return
+    }
+    
+    // Return success response
+    c.JSON(http.StatusOK, gin.H{
+        "message": "order history retrieved successfully",
+        "data": history,
+    })
+}
+
 // UpdateOrderStatus godoc
 // @Summary Update order status
-// @Description Update the status of an order (Admin only)
+// @Description Update the status of an order (Admin only). Shipping statuses (partially_shipped, shipped, delivered)
+// @Description follow the shipments of the order and are rejected here, see POST /admin/orders/{id}/shipments.
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -243,6 +492,15 @@ func (h *OrderHandler) GetOrderByID(c *gin.Context) {
 // @Security BearerAuth
 // @Router /admin/orders/{id}/status [put]
 func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
+    // ดึง user ID จาก JWT context
+    userID, err := h.getUserIDFromContext(c)
+    if err != nil {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error": "unauthorized",
+        })
+        return
+    }
+    
     // Parse order ID from URL parameter
     orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
     if err != nil {
@@ -266,7 +524,8 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
     }
     
     // Call service to update status
-    err = h.service.UpdateOrderStatus(c.Request.Context(), uint(orderID), req.Status)
+    actor := models.Actor{Type: models.ActorTypeAdmin, ID: userID}
+    err = h.service.UpdateOrderStatus(c.Request.Context(), uint(orderID), req.Status, actor)
     if err != nil {
         statusCode := http.StatusInternalServerError
         errorMessage := err.Error()
@@ -290,6 +549,167 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
     })
 }
 
+// ListOrders godoc
+// @Summary List orders
+// @Description List the orders of every user with filters, sorting and cursor pagination (Admin only).
+// @Description Pass next_cursor of a page as cursor to get the next page with the same filters and sort.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param status query []string false "Order statuses, repeated or comma separated" collectionFormat(multi)
+// @Param user_id query int false "User ID"
+// @Param product_id query int false "Only orders containing this product"
+// @Param q query string false "Order ID, recipient name or phone"
+// @Param created_from query string false "Created at or after (RFC 3339)"
+// @Param created_to query string false "Created before (RFC 3339)"
+// @Param min_total query int false "Minimum total in minor units"
+// @Param max_total query int false "Maximum total in minor units"
+// @Param currency query string false "Currency of the total, required to sort by total, defaults to THB when an amount is given"
+// @Param sort query string false "created_at, -created_at, total or -total" default(-created_at)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Orders per page, at most 100" default(20)
+// @Success 200 {object} map[string]interface{} "Orders retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/orders [get]
+func (h *OrderHandler) ListOrders(c *gin.Context) {
+    var query models.AdminOrderQuery
+    if err := c.ShouldBindQuery(&query); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "invalid query",
+            "details": err.Error(),
+        })
+        return
+    }
+    
+    page, err := h.service.ListOrders(c.Request.Context(), &query)
+    if err != nil {
+        statusCode := http.StatusInternalServerError
+        if contains(err.Error(), "invalid") {
+            statusCode = http.StatusBadRequest
+        }
+        c.JSON(statusCode, gin.H{
+            "error": err.Error(),
+        })
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "message": "orders retrieved successfully",
+        "data": page.Orders,
+        "pagination": gin.H{
+            "next_cursor": page.NextCursor,
+            "has_more":    page.HasMore,
+        },
+    })
+}
+
+// GetOrderByIDAdmin godoc
+// @Summary Get any order
+// @Description Get an order by ID regardless of the user who placed it (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} map[string]interface{} "Order retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid order ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/orders/{id} [get]
+func (h *OrderHandler) GetOrderByIDAdmin(c *gin.Context) {
+    orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "invalid order id",
+        })
+        return
+    }
+    
+    order, err := h.service.GetOrder(c.Request.Context(), uint(orderID))
+    if err != nil {
+        statusCode := http.StatusInternalServerError
+        if contains(err.Error(), "not found") {
+            statusCode = http.StatusNotFound
+        }
+        c.JSON(statusCode, gin.H{
+            "error": err.Error(),
+        })
+        return
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "message": "order retrieved successfully",
+        "data": order,
+    })
+}
+
+// BulkUpdateOrderStatus godoc
+// @Summary Update the status of several orders
+// @Description Move up to 100 orders to a status through the order state machine (Admin only).
+// @Description Each order is changed on its own; the result of every order is reported and one failure does not stop the others.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.BulkOrderStatusRequest true "Orders and new status"
+// @Success 200 {object} map[string]interface{} "Bulk status update finished"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/orders/bulk-status [post]
+func (h *OrderHandler) BulkUpdateOrderStatus(c *gin.Context) {
+    userID, err := h.getUserIDFromContext(c)
+    if err != nil {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error": "unauthorized",
+        })
+        return
+    }
+    
+    var req models.BulkOrderStatusRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "invalid request body",
+            "details": err.Error(),
+        })
+        return
+    }
+    
+    actor := models.Actor{Type: models.ActorTypeAdmin, ID: userID}
+    results, err := h.service.BulkUpdateOrderStatus(c.Request.Context(), &req, actor)
+    if err != nil {
+        statusCode := http.StatusInternalServerError
+        if contains(err.Error(), "invalid") {
+            statusCode = http.StatusBadRequest
+        }
+        c.JSON(statusCode, gin.H{
+            "error": err.Error(),
+        })
+        return
+    }
+    
+    succeeded := 0
+    for _, result := range results {
+        if result.Success {
+            succeeded++
+        }
+    }
+    
+    c.JSON(http.StatusOK, gin.H{
+        "message": "bulk status update finished",
+        "data": results,
+        "summary": gin.H{
+            "total":     len(results),
+            "succeeded": succeeded,
+            "failed":    len(results) - succeeded,
+        },
+    })
+}
+
 // CancelOrder godoc
 // @Summary Cancel an order
 // @Description Cancel a pending order and restore product stock
@@ -297,6 +717,7 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Order ID"
+// @Param request body models.CancelOrderRequest false "Cancellation reason"
 // @Success 200 {object} map[string]interface{} "Order cancelled successfully"
 // @Failure 400 {object} map[string]interface{} "Bad request"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
@@ -324,8 +745,20 @@ func (h *OrderHandler) CancelOrder(c *gin.Context) {
         return
     }
     
+    // Parse optional request body
+    var req models.CancelOrderRequest
+    if c.Request.ContentLength > 0 {
+        if err := c.ShouldBindJSON(&req); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "invalid request body",
+                "details": err.Error(),
+            })
+            return
+        }
+    }
+    
     // Call service to cancel order
-    err = h.service.CancelOrder(c.Request.Context(), uint(orderID), userID)
+    err = h.service.CancelOrder(c.Request.Context(), uint(orderID), userID, req.Reason)
     if err != nil {
         statusCode := http.StatusInternalServerError
         errorMessage := err.Error()
@@ -335,7 +768,7 @@ func (h *OrderHandler) CancelOrder(c *gin.Context) {
             statusCode = http.StatusNotFound
         } else if contains(errorMessage, "unauthorized") {
             statusCode = http.StatusForbidden
-        } else if contains(errorMessage, "cannot cancel") {
+        } else if contains(errorMessage, "cannot change") {
             statusCode = http.StatusBadRequest
         }
         
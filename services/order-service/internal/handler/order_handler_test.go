@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/idempotency"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+)
+
+// fakeOrderService creates orders with the results in createErrs, in order,
+// and reports sagaErr for the saga of an earlier attempt
+type fakeOrderService struct {
+	service.OrderService
+	createErrs []error
+	creates    int
+	sagaErr    error
+}
+
+func (s *fakeOrderService) CreateOrder(ctx context.Context, userID uint, req *models.CreateOrderRequest) (*models.Order, error) {
+	s.creates++
+	if len(s.createErrs) > 0 {
+		err := s.createErrs[0]
+		s.createErrs = s.createErrs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &models.Order{ID: uint(s.creates), UserID: userID}, nil
+}
+
+func (s *fakeOrderService) GetOrderBySaga(ctx context.Context, sagaID string, userID uint) (*models.Order, error) {
+	if s.sagaErr != nil {
+		return nil, s.sagaErr
+	}
+	return &models.Order{ID: 41, UserID: userID}, nil
+}
+
+func newIdempotentRouter(t *testing.T, orders *fakeOrderService) *gin.Engine {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	h := NewOrderHandler(orders, idempotency.NewStore(client, time.Hour, time.Minute))
+	router.POST("/orders", func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+		h.CreateOrder(c)
+	})
+	return router
+}
+
+func postOrder(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotency.HeaderName, key)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+const (
+	orderBody      = `{"items":[{"product_id":1,"quantity":1}]}`
+	otherOrderBody = `{"items":[{"product_id":1,"quantity":2}]}`
+)
+
+func TestCreateOrderReplaysResponse(t *testing.T) {
+	orders := &fakeOrderService{}
+	router := newIdempotentRouter(t, orders)
+
+	first := postOrder(router, "key-1", orderBody)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first attempt status = %d, want %d", first.Code, http.StatusCreated)
+	}
+	retry := postOrder(router, "key-1", orderBody)
+	if retry.Code != http.StatusCreated || retry.Header().Get("Idempotent-Replayed") != "true" || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s, want the replayed response %s", retry.Code, retry.Body, first.Body)
+	}
+	if rec := postOrder(router, "key-1", otherOrderBody); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("retry with another body status = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	if orders.creates != 1 {
+		t.Errorf("CreateOrder() called %d times, want 1", orders.creates)
+	}
+}
+
+func TestRetryAfterServerErrorReturnsOrderOfSaga(t *testing.T) {
+	orders := &fakeOrderService{createErrs: []error{errors.New("failed to get order: connection reset")}}
+	router := newIdempotentRouter(t, orders)
+
+	if rec := postOrder(router, "key-1", orderBody); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("first attempt status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+
+	// The saga of the first attempt created order 41 after all
+	retry := postOrder(router, "key-1", orderBody)
+	if retry.Code != http.StatusCreated || !strings.Contains(retry.Body.String(), `"id":41`) {
+		t.Fatalf("retry = %d %s, want order 41", retry.Code, retry.Body)
+	}
+	if again := postOrder(router, "key-1", orderBody); again.Body.String() != retry.Body.String() {
+		t.Errorf("second retry = %s, want %s", again.Body, retry.Body)
+	}
+	if orders.creates != 1 {
+		t.Errorf("CreateOrder() called %d times, want 1", orders.creates)
+	}
+}
+
+func TestRetryAfterServerErrorCreatesOrderWhenSagaFailed(t *testing.T) {
+	orders := &fakeOrderService{
+		createErrs: []error{errors.New("reserve_stock: connection refused")},
+		sagaErr:    service.ErrOrderNotCreated,
+	}
+	router := newIdempotentRouter(t, orders)
+
+	if rec := postOrder(router, "key-1", orderBody); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("first attempt status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if rec := postOrder(router, "key-1", orderBody); rec.Code != http.StatusCreated {
+		t.Fatalf("retry status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if orders.creates != 2 {
+		t.Errorf("CreateOrder() called %d times, want 2", orders.creates)
+	}
+}
+
+func TestRetryWhileSagaRunsConflicts(t *testing.T) {
+	orders := &fakeOrderService{
+		createErrs: []error{errors.New("commit_reservation: deadline exceeded")},
+		sagaErr:    service.ErrOrderInProgress,
+	}
+	router := newIdempotentRouter(t, orders)
+
+	postOrder(router, "key-1", orderBody)
+	if rec := postOrder(router, "key-1", orderBody); rec.Code != http.StatusConflict {
+		t.Errorf("retry status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if orders.creates != 1 {
+		t.Errorf("CreateOrder() called %d times, want 1", orders.creates)
+	}
+}
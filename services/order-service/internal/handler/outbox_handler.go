@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+)
+
+type OutboxHandler struct {
+	service service.OutboxService
+}
+
+func NewOutboxHandler(service service.OutboxService) *OutboxHandler {
+	return &OutboxHandler{
+		service: service,
+	}
+}
+
+// ListOutboxEvents godoc
+// @Summary List outbox events
+// @Description List outbox events filtered by status (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param status query string false "Status filter (pending, sent, failed)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{} "Outbox events retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/outbox [get]
+func (h *OutboxHandler) ListOutboxEvents(c *gin.Context) {
+	status := c.Query("status")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	events, total, err := h.service.ListEvents(c.Request.Context(), status, page, limit)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if contains(err.Error(), "invalid") {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "outbox events retrieved successfully",
+		"data":    events,
+		"pagination": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total":        total,
+			"total_pages":  (int(total) + limit - 1) / limit,
+		},
+	})
+}
+
+// GetOutboxEvent godoc
+// @Summary Get outbox event
+// @Description Get an outbox event by ID including its payload and last error (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Outbox event ID"
+// @Success 200 {object} map[string]interface{} "Outbox event retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid outbox event ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Outbox event not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/outbox/{id} [get]
+func (h *OutboxHandler) GetOutboxEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid outbox event id",
+		})
+		return
+	}
+
+	event, err := h.service.GetEvent(c.Request.Context(), uint(id))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "outbox event retrieved successfully",
+		"data":    event,
+	})
+}
+
+// ReplayOutboxEvent godoc
+// @Summary Replay outbox event
+// @Description Queue a failed outbox event for publishing again (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Outbox event ID"
+// @Success 200 {object} map[string]interface{} "Outbox event queued for replay"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Outbox event not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/outbox/{id}/replay [post]
+func (h *OutboxHandler) ReplayOutboxEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid outbox event id",
+		})
+		return
+	}
+
+	event, err := h.service.ReplayEvent(c.Request.Context(), uint(id))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if contains(err.Error(), "cannot replay") {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "outbox event queued for replay",
+		"data":    event,
+	})
+}
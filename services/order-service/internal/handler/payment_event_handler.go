@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/correlation"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
+	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+// PaymentEventHandler applies payment-service events to orders
+type PaymentEventHandler struct {
+	service service.OrderService
+}
+
+// NewPaymentEventHandler creates a new payment event handler
+func NewPaymentEventHandler(service service.OrderService) *PaymentEventHandler {
+	return &PaymentEventHandler{service: service}
+}
+
+// HandlePaymentCaptured moves the order of a payment.captured event to
+// processing. A capture the order cannot take, because the order was
+// cancelled, expired or is missing or the capture is below the total, is
+// refunded. Malformed events are logged and skipped, other errors are returned
+// so the consumer retries the message.
+func (h *PaymentEventHandler) HandlePaymentCaptured(ctx context.Context, value []byte) error {
+	var envelope kafka.IncomingEnvelope
+	if err := json.Unmarshal(value, &envelope); err != nil {
+		log.Printf("Skipping malformed payment event: %v", err)
+		return nil
+	}
+	if envelope.SchemaVersion != kafka.PaymentSchemaVersion {
+		log.Printf("Skipping payment event %s: unsupported schema version %d", envelope.EventID, envelope.SchemaVersion)
+		return nil
+	}
+
+	var event kafka.PaymentEvent
+	if err := json.Unmarshal(envelope.Data, &event); err != nil {
+		log.Printf("Skipping payment event %s: malformed data: %v", envelope.EventID, err)
+		return nil
+	}
+
+	if envelope.CorrelationID != "" {
+		ctx = correlation.NewContext(ctx, envelope.CorrelationID)
+	}
+
+	err := h.service.MarkOrderPaid(ctx, event.OrderID, event.PaymentIntentID, money.New(event.CapturedTotal, event.Currency))
+	switch {
+	case err == nil:
+		log.Printf("Order %d paid by payment intent %s", event.OrderID, event.PaymentIntentID)
+		return nil
+	case errors.Is(err, statemachine.ErrInvalidTransition),
+		errors.Is(err, service.ErrPaymentTooLow),
+		errors.Is(err, service.ErrOrderNotFound):
+		// Redelivered events end up here too, RefundCapture skips them
+		log.Printf("Refunding payment event %s for order %d: %v", envelope.EventID, event.OrderID, err)
+		return h.service.RefundCapture(ctx, event.OrderID, event.PaymentIntentID, "capture not applied: "+err.Error())
+	default:
+		return err
+	}
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
+	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+	paymentpb "github.com/ploezy/ecommerce-platform/proto/payment"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakePayments voids the payments of every order except the captured ones
+type fakePayments struct {
+	captured map[uint]bool
+	voided   []uint
+}
+
+func (p *fakePayments) RefundPayment(ctx context.Context, intentID string, amount int64, reason, idempotencyKey string) (*paymentpb.PaymentIntentResponse, error) {
+	return &paymentpb.PaymentIntentResponse{Success: true}, nil
+}
+
+func (p *fakePayments) VoidOrderPayments(ctx context.Context, orderID uint) (*paymentpb.VoidOrderPaymentsResponse, error) {
+	if p.captured[orderID] {
+		return &paymentpb.VoidOrderPaymentsResponse{Success: false, Captured: true, Message: "payment already captured"}, nil
+	}
+	p.voided = append(p.voided, orderID)
+	return &paymentpb.VoidOrderPaymentsResponse{Success: true}, nil
+}
+
+type paymentFixture struct {
+	db       *gorm.DB
+	orders   service.OrderService
+	payments *fakePayments
+	handler  *PaymentEventHandler
+}
+
+// newPaymentFixture wires the payment event handler to an order service on an
+// in-memory SQLite database
+func newPaymentFixture(t *testing.T) *paymentFixture {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	err = db.AutoMigrate(&models.Order{}, &models.OrderItem{}, &models.OrderItemAdjustment{}, &models.OrderDiscount{},
+		&models.OrderStatusHistory{}, &models.OutboxEvent{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orderRepo := repository.NewOrderRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	machine := statemachine.NewOrderMachine(db, orderRepo, repository.NewOrderStatusHistoryRepository(db), outboxRepo)
+	payments := &fakePayments{captured: make(map[uint]bool)}
+	orders := service.NewOrderService(orderRepo, repository.NewSagaRepository(db), outboxRepo, nil, nil, machine, db, nil, nil, payments, time.Minute)
+	return &paymentFixture{db: db, orders: orders, payments: payments, handler: NewPaymentEventHandler(orders)}
+}
+
+func (f *paymentFixture) createOrder(t *testing.T) *models.Order {
+	t.Helper()
+	order := &models.Order{
+		UserID:    1,
+		CreatedAt: time.Now().Add(-time.Hour),
+		Status:    models.OrderStatusPending,
+		Total:     money.New(500, "THB"),
+		Items:     []models.OrderItem{{ProductID: 10, Quantity: 1, Price: money.New(500, "THB"), Subtotal: money.New(500, "THB")}},
+	}
+	if err := f.db.Create(order).Error; err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+// capture delivers a payment.captured event of intentID for orderID
+func (f *paymentFixture) capture(t *testing.T, orderID uint, intentID string, captured int64) {
+	t.Helper()
+	value, err := json.Marshal(kafka.NewEnvelope(kafka.TopicPaymentCaptured, kafka.PaymentSchemaVersion, "", kafka.PaymentEvent{
+		PaymentIntentID: intentID,
+		OrderID:         orderID,
+		Amount:          captured,
+		Currency:        "THB",
+		CapturedTotal:   captured,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.handler.HandlePaymentCaptured(context.Background(), value); err != nil {
+		t.Fatalf("HandlePaymentCaptured() error = %v", err)
+	}
+}
+
+func (f *paymentFixture) status(t *testing.T, orderID uint) string {
+	t.Helper()
+	var order models.Order
+	if err := f.db.First(&order, orderID).Error; err != nil {
+		t.Fatal(err)
+	}
+	return order.Status
+}
+
+// refundKeys returns the keys of the refund commands in the outbox
+func (f *paymentFixture) refundKeys(t *testing.T) []string {
+	t.Helper()
+	var keys []string
+	if err := f.db.Model(&models.OutboxEvent{}).Where("topic = ?", statemachine.TopicRefund).Order("id").Pluck("aggregate_id", &keys).Error; err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestCaptureAfterCancelIsRefunded(t *testing.T) {
+	f := newPaymentFixture(t)
+	order := f.createOrder(t)
+	if err := f.orders.CancelOrder(context.Background(), order.ID, order.UserID, "changed my mind"); err != nil {
+		t.Fatal(err)
+	}
+
+	f.capture(t, order.ID, "pi_late", 500)
+	// A redelivery writes the same key, payment-service refunds it once
+	f.capture(t, order.ID, "pi_late", 500)
+
+	key := statemachine.CaptureRefundKey("pi_late")
+	if keys := f.refundKeys(t); fmt.Sprint(keys) != fmt.Sprint([]string{key, key}) {
+		t.Errorf("refund commands = %v, want two for %s", keys, key)
+	}
+	var stored models.Order
+	if err := f.db.First(&stored, order.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != models.OrderStatusCancelled {
+		t.Errorf("order status = %s, want cancelled", stored.Status)
+	}
+}
+
+func TestCaptureBelowTotalOrForMissingOrderIsRefunded(t *testing.T) {
+	f := newPaymentFixture(t)
+	order := f.createOrder(t)
+
+	f.capture(t, order.ID, "pi_short", 300)
+	f.capture(t, order.ID+100, "pi_missing", 500)
+
+	want := []string{statemachine.CaptureRefundKey("pi_short"), statemachine.CaptureRefundKey("pi_missing")}
+	if keys := f.refundKeys(t); fmt.Sprint(keys) != fmt.Sprint(want) {
+		t.Errorf("refund commands = %v, want %v", keys, want)
+	}
+}
+
+func TestRedeliveredCaptureIsNotRefunded(t *testing.T) {
+	f := newPaymentFixture(t)
+	order := f.createOrder(t)
+
+	f.capture(t, order.ID, "pi_1", 500)
+	f.capture(t, order.ID, "pi_1", 500)
+
+	if keys := f.refundKeys(t); len(keys) != 0 {
+		t.Errorf("refund commands = %v, want none for the capture that paid the order", keys)
+	}
+}
+
+func TestExpiryRacingCapture(t *testing.T) {
+	f := newPaymentFixture(t)
+	ctx := context.Background()
+	// paid was captured before the sweep voided it, its event is still on its way
+	paid := f.createOrder(t)
+	unpaid := f.createOrder(t)
+	f.payments.captured[paid.ID] = true
+
+	cancelled, err := f.orders.ExpirePendingOrders(ctx, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("ExpirePendingOrders() error = %v", err)
+	}
+	if cancelled != 1 || fmt.Sprint(f.payments.voided) != fmt.Sprint([]uint{unpaid.ID}) {
+		t.Fatalf("cancelled %d orders after voiding %v, want 1 after voiding [%d]", cancelled, f.payments.voided, unpaid.ID)
+	}
+	if status := f.status(t, paid.ID); status != models.OrderStatusPending {
+		t.Fatalf("captured order status = %s, want pending until its capture arrives", status)
+	}
+
+	// The capture of the skipped order pays it; a capture of the expired order
+	// that slipped past the void is refunded
+	f.capture(t, paid.ID, "pi_paid", 500)
+	f.capture(t, unpaid.ID, "pi_unpaid", 500)
+
+	if status := f.status(t, paid.ID); status != models.OrderStatusProcessing {
+		t.Errorf("captured order status = %s, want processing", status)
+	}
+	if status := f.status(t, unpaid.ID); status != models.OrderStatusCancelled {
+		t.Errorf("expired order status = %s, want cancelled", status)
+	}
+	want := []string{statemachine.CaptureRefundKey("pi_unpaid")}
+	if keys := f.refundKeys(t); fmt.Sprint(keys) != fmt.Sprint(want) {
+		t.Errorf("refund commands = %v, want %v", keys, want)
+	}
+}
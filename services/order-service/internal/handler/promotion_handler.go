@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+)
+
+type PromotionHandler struct {
+	service service.PromotionService
+}
+
+func NewPromotionHandler(service service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		service: service,
+	}
+}
+
+// promotionErrorStatus maps promotion service errors to HTTP status codes
+func promotionErrorStatus(err error) int {
+	errorMessage := err.Error()
+	switch {
+	case contains(errorMessage, "not found"):
+		return http.StatusNotFound
+	case contains(errorMessage, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreatePromotion godoc
+// @Summary Create promotion
+// @Description Create a coupon, or a promotion that applies automatically when no code is given (Admin only).
+// @Description Types: percentage (percent_off), fixed (amount_off) and buy_x_get_y (buy_quantity, get_quantity).
+// @Description category and product_id limit the items a promotion applies to.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param promotion body models.PromotionRequest true "Promotion"
+// @Success 201 {object} map[string]interface{} "Promotion created successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/promotions [post]
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	var req models.PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	promotion, err := h.service.CreatePromotion(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(promotionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "promotion created successfully",
+		"data":    promotion,
+	})
+}
+
+// ListPromotions godoc
+// @Summary List promotions
+// @Description List promotions, newest first (Admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} map[string]interface{} "Promotions retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/promotions [get]
+func (h *PromotionHandler) ListPromotions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	promotions, total, err := h.service.ListPromotions(c.Request.Context(), page, limit)
+	if err != nil {
+		c.JSON(promotionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "promotions retrieved successfully",
+		"data":    promotions,
+		"pagination": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total":        total,
+			"total_pages":  (int(total) + limit - 1) / limit,
+		},
+	})
+}
+
+// GetPromotion godoc
+// @Summary Get promotion
+// @Description Get a promotion by ID (Admin only)
+// @Tags admin
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} map[string]interface{} "Promotion retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid promotion ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Promotion not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/promotions/{id} [get]
+func (h *PromotionHandler) GetPromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid promotion id"})
+		return
+	}
+
+	promotion, err := h.service.GetPromotion(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(promotionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "promotion retrieved successfully",
+		"data":    promotion,
+	})
+}
+
+// UpdatePromotion godoc
+// @Summary Update promotion
+// @Description Replace a promotion (Admin only). Set active to false to end it early, orders that used it keep their discounts.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Param promotion body models.PromotionRequest true "Promotion"
+// @Success 200 {object} map[string]interface{} "Promotion updated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Promotion not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid promotion id"})
+		return
+	}
+
+	var req models.PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	promotion, err := h.service.UpdatePromotion(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		c.JSON(promotionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "promotion updated successfully",
+		"data":    promotion,
+	})
+}
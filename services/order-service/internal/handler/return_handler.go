@@ -0,0 +1,328 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+)
+
+type ReturnHandler struct {
+	service service.ReturnService
+}
+
+func NewReturnHandler(service service.ReturnService) *ReturnHandler {
+	return &ReturnHandler{
+		service: service,
+	}
+}
+
+// returnErrorStatus maps return service errors to HTTP status codes
+func returnErrorStatus(err error) int {
+	errorMessage := err.Error()
+	switch {
+	case errors.Is(err, service.ErrRefundDeclined):
+		return http.StatusConflict
+	case contains(errorMessage, "not found"):
+		return http.StatusNotFound
+	case contains(errorMessage, "unauthorized"):
+		return http.StatusForbidden
+	case contains(errorMessage, "invalid") || contains(errorMessage, "cannot"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// RequestReturn godoc
+// @Summary Request a return
+// @Description Request a return for items of a delivered order
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body models.CreateReturnRequest true "Items to return and reason"
+// @Success 201 {object} map[string]interface{} "Return requested successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /orders/{id}/returns [post]
+func (h *ReturnHandler) RequestReturn(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order id"})
+		return
+	}
+
+	var req models.CreateReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ret, err := h.service.RequestReturn(c.Request.Context(), uint(orderID), userID.(uint), &req)
+	if err != nil {
+		c.JSON(returnErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "return requested successfully",
+		"data":    ret,
+	})
+}
+
+// GetOrderReturns godoc
+// @Summary Get order returns
+// @Description Get the return requests of an order
+// @Tags returns
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} map[string]interface{} "Returns retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid order ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /orders/{id}/returns [get]
+func (h *ReturnHandler) GetOrderReturns(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order id"})
+		return
+	}
+
+	returns, err := h.service.GetOrderReturns(c.Request.Context(), uint(orderID), userID.(uint))
+	if err != nil {
+		c.JSON(returnErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "returns retrieved successfully",
+		"data":    returns,
+	})
+}
+
+// GetReturn godoc
+// @Summary Get return request
+// @Description Get a return request by ID (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Success 200 {object} map[string]interface{} "Return retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid return ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Return not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/returns/{id} [get]
+func (h *ReturnHandler) GetReturn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid return id"})
+		return
+	}
+
+	ret, err := h.service.GetReturn(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(returnErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "return retrieved successfully",
+		"data":    ret,
+	})
+}
+
+// ApproveReturn godoc
+// @Summary Approve return request
+// @Description Approve a requested return (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Param request body models.ReviewReturnRequest false "Note for the customer"
+// @Success 200 {object} map[string]interface{} "Return approved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Return not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/returns/{id}/approve [post]
+func (h *ReturnHandler) ApproveReturn(c *gin.Context) {
+	h.review(c, "return approved successfully", h.service.ApproveReturn)
+}
+
+// RejectReturn godoc
+// @Summary Reject return request
+// @Description Reject a return and move the order back to delivered (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Param request body models.ReviewReturnRequest false "Reason for the rejection"
+// @Success 200 {object} map[string]interface{} "Return rejected successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Return not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/returns/{id}/reject [post]
+func (h *ReturnHandler) RejectReturn(c *gin.Context) {
+	h.review(c, "return rejected successfully", h.service.RejectReturn)
+}
+
+// ReceiveReturn godoc
+// @Summary Receive returned goods
+// @Description Record that the goods of an approved return arrived and restock them (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Success 200 {object} map[string]interface{} "Return received successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Return not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/returns/{id}/receive [post]
+func (h *ReturnHandler) ReceiveReturn(c *gin.Context) {
+	id, actor, ok := h.adminRequest(c)
+	if !ok {
+		return
+	}
+
+	ret, err := h.service.ReceiveReturn(c.Request.Context(), id, actor)
+	if err != nil {
+		c.JSON(returnErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "return received successfully",
+		"data":    ret,
+	})
+}
+
+// RefundReturn godoc
+// @Summary Refund return
+// @Description Refund a received return, fully or partially, to the payment of the order (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Return ID"
+// @Param request body models.RefundReturnRequest false "Refund amount, defaults to the full value of the returned items"
+// @Success 200 {object} map[string]interface{} "Return refunded successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Return not found"
+// @Failure 409 {object} map[string]interface{} "Refund declined by payment-service"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/returns/{id}/refund [post]
+func (h *ReturnHandler) RefundReturn(c *gin.Context) {
+	id, actor, ok := h.adminRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.RefundReturnRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	ret, err := h.service.RefundReturn(c.Request.Context(), id, actor, req.Amount)
+	if err != nil {
+		c.JSON(returnErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "return refunded successfully",
+		"data":    ret,
+	})
+}
+
+// review handles approve and reject, which take the same optional note
+func (h *ReturnHandler) review(
+	c *gin.Context,
+	message string,
+	decide func(ctx context.Context, id uint, actor models.Actor, note string) (*models.ReturnRequest, error),
+) {
+	id, actor, ok := h.adminRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReviewReturnRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	ret, err := decide(c.Request.Context(), id, actor, req.Note)
+	if err != nil {
+		c.JSON(returnErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    ret,
+	})
+}
+
+// adminRequest reads the return ID and the acting admin, writing an error response on failure
+func (h *ReturnHandler) adminRequest(c *gin.Context) (uint, models.Actor, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, models.Actor{}, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid return id"})
+		return 0, models.Actor{}, false
+	}
+
+	return uint(id), models.Actor{Type: models.ActorTypeAdmin, ID: userID.(uint)}, true
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+)
+
+type ShipmentHandler struct {
+	service service.ShipmentService
+}
+
+func NewShipmentHandler(service service.ShipmentService) *ShipmentHandler {
+	return &ShipmentHandler{
+		service: service,
+	}
+}
+
+// shipmentErrorStatus maps shipment service errors to HTTP status codes
+func shipmentErrorStatus(err error) int {
+	errorMessage := err.Error()
+	switch {
+	case contains(errorMessage, "not found"):
+		return http.StatusNotFound
+	case contains(errorMessage, "unauthorized"):
+		return http.StatusForbidden
+	case contains(errorMessage, "invalid") || contains(errorMessage, "cannot"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CreateShipment godoc
+// @Summary Create shipment
+// @Description Hand some items of a processing order to a carrier (Admin only).
+// @Description The order becomes partially_shipped until every item has shipped, then shipped.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body models.CreateShipmentRequest true "Carrier, tracking number and items"
+// @Success 201 {object} map[string]interface{} "Shipment created successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/orders/{id}/shipments [post]
+func (h *ShipmentHandler) CreateShipment(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order id"})
+		return
+	}
+
+	var req models.CreateShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	actor := models.Actor{Type: models.ActorTypeAdmin, ID: userID.(uint)}
+	shipment, err := h.service.CreateShipment(c.Request.Context(), uint(orderID), actor, &req)
+	if err != nil {
+		c.JSON(shipmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "shipment created successfully",
+		"data":    shipment,
+	})
+}
+
+// RecordTracking godoc
+// @Summary Record carrier tracking update
+// @Description Record a tracking update pushed by a carrier for a shipment (Admin only).
+// @Description Updates already recorded are ignored. The order becomes delivered once every shipment is delivered.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.ShipmentTrackingRequest true "Tracking update"
+// @Success 200 {object} map[string]interface{} "Tracking update recorded successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Shipment not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/shipments/tracking [post]
+func (h *ShipmentHandler) RecordTracking(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req models.ShipmentTrackingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	actor := models.Actor{Type: models.ActorTypeAdmin, ID: userID.(uint)}
+	shipment, err := h.service.RecordTracking(c.Request.Context(), actor, &req)
+	if err != nil {
+		c.JSON(shipmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "tracking update recorded successfully",
+		"data":    shipment,
+	})
+}
+
+// GetShipment godoc
+// @Summary Get shipment
+// @Description Get a shipment with its items and tracking updates (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipment ID"
+// @Success 200 {object} map[string]interface{} "Shipment retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid shipment ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Shipment not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /admin/shipments/{id} [get]
+func (h *ShipmentHandler) GetShipment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shipment id"})
+		return
+	}
+
+	shipment, err := h.service.GetShipment(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(shipmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "shipment retrieved successfully",
+		"data":    shipment,
+	})
+}
+
+// GetOrderShipments godoc
+// @Summary Get order shipments
+// @Description Get the shipments of an order with their tracking updates
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} map[string]interface{} "Shipments retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid order ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security BearerAuth
+// @Router /orders/{id}/shipments [get]
+func (h *ShipmentHandler) GetOrderShipments(c *gin.Context) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order id"})
+		return
+	}
+
+	shipments, err := h.service.GetOrderShipments(c.Request.Context(), uint(orderID), userID.(uint))
+	if err != nil {
+		c.JSON(shipmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "shipments retrieved successfully",
+		"data":    shipments,
+	})
+}
@@ -0,0 +1,183 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// HeaderName is the request header that carries the idempotency key
+const HeaderName = "Idempotency-Key"
+
+// Record status constants. A request whose outcome is unknown failed with a
+// server error after it may have started its saga.
+const (
+	StatusInProgress = "in_progress"
+	StatusUnknown    = "unknown"
+	StatusCompleted  = "completed"
+)
+
+// Record is what is stored in Redis for one idempotency key
+type Record struct {
+	RequestHash string          `json:"request_hash"`
+	Status      string          `json:"status"`
+	SagaID      string          `json:"saga_id,omitempty"`
+	StartedAt   time.Time       `json:"started_at"`
+	StatusCode  int             `json:"status_code,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+
+	// raw is the stored value, Retake only replaces a record that is unchanged
+	raw []byte
+}
+
+// retakeScript replaces the value of KEYS[1] with ARGV[2] only if it is still ARGV[1]
+var retakeScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+end
+return false
+`)
+
+// Store keeps idempotency records in Redis
+type Store struct {
+	client  *redis.Client
+	ttl     time.Duration
+	lockTTL time.Duration
+}
+
+// NewStore creates a new idempotency store. Every record is kept for ttl, so a
+// key stays bound to its saga. A request that has not finished after lockTTL
+// is assumed to be gone, and a retry looks up its saga instead of waiting.
+func NewStore(client *redis.Client, ttl, lockTTL time.Duration) *Store {
+	return &Store{
+		client:  client,
+		ttl:     ttl,
+		lockTTL: lockTTL,
+	}
+}
+
+// HashRequest returns a stable hash of a request value
+func HashRequest(request interface{}) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Begin claims key for a new request that runs the saga sagaID. When the key
+// was already used the existing record is returned and started is false.
+func (s *Store) Begin(ctx context.Context, key, requestHash, sagaID string) (*Record, bool, error) {
+	record, data, err := newRecord(requestHash, sagaID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	started, err := s.client.SetNX(ctx, key, data, s.ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	if started {
+		return record, true, nil
+	}
+
+	existing, err := s.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// The record expired between the two calls, try again
+			return s.Begin(ctx, key, requestHash, sagaID)
+		}
+		return nil, false, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	var stored Record
+	if err := json.Unmarshal(existing, &stored); err != nil {
+		return nil, false, fmt.Errorf("failed to decode idempotency record: %w", err)
+	}
+	stored.raw = existing
+	return &stored, false, nil
+}
+
+// Running reports whether the request that holds record may still be running
+func (s *Store) Running(record *Record) bool {
+	return record.Status == StatusInProgress && time.Since(record.StartedAt) < s.lockTTL
+}
+
+// Retake claims key again for a new attempt with the saga sagaID, after the
+// saga of record turned out to have created nothing. It returns false when
+// another request changed the record first.
+func (s *Store) Retake(ctx context.Context, key string, record *Record, sagaID string) (bool, error) {
+	_, data, err := newRecord(record.RequestHash, sagaID)
+	if err != nil {
+		return false, err
+	}
+
+	err = retakeScript.Run(ctx, s.client, []string{key}, record.raw, data, s.ttl.Milliseconds()).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	return true, nil
+}
+
+// Complete stores the final response for key so retries can replay it
+func (s *Store) Complete(ctx context.Context, key, requestHash string, statusCode int, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	data, err := json.Marshal(Record{
+		RequestHash: requestHash,
+		Status:      StatusCompleted,
+		StartedAt:   time.Now(),
+		StatusCode:  statusCode,
+		Body:        payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.client.Set(ctx, key, data, s.ttl).Err()
+}
+
+// MarkUnknown records that the request of key failed with a server error. The
+// key stays bound to the saga sagaID, so a retry returns the order if the saga
+// still went through instead of creating a second one.
+func (s *Store) MarkUnknown(ctx context.Context, key, requestHash, sagaID string) error {
+	record, _, err := newRecord(requestHash, sagaID)
+	if err != nil {
+		return err
+	}
+	record.Status = StatusUnknown
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, key, data, s.ttl).Err()
+}
+
+// newRecord returns an in-progress record and its stored value
+func newRecord(requestHash, sagaID string) (*Record, []byte, error) {
+	record := &Record{
+		RequestHash: requestHash,
+		Status:      StatusInProgress,
+		SagaID:      sagaID,
+		StartedAt:   time.Now(),
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, nil, err
+	}
+	record.raw = data
+	return record, data, nil
+}
@@ -0,0 +1,121 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewStore(client, time.Hour, time.Minute), server
+}
+
+func TestBeginReplaysCompletedResponse(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	record, started, err := store.Begin(ctx, "key", "hash", "saga-1")
+	if err != nil || !started {
+		t.Fatalf("Begin() = %v, %v, want a new record", started, err)
+	}
+	if record.Status != StatusInProgress || record.SagaID != "saga-1" || !store.Running(record) {
+		t.Errorf("record = %+v, want a running in-progress record of saga-1", record)
+	}
+
+	if err := store.Complete(ctx, "key", "hash", http.StatusCreated, map[string]int{"id": 7}); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	record, started, err = store.Begin(ctx, "key", "hash", "saga-2")
+	if err != nil || started {
+		t.Fatalf("Begin() = %v, %v, want the completed record", started, err)
+	}
+	if record.Status != StatusCompleted || record.StatusCode != http.StatusCreated || string(record.Body) != `{"id":7}` {
+		t.Errorf("record = %s %d %s, want the completed response", record.Status, record.StatusCode, record.Body)
+	}
+}
+
+func TestBeginReturnsHashOfOriginalRequest(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	first, _ := HashRequest(map[string]int{"quantity": 1})
+	second, _ := HashRequest(map[string]int{"quantity": 2})
+	if first == second {
+		t.Fatal("HashRequest() returned the same hash for different requests")
+	}
+
+	if _, _, err := store.Begin(ctx, "key", first, "saga-1"); err != nil {
+		t.Fatal(err)
+	}
+	// The handler answers 422 when the stored hash is not the hash of the retry
+	record, started, err := store.Begin(ctx, "key", second, "saga-2")
+	if err != nil || started || record.RequestHash != first {
+		t.Errorf("Begin() with another body = %v, %v, hash %s, want the original record", started, err, record.RequestHash)
+	}
+}
+
+func TestRecordsExpire(t *testing.T) {
+	store, server := newTestStore(t)
+	ctx := context.Background()
+
+	if _, _, err := store.Begin(ctx, "key", "hash", "saga-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Complete(ctx, "key", "hash", http.StatusCreated, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	server.FastForward(time.Hour + time.Second)
+	record, started, err := store.Begin(ctx, "key", "hash", "saga-2")
+	if err != nil || !started || record.SagaID != "saga-2" {
+		t.Errorf("Begin() after expiry = %v, %v, want a new record for saga-2", started, err)
+	}
+}
+
+func TestUnknownOutcomeKeepsSaga(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	if _, _, err := store.Begin(ctx, "key", "hash", "saga-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.MarkUnknown(ctx, "key", "hash", "saga-1"); err != nil {
+		t.Fatalf("MarkUnknown() error = %v", err)
+	}
+
+	record, started, err := store.Begin(ctx, "key", "hash", "saga-2")
+	if err != nil || started {
+		t.Fatalf("Begin() = %v, %v, want the unknown record", started, err)
+	}
+	if record.Status != StatusUnknown || record.SagaID != "saga-1" || store.Running(record) {
+		t.Errorf("record = %+v, want the unknown outcome of saga-1", record)
+	}
+
+	// Only one retry can take the key over for a new saga
+	if ok, err := store.Retake(ctx, "key", record, "saga-2"); err != nil || !ok {
+		t.Fatalf("Retake() = %v, %v, want true", ok, err)
+	}
+	if ok, err := store.Retake(ctx, "key", record, "saga-3"); err != nil || ok {
+		t.Errorf("second Retake() = %v, %v, want false", ok, err)
+	}
+	record, _, _ = store.Begin(ctx, "key", "hash", "saga-4")
+	if record.SagaID != "saga-2" || record.Status != StatusInProgress {
+		t.Errorf("record = %+v, want saga-2 in progress", record)
+	}
+}
+
+func TestStaleInProgressRecordIsNotRunning(t *testing.T) {
+	store, _ := newTestStore(t)
+	record := &Record{Status: StatusInProgress, StartedAt: time.Now().Add(-2 * time.Minute)}
+	if store.Running(record) {
+		t.Error("Running() = true for a request that started longer than the lock TTL ago")
+	}
+}
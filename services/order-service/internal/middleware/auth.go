@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/grpc/client"
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+)
+
+// Roles of a user as issued by user-service
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+)
+
+// Context keys set by AuthMiddleware. The permissions of the caller are set by
+// authz.SetClaims.
+const (
+	ContextUserID = "user_id"
+	ContextRole   = "role"
+)
+
+// TokenVerifier verifies access tokens, usually a token.Verifier that checks
+// them locally against the JWKS of user-service
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*token.Claims, error)
+}
+
+// AuthMiddleware verifies the JWT token and sets the user ID, role and claims
+// of the caller in the context. Routes check permissions with
+// authz.RequirePermission.
+func AuthMiddleware(verifier TokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get token from Authorization header
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(401, gin.H{"error": "authorization header required"})
+			c.Abort()
+			return
+		}
+
+		// Extract token (format: "Bearer <token>")
+		token := ""
+		if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+			token = authHeader[7:]
+		} else {
+			c.JSON(401, gin.H{"error": "invalid authorization format"})
+			c.Abort()
+			return
+		}
+
+		// Verify signature, expiry and revocation
+		claims, err := verifier.Verify(c.Request.Context(), token)
+		if err != nil {
+			c.JSON(401, gin.H{"error": "invalid or expired token"})
+			c.Abort()
+			return
+		}
+
+		// Set user info in context for handlers. The token goes along to
+		// user-service, which only serves addresses to their user.
+		c.Set(ContextUserID, claims.UserID)
+		c.Set(ContextRole, claims.Role)
+		authz.SetClaims(c, claims)
+		c.Request = c.Request.WithContext(client.WithAccessToken(c.Request.Context(), token))
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware validates the JWT token like AuthMiddleware when one is
+// sent and lets requests without an Authorization header through as guests
+func OptionalAuthMiddleware(verifier TokenVerifier) gin.HandlerFunc {
+	auth := AuthMiddleware(verifier)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		auth(c)
+	}
+}
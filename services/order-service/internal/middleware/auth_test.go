@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+)
+
+// fakeVerifier accepts the tokens in its map
+type fakeVerifier map[string]*token.Claims
+
+func (v fakeVerifier) Verify(ctx context.Context, accessToken string) (*token.Claims, error) {
+	claims, ok := v[accessToken]
+	if !ok {
+		return nil, errors.New("token rejected")
+	}
+	return claims, nil
+}
+
+var verifier = fakeVerifier{
+	"customer": {UserID: 1, Role: RoleCustomer},
+	"admin":    {UserID: 2, Role: RoleAdmin, Permissions: []string{"order:read", "order:write"}},
+}
+
+// serve runs a request with the Authorization header through handlers and
+// returns the response status
+func serve(authorization string, handlers ...gin.HandlerFunc) int {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handlers = append(handlers, func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/", handlers...)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		authorization string
+		want          int
+	}{
+		{"", http.StatusUnauthorized},
+		{"customer", http.StatusUnauthorized},
+		{"Bearer unknown", http.StatusUnauthorized},
+		{"Bearer customer", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		if got := serve(tt.authorization, AuthMiddleware(verifier)); got != tt.want {
+			t.Errorf("Authorization %q: status = %d, want %d", tt.authorization, got, tt.want)
+		}
+	}
+}
+
+func TestAuthMiddlewareSetsCaller(t *testing.T) {
+	var userID uint
+	var role string
+	serve("Bearer admin", AuthMiddleware(verifier), func(c *gin.Context) {
+		userID = c.GetUint(ContextUserID)
+		role = c.GetString(ContextRole)
+	})
+
+	if userID != 2 || role != RoleAdmin {
+		t.Errorf("caller = %d %q, want 2 %q", userID, role, RoleAdmin)
+	}
+}
+
+func TestOptionalAuthMiddleware(t *testing.T) {
+	if got := serve("", OptionalAuthMiddleware(verifier)); got != http.StatusOK {
+		t.Errorf("guest: status = %d, want %d", got, http.StatusOK)
+	}
+	if got := serve("Bearer unknown", OptionalAuthMiddleware(verifier)); got != http.StatusUnauthorized {
+		t.Errorf("invalid token: status = %d, want %d", got, http.StatusUnauthorized)
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		token       string
+		permissions []string
+		want        int
+	}{
+		{"customer", []string{"order:read"}, http.StatusForbidden},
+		{"admin", []string{"order:read"}, http.StatusOK},
+		{"admin", []string{"order:read", "order:write"}, http.StatusOK},
+		{"admin", []string{"order:read", "outbox:write"}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		got := serve("Bearer "+tt.token, AuthMiddleware(verifier), authz.RequirePermission(tt.permissions...))
+		if got != tt.want {
+			t.Errorf("%s with %v: status = %d, want %d", tt.token, tt.permissions, got, tt.want)
+		}
+	}
+}
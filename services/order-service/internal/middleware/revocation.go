@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+)
+
+// RedisRevocationChecker reads the token denylist that user-service keeps in
+// Redis, so that tokens revoked by logout are rejected before they expire
+type RedisRevocationChecker struct {
+	client *redis.Client
+}
+
+// NewRedisRevocationChecker creates a revocation checker on the Redis DB of
+// the denylist
+func NewRedisRevocationChecker(client *redis.Client) *RedisRevocationChecker {
+	return &RedisRevocationChecker{client: client}
+}
+
+// IsRevoked implements token.RevocationChecker
+func (r *RedisRevocationChecker) IsRevoked(ctx context.Context, tokenID, sessionID string) (bool, error) {
+	n, err := r.client.Exists(ctx, token.DenylistKeys(tokenID, sessionID)...).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import "github.com/ploezy/ecommerce-platform/pkg/money"
+
+// AddCartItemRequest represents the request to add a product to the cart
+type AddCartItemRequest struct {
+	ProductID uint `json:"product_id" binding:"required,min=1"`
+	Quantity  int  `json:"quantity" binding:"required,min=1"`
+}
+
+// UpdateCartItemRequest represents the request to change the quantity of a cart item
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+// CartResponse represents a cart priced with the current product prices
+type CartResponse struct {
+	Items     []CartItemResponse `json:"items"`
+	ItemCount int                `json:"item_count"`
+	Total     money.Money        `json:"total"`
+	// Valid is false when an item is unavailable, the cart cannot be checked out then
+	Valid bool `json:"valid"`
+}
+
+// CartItemResponse represents an item in the cart response
+type CartItemResponse struct {
+	ProductID uint        `json:"product_id"`
+	Name      string      `json:"name"`
+	Price     money.Money `json:"price"`
+	Quantity  int         `json:"quantity"`
+	Subtotal  money.Money `json:"subtotal"`
+	Available bool        `json:"available"`
+	Message   string      `json:"message,omitempty"`
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/ploezy/ecommerce-platform/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -13,75 +14,39 @@ const (
 	OrderStatusShipped    = "shipped"
 	OrderStatusDelivered  = "delivered"
 	OrderStatusCancelled  = "cancelled"
+
+	OrderStatusPartiallyShipped = "partially_shipped"
+
+	OrderStatusReturnRequested = "return_requested"
+	OrderStatusReturned        = "returned"
+	OrderStatusRefunded        = "refunded"
 )
 
-// Order represents an order in the system
+// Order represents an order in the system. Total is Subtotal - Discount +
+// ShippingFee + the part of Tax that is not already in TaxIncluded.
+// StockPending is set while the create order saga has reserved the stock of
+// the order but not committed it yet.
 type Order struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	UserID      uint           `gorm:"not null;index" json:"user_id"`
-	TotalAmount float64        `gorm:"type:decimal(10,2);not null" json:"total_amount"`
-	Status      string         `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
-	Items       []OrderItem    `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uint            `gorm:"primaryKey" json:"id"`
+	UserID          uint            `gorm:"not null;index" json:"user_id"`
+	Subtotal        money.Money     `gorm:"embedded;embeddedPrefix:subtotal_" json:"subtotal"`
+	Discount        money.Money     `gorm:"embedded;embeddedPrefix:discount_" json:"discount"`
+	ShippingFee     money.Money     `gorm:"embedded;embeddedPrefix:shipping_fee_" json:"shipping_fee"`
+	Tax             money.Money     `gorm:"embedded;embeddedPrefix:tax_" json:"tax"`
+	TaxIncluded     money.Money     `gorm:"embedded;embeddedPrefix:tax_included_" json:"tax_included"`
+	Total           money.Money     `gorm:"embedded;embeddedPrefix:total_" json:"total"`
+	ShippingAddress ShippingAddress `gorm:"embedded;embeddedPrefix:shipping_" json:"shipping_address"`
+	Status          string          `gorm:"type:varchar(20);not null;default:'pending';index:idx_orders_status_created_at,priority:1" json:"status"`
+	PaymentIntentID string          `gorm:"type:varchar(36)" json:"payment_intent_id,omitempty"`
+	StockPending    bool            `gorm:"not null;default:false" json:"-"`
+	Items           []OrderItem     `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	Discounts       []OrderDiscount `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"discounts,omitempty"`
+	CreatedAt       time.Time       `gorm:"index:idx_orders_status_created_at,priority:2" json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt  `gorm:"index" json:"-"`
 }
+
 // TableName specifies the table name for Order model
 func (Order) TableName() string {
 	return "orders"
 }
-
-// IsValidStatus checks if the status is valid
-func IsValidStatus(status string) bool {
-	validStatuses := []string{
-		OrderStatusPending,
-		OrderStatusProcessing,
-		OrderStatusShipped,
-		OrderStatusDelivered,
-		OrderStatusCancelled,
-	}
-
-	for _, s := range validStatuses {
-		if s == status {
-			return true
-		}
-	}
-	return false
-}
-
-// CanTransitionTo checks if status can transition to newStatus
-func (o *Order) CanTransitionTo(newStatus string) bool {
-	// Define valid transitions
-	validTransitions := map[string][]string{
-		OrderStatusPending: {
-			OrderStatusProcessing,
-			OrderStatusCancelled,
-		},
-		OrderStatusProcessing: {
-			OrderStatusShipped,
-			OrderStatusCancelled,
-		},
-		OrderStatusShipped: {
-			OrderStatusDelivered,
-		},
-		OrderStatusDelivered: {},
-		OrderStatusCancelled: {},
-	}
-
-	allowedStatuses, exists := validTransitions[o.Status]
-	if !exists {
-		return false
-	}
-
-	for _, status := range allowedStatuses {
-		if status == newStatus {
-			return true
-		}
-	}
-	return false
-}
-
-// CanBeCancelled checks if order can be cancelled
-func (o *Order) CanBeCancelled() bool {
-	return o.Status == OrderStatusPending
-}
\ No newline at end of file
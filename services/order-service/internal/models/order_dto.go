@@ -1,8 +1,22 @@
 package models
 
-// CreateOrderRequest represents the request to create an order
+import (
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+// CreateOrderRequest represents the request to create an order. The order ships
+// to ShippingAddressID from the address book, to ShippingAddress, or when both
+// are empty to the default address of the user. ShippingCountry and
+// ShippingRegion only estimate the shipping fee and tax of quotes without an address.
 type CreateOrderRequest struct {
-	Items []CreateOrderItemRequest `json:"items" binding:"required,min=1"`
+	Items             []CreateOrderItemRequest `json:"items" binding:"required,min=1"`
+	CouponCode        string                   `json:"coupon_code,omitempty" example:"SAVE10"`
+	ShippingAddressID uint                     `json:"shipping_address_id,omitempty" example:"1"`
+	ShippingAddress   *ShippingAddressRequest  `json:"shipping_address,omitempty"`
+	ShippingCountry   string                   `json:"shipping_country,omitempty" binding:"omitempty,len=2" example:"TH"`
+	ShippingRegion    string                   `json:"shipping_region,omitempty" binding:"omitempty,max=6" example:"TH-10"`
 }
 
 // CreateOrderItemRequest represents an item in the create order request
@@ -23,22 +37,32 @@ type CancelOrderRequest struct {
 
 // OrderResponse represents the response for an order
 type OrderResponse struct {
-	ID          uint                `json:"id"`
-	UserID      uint                `json:"user_id"`
-	TotalAmount float64             `json:"total_amount"`
-	Status      string              `json:"status"`
-	Items       []OrderItemResponse `json:"items"`
-	CreatedAt   string              `json:"created_at"`
-	UpdatedAt   string              `json:"updated_at"`
+	ID              uint                `json:"id"`
+	UserID          uint                `json:"user_id"`
+	Subtotal        money.Money         `json:"subtotal"`
+	Discount        money.Money         `json:"discount"`
+	ShippingFee     money.Money         `json:"shipping_fee"`
+	Tax             money.Money         `json:"tax"`
+	TaxIncluded     money.Money         `json:"tax_included"`
+	Total           money.Money         `json:"total"`
+	ShippingAddress ShippingAddress     `json:"shipping_address"`
+	Status          string              `json:"status"`
+	Items           []OrderItemResponse `json:"items"`
+	CreatedAt       string              `json:"created_at"`
+	UpdatedAt       string              `json:"updated_at"`
 }
 
 // OrderItemResponse represents an item in the order response
 type OrderItemResponse struct {
-	ID        uint    `json:"id"`
-	ProductID uint    `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
-	Subtotal  float64 `json:"subtotal"`
+	ID           uint        `json:"id"`
+	ProductID    uint        `json:"product_id"`
+	Quantity     int         `json:"quantity"`
+	Price        money.Money `json:"price"`
+	Subtotal     money.Money `json:"subtotal"`
+	Discount     money.Money `json:"discount"`
+	Tax          money.Money `json:"tax"`
+	TaxRate      int64       `json:"tax_rate_bps"`
+	TaxInclusive bool        `json:"tax_inclusive"`
 }
 
 // PaginationQuery represents pagination parameters
@@ -53,4 +77,93 @@ type PaginatedOrdersResponse struct {
 	Total  int64           `json:"total"`
 	Page   int             `json:"page"`
 	Limit  int             `json:"limit"`
-}
\ No newline at end of file
+}
+// CreateReturnRequest represents the request to return items of a delivered order
+type CreateReturnRequest struct {
+	Items  []CreateReturnItemRequest `json:"items" binding:"required,min=1,dive"`
+	Reason string                    `json:"reason" binding:"required"`
+}
+
+// CreateReturnItemRequest represents an item in the create return request
+type CreateReturnItemRequest struct {
+	OrderItemID uint `json:"order_item_id" binding:"required,min=1"`
+	Quantity    int  `json:"quantity" binding:"required,min=1"`
+}
+
+// ReviewReturnRequest represents an admin decision on a return request
+type ReviewReturnRequest struct {
+	Note string `json:"note"`
+}
+
+// RefundReturnRequest represents the request to refund a received return.
+// Amount is in minor units and defaults to the full value of the returned items.
+type RefundReturnRequest struct {
+	Amount *money.Money `json:"amount"`
+}
+
+// CreateShipmentRequest represents the request to hand some items of an order
+// to a carrier
+type CreateShipmentRequest struct {
+	Carrier        string                      `json:"carrier" binding:"required,max=50" example:"kerry"`
+	TrackingNumber string                      `json:"tracking_number" binding:"required,max=100" example:"KEX123456789TH"`
+	Items          []CreateShipmentItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// CreateShipmentItemRequest represents an item in the create shipment request
+type CreateShipmentItemRequest struct {
+	OrderItemID uint `json:"order_item_id" binding:"required,min=1"`
+	Quantity    int  `json:"quantity" binding:"required,min=1"`
+}
+
+// ShipmentTrackingRequest represents a tracking update pushed by a carrier.
+// The shipment is looked up by carrier and tracking number.
+type ShipmentTrackingRequest struct {
+	Carrier        string    `json:"carrier" binding:"required" example:"kerry"`
+	TrackingNumber string    `json:"tracking_number" binding:"required" example:"KEX123456789TH"`
+	Status         string    `json:"status" binding:"required,oneof=in_transit out_for_delivery delivered exception" example:"in_transit"`
+	Description    string    `json:"description" example:"Arrived at sorting facility"`
+	Location       string    `json:"location" binding:"max=255" example:"Bangkok"`
+	OccurredAt     time.Time `json:"occurred_at" binding:"required"`
+}
+
+// AdminOrderQuery represents the filters, sorting and page of the admin order
+// list. Status may be repeated or comma separated. Dates are RFC 3339, amounts
+// are total amounts in minor units of Currency, which defaults to THB when an
+// amount is given. Sort is created_at or total, prefixed with - for descending.
+type AdminOrderQuery struct {
+	Status      []string   `form:"status" example:"processing,partially_shipped"`
+	UserID      uint       `form:"user_id" example:"1"`
+	ProductID   uint       `form:"product_id" example:"1"`
+	Search      string     `form:"q" example:"Somchai"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	MinTotal    *int64     `form:"min_total" binding:"omitempty,min=0" example:"100000"`
+	MaxTotal    *int64     `form:"max_total" binding:"omitempty,min=0" example:"500000"`
+	Currency    string     `form:"currency" binding:"omitempty,len=3" example:"THB"`
+	Sort        string     `form:"sort" binding:"omitempty,oneof=created_at -created_at total -total" example:"-created_at"`
+	Cursor      string     `form:"cursor"`
+	Limit       int        `form:"limit" example:"20"`
+}
+
+// OrderPage is a page of the admin order list. Pass NextCursor as cursor to
+// get the next page; it is empty on the last page.
+type OrderPage struct {
+	Orders     []Order `json:"orders"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	HasMore    bool    `json:"has_more"`
+}
+
+// BulkOrderStatusRequest represents the request to move several orders to one status
+type BulkOrderStatusRequest struct {
+	OrderIDs []uint `json:"order_ids" binding:"required,min=1,max=100,dive,min=1"`
+	Status   string `json:"status" binding:"required" example:"cancelled"`
+	Reason   string `json:"reason" example:"Supplier out of stock"`
+}
+
+// BulkOrderStatusResult is the outcome of a bulk status change for one order
+type BulkOrderStatusResult struct {
+	OrderID uint   `json:"order_id"`
+	Success bool   `json:"success"`
+	Status  string `json:"status,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
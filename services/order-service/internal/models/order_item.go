@@ -3,20 +3,29 @@ package models
 import (
 	"time"
 
+	"github.com/ploezy/ecommerce-platform/pkg/money"
 	"gorm.io/gorm"
 )
 
 // OrderItem represents an item in an order
 type OrderItem struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	OrderID   uint           `gorm:"not null;index" json:"order_id"`
-	ProductID uint           `gorm:"not null;index" json:"product_id"`
-	Quantity  int            `gorm:"not null" json:"quantity"`
-	Price     float64        `gorm:"type:decimal(10,2);not null" json:"price"`
-	Subtotal  float64        `gorm:"type:decimal(10,2);not null" json:"subtotal"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	OrderID   uint        `gorm:"not null;index" json:"order_id"`
+	ProductID uint        `gorm:"not null;index" json:"product_id"`
+	Quantity  int         `gorm:"not null" json:"quantity"`
+	Price     money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+	Subtotal  money.Money `gorm:"embedded;embeddedPrefix:subtotal_" json:"subtotal"`
+	Discount  money.Money `gorm:"embedded;embeddedPrefix:discount_" json:"discount"`
+	// Tax is the tax of the discounted subtotal at TaxRate, in basis points.
+	// Inclusive tax is part of the price, exclusive tax is paid on top.
+	Tax          money.Money `gorm:"embedded;embeddedPrefix:tax_" json:"tax"`
+	TaxRate      int64       `gorm:"not null;default:0" json:"tax_rate_bps"`
+	TaxInclusive bool        `gorm:"not null;default:true" json:"tax_inclusive"`
+	// Adjustments are the discounts taken off this item, they add up to Discount
+	Adjustments []OrderItemAdjustment `gorm:"foreignKey:OrderItemID;constraint:OnDelete:CASCADE" json:"adjustments,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt        `gorm:"index" json:"-"`
 }
 
 // TableName specifies the table name for OrderItem model
@@ -24,7 +33,21 @@ func (OrderItem) TableName() string {
 	return "order_items"
 }
 
-// CalculateSubtotal calculates the subtotal (price * quantity)
+// CalculateSubtotal calculates the subtotal (price * quantity). It is exact, no rounding.
 func (oi *OrderItem) CalculateSubtotal() {
-	oi.Subtotal = oi.Price * float64(oi.Quantity)
-}
\ No newline at end of file
+	oi.Subtotal = oi.Price.Mul(int64(oi.Quantity))
+}
+
+// NetUnitPrice is the price paid for one unit after discounts and with tax that
+// is not included in the price, rounded down so that refunds never exceed what
+// was paid
+func (oi *OrderItem) NetUnitPrice() money.Money {
+	net, err := oi.Subtotal.Sub(oi.Discount)
+	if err != nil || oi.Quantity <= 0 {
+		return oi.Price
+	}
+	if !oi.TaxInclusive && oi.Tax.SameCurrency(net) {
+		net.Amount += oi.Tax.Amount
+	}
+	return net.MulRate(1, int64(oi.Quantity), money.RoundDown)
+}
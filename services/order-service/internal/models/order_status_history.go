@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Actor type constants
+const (
+	ActorTypeUser   = "user"
+	ActorTypeAdmin  = "admin"
+	ActorTypeSystem = "system"
+)
+
+// Actor is who caused an order status change
+type Actor struct {
+	Type string
+	ID   uint
+}
+
+// SystemActor is the actor of changes made by order-service itself
+var SystemActor = Actor{Type: ActorTypeSystem}
+
+// OrderStatusHistory records one status transition of an order
+type OrderStatusHistory struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	OrderID       uint      `gorm:"not null;index" json:"order_id"`
+	FromStatus    string    `gorm:"type:varchar(20)" json:"from_status"`
+	ToStatus      string    `gorm:"type:varchar(20);not null" json:"to_status"`
+	ActorType     string    `gorm:"type:varchar(20);not null" json:"actor_type"`
+	ActorID       *uint     `json:"actor_id,omitempty"`
+	Reason        string    `gorm:"type:text" json:"reason,omitempty"`
+	CorrelationID string    `gorm:"type:varchar(64)" json:"correlation_id,omitempty"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName specifies the table name for OrderStatusHistory model
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Outbox event status constants
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// OutboxEvent is an event written in the same transaction as the change it
+// describes and published to Kafka later by the outbox relay
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	AggregateType string     `gorm:"type:varchar(50);not null;index:idx_outbox_aggregate" json:"aggregate_type"`
+	AggregateID   string     `gorm:"type:varchar(50);not null;index:idx_outbox_aggregate" json:"aggregate_id"`
+	EventType     string     `gorm:"type:varchar(100);not null" json:"event_type"`
+	Topic         string     `gorm:"type:varchar(100);not null" json:"topic"`
+	Key           string     `gorm:"type:varchar(100)" json:"key"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status        string     `gorm:"type:varchar(20);not null;default:'pending';index:idx_outbox_status_next" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_status_next" json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for OutboxEvent model
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+// Promotion type constants
+const (
+	// PromotionTypePercentage takes PercentOff percent off the eligible items
+	PromotionTypePercentage = "percentage"
+	// PromotionTypeFixed takes AmountOff off the eligible items, spread by value
+	PromotionTypeFixed = "fixed"
+	// PromotionTypeBuyXGetY gives GetQuantity units free for every BuyQuantity
+	// units bought of the same eligible product
+	PromotionTypeBuyXGetY = "buy_x_get_y"
+)
+
+// Promotion is a discount rule. Promotions with a code are coupons the customer
+// has to enter, promotions without one apply automatically, e.g. a category-wide
+// sale. Category and ProductID limit the items a promotion applies to.
+type Promotion struct {
+	ID           uint        `gorm:"primaryKey" json:"id"`
+	Code         string      `gorm:"type:varchar(50);index:idx_promotions_code,unique,where:code <> ''" json:"code,omitempty"`
+	Name         string      `gorm:"type:varchar(100);not null" json:"name"`
+	Type         string      `gorm:"type:varchar(20);not null" json:"type"`
+	PercentOff   int         `gorm:"not null;default:0" json:"percent_off,omitempty"`
+	AmountOff    money.Money `gorm:"embedded;embeddedPrefix:amount_off_" json:"amount_off"`
+	BuyQuantity  int         `gorm:"not null;default:0" json:"buy_quantity,omitempty"`
+	GetQuantity  int         `gorm:"not null;default:0" json:"get_quantity,omitempty"`
+	Category     string      `gorm:"type:varchar(100)" json:"category,omitempty"`
+	ProductID    uint        `gorm:"not null;default:0" json:"product_id,omitempty"`
+	MinSpend     money.Money `gorm:"embedded;embeddedPrefix:min_spend_" json:"min_spend"`
+	UsageLimit   int         `gorm:"not null;default:0" json:"usage_limit"`
+	PerUserLimit int         `gorm:"not null;default:0" json:"per_user_limit"`
+	StartsAt     *time.Time  `json:"starts_at,omitempty"`
+	EndsAt       *time.Time  `json:"ends_at,omitempty"`
+	Active       bool        `gorm:"not null;default:true;index" json:"active"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// TableName specifies the table name for Promotion model
+func (Promotion) TableName() string {
+	return "promotions"
+}
+
+// IsCoupon reports whether the promotion needs a code
+func (p *Promotion) IsCoupon() bool {
+	return p.Code != ""
+}
+
+// OrderDiscount is a promotion applied to an order. It also counts as one use
+// of the promotion while the order is not cancelled.
+type OrderDiscount struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	OrderID     uint        `gorm:"not null;uniqueIndex:idx_order_discounts_order_promotion" json:"order_id"`
+	PromotionID uint        `gorm:"not null;uniqueIndex:idx_order_discounts_order_promotion;index" json:"promotion_id"`
+	Code        string      `gorm:"type:varchar(50)" json:"code,omitempty"`
+	Name        string      `gorm:"type:varchar(100);not null" json:"name"`
+	Amount      money.Money `gorm:"embedded;embeddedPrefix:discount_" json:"amount"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// TableName specifies the table name for OrderDiscount model
+func (OrderDiscount) TableName() string {
+	return "order_discounts"
+}
+
+// OrderItemAdjustment is the part of an order discount taken off one order item
+type OrderItemAdjustment struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	OrderItemID uint        `gorm:"not null;index" json:"order_item_id"`
+	PromotionID uint        `gorm:"not null" json:"promotion_id"`
+	Amount      money.Money `gorm:"embedded;embeddedPrefix:discount_" json:"amount"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// TableName specifies the table name for OrderItemAdjustment model
+func (OrderItemAdjustment) TableName() string {
+	return "order_item_adjustments"
+}
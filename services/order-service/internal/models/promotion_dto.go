@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+// PromotionRequest represents the request to create or replace a promotion.
+// Leave Code empty for a promotion that applies automatically.
+type PromotionRequest struct {
+	Code         string       `json:"code" example:"SAVE10"`
+	Name         string       `json:"name" binding:"required" example:"10% off everything"`
+	Type         string       `json:"type" binding:"required,oneof=percentage fixed buy_x_get_y" example:"percentage"`
+	PercentOff   int          `json:"percent_off" example:"10"`
+	AmountOff    *money.Money `json:"amount_off"`
+	BuyQuantity  int          `json:"buy_quantity"`
+	GetQuantity  int          `json:"get_quantity"`
+	Category     string       `json:"category" example:"Electronics"`
+	ProductID    uint         `json:"product_id"`
+	MinSpend     *money.Money `json:"min_spend"`
+	UsageLimit   int          `json:"usage_limit" binding:"min=0"`
+	PerUserLimit int          `json:"per_user_limit" binding:"min=0"`
+	StartsAt     *time.Time   `json:"starts_at"`
+	EndsAt       *time.Time   `json:"ends_at"`
+	// Active defaults to true
+	Active *bool `json:"active"`
+}
+
+// CheckoutRequest represents the optional body of a cart checkout. Without a
+// shipping address the order ships to the default address of the user.
+type CheckoutRequest struct {
+	CouponCode        string                  `json:"coupon_code,omitempty" example:"SAVE10"`
+	ShippingAddressID uint                    `json:"shipping_address_id,omitempty" example:"1"`
+	ShippingAddress   *ShippingAddressRequest `json:"shipping_address,omitempty"`
+}
+
+// OrderQuote is a basket priced like an order would be, without creating one
+type OrderQuote struct {
+	Items           []OrderQuoteItem     `json:"items"`
+	Discounts       []OrderQuoteDiscount `json:"discounts"`
+	Subtotal        money.Money          `json:"subtotal"`
+	Discount        money.Money          `json:"discount"`
+	ShippingFee     money.Money          `json:"shipping_fee"`
+	Tax             money.Money          `json:"tax"`
+	TaxIncluded     money.Money          `json:"tax_included"`
+	Total           money.Money          `json:"total"`
+	CouponCode      string               `json:"coupon_code,omitempty"`
+	ShippingAddress *ShippingAddress     `json:"shipping_address,omitempty"`
+	ShippingCountry string               `json:"shipping_country"`
+	ShippingRegion  string               `json:"shipping_region,omitempty"`
+	ShippingZone    string               `json:"shipping_zone"`
+	WeightGrams     int                  `json:"weight_grams"`
+}
+
+// OrderQuoteItem represents a priced line of an order quote
+type OrderQuoteItem struct {
+	ProductID uint        `json:"product_id"`
+	Name      string      `json:"name"`
+	Quantity  int         `json:"quantity"`
+	Price     money.Money `json:"price"`
+	Subtotal  money.Money `json:"subtotal"`
+	Discount  money.Money `json:"discount"`
+	Tax       money.Money `json:"tax"`
+	TaxRate   int64       `json:"tax_rate_bps"`
+	Total     money.Money `json:"total"`
+}
+
+// OrderQuoteDiscount represents a promotion applied in an order quote
+type OrderQuoteDiscount struct {
+	PromotionID uint        `json:"promotion_id"`
+	Code        string      `json:"code,omitempty"`
+	Name        string      `json:"name"`
+	Amount      money.Money `json:"amount"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+// Return request status constants
+const (
+	ReturnStatusRequested = "requested"
+	ReturnStatusApproved  = "approved"
+	ReturnStatusRejected  = "rejected"
+	ReturnStatusReceived  = "received"
+	ReturnStatusRefunded  = "refunded"
+)
+
+// ReturnRequest is a customer request to send back items of a delivered order
+type ReturnRequest struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	OrderID      uint         `gorm:"not null;index" json:"order_id"`
+	UserID       uint         `gorm:"not null;index" json:"user_id"`
+	Status       string       `gorm:"type:varchar(20);not null;default:'requested'" json:"status"`
+	Reason       string       `gorm:"type:text;not null" json:"reason"`
+	AdminNote    string       `gorm:"type:text" json:"admin_note,omitempty"`
+	Refund       money.Money  `gorm:"embedded;embeddedPrefix:refund_" json:"refund"`
+	ReviewedBy   *uint        `json:"reviewed_by,omitempty"`
+	Items        []ReturnItem `gorm:"foreignKey:ReturnID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	ApprovedAt   *time.Time   `json:"approved_at,omitempty"`
+	RejectedAt   *time.Time   `json:"rejected_at,omitempty"`
+	ReceivedAt   *time.Time   `json:"received_at,omitempty"`
+	RefundedAt   *time.Time   `json:"refunded_at,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// TableName specifies the table name for ReturnRequest model
+func (ReturnRequest) TableName() string {
+	return "return_requests"
+}
+
+// ReturnItem is a quantity of one order item in a return request
+type ReturnItem struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	ReturnID    uint        `gorm:"not null;index" json:"return_id"`
+	OrderItemID uint        `gorm:"not null;index" json:"order_item_id"`
+	ProductID   uint        `gorm:"not null" json:"product_id"`
+	Quantity    int         `gorm:"not null" json:"quantity"`
+	Price       money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+}
+
+// TableName specifies the table name for ReturnItem model
+func (ReturnItem) TableName() string {
+	return "return_items"
+}
+
+// Value returns the amount paid for the returned items. Items of a return come
+// from one order and share its currency.
+func (r *ReturnRequest) Value() money.Money {
+	if len(r.Items) == 0 {
+		return money.Zero(money.DefaultCurrency)
+	}
+
+	total := money.Zero(r.Items[0].Price.Currency)
+	for _, item := range r.Items {
+		total.Amount += item.Price.Mul(int64(item.Quantity)).Amount
+	}
+	return total
+}
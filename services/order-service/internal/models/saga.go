@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Saga status constants
+const (
+	SagaStatusRunning      = "running"
+	SagaStatusCompensating = "compensating"
+	SagaStatusCompleted    = "completed"
+	SagaStatusCompensated  = "compensated"
+)
+
+// Saga stores the progress of a long running, multi-step operation
+type Saga struct {
+	ID          string    `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	Type        string    `gorm:"type:varchar(50);not null;index" json:"type"`
+	Status      string    `gorm:"type:varchar(20);not null;index" json:"status"`
+	CurrentStep int       `gorm:"not null;default:0" json:"current_step"` // index of the next step to execute or compensate
+	Payload     string    `gorm:"type:jsonb;not null" json:"payload"`
+	LastError   string    `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Saga model
+func (Saga) TableName() string {
+	return "sagas"
+}
+
+// IsFinished checks if the saga no longer needs the orchestrator
+func (s *Saga) IsFinished() bool {
+	return s.Status == SagaStatusCompleted || s.Status == SagaStatusCompensated
+}
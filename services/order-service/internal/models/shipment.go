@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Shipment status constants. Shipments are created as shipped; the other
+// statuses come from carrier tracking updates.
+const (
+	ShipmentStatusShipped        = "shipped"
+	ShipmentStatusInTransit      = "in_transit"
+	ShipmentStatusOutForDelivery = "out_for_delivery"
+	ShipmentStatusDelivered      = "delivered"
+	ShipmentStatusException      = "exception"
+)
+
+// Shipment is a parcel handed to a carrier with some of the items of an order.
+// An order is fulfilled once its shipments hold every order item.
+type Shipment struct {
+	ID             uint                    `gorm:"primaryKey" json:"id"`
+	OrderID        uint                    `gorm:"not null;index" json:"order_id"`
+	Status         string                  `gorm:"type:varchar(20);not null;default:'shipped'" json:"status"`
+	Carrier        string                  `gorm:"type:varchar(50);not null;uniqueIndex:idx_shipments_tracking,priority:1" json:"carrier"`
+	TrackingNumber string                  `gorm:"type:varchar(100);not null;uniqueIndex:idx_shipments_tracking,priority:2" json:"tracking_number"`
+	Items          []ShipmentItem          `gorm:"foreignKey:ShipmentID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	TrackingEvents []ShipmentTrackingEvent `gorm:"foreignKey:ShipmentID;constraint:OnDelete:CASCADE" json:"tracking_events,omitempty"`
+	ShippedAt      *time.Time              `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time              `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// TableName specifies the table name for Shipment model
+func (Shipment) TableName() string {
+	return "shipments"
+}
+
+// ShipmentItem is a quantity of one order item in a shipment
+type ShipmentItem struct {
+	ID          uint `gorm:"primaryKey" json:"id"`
+	ShipmentID  uint `gorm:"not null;index" json:"shipment_id"`
+	OrderItemID uint `gorm:"not null;index" json:"order_item_id"`
+	ProductID   uint `gorm:"not null" json:"product_id"`
+	Quantity    int  `gorm:"not null" json:"quantity"`
+}
+
+// TableName specifies the table name for ShipmentItem model
+func (ShipmentItem) TableName() string {
+	return "shipment_items"
+}
+
+// ShipmentTrackingEvent is a status update reported by the carrier. Carriers
+// resend updates, so an update is only stored once per status and time.
+type ShipmentTrackingEvent struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ShipmentID  uint      `gorm:"not null;uniqueIndex:idx_shipment_tracking_events_dedup,priority:1" json:"shipment_id"`
+	Status      string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_shipment_tracking_events_dedup,priority:2" json:"status"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	Location    string    `gorm:"type:varchar(255)" json:"location,omitempty"`
+	OccurredAt  time.Time `gorm:"not null;uniqueIndex:idx_shipment_tracking_events_dedup,priority:3" json:"occurred_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for ShipmentTrackingEvent model
+func (ShipmentTrackingEvent) TableName() string {
+	return "shipment_tracking_events"
+}
+
+// IsDelivered reports whether the carrier delivered the shipment
+func (s *Shipment) IsDelivered() bool {
+	return s.Status == ShipmentStatusDelivered
+}
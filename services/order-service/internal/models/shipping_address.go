@@ -0,0 +1,31 @@
+package models
+
+// ShippingAddress is a copy of the address an order ships to, taken when the
+// order is created so that later edits of the address book do not change it
+type ShippingAddress struct {
+	// AddressID is the address book entry the copy was taken from, 0 for an address entered at checkout
+	AddressID     uint   `json:"address_id,omitempty"`
+	RecipientName string `gorm:"type:varchar(100)" json:"recipient_name"`
+	Phone         string `gorm:"type:varchar(20)" json:"phone"`
+	Line1         string `gorm:"type:varchar(255)" json:"line1"`
+	Line2         string `gorm:"type:varchar(255)" json:"line2,omitempty"`
+	District      string `gorm:"type:varchar(100)" json:"district,omitempty"`
+	City          string `gorm:"type:varchar(100)" json:"city"`
+	Region        string `gorm:"type:varchar(6)" json:"region,omitempty"`
+	PostalCode    string `gorm:"type:varchar(10)" json:"postal_code"`
+	Country       string `gorm:"type:varchar(2)" json:"country"`
+}
+
+// ShippingAddressRequest represents an address entered at checkout instead of
+// one from the address book
+type ShippingAddressRequest struct {
+	RecipientName string `json:"recipient_name" binding:"required,max=100" example:"Somchai Jaidee"`
+	Phone         string `json:"phone" binding:"required,max=20" example:"+66812345678"`
+	Line1         string `json:"line1" binding:"required,max=255" example:"99/1 Sukhumvit Road"`
+	Line2         string `json:"line2" binding:"max=255"`
+	District      string `json:"district" binding:"max=100" example:"Khlong Toei"`
+	City          string `json:"city" binding:"required,max=100" example:"Bangkok"`
+	Region        string `json:"region" binding:"max=6" example:"TH-10"`
+	PostalCode    string `json:"postal_code" binding:"required,max=10" example:"10110"`
+	Country       string `json:"country" binding:"required,len=2" example:"TH"`
+}
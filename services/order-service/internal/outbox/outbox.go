@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+	"gorm.io/gorm"
+)
+
+// Aggregate types of outbox events
+const (
+	// AggregateOrder is the aggregate type of events about orders
+	AggregateOrder = "order"
+	// AggregateRestock is the aggregate type of restock commands, keyed by
+	// their reference so a failing restock does not hold back order events
+	AggregateRestock = "restock"
+	// AggregateRefund is the aggregate type of refund commands, keyed by
+	// their idempotency key
+	AggregateRefund = "refund"
+)
+
+// Write stores an event in the outbox inside tx, so it is only published when
+// tx commits. The aggregate ID is used as the Kafka message key.
+func Write(ctx context.Context, tx *gorm.DB, repo repository.OutboxRepository, aggregateType, aggregateID, topic string, envelope kafka.Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	event := &models.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     envelope.EventType,
+		Topic:         topic,
+		Key:           aggregateID,
+		Payload:       string(data),
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+
+	if err := repo.WithTx(tx).Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// RelayConfig controls how often the relay polls and how it retries
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+// Publisher publishes a message to a Kafka topic, kafka.Producer implements it
+type Publisher interface {
+	PublishEvent(topic string, key string, data interface{}) error
+}
+
+// CommandHandler carries out a command stored in the outbox, such as a call to
+// another service. The relay retries it like a publish until it succeeds, so
+// it must be idempotent.
+type CommandHandler func(ctx context.Context, payload json.RawMessage) error
+
+// Relay publishes pending outbox events to Kafka and marks them sent
+type Relay struct {
+	db       *gorm.DB
+	repo     repository.OutboxRepository
+	producer Publisher
+	handlers map[string]CommandHandler
+	cfg      RelayConfig
+}
+
+// NewRelay creates a new outbox relay
+func NewRelay(db *gorm.DB, repo repository.OutboxRepository, producer Publisher, cfg RelayConfig) *Relay {
+	return &Relay{
+		db:       db,
+		repo:     repo,
+		producer: producer,
+		handlers: make(map[string]CommandHandler),
+		cfg:      cfg,
+	}
+}
+
+// Handle makes the relay pass events of topic to handler instead of Kafka
+func (r *Relay) Handle(topic string, handler CommandHandler) {
+	r.handlers[topic] = handler
+}
+
+// Start polls the outbox until ctx is cancelled
+func (r *Relay) Start(ctx context.Context) {
+	log.Printf("Outbox relay started (interval: %v, batch: %d)", r.cfg.PollInterval, r.cfg.BatchSize)
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			for {
+				processed, err := r.publishBatch(ctx)
+				if err != nil {
+					log.Printf("Outbox relay failed: %v", err)
+					break
+				}
+				if processed < r.cfg.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// publishBatch publishes one batch of due events and returns how many were processed
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	processed := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := r.repo.WithTx(tx)
+
+		events, err := repo.LockDue(ctx, time.Now(), r.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		// Once an event of an aggregate fails, later events of it wait for the retry
+		blocked := make(map[string]bool)
+
+		for i := range events {
+			event := &events[i]
+			aggregate := event.AggregateType + ":" + event.AggregateID
+			if blocked[aggregate] {
+				continue
+			}
+
+			if err := r.deliver(ctx, event); err != nil {
+				blocked[aggregate] = true
+				r.scheduleRetry(event, err)
+			} else {
+				now := time.Now()
+				event.Status = models.OutboxStatusSent
+				event.SentAt = &now
+				event.LastError = ""
+			}
+
+			if err := repo.Save(ctx, event); err != nil {
+				return err
+			}
+			processed++
+		}
+		return nil
+	})
+	return processed, err
+}
+
+// deliver runs the command handler of the event topic, or publishes the event
+// to Kafka when the topic has none
+func (r *Relay) deliver(ctx context.Context, event *models.OutboxEvent) error {
+	if handler, ok := r.handlers[event.Topic]; ok {
+		return handler(ctx, json.RawMessage(event.Payload))
+	}
+	return r.producer.PublishEvent(event.Topic, event.Key, json.RawMessage(event.Payload))
+}
+
+// scheduleRetry records a failed attempt and backs off exponentially. After
+// MaxAttempts the event is marked failed and waits for a manual replay.
+func (r *Relay) scheduleRetry(event *models.OutboxEvent, err error) {
+	event.Attempts++
+	event.LastError = err.Error()
+
+	if event.Attempts >= r.cfg.MaxAttempts {
+		event.Status = models.OutboxStatusFailed
+		log.Printf("Outbox event %d (%s) failed after %d attempts: %v", event.ID, event.EventType, event.Attempts, err)
+		return
+	}
+
+	backoff := r.cfg.BaseBackoff << (event.Attempts - 1)
+	if backoff <= 0 || backoff > r.cfg.MaxBackoff {
+		backoff = r.cfg.MaxBackoff
+	}
+	event.NextAttemptAt = time.Now().Add(backoff)
+	log.Printf("Outbox event %d (%s) failed, retry %d in %v: %v", event.ID, event.EventType, event.Attempts, backoff, err)
+}
@@ -0,0 +1,275 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakePublisher records the published payloads and fails for the payloads in failing
+type fakePublisher struct {
+	published []string
+	failing   map[string]bool
+}
+
+func (p *fakePublisher) PublishEvent(topic string, key string, data interface{}) error {
+	payload := string(data.(json.RawMessage))
+	if p.failing[payload] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, payload)
+	return nil
+}
+
+type relayFixture struct {
+	db        *gorm.DB
+	repo      repository.OutboxRepository
+	publisher *fakePublisher
+	relay     *Relay
+}
+
+func newRelayFixture(t *testing.T, cfg RelayConfig) *relayFixture {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.OutboxEvent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &relayFixture{
+		db:        db,
+		repo:      repository.NewOutboxRepository(db),
+		publisher: &fakePublisher{failing: map[string]bool{}},
+	}
+	f.relay = NewRelay(db, f.repo, f.publisher, cfg)
+	return f
+}
+
+// add writes a due event whose payload is its name
+func (f *relayFixture) add(t *testing.T, orderID, name string) *models.OutboxEvent {
+	t.Helper()
+	event := &models.OutboxEvent{
+		AggregateType: AggregateOrder,
+		AggregateID:   orderID,
+		EventType:     name,
+		Topic:         "order-events",
+		Key:           orderID,
+		Payload:       name,
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: time.Now().Add(-time.Second),
+	}
+	if err := f.repo.Create(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func (f *relayFixture) reload(t *testing.T, event *models.OutboxEvent) *models.OutboxEvent {
+	t.Helper()
+	reloaded, err := f.repo.FindByID(context.Background(), event.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reloaded
+}
+
+// makeDue moves the retry of event to now, as if its backoff had passed
+func (f *relayFixture) makeDue(t *testing.T, event *models.OutboxEvent) {
+	t.Helper()
+	err := f.db.Model(&models.OutboxEvent{}).Where("id = ?", event.ID).
+		Update("next_attempt_at", time.Now().Add(-time.Second)).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+var testRelayConfig = RelayConfig{
+	PollInterval: time.Second,
+	BatchSize:    10,
+	MaxAttempts:  3,
+	BaseBackoff:  time.Second,
+	MaxBackoff:   3 * time.Second,
+}
+
+func TestScheduleRetryBacksOffExponentially(t *testing.T) {
+	relay := NewRelay(nil, nil, nil, testRelayConfig)
+
+	tests := []struct {
+		attempts   int
+		wantStatus string
+		wantDelay  time.Duration
+	}{
+		{attempts: 0, wantStatus: models.OutboxStatusPending, wantDelay: time.Second},
+		{attempts: 1, wantStatus: models.OutboxStatusPending, wantDelay: 2 * time.Second},
+		{attempts: 2, wantStatus: models.OutboxStatusFailed},
+	}
+	for _, tt := range tests {
+		event := &models.OutboxEvent{Status: models.OutboxStatusPending, Attempts: tt.attempts}
+		before := time.Now()
+		relay.scheduleRetry(event, errors.New("broker unavailable"))
+
+		if event.Attempts != tt.attempts+1 || event.Status != tt.wantStatus || event.LastError != "broker unavailable" {
+			t.Errorf("after attempt %d: attempts = %d, status = %s, last error = %q", tt.attempts+1, event.Attempts, event.Status, event.LastError)
+		}
+		if tt.wantDelay > 0 {
+			if delay := event.NextAttemptAt.Sub(before); delay < tt.wantDelay || delay > tt.wantDelay+time.Second {
+				t.Errorf("after attempt %d: retry in %v, want %v", tt.attempts+1, delay, tt.wantDelay)
+			}
+		}
+	}
+
+	// The backoff is capped, also once the shift overflows
+	relay = NewRelay(nil, nil, nil, RelayConfig{MaxAttempts: 100, BaseBackoff: time.Second, MaxBackoff: time.Minute})
+	for _, attempts := range []int{10, 70} {
+		event := &models.OutboxEvent{Attempts: attempts}
+		relay.scheduleRetry(event, errors.New("broker unavailable"))
+		if delay := time.Until(event.NextAttemptAt); delay <= 0 || delay > time.Minute {
+			t.Errorf("after attempt %d: retry in %v, want at most %v", attempts+1, delay, time.Minute)
+		}
+	}
+}
+
+func TestPublishBatchKeepsAggregateOrderAcrossRetries(t *testing.T) {
+	f := newRelayFixture(t, testRelayConfig)
+	ctx := context.Background()
+	created := f.add(t, "1", "created-1")
+	f.add(t, "1", "paid-1")
+	f.add(t, "2", "created-2")
+	f.publisher.failing["created-1"] = true
+
+	processed, err := f.relay.publishBatch(ctx)
+	if err != nil {
+		t.Fatalf("publishBatch() error = %v", err)
+	}
+	// paid-1 is held back behind the failed created-1, order 2 is not
+	if processed != 2 || strings.Join(f.publisher.published, ",") != "created-2" {
+		t.Fatalf("processed %d, published %v, want 2 and [created-2]", processed, f.publisher.published)
+	}
+	if event := f.reload(t, created); event.Status != models.OutboxStatusPending || event.Attempts != 1 || !event.NextAttemptAt.After(time.Now()) {
+		t.Errorf("failed event = %s after %d attempts, next at %v", event.Status, event.Attempts, event.NextAttemptAt)
+	}
+
+	// Nothing of order 1 goes out before the backoff has passed
+	if _, err := f.relay.publishBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.publisher.published) != 1 {
+		t.Fatalf("published %v during the backoff", f.publisher.published)
+	}
+
+	delete(f.publisher.failing, "created-1")
+	f.makeDue(t, created)
+	for i := 0; i < 2; i++ {
+		if _, err := f.relay.publishBatch(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := strings.Join(f.publisher.published, ","); got != "created-2,created-1,paid-1" {
+		t.Errorf("published %s, want created-2,created-1,paid-1", got)
+	}
+	if event := f.reload(t, created); event.Status != models.OutboxStatusSent || event.SentAt == nil || event.LastError != "" {
+		t.Errorf("retried event = %s, sent at %v, last error %q", event.Status, event.SentAt, event.LastError)
+	}
+}
+
+func TestFailedEventIsPublishedAfterReplay(t *testing.T) {
+	f := newRelayFixture(t, RelayConfig{BatchSize: 10, MaxAttempts: 1, BaseBackoff: time.Second, MaxBackoff: time.Second})
+	ctx := context.Background()
+	created := f.add(t, "1", "created-1")
+	f.add(t, "1", "paid-1")
+	f.publisher.failing["created-1"] = true
+
+	if _, err := f.relay.publishBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	failed := f.reload(t, created)
+	if failed.Status != models.OutboxStatusFailed {
+		t.Fatalf("status = %s, want %s after the last attempt", failed.Status, models.OutboxStatusFailed)
+	}
+
+	// A failed event stays out of the queue and holds back its aggregate
+	if processed, err := f.relay.publishBatch(ctx); err != nil || processed != 0 {
+		t.Fatalf("publishBatch() = %d, %v, want nothing to publish", processed, err)
+	}
+
+	// Replay puts the event back in the queue the way the outbox service does
+	delete(f.publisher.failing, "created-1")
+	failed.Status = models.OutboxStatusPending
+	failed.Attempts = 0
+	failed.NextAttemptAt = time.Now()
+	if err := f.repo.Save(ctx, failed); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := f.relay.publishBatch(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := strings.Join(f.publisher.published, ","); got != "created-1,paid-1" {
+		t.Errorf("published %s, want created-1,paid-1", got)
+	}
+}
+
+func TestCommandsGoToTheirHandlerAndAreRetried(t *testing.T) {
+	f := newRelayFixture(t, testRelayConfig)
+	ctx := context.Background()
+	command := f.add(t, "order:1:cancel", "restock-1")
+	command.AggregateType = AggregateRestock
+	command.Topic = "command.product.restock"
+	if err := f.repo.Save(ctx, command); err != nil {
+		t.Fatal(err)
+	}
+	f.add(t, "1", "cancelled-1")
+
+	var handled []string
+	handlerErr := errors.New("product-service unavailable")
+	f.relay.Handle("command.product.restock", func(ctx context.Context, payload json.RawMessage) error {
+		if handlerErr != nil {
+			return handlerErr
+		}
+		handled = append(handled, string(payload))
+		return nil
+	})
+
+	if _, err := f.relay.publishBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The failing command does not hold back the events of the order
+	if strings.Join(f.publisher.published, ",") != "cancelled-1" {
+		t.Fatalf("published %v, want [cancelled-1]", f.publisher.published)
+	}
+	if event := f.reload(t, command); event.Status != models.OutboxStatusPending || event.Attempts != 1 {
+		t.Fatalf("failed command = %s after %d attempts, want a pending retry", event.Status, event.Attempts)
+	}
+
+	handlerErr = nil
+	f.makeDue(t, command)
+	if _, err := f.relay.publishBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(handled, ",") != "restock-1" || len(f.publisher.published) != 1 {
+		t.Errorf("handled %v and published %v, want the command handled and never published", handled, f.publisher.published)
+	}
+	if event := f.reload(t, command); event.Status != models.OutboxStatusSent {
+		t.Errorf("command status = %s, want %s", event.Status, models.OutboxStatusSent)
+	}
+}
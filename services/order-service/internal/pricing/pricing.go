@@ -0,0 +1,334 @@
+// Package pricing works out the amounts stored on an order from a basket that
+// is already priced and discounted. It runs in fixed stages:
+//
+//  1. subtotal  sum of the line prices
+//  2. discount  sum of the line discounts from the promotion engine
+//  3. shipping  fee of the shipping zone of the destination, by parcel weight
+//  4. tax       rate of the most specific tax rule per line and for shipping
+//  5. total     subtotal - discount + shipping + tax that is not included in prices
+//
+// Tax rules and shipping zones come from a Config, by default Thai VAT of 7%
+// included in prices and two domestic shipping zones.
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+// TaxRule is a tax rate for a country, optionally limited to a region
+// (ISO 3166-2 code such as TH-10) and a product category. The most specific
+// matching rule is used; region beats category.
+type TaxRule struct {
+	Country  string `json:"country"`
+	Region   string `json:"region,omitempty"`
+	Category string `json:"category,omitempty"`
+	Name     string `json:"name"`
+	// RateBasisPoints is the rate in hundredths of a percent, 700 is 7%
+	RateBasisPoints int64 `json:"rate_bps"`
+	// Inclusive rules treat prices as already including the tax
+	Inclusive bool `json:"inclusive"`
+}
+
+// WeightRate is the shipping fee of parcels up to MaxGrams
+type WeightRate struct {
+	MaxGrams int         `json:"max_grams"`
+	Fee      money.Money `json:"fee"`
+}
+
+// ShippingZone is a set of destinations shipped at the same rates. Regions are
+// country codes (TH) or region codes (TH-10); a region match beats a country match.
+type ShippingZone struct {
+	Name    string   `json:"name"`
+	Regions []string `json:"regions"`
+	// Rates are ordered by MaxGrams, the first rate the parcel fits in is used
+	Rates []WeightRate `json:"rates"`
+}
+
+// Config holds the tax rules and shipping zones
+type Config struct {
+	// DefaultCountry is used for orders without a destination country
+	DefaultCountry string         `json:"default_country"`
+	Tax            []TaxRule      `json:"tax"`
+	Shipping       []ShippingZone `json:"shipping"`
+}
+
+// DefaultConfig returns Thai VAT of 7% included in prices, and shipping rates
+// for Bangkok and its vicinity and for the rest of Thailand
+func DefaultConfig() *Config {
+	thb := func(baht int64) money.Money { return money.New(baht*100, "THB") }
+	return &Config{
+		DefaultCountry: "TH",
+		Tax: []TaxRule{
+			{Country: "TH", Name: "VAT", RateBasisPoints: 700, Inclusive: true},
+		},
+		Shipping: []ShippingZone{
+			{
+				Name:    "bangkok",
+				Regions: []string{"TH-10", "TH-11", "TH-12", "TH-13"},
+				Rates: []WeightRate{
+					{MaxGrams: 1000, Fee: thb(40)},
+					{MaxGrams: 5000, Fee: thb(80)},
+					{MaxGrams: 20000, Fee: thb(150)},
+					{MaxGrams: 30000, Fee: thb(250)},
+				},
+			},
+			{
+				Name:    "domestic",
+				Regions: []string{"TH"},
+				Rates: []WeightRate{
+					{MaxGrams: 1000, Fee: thb(60)},
+					{MaxGrams: 5000, Fee: thb(120)},
+					{MaxGrams: 20000, Fee: thb(220)},
+					{MaxGrams: 30000, Fee: thb(350)},
+				},
+			},
+		},
+	}
+}
+
+// LoadConfig reads a JSON config from path, or returns DefaultConfig when path is empty
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		return DefaultConfig(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing config: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Validate checks that the rules are complete
+func (c *Config) Validate() error {
+	if c.DefaultCountry == "" {
+		return errors.New("pricing config: default_country is required")
+	}
+	for _, rule := range c.Tax {
+		if rule.Country == "" || rule.Name == "" {
+			return errors.New("pricing config: tax rules need a country and a name")
+		}
+		if rule.RateBasisPoints < 0 {
+			return fmt.Errorf("pricing config: tax rule %s has a negative rate", rule.Name)
+		}
+	}
+	for _, zone := range c.Shipping {
+		if zone.Name == "" || len(zone.Regions) == 0 || len(zone.Rates) == 0 {
+			return errors.New("pricing config: shipping zones need a name, regions and rates")
+		}
+		for i, rate := range zone.Rates {
+			if rate.MaxGrams <= 0 || (i > 0 && rate.MaxGrams <= zone.Rates[i-1].MaxGrams) {
+				return fmt.Errorf("pricing config: rates of zone %s must have increasing max_grams", zone.Name)
+			}
+			if rate.Fee.IsNegative() || !money.ValidCurrency(rate.Fee.Currency) {
+				return fmt.Errorf("pricing config: rates of zone %s need a fee in a valid currency", zone.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Destination is where an order is shipped
+type Destination struct {
+	Country string
+	Region  string
+}
+
+// Line is a basket line after promotions
+type Line struct {
+	Category    string
+	Quantity    int
+	WeightGrams int
+	Subtotal    money.Money
+	Discount    money.Money
+}
+
+// Tax is the tax of a line or of the shipping fee
+type Tax struct {
+	Amount          money.Money
+	RateBasisPoints int64
+	Inclusive       bool
+}
+
+// Breakdown is every component of an order total
+type Breakdown struct {
+	Destination  Destination
+	ShippingZone string
+	WeightGrams  int
+	Subtotal     money.Money
+	Discount     money.Money
+	Shipping     money.Money
+	// Tax is all tax of the order, TaxIncluded the part of it already in the prices
+	Tax         money.Money
+	TaxIncluded money.Money
+	Total       money.Money
+	Lines       []Tax
+}
+
+// Calculator prices orders with one Config
+type Calculator struct {
+	config *Config
+}
+
+// NewCalculator creates a calculator for config
+func NewCalculator(config *Config) *Calculator {
+	return &Calculator{config: config}
+}
+
+// Destination normalizes a destination, filling in the default country
+func (c *Calculator) Destination(country, region string) (Destination, error) {
+	dest := Destination{
+		Country: strings.ToUpper(strings.TrimSpace(country)),
+		Region:  strings.ToUpper(strings.TrimSpace(region)),
+	}
+	if dest.Country == "" {
+		dest.Country = c.config.DefaultCountry
+	}
+	if dest.Region != "" && !strings.HasPrefix(dest.Region, dest.Country+"-") {
+		return Destination{}, fmt.Errorf("invalid destination: region %s is not in %s", dest.Region, dest.Country)
+	}
+	return dest, nil
+}
+
+// Calculate runs the pricing stages for lines shipped to dest. All lines must
+// be in the same currency.
+func (c *Calculator) Calculate(dest Destination, lines []Line) (*Breakdown, error) {
+	currency := money.DefaultCurrency
+	if len(lines) > 0 {
+		currency = lines[0].Subtotal.Currency
+	}
+
+	b := &Breakdown{
+		Destination: dest,
+		Subtotal:    money.Zero(currency),
+		Discount:    money.Zero(currency),
+		Tax:         money.Zero(currency),
+		TaxIncluded: money.Zero(currency),
+		Lines:       make([]Tax, len(lines)),
+	}
+
+	for _, line := range lines {
+		b.Subtotal.Amount += line.Subtotal.Amount
+		b.Discount.Amount += line.Discount.Amount
+		b.WeightGrams += line.WeightGrams * line.Quantity
+	}
+
+	zone, fee, err := c.shipping(dest, b.WeightGrams, currency)
+	if err != nil {
+		return nil, err
+	}
+	b.ShippingZone = zone
+	b.Shipping = fee
+
+	for i, line := range lines {
+		b.Lines[i] = c.tax(dest, line.Category, money.New(line.Subtotal.Amount-line.Discount.Amount, currency))
+		b.addTax(b.Lines[i])
+	}
+	b.addTax(c.tax(dest, "", b.Shipping))
+
+	b.Total = money.New(b.Subtotal.Amount-b.Discount.Amount+b.Shipping.Amount+b.Tax.Amount-b.TaxIncluded.Amount, currency)
+	return b, nil
+}
+
+// addTax adds a line or shipping tax to the order tax
+func (b *Breakdown) addTax(tax Tax) {
+	b.Tax.Amount += tax.Amount.Amount
+	if tax.Inclusive {
+		b.TaxIncluded.Amount += tax.Amount.Amount
+	}
+}
+
+// shipping returns the zone and fee of a parcel of weight grams
+func (c *Calculator) shipping(dest Destination, weight int, currency string) (string, money.Money, error) {
+	var zone *ShippingZone
+	best := 0
+	for i := range c.config.Shipping {
+		for _, region := range c.config.Shipping[i].Regions {
+			score := 0
+			switch {
+			case dest.Region != "" && strings.EqualFold(region, dest.Region):
+				score = 2
+			case strings.EqualFold(region, dest.Country):
+				score = 1
+			}
+			if score > best {
+				zone, best = &c.config.Shipping[i], score
+			}
+		}
+	}
+	if zone == nil {
+		return "", money.Money{}, fmt.Errorf("invalid destination: no shipping to %s", dest)
+	}
+
+	for _, rate := range zone.Rates {
+		if weight <= rate.MaxGrams {
+			if rate.Fee.Currency != currency {
+				return "", money.Money{}, fmt.Errorf("invalid order: shipping to %s is not available in %s", dest, currency)
+			}
+			return zone.Name, rate.Fee, nil
+		}
+	}
+	return "", money.Money{}, fmt.Errorf("invalid order: a parcel of %d g is too heavy to ship to %s", weight, dest)
+}
+
+// tax returns the tax of amount for a product category shipped to dest. The
+// shipping fee is taxed with category "".
+func (c *Calculator) tax(dest Destination, category string, amount money.Money) Tax {
+	var rule *TaxRule
+	best := -1
+	for i := range c.config.Tax {
+		r := &c.config.Tax[i]
+		if !strings.EqualFold(r.Country, dest.Country) {
+			continue
+		}
+		score := 0
+		if r.Region != "" {
+			if !strings.EqualFold(r.Region, dest.Region) {
+				continue
+			}
+			score += 2
+		}
+		if r.Category != "" {
+			if !strings.EqualFold(r.Category, category) {
+				continue
+			}
+			score++
+		}
+		if score > best {
+			rule, best = r, score
+		}
+	}
+	if rule == nil {
+		return Tax{Amount: money.Zero(amount.Currency)}
+	}
+
+	tax := Tax{RateBasisPoints: rule.RateBasisPoints, Inclusive: rule.Inclusive}
+	if rule.Inclusive {
+		tax.Amount = amount.MulRate(rule.RateBasisPoints, 10000+rule.RateBasisPoints, money.RoundHalfUp)
+	} else {
+		tax.Amount = amount.MulRate(rule.RateBasisPoints, 10000, money.RoundHalfUp)
+	}
+	return tax
+}
+
+// String returns the destination as its region, or its country without one
+func (d Destination) String() string {
+	if d.Region != "" {
+		return d.Region
+	}
+	return d.Country
+}
@@ -0,0 +1,113 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+func thb(amount int64) money.Money {
+	return money.New(amount, "THB")
+}
+
+func TestCalculateInclusiveVAT(t *testing.T) {
+	c := NewCalculator(DefaultConfig())
+	dest, err := c.Destination("th", "th-10")
+	if err != nil {
+		t.Fatalf("Destination: %v", err)
+	}
+
+	b, err := c.Calculate(dest, []Line{
+		{Category: "Electronics", Quantity: 1, WeightGrams: 500, Subtotal: thb(107000), Discount: thb(0)},
+	})
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+
+	if b.ShippingZone != "bangkok" || b.Shipping != thb(4000) {
+		t.Errorf("Shipping = %s %v, want bangkok 40.00 THB", b.ShippingZone, b.Shipping)
+	}
+	// 7000 on the item and 261.68 rounded to 262 on the shipping fee
+	if got, want := b.Tax, thb(7262); got != want {
+		t.Errorf("Tax = %v, want %v", got, want)
+	}
+	if b.TaxIncluded != b.Tax {
+		t.Errorf("TaxIncluded = %v, want all tax %v", b.TaxIncluded, b.Tax)
+	}
+	if got, want := b.Total, thb(111000); got != want {
+		t.Errorf("Total = %v, want %v", got, want)
+	}
+	if got := b.Lines[0]; got.Amount != thb(7000) || got.RateBasisPoints != 700 || !got.Inclusive {
+		t.Errorf("Line tax = %+v, want 70.00 THB inclusive at 700 bps", got)
+	}
+}
+
+func TestCalculateExclusiveTaxOnDiscountedLines(t *testing.T) {
+	config := DefaultConfig()
+	config.Tax = []TaxRule{
+		{Country: "TH", Name: "VAT", RateBasisPoints: 700},
+		{Country: "TH", Category: "Books", Name: "VAT exempt", RateBasisPoints: 0},
+	}
+	c := NewCalculator(config)
+	dest, err := c.Destination("", "TH-50")
+	if err != nil {
+		t.Fatalf("Destination: %v", err)
+	}
+
+	b, err := c.Calculate(dest, []Line{
+		{Category: "Electronics", Quantity: 2, WeightGrams: 1500, Subtotal: thb(100000), Discount: thb(10000)},
+		{Category: "Books", Quantity: 1, WeightGrams: 400, Subtotal: thb(30000), Discount: thb(0)},
+	})
+	if err != nil {
+		t.Fatalf("Calculate: %v", err)
+	}
+
+	if b.ShippingZone != "domestic" || b.WeightGrams != 3400 || b.Shipping != thb(12000) {
+		t.Errorf("Shipping = %s %d g %v, want domestic 3400 g 120.00 THB", b.ShippingZone, b.WeightGrams, b.Shipping)
+	}
+	if got, want := b.Lines[0].Amount, thb(6300); got != want {
+		t.Errorf("Electronics tax = %v, want %v", got, want)
+	}
+	if !b.Lines[1].Amount.IsZero() {
+		t.Errorf("Books tax = %v, want zero", b.Lines[1].Amount)
+	}
+	if got, want := b.Tax, thb(7140); got != want {
+		t.Errorf("Tax = %v, want %v", got, want)
+	}
+	if !b.TaxIncluded.IsZero() {
+		t.Errorf("TaxIncluded = %v, want zero", b.TaxIncluded)
+	}
+	// 1300.00 - 100.00 + 120.00 + 71.40
+	if got, want := b.Total, thb(139140); got != want {
+		t.Errorf("Total = %v, want %v", got, want)
+	}
+}
+
+func TestCalculateRejectsUnshippableOrders(t *testing.T) {
+	c := NewCalculator(DefaultConfig())
+	heavy := []Line{{Quantity: 4, WeightGrams: 10000, Subtotal: thb(100000), Discount: thb(0)}}
+	light := []Line{{Quantity: 1, WeightGrams: 100, Subtotal: money.New(1000, "USD"), Discount: money.New(0, "USD")}}
+
+	if _, err := c.Calculate(Destination{Country: "TH"}, heavy); err == nil {
+		t.Error("Expected a parcel over the heaviest rate to be rejected")
+	}
+	if _, err := c.Calculate(Destination{Country: "US"}, heavy[:0]); err == nil {
+		t.Error("Expected a destination without a shipping zone to be rejected")
+	}
+	if _, err := c.Calculate(Destination{Country: "TH"}, light); err == nil {
+		t.Error("Expected a basket in another currency than the shipping rates to be rejected")
+	}
+	if _, err := c.Destination("TH", "US-CA"); err == nil {
+		t.Error("Expected a region outside the country to be rejected")
+	}
+}
+
+func TestLoadConfigExample(t *testing.T) {
+	config, err := LoadConfig("../../config/pricing.example.json")
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(config.Tax) != 2 || len(config.Shipping) != 2 {
+		t.Errorf("Loaded %d tax rules and %d shipping zones, want 2 and 2", len(config.Tax), len(config.Shipping))
+	}
+}
@@ -0,0 +1,278 @@
+// Package promotion prices a basket with promotions. It does no I/O: callers
+// load the promotions and how often they were used, Check decides whether a
+// promotion may be used and Apply works out the discounts.
+//
+// Promotions are applied one after another, each to what the earlier ones left
+// of a line, so stacked promotions never take a line below zero. Every discount
+// is spread over the lines it applies to and kept per line, so a returned item
+// is refunded at the price that was actually paid for it.
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+var codePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,50}$`)
+
+// Line is a priced basket line
+type Line struct {
+	ProductID uint
+	Category  string
+	Quantity  int
+	UnitPrice money.Money
+}
+
+// Subtotal is the price of the line before discounts
+func (l Line) Subtotal() money.Money {
+	return l.UnitPrice.Mul(int64(l.Quantity))
+}
+
+// Usage is how often a promotion was used by orders that are not cancelled
+type Usage struct {
+	Total  int64
+	ByUser int64
+}
+
+// Applied is a promotion that took something off the basket
+type Applied struct {
+	Promotion models.Promotion
+	Amount    money.Money
+	// Lines is the discount per basket line, in the order of the basket
+	Lines []money.Money
+}
+
+// Result is a basket priced with promotions
+type Result struct {
+	Subtotal money.Money
+	Discount money.Money
+	Total    money.Money
+	// LineDiscounts is the total discount per basket line
+	LineDiscounts []money.Money
+	Applied       []Applied
+}
+
+// NormalizeCode returns a coupon code the way it is stored
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks that a promotion is complete and consistent
+func Validate(p *models.Promotion) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("invalid promotion: name is required")
+	}
+	if p.Code != "" && !codePattern.MatchString(p.Code) {
+		return errors.New("invalid promotion: code must be 3 to 50 letters, digits, '-' or '_'")
+	}
+
+	switch p.Type {
+	case models.PromotionTypePercentage:
+		if p.PercentOff < 1 || p.PercentOff > 100 {
+			return errors.New("invalid promotion: percent_off must be between 1 and 100")
+		}
+	case models.PromotionTypeFixed:
+		if !p.AmountOff.IsPositive() || !money.ValidCurrency(p.AmountOff.Currency) {
+			return errors.New("invalid promotion: amount_off must be a positive amount in a valid currency")
+		}
+	case models.PromotionTypeBuyXGetY:
+		if p.BuyQuantity < 1 || p.GetQuantity < 1 {
+			return errors.New("invalid promotion: buy_quantity and get_quantity must be at least 1")
+		}
+	default:
+		return fmt.Errorf("invalid promotion: unknown type %q", p.Type)
+	}
+
+	if p.MinSpend.IsNegative() || (p.MinSpend.IsPositive() && !money.ValidCurrency(p.MinSpend.Currency)) {
+		return errors.New("invalid promotion: min_spend must be a positive amount in a valid currency")
+	}
+	if p.UsageLimit < 0 || p.PerUserLimit < 0 {
+		return errors.New("invalid promotion: usage limits cannot be negative")
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return errors.New("invalid promotion: ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// Check returns why p cannot be applied to lines at now, or nil if it can
+func Check(p *models.Promotion, usage Usage, lines []Line, now time.Time) error {
+	if err := CheckAvailable(p, now); err != nil {
+		return err
+	}
+	if err := CheckUsage(p, usage); err != nil {
+		return err
+	}
+
+	currency := basketCurrency(lines)
+	if p.Type == models.PromotionTypeFixed && p.AmountOff.Currency != currency {
+		return fmt.Errorf("is not available in %s", currency)
+	}
+
+	eligible := money.Zero(currency)
+	matched := false
+	for _, line := range lines {
+		if matches(p, line) {
+			eligible.Amount += line.Subtotal().Amount
+			matched = true
+		}
+	}
+	if !matched {
+		return errors.New("does not apply to any item")
+	}
+
+	if p.MinSpend.IsPositive() {
+		if p.MinSpend.Currency != currency {
+			return fmt.Errorf("is not available in %s", currency)
+		}
+		if eligible.Amount < p.MinSpend.Amount {
+			return fmt.Errorf("requires a minimum spend of %s", p.MinSpend)
+		}
+	}
+	return nil
+}
+
+// CheckAvailable returns why p cannot be used at now because it is inactive or
+// outside its validity window, or nil if it can
+func CheckAvailable(p *models.Promotion, now time.Time) error {
+	if !p.Active {
+		return errors.New("is not active")
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return errors.New("is not valid yet")
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return errors.New("has expired")
+	}
+	return nil
+}
+
+// CheckUsage returns why p cannot be used again, or nil if it can
+func CheckUsage(p *models.Promotion, usage Usage) error {
+	if p.UsageLimit > 0 && usage.Total >= int64(p.UsageLimit) {
+		return errors.New("has reached its usage limit")
+	}
+	if p.PerUserLimit > 0 && usage.ByUser >= int64(p.PerUserLimit) {
+		return errors.New("was already used the maximum number of times")
+	}
+	return nil
+}
+
+// Apply applies promotions to lines in the given order. The caller checks the
+// promotions first. Promotions that take nothing off are left out of the result.
+// All lines must be in the same currency.
+func Apply(lines []Line, promotions []models.Promotion) *Result {
+	currency := basketCurrency(lines)
+	remaining := make([]int64, len(lines))
+	subtotal := money.Zero(currency)
+	for i, line := range lines {
+		remaining[i] = line.Subtotal().Amount
+		subtotal.Amount += remaining[i]
+	}
+
+	result := &Result{
+		Subtotal:      subtotal,
+		Discount:      money.Zero(currency),
+		LineDiscounts: make([]money.Money, len(lines)),
+	}
+	for i := range result.LineDiscounts {
+		result.LineDiscounts[i] = money.Zero(currency)
+	}
+
+	for _, p := range promotions {
+		amounts := discounts(&p, lines, remaining, currency)
+
+		applied := Applied{
+			Promotion: p,
+			Amount:    money.Zero(currency),
+			Lines:     make([]money.Money, len(lines)),
+		}
+		for i, amount := range amounts {
+			remaining[i] -= amount
+			applied.Lines[i] = money.New(amount, currency)
+			applied.Amount.Amount += amount
+		}
+		if applied.Amount.IsZero() {
+			continue
+		}
+
+		for i, amount := range amounts {
+			result.LineDiscounts[i].Amount += amount
+		}
+		result.Discount.Amount += applied.Amount.Amount
+		result.Applied = append(result.Applied, applied)
+	}
+
+	result.Total = money.New(subtotal.Amount-result.Discount.Amount, currency)
+	return result
+}
+
+// discounts returns what p takes off each line, given what is left of the lines
+func discounts(p *models.Promotion, lines []Line, remaining []int64, currency string) []int64 {
+	amounts := make([]int64, len(lines))
+
+	switch p.Type {
+	case models.PromotionTypePercentage:
+		for i, line := range lines {
+			if matches(p, line) {
+				amounts[i] = money.New(remaining[i], currency).MulRate(int64(p.PercentOff), 100, money.RoundHalfUp).Amount
+			}
+		}
+
+	case models.PromotionTypeFixed:
+		weights := make([]int64, len(lines))
+		var eligible int64
+		for i, line := range lines {
+			if matches(p, line) {
+				weights[i] = remaining[i]
+				eligible += remaining[i]
+			}
+		}
+		off := p.AmountOff.Amount
+		if off > eligible {
+			off = eligible
+		}
+		for i, part := range money.New(off, currency).Allocate(weights) {
+			amounts[i] = part.Amount
+		}
+
+	case models.PromotionTypeBuyXGetY:
+		for i, line := range lines {
+			if !matches(p, line) {
+				continue
+			}
+			free := int64(line.Quantity/(p.BuyQuantity+p.GetQuantity)) * int64(p.GetQuantity)
+			amounts[i] = line.UnitPrice.Mul(free).Amount
+			if amounts[i] > remaining[i] {
+				amounts[i] = remaining[i]
+			}
+		}
+	}
+
+	return amounts
+}
+
+// matches reports whether p applies to line
+func matches(p *models.Promotion, line Line) bool {
+	if p.ProductID != 0 && p.ProductID != line.ProductID {
+		return false
+	}
+	if p.Category != "" && !strings.EqualFold(p.Category, line.Category) {
+		return false
+	}
+	return true
+}
+
+// basketCurrency returns the currency of the lines
+func basketCurrency(lines []Line) string {
+	if len(lines) == 0 {
+		return money.DefaultCurrency
+	}
+	return lines[0].UnitPrice.Currency
+}
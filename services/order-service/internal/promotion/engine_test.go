@@ -0,0 +1,104 @@
+package promotion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+func thb(amount int64) money.Money {
+	return money.New(amount, "THB")
+}
+
+func basket() []Line {
+	return []Line{
+		{ProductID: 1, Category: "Electronics", Quantity: 1, UnitPrice: thb(100000)},
+		{ProductID: 2, Category: "Books", Quantity: 3, UnitPrice: thb(25000)},
+	}
+}
+
+func TestApplyCategorySale(t *testing.T) {
+	sale := models.Promotion{ID: 1, Name: "Book week", Type: models.PromotionTypePercentage, PercentOff: 10, Category: "books", Active: true}
+
+	result := Apply(basket(), []models.Promotion{sale})
+	if got, want := result.Discount, thb(7500); got != want {
+		t.Errorf("Discount = %v, want %v", got, want)
+	}
+	if got, want := result.Total, thb(167500); got != want {
+		t.Errorf("Total = %v, want %v", got, want)
+	}
+	if !result.LineDiscounts[0].IsZero() {
+		t.Errorf("Expected no discount on electronics, got %v", result.LineDiscounts[0])
+	}
+}
+
+func TestApplyFixedIsSpreadAndCapped(t *testing.T) {
+	coupon := models.Promotion{ID: 2, Code: "SAVE100", Name: "Save 100", Type: models.PromotionTypeFixed, AmountOff: thb(10000), Active: true}
+
+	result := Apply(basket(), []models.Promotion{coupon})
+	if got, want := result.Discount, thb(10000); got != want {
+		t.Errorf("Discount = %v, want %v", got, want)
+	}
+	if sum := result.LineDiscounts[0].Amount + result.LineDiscounts[1].Amount; sum != 10000 {
+		t.Errorf("Line discounts add up to %d, want 10000", sum)
+	}
+
+	coupon.AmountOff = thb(1000000)
+	result = Apply(basket(), []models.Promotion{coupon})
+	if !result.Total.IsZero() {
+		t.Errorf("Expected a discount larger than the basket to be capped, total %v", result.Total)
+	}
+}
+
+func TestApplyBuyXGetYAndStacking(t *testing.T) {
+	bogo := models.Promotion{ID: 3, Name: "Buy 2 get 1", Type: models.PromotionTypeBuyXGetY, BuyQuantity: 2, GetQuantity: 1, ProductID: 2, Active: true}
+	half := models.Promotion{ID: 4, Code: "HALF", Name: "Half price", Type: models.PromotionTypePercentage, PercentOff: 50, Active: true}
+
+	result := Apply(basket(), []models.Promotion{bogo, half})
+	if len(result.Applied) != 2 {
+		t.Fatalf("Expected 2 applied promotions, got %d", len(result.Applied))
+	}
+	if got, want := result.Applied[0].Amount, thb(25000); got != want {
+		t.Errorf("Buy 2 get 1 discount = %v, want %v", got, want)
+	}
+	// Half price applies to what is left after the free book
+	if got, want := result.Applied[1].Amount, thb(75000); got != want {
+		t.Errorf("Half price discount = %v, want %v", got, want)
+	}
+	if got, want := result.Total, thb(75000); got != want {
+		t.Errorf("Total = %v, want %v", got, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		promotion models.Promotion
+		usage     Usage
+		ok        bool
+	}{
+		{"active", models.Promotion{Type: models.PromotionTypePercentage, PercentOff: 5, Active: true}, Usage{}, true},
+		{"inactive", models.Promotion{Type: models.PromotionTypePercentage, PercentOff: 5}, Usage{}, false},
+		{"not started", models.Promotion{Type: models.PromotionTypePercentage, PercentOff: 5, Active: true, StartsAt: &future}, Usage{}, false},
+		{"expired", models.Promotion{Type: models.PromotionTypePercentage, PercentOff: 5, Active: true, EndsAt: &past}, Usage{}, false},
+		{"usage limit", models.Promotion{Type: models.PromotionTypePercentage, PercentOff: 5, Active: true, UsageLimit: 10}, Usage{Total: 10}, false},
+		{"per user limit", models.Promotion{Type: models.PromotionTypePercentage, PercentOff: 5, Active: true, PerUserLimit: 1}, Usage{Total: 3, ByUser: 1}, false},
+		{"min spend met", models.Promotion{Type: models.PromotionTypePercentage, PercentOff: 5, Active: true, MinSpend: thb(175000)}, Usage{}, true},
+		{"min spend not met", models.Promotion{Type: models.PromotionTypePercentage, PercentOff: 5, Active: true, Category: "Books", MinSpend: thb(100000)}, Usage{}, false},
+		{"no matching item", models.Promotion{Type: models.PromotionTypePercentage, PercentOff: 5, Active: true, Category: "Toys"}, Usage{}, false},
+		{"other currency", models.Promotion{Type: models.PromotionTypeFixed, AmountOff: money.New(500, "USD"), Active: true}, Usage{}, false},
+	}
+
+	for _, tt := range tests {
+		err := Check(&tt.promotion, tt.usage, basket(), now)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: Check() = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+)
+
+// Sort columns of the admin order list
+const (
+	OrderSortCreatedAt = "created_at"
+	OrderSortTotal     = "total_amount"
+)
+
+// OrderFilter selects and orders the orders of the admin order list. Zero
+// fields do not filter.
+type OrderFilter struct {
+	Statuses    []string
+	UserID      uint
+	ProductID   uint
+	Search      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MinTotal    *int64
+	MaxTotal    *int64
+	Currency    string
+	SortBy      string
+	Desc        bool
+	After       *OrderCursor
+	Limit       int
+}
+
+// OrderCursor is the position of the last order of a page: its sort column
+// value and ID, which breaks ties
+type OrderCursor struct {
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	Total     int64     `json:"total,omitempty"`
+	ID        uint      `json:"id"`
+}
+
+// List returns up to filter.Limit orders after filter.After, using keyset
+// pagination on the sort column and ID so pages stay stable while orders are
+// being created
+func (r *orderRepository) List(ctx context.Context, filter OrderFilter) ([]models.Order, error) {
+	query := r.db.WithContext(ctx).Model(&models.Order{})
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ProductID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM order_items WHERE order_items.order_id = orders.id AND order_items.product_id = ?)", filter.ProductID)
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		like := "%" + search + "%"
+		if id, err := strconv.ParseUint(search, 10, 32); err == nil {
+			query = query.Where("id = ? OR shipping_recipient_name ILIKE ? OR shipping_phone LIKE ?", id, like, like)
+		} else {
+			query = query.Where("shipping_recipient_name ILIKE ? OR shipping_phone LIKE ?", like, like)
+		}
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if filter.Currency != "" {
+		query = query.Where("total_currency = ?", filter.Currency)
+	}
+	if filter.MinTotal != nil {
+		query = query.Where("total_amount >= ?", *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		query = query.Where("total_amount <= ?", *filter.MaxTotal)
+	}
+
+	sortBy := filter.SortBy
+	if sortBy != OrderSortTotal {
+		sortBy = OrderSortCreatedAt
+	}
+	direction, compare := "ASC", ">"
+	if filter.Desc {
+		direction, compare = "DESC", "<"
+	}
+
+	if filter.After != nil {
+		var value interface{} = filter.After.CreatedAt
+		if sortBy == OrderSortTotal {
+			value = filter.After.Total
+		}
+		query = query.Where("("+sortBy+", id) "+compare+" (?, ?)", value, filter.After.ID)
+	}
+
+	var orders []models.Order
+	err := query.
+		Preload("Items.Adjustments").
+		Preload("Discounts").
+		Order(sortBy + " " + direction).
+		Order("id " + direction).
+		Limit(filter.Limit).
+		Find(&orders).Error
+	return orders, err
+}
@@ -2,17 +2,25 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository interface {
+    WithTx(tx *gorm.DB) OrderRepository
     Create(ctx context.Context, order *models.Order) error
     FindByID(ctx context.Context, id uint) (*models.Order, error)
+    FindByIDForUpdate(ctx context.Context, id uint) (*models.Order, error)
     FindByUserID(ctx context.Context, userID uint, limit, offset int) ([]models.Order, int64, error)
+    List(ctx context.Context, filter OrderFilter) ([]models.Order, error)
+    FindPendingIDsCreatedBefore(ctx context.Context, before time.Time, limit int) ([]uint, error)
     Update(ctx context.Context, order *models.Order) error
     UpdateStatus(ctx context.Context, orderID uint, status string) error
+    SetPaymentIntent(ctx context.Context, orderID uint, paymentIntentID string) error
+    MarkStockCommitted(ctx context.Context, orderID uint) error
 }
 
 type orderRepository struct {
@@ -23,6 +31,11 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
     return &orderRepository{db: db}
 }
 
+// WithTx returns a repository that runs its queries inside tx
+func (r *orderRepository) WithTx(tx *gorm.DB) OrderRepository {
+    return &orderRepository{db: tx}
+}
+
 func (r *orderRepository) Create(ctx context.Context, order *models.Order) error {
     return r.db.WithContext(ctx).Create(order).Error
 }
@@ -30,7 +43,8 @@ func (r *orderRepository) Create(ctx context.Context, order *models.Order) error
 func (r *orderRepository) FindByID(ctx context.Context, id uint) (*models.Order, error){
 	var order models.Order
 	err := r.db.WithContext(ctx).
-		Preload("Items").
+		Preload("Items.Adjustments").
+		Preload("Discounts").
 		First(&order, id ).Error
 
 	if err != nil{
@@ -39,6 +53,19 @@ func (r *orderRepository) FindByID(ctx context.Context, id uint) (*models.Order,
 	return &order,nil
 }
 
+// FindByIDForUpdate loads an order and locks its row until the transaction ends
+func (r *orderRepository) FindByIDForUpdate(ctx context.Context, id uint) (*models.Order, error) {
+    var order models.Order
+    err := r.db.WithContext(ctx).
+        Clauses(clause.Locking{Strength: "UPDATE"}).
+        Preload("Items").
+        First(&order, id).Error
+    if err != nil {
+        return nil, err
+    }
+    return &order, nil
+}
+
 func (r *orderRepository) FindByUserID(ctx context.Context, userID uint, limit, offset int) ([]models.Order , int64 , error ){
 	var orders []models.Order
     var total int64
@@ -53,7 +80,8 @@ func (r *orderRepository) FindByUserID(ctx context.Context, userID uint, limit,
     
     // ดึง orders พร้อม pagination
     err := r.db.WithContext(ctx).
-        Preload("Items").
+        Preload("Items.Adjustments").
+        Preload("Discounts").
         Where("user_id = ?", userID).
         Order("created_at DESC").
         Limit(limit).
@@ -76,4 +104,33 @@ func (r *orderRepository) UpdateStatus(ctx context.Context, orderID uint, status
         Model(&models.Order{}).
         Where("id = ?", orderID).
         Update("status", status).Error
-}
\ No newline at end of file
+}
+
+// SetPaymentIntent records the payment intent that paid an order
+func (r *orderRepository) SetPaymentIntent(ctx context.Context, orderID uint, paymentIntentID string) error {
+    return r.db.WithContext(ctx).
+        Model(&models.Order{}).
+        Where("id = ?", orderID).
+        Update("payment_intent_id", paymentIntentID).Error
+}
+
+// MarkStockCommitted records that the stock reservation of an order is committed
+func (r *orderRepository) MarkStockCommitted(ctx context.Context, orderID uint) error {
+    return r.db.WithContext(ctx).
+        Model(&models.Order{}).
+        Where("id = ?", orderID).
+        Update("stock_pending", false).Error
+}
+
+// FindPendingIDsCreatedBefore returns the IDs of pending orders created before
+// the given time whose stock is committed, oldest first
+func (r *orderRepository) FindPendingIDsCreatedBefore(ctx context.Context, before time.Time, limit int) ([]uint, error) {
+    var ids []uint
+    err := r.db.WithContext(ctx).
+        Model(&models.Order{}).
+        Where("status = ? AND created_at < ? AND stock_pending = ?", models.OrderStatusPending, before, false).
+        Order("created_at ASC").
+        Limit(limit).
+        Pluck("id", &ids).Error
+    return ids, err
+}
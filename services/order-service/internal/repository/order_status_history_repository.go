@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type OrderStatusHistoryRepository interface {
+	WithTx(tx *gorm.DB) OrderStatusHistoryRepository
+	Create(ctx context.Context, entry *models.OrderStatusHistory) error
+	FindByOrderID(ctx context.Context, orderID uint) ([]models.OrderStatusHistory, error)
+}
+
+type orderStatusHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderStatusHistoryRepository(db *gorm.DB) OrderStatusHistoryRepository {
+	return &orderStatusHistoryRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries inside tx
+func (r *orderStatusHistoryRepository) WithTx(tx *gorm.DB) OrderStatusHistoryRepository {
+	return &orderStatusHistoryRepository{db: tx}
+}
+
+func (r *orderStatusHistoryRepository) Create(ctx context.Context, entry *models.OrderStatusHistory) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// FindByOrderID returns the transitions of an order, oldest first
+func (r *orderStatusHistoryRepository) FindByOrderID(ctx context.Context, orderID uint) ([]models.OrderStatusHistory, error) {
+	var entries []models.OrderStatusHistory
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC, id ASC").
+		Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	WithTx(tx *gorm.DB) OutboxRepository
+	Create(ctx context.Context, event *models.OutboxEvent) error
+	Save(ctx context.Context, event *models.OutboxEvent) error
+	FindByID(ctx context.Context, id uint) (*models.OutboxEvent, error)
+	FindByStatus(ctx context.Context, status string, limit, offset int) ([]models.OutboxEvent, int64, error)
+	LockDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries inside tx
+func (r *outboxRepository) WithTx(tx *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: tx}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, event *models.OutboxEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *outboxRepository) Save(ctx context.Context, event *models.OutboxEvent) error {
+	return r.db.WithContext(ctx).Save(event).Error
+}
+
+func (r *outboxRepository) FindByID(ctx context.Context, id uint) (*models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	if err := r.db.WithContext(ctx).First(&event, id).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// FindByStatus lists outbox events, newest first. An empty status lists all events.
+func (r *outboxRepository) FindByStatus(ctx context.Context, status string, limit, offset int) ([]models.OutboxEvent, int64, error) {
+	var events []models.OutboxEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.OutboxEvent{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// LockDue locks pending events that are due for publishing. Rows locked by
+// another relay are skipped, and an event is only returned when no older event
+// of the same aggregate is still waiting, so events of one order stay in order.
+func (r *outboxRepository) LockDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", models.OutboxStatusPending, now).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM outbox_events older
+			WHERE older.aggregate_type = outbox_events.aggregate_type
+			AND older.aggregate_id = outbox_events.aggregate_id
+			AND older.status <> ?
+			AND older.id < outbox_events.id
+		)`, models.OutboxStatusSent).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
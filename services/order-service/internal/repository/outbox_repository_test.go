@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens an empty in-memory SQLite database with the given tables
+func newTestDB(t *testing.T, tables ...interface{}) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(tables...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func createOutboxEvent(t *testing.T, repo OutboxRepository, aggregateID, status string, nextAttemptAt time.Time) *models.OutboxEvent {
+	t.Helper()
+	event := &models.OutboxEvent{
+		AggregateType: "order",
+		AggregateID:   aggregateID,
+		EventType:     "order.updated",
+		Topic:         "order-events",
+		Key:           aggregateID,
+		Payload:       "{}",
+		Status:        status,
+		NextAttemptAt: nextAttemptAt,
+	}
+	if err := repo.Create(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func eventIDs(events []models.OutboxEvent) []uint {
+	ids := make([]uint, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestLockDueKeepsEventsOfAnAggregateInOrder(t *testing.T) {
+	repo := NewOutboxRepository(newTestDB(t, &models.OutboxEvent{}))
+	ctx := context.Background()
+	now := time.Now()
+
+	createOutboxEvent(t, repo, "1", models.OutboxStatusSent, now.Add(-time.Hour))
+	first := createOutboxEvent(t, repo, "1", models.OutboxStatusPending, now.Add(-time.Minute))
+	second := createOutboxEvent(t, repo, "1", models.OutboxStatusPending, now.Add(-time.Minute))
+	createOutboxEvent(t, repo, "2", models.OutboxStatusPending, now.Add(time.Minute))
+	createOutboxEvent(t, repo, "2", models.OutboxStatusPending, now.Add(-time.Minute))
+	failed := createOutboxEvent(t, repo, "3", models.OutboxStatusFailed, now.Add(-time.Minute))
+	createOutboxEvent(t, repo, "3", models.OutboxStatusPending, now.Add(-time.Minute))
+	other := createOutboxEvent(t, repo, "4", models.OutboxStatusPending, now.Add(-time.Minute))
+
+	events, err := repo.LockDue(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("LockDue() error = %v", err)
+	}
+	// Only the oldest unsent event of each aggregate is due, and only once its
+	// retry time has come. Later events of orders 1 and 2 wait behind an older
+	// event, and the failed event holds back order 3 until it is replayed.
+	want := []uint{first.ID, other.ID}
+	if got := eventIDs(events); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("LockDue() = %v, want %v", got, want)
+	}
+
+	// Once the first event is sent the second one becomes due
+	first.Status = models.OutboxStatusSent
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	// Replaying the failed event makes it due again, still ahead of the next one
+	failed.Status = models.OutboxStatusPending
+	if err := repo.Save(ctx, failed); err != nil {
+		t.Fatal(err)
+	}
+	events, err = repo.LockDue(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("LockDue() error = %v", err)
+	}
+	want = []uint{second.ID, failed.ID, other.ID}
+	if got := eventIDs(events); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("LockDue() = %v, want %v", got, want)
+	}
+
+	if events, _ := repo.LockDue(ctx, now, 1); len(events) != 1 {
+		t.Errorf("LockDue() with limit 1 returned %d events", len(events))
+	}
+}
+
+func TestLockDueSkipsLockedRows(t *testing.T) {
+	// SQLite has no row locks, so check the statement Postgres would run
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statement string
+	err = db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		statement = tx.Statement.SQL.String()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewOutboxRepository(db).LockDue(context.Background(), time.Now(), 10); err != nil {
+		t.Fatalf("LockDue() error = %v", err)
+	}
+	if !strings.HasSuffix(statement, "FOR UPDATE SKIP LOCKED") {
+		t.Errorf("LockDue() statement = %q, want FOR UPDATE SKIP LOCKED", statement)
+	}
+}
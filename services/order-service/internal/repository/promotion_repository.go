@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromotionRepository interface {
+	WithTx(tx *gorm.DB) PromotionRepository
+	Create(ctx context.Context, promotion *models.Promotion) error
+	Update(ctx context.Context, promotion *models.Promotion) error
+	FindByID(ctx context.Context, id uint) (*models.Promotion, error)
+	FindByCode(ctx context.Context, code string) (*models.Promotion, error)
+	FindAll(ctx context.Context, limit, offset int) ([]models.Promotion, int64, error)
+	FindAutomatic(ctx context.Context) ([]models.Promotion, error)
+	LockByIDs(ctx context.Context, ids []uint) ([]models.Promotion, error)
+	CountUsage(ctx context.Context, promotionID, userID uint) (total, byUser int64, err error)
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries inside tx
+func (r *promotionRepository) WithTx(tx *gorm.DB) PromotionRepository {
+	return &promotionRepository{db: tx}
+}
+
+func (r *promotionRepository) Create(ctx context.Context, promotion *models.Promotion) error {
+	return r.db.WithContext(ctx).Create(promotion).Error
+}
+
+func (r *promotionRepository) Update(ctx context.Context, promotion *models.Promotion) error {
+	return r.db.WithContext(ctx).Save(promotion).Error
+}
+
+func (r *promotionRepository) FindByID(ctx context.Context, id uint) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.WithContext(ctx).First(&promotion, id).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepository) FindByCode(ctx context.Context, code string) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&promotion).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepository) FindAll(ctx context.Context, limit, offset int) ([]models.Promotion, int64, error) {
+	var promotions []models.Promotion
+	var total int64
+
+	if err := r.db.WithContext(ctx).Model(&models.Promotion{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&promotions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return promotions, total, nil
+}
+
+// FindAutomatic returns the active promotions without a code, oldest first.
+// Their validity window is checked by the caller.
+func (r *promotionRepository) FindAutomatic(ctx context.Context) ([]models.Promotion, error) {
+	var promotions []models.Promotion
+	err := r.db.WithContext(ctx).
+		Where("active = ? AND code = ''", true).
+		Order("id ASC").
+		Find(&promotions).Error
+	return promotions, err
+}
+
+// LockByIDs loads promotions and locks their rows until the transaction ends,
+// so orders using the same promotion count its usage one at a time
+func (r *promotionRepository) LockByIDs(ctx context.Context, ids []uint) ([]models.Promotion, error) {
+	var promotions []models.Promotion
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id ASC").
+		Find(&promotions).Error
+	return promotions, err
+}
+
+// CountUsage counts the orders that are not cancelled and used a promotion, in
+// total and for userID
+func (r *promotionRepository) CountUsage(ctx context.Context, promotionID, userID uint) (int64, int64, error) {
+	var row struct {
+		Total  int64
+		ByUser int64
+	}
+	err := r.db.WithContext(ctx).
+		Table("order_discounts").
+		Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN orders.user_id = ? THEN 1 ELSE 0 END), 0) AS by_user", userID).
+		Joins("JOIN orders ON orders.id = order_discounts.order_id").
+		Where("order_discounts.promotion_id = ? AND orders.status <> ? AND orders.deleted_at IS NULL", promotionID, models.OrderStatusCancelled).
+		Scan(&row).Error
+	return row.Total, row.ByUser, err
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type ReturnRepository interface {
+	WithTx(tx *gorm.DB) ReturnRepository
+	Create(ctx context.Context, ret *models.ReturnRequest) error
+	FindByID(ctx context.Context, id uint) (*models.ReturnRequest, error)
+	FindByOrderID(ctx context.Context, orderID uint) ([]models.ReturnRequest, error)
+	ReturnedQuantities(ctx context.Context, orderID uint) (map[uint]int, error)
+	UpdateStatus(ctx context.Context, id uint, updates map[string]interface{}, fromStatuses ...string) (bool, error)
+}
+
+type returnRepository struct {
+	db *gorm.DB
+}
+
+func NewReturnRepository(db *gorm.DB) ReturnRepository {
+	return &returnRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries inside tx
+func (r *returnRepository) WithTx(tx *gorm.DB) ReturnRepository {
+	return &returnRepository{db: tx}
+}
+
+func (r *returnRepository) Create(ctx context.Context, ret *models.ReturnRequest) error {
+	return r.db.WithContext(ctx).Create(ret).Error
+}
+
+func (r *returnRepository) FindByID(ctx context.Context, id uint) (*models.ReturnRequest, error) {
+	var ret models.ReturnRequest
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		First(&ret, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (r *returnRepository) FindByOrderID(ctx context.Context, orderID uint) ([]models.ReturnRequest, error) {
+	var returns []models.ReturnRequest
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&returns).Error
+	return returns, err
+}
+
+// ReturnedQuantities sums the quantities of every order item in returns of an
+// order that were not rejected, keyed by order item ID
+func (r *returnRepository) ReturnedQuantities(ctx context.Context, orderID uint) (map[uint]int, error) {
+	var rows []struct {
+		OrderItemID uint
+		Quantity    int
+	}
+	err := r.db.WithContext(ctx).
+		Table("return_items").
+		Select("return_items.order_item_id, SUM(return_items.quantity) AS quantity").
+		Joins("JOIN return_requests ON return_requests.id = return_items.return_id").
+		Where("return_requests.order_id = ? AND return_requests.status <> ?", orderID, models.ReturnStatusRejected).
+		Group("return_items.order_item_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		quantities[row.OrderItemID] = row.Quantity
+	}
+	return quantities, nil
+}
+
+// UpdateStatus applies updates only while the return is in one of fromStatuses
+// and reports whether it did
+func (r *returnRepository) UpdateStatus(ctx context.Context, id uint, updates map[string]interface{}, fromStatuses ...string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ReturnRequest{}).
+		Where("id = ? AND status IN ?", id, fromStatuses).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type SagaRepository interface {
+	WithTx(tx *gorm.DB) SagaRepository
+	Create(ctx context.Context, saga *models.Saga) error
+	Save(ctx context.Context, saga *models.Saga) error
+	FindByID(ctx context.Context, id string) (*models.Saga, error)
+	Claim(ctx context.Context, saga *models.Saga) (bool, error)
+	FindUnfinished(ctx context.Context, sagaType string, staleBefore time.Time) ([]models.Saga, error)
+}
+
+type sagaRepository struct {
+	db *gorm.DB
+}
+
+func NewSagaRepository(db *gorm.DB) SagaRepository {
+	return &sagaRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries inside tx
+func (r *sagaRepository) WithTx(tx *gorm.DB) SagaRepository {
+	return &sagaRepository{db: tx}
+}
+
+func (r *sagaRepository) Create(ctx context.Context, saga *models.Saga) error {
+	return r.db.WithContext(ctx).Create(saga).Error
+}
+
+func (r *sagaRepository) Save(ctx context.Context, saga *models.Saga) error {
+	return r.db.WithContext(ctx).Save(saga).Error
+}
+
+func (r *sagaRepository) FindByID(ctx context.Context, id string) (*models.Saga, error) {
+	var saga models.Saga
+	if err := r.db.WithContext(ctx).First(&saga, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &saga, nil
+}
+
+// Claim takes ownership of a saga found by FindUnfinished. It fails when another
+// replica touched the saga after it was loaded.
+func (r *sagaRepository) Claim(ctx context.Context, saga *models.Saga) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&models.Saga{}).
+		Where("id = ? AND updated_at = ?", saga.ID, saga.UpdatedAt).
+		UpdateColumn("updated_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	saga.UpdatedAt = now
+	return true, nil
+}
+
+// FindUnfinished finds sagas of a type that are still in flight and were not updated since staleBefore
+func (r *sagaRepository) FindUnfinished(ctx context.Context, sagaType string, staleBefore time.Time) ([]models.Saga, error) {
+	var sagas []models.Saga
+	err := r.db.WithContext(ctx).
+		Where("type = ? AND status IN ? AND updated_at < ?",
+			sagaType,
+			[]string{models.SagaStatusRunning, models.SagaStatusCompensating},
+			staleBefore).
+		Order("created_at ASC").
+		Find(&sagas).Error
+	if err != nil {
+		return nil, err
+	}
+	return sagas, nil
+}
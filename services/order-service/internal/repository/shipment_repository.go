@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShipmentRepository interface {
+	WithTx(tx *gorm.DB) ShipmentRepository
+	Create(ctx context.Context, shipment *models.Shipment) error
+	FindByID(ctx context.Context, id uint) (*models.Shipment, error)
+	FindByOrderID(ctx context.Context, orderID uint) ([]models.Shipment, error)
+	FindByTracking(ctx context.Context, carrier, trackingNumber string) (*models.Shipment, error)
+	AddTrackingEvent(ctx context.Context, event *models.ShipmentTrackingEvent) (bool, error)
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+}
+
+type shipmentRepository struct {
+	db *gorm.DB
+}
+
+func NewShipmentRepository(db *gorm.DB) ShipmentRepository {
+	return &shipmentRepository{db: db}
+}
+
+// WithTx returns a repository that runs its queries inside tx
+func (r *shipmentRepository) WithTx(tx *gorm.DB) ShipmentRepository {
+	return &shipmentRepository{db: tx}
+}
+
+func (r *shipmentRepository) Create(ctx context.Context, shipment *models.Shipment) error {
+	return r.db.WithContext(ctx).Create(shipment).Error
+}
+
+func (r *shipmentRepository) FindByID(ctx context.Context, id uint) (*models.Shipment, error) {
+	var shipment models.Shipment
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Preload("TrackingEvents", func(db *gorm.DB) *gorm.DB {
+			return db.Order("occurred_at")
+		}).
+		First(&shipment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+// FindByOrderID returns the shipments of an order, oldest first
+func (r *shipmentRepository) FindByOrderID(ctx context.Context, orderID uint) ([]models.Shipment, error) {
+	var shipments []models.Shipment
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Preload("TrackingEvents", func(db *gorm.DB) *gorm.DB {
+			return db.Order("occurred_at")
+		}).
+		Where("order_id = ?", orderID).
+		Order("id").
+		Find(&shipments).Error
+	return shipments, err
+}
+
+// FindByTracking finds a shipment by the tracking number the carrier gave it
+func (r *shipmentRepository) FindByTracking(ctx context.Context, carrier, trackingNumber string) (*models.Shipment, error) {
+	var shipment models.Shipment
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("carrier = ? AND tracking_number = ?", carrier, trackingNumber).
+		First(&shipment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+// AddTrackingEvent stores a carrier update and reports whether it was new.
+// An update with the same status and time as a stored one is ignored.
+func (r *shipmentRepository) AddTrackingEvent(ctx context.Context, event *models.ShipmentTrackingEvent) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *shipmentRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Shipment{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
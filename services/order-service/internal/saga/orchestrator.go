@@ -0,0 +1,265 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Step is one action of a saga and the action that undoes it.
+//
+// A step either talks to another service (Execute) or changes only the local
+// database (ExecuteTx). Local steps run in the same transaction as the saga
+// checkpoint so they happen exactly once. Remote steps and all compensations may
+// be retried after a crash and must be idempotent.
+type Step[T any] struct {
+	Name       string
+	Execute    func(ctx context.Context, sagaID string, data *T) error
+	ExecuteTx  func(ctx context.Context, tx *gorm.DB, sagaID string, data *T) error
+	Compensate func(ctx context.Context, sagaID string, data *T) error
+}
+
+// Definition describes a saga type.
+//
+// Steps before PivotStep are compensated when they or the pivot step fail. The
+// pivot step itself is never compensated: it must be idempotent, and a failure
+// that is not a rejection (see Reject) may have taken effect remotely, so the
+// pivot is retried by Resume instead. Once the pivot step has completed the saga
+// can only move forward, so failures of later steps leave it running for Resume
+// to retry.
+type Definition[T any] struct {
+	Type      string
+	Steps     []Step[T]
+	PivotStep int
+}
+
+// rejection is a step error that is known to have had no effect
+type rejection struct {
+	err error
+}
+
+func (r rejection) Error() string { return r.err.Error() }
+func (r rejection) Unwrap() error { return r.err }
+
+// Reject marks err as a definite refusal of a step, for example a remote
+// service answering that the request cannot be done. A rejected pivot step is
+// not retried, the steps before it are compensated instead.
+func Reject(err error) error {
+	return rejection{err: err}
+}
+
+// IsRejected reports whether err was marked with Reject
+func IsRejected(err error) bool {
+	var r rejection
+	return errors.As(err, &r)
+}
+
+type idContextKey struct{}
+
+// WithID returns a copy of ctx that makes Run use id as the saga ID, so that a
+// caller can record the ID before the saga starts
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idContextKey{}, id)
+}
+
+// Orchestrator runs sagas of one definition and persists their progress
+type Orchestrator[T any] struct {
+	def  Definition[T]
+	db   *gorm.DB
+	repo repository.SagaRepository
+}
+
+// NewOrchestrator creates a new saga orchestrator
+func NewOrchestrator[T any](def Definition[T], db *gorm.DB, repo repository.SagaRepository) *Orchestrator[T] {
+	return &Orchestrator[T]{
+		def:  def,
+		db:   db,
+		repo: repo,
+	}
+}
+
+// Run starts a new saga and drives it to completion or full compensation.
+// The returned error is the error of the step that caused compensation. The
+// saga gets the ID set with WithID, or a new one.
+func (o *Orchestrator[T]) Run(ctx context.Context, data *T) (string, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal saga data: %w", err)
+	}
+
+	id, _ := ctx.Value(idContextKey{}).(string)
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	saga := &models.Saga{
+		ID:      id,
+		Type:    o.def.Type,
+		Status:  models.SagaStatusRunning,
+		Payload: string(payload),
+	}
+	if err := o.repo.Create(ctx, saga); err != nil {
+		return "", fmt.Errorf("failed to create saga: %w", err)
+	}
+
+	return saga.ID, o.drive(ctx, saga, data)
+}
+
+// Resume picks up sagas of this type left in flight by a crash or restart, or
+// left running for a retry. Sagas interrupted before the pivot step are
+// compensated, sagas at or after the pivot step are moved forward. Only sagas
+// not updated for staleAfter are touched so that sagas still owned by a live
+// replica are left alone.
+func (o *Orchestrator[T]) Resume(ctx context.Context, staleAfter time.Duration) error {
+	sagas, err := o.repo.FindUnfinished(ctx, o.def.Type, time.Now().Add(-staleAfter))
+	if err != nil {
+		return fmt.Errorf("failed to find unfinished sagas: %w", err)
+	}
+
+	for i := range sagas {
+		saga := &sagas[i]
+
+		claimed, err := o.repo.Claim(ctx, saga)
+		if err != nil {
+			return fmt.Errorf("failed to claim saga %s: %w", saga.ID, err)
+		}
+		if !claimed {
+			continue
+		}
+
+		var data T
+		if err := json.Unmarshal([]byte(saga.Payload), &data); err != nil {
+			log.Printf("Saga %s has an unreadable payload: %v", saga.ID, err)
+			continue
+		}
+
+		// The step at CurrentStep may have run partially, compensate it too.
+		// The pivot step may have run as well but it is retried, not undone.
+		if saga.Status == models.SagaStatusRunning && saga.CurrentStep < o.def.PivotStep {
+			saga.Status = models.SagaStatusCompensating
+			saga.LastError = "interrupted before completion"
+		}
+
+		log.Printf("Resuming saga %s (type=%s, status=%s, step=%d)", saga.ID, saga.Type, saga.Status, saga.CurrentStep)
+		if err := o.drive(ctx, saga, &data); err != nil {
+			log.Printf("Saga %s finished with error: %v", saga.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// drive executes or compensates the remaining steps of a saga based on its status
+func (o *Orchestrator[T]) drive(ctx context.Context, saga *models.Saga, data *T) error {
+	if saga.Status == models.SagaStatusCompensating {
+		return o.compensate(ctx, saga, data, errors.New(saga.LastError))
+	}
+
+	for saga.CurrentStep < len(o.def.Steps) {
+		step := o.def.Steps[saga.CurrentStep]
+
+		if err := o.execute(ctx, saga, step, data); err != nil {
+			saga.LastError = fmt.Sprintf("%s: %v", step.Name, err)
+
+			if saga.CurrentStep > o.def.PivotStep || (saga.CurrentStep == o.def.PivotStep && !IsRejected(err)) {
+				log.Printf("Saga %s step %s failed at or after pivot, will retry on resume: %v", saga.ID, step.Name, err)
+				if saveErr := o.checkpoint(ctx, o.repo, saga, data); saveErr != nil {
+					log.Printf("Failed to save saga %s: %v", saga.ID, saveErr)
+				}
+				return nil
+			}
+
+			// A failed step before the pivot is compensated too, it may have
+			// partially run. A rejected pivot had no effect, so only the
+			// steps before it are undone.
+			if saga.CurrentStep == o.def.PivotStep {
+				saga.CurrentStep--
+			}
+			saga.Status = models.SagaStatusCompensating
+			if saveErr := o.checkpoint(ctx, o.repo, saga, data); saveErr != nil {
+				log.Printf("Failed to save saga %s: %v", saga.ID, saveErr)
+			}
+			return o.compensate(ctx, saga, data, err)
+		}
+	}
+
+	saga.Status = models.SagaStatusCompleted
+	saga.LastError = ""
+	if err := o.checkpoint(ctx, o.repo, saga, data); err != nil {
+		log.Printf("Failed to save saga %s: %v", saga.ID, err)
+	}
+	return nil
+}
+
+// execute runs one step and records that it completed
+func (o *Orchestrator[T]) execute(ctx context.Context, saga *models.Saga, step Step[T], data *T) error {
+	if step.ExecuteTx != nil {
+		return o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := step.ExecuteTx(ctx, tx, saga.ID, data); err != nil {
+				return err
+			}
+			saga.CurrentStep++
+			if err := o.checkpoint(ctx, o.repo.WithTx(tx), saga, data); err != nil {
+				saga.CurrentStep--
+				return err
+			}
+			return nil
+		})
+	}
+
+	if err := step.Execute(ctx, saga.ID, data); err != nil {
+		return err
+	}
+	saga.CurrentStep++
+	return o.checkpoint(ctx, o.repo, saga, data)
+}
+
+// compensate undoes completed steps in reverse order, starting at CurrentStep
+func (o *Orchestrator[T]) compensate(ctx context.Context, saga *models.Saga, data *T, cause error) error {
+	if saga.CurrentStep >= len(o.def.Steps) {
+		saga.CurrentStep = len(o.def.Steps) - 1
+	}
+
+	for saga.CurrentStep >= 0 {
+		step := o.def.Steps[saga.CurrentStep]
+		if step.Compensate != nil {
+			if err := step.Compensate(ctx, saga.ID, data); err != nil {
+				saga.LastError = fmt.Sprintf("compensate %s: %v", step.Name, err)
+				if saveErr := o.checkpoint(ctx, o.repo, saga, data); saveErr != nil {
+					log.Printf("Failed to save saga %s: %v", saga.ID, saveErr)
+				}
+				return fmt.Errorf("%w (compensation failed: %v)", cause, err)
+			}
+		}
+
+		saga.CurrentStep--
+		if err := o.checkpoint(ctx, o.repo, saga, data); err != nil {
+			log.Printf("Failed to save saga %s: %v", saga.ID, err)
+		}
+	}
+
+	saga.CurrentStep = 0
+	saga.Status = models.SagaStatusCompensated
+	if err := o.checkpoint(ctx, o.repo, saga, data); err != nil {
+		log.Printf("Failed to save saga %s: %v", saga.ID, err)
+	}
+	return cause
+}
+
+// checkpoint persists the saga status, position and data
+func (o *Orchestrator[T]) checkpoint(ctx context.Context, repo repository.SagaRepository, saga *models.Saga, data *T) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saga data: %w", err)
+	}
+	saga.Payload = string(payload)
+	return repo.Save(ctx, saga)
+}
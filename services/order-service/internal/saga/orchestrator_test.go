@@ -0,0 +1,289 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+type memorySagaRepository struct {
+	sagas map[string]models.Saga
+}
+
+func newMemorySagaRepository() *memorySagaRepository {
+	return &memorySagaRepository{sagas: make(map[string]models.Saga)}
+}
+
+func (r *memorySagaRepository) WithTx(tx *gorm.DB) repository.SagaRepository { return r }
+
+func (r *memorySagaRepository) Create(ctx context.Context, saga *models.Saga) error {
+	saga.UpdatedAt = time.Now()
+	r.sagas[saga.ID] = *saga
+	return nil
+}
+
+func (r *memorySagaRepository) Save(ctx context.Context, saga *models.Saga) error {
+	saga.UpdatedAt = time.Now()
+	r.sagas[saga.ID] = *saga
+	return nil
+}
+
+func (r *memorySagaRepository) FindByID(ctx context.Context, id string) (*models.Saga, error) {
+	saga, ok := r.sagas[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &saga, nil
+}
+
+func (r *memorySagaRepository) Claim(ctx context.Context, saga *models.Saga) (bool, error) {
+	return true, nil
+}
+
+func (r *memorySagaRepository) FindUnfinished(ctx context.Context, sagaType string, staleBefore time.Time) ([]models.Saga, error) {
+	var sagas []models.Saga
+	for _, saga := range r.sagas {
+		if saga.Type == sagaType && !saga.IsFinished() {
+			sagas = append(sagas, saga)
+		}
+	}
+	return sagas, nil
+}
+
+type testData struct {
+	Log []string `json:"log"`
+}
+
+func recordStep(name string, fail bool) Step[testData] {
+	return Step[testData]{
+		Name: name,
+		Execute: func(ctx context.Context, sagaID string, data *testData) error {
+			if fail {
+				return errors.New(name + " failed")
+			}
+			data.Log = append(data.Log, "do "+name)
+			return nil
+		},
+		Compensate: func(ctx context.Context, sagaID string, data *testData) error {
+			data.Log = append(data.Log, "undo "+name)
+			return nil
+		},
+	}
+}
+
+func TestRunCompletesAllSteps(t *testing.T) {
+	repo := newMemorySagaRepository()
+	orchestrator := NewOrchestrator(Definition[testData]{
+		Type:      "test",
+		Steps:     []Step[testData]{recordStep("a", false), recordStep("b", false)},
+		PivotStep: 1,
+	}, nil, repo)
+
+	data := &testData{}
+	id, err := orchestrator.Run(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if got := repo.sagas[id].Status; got != models.SagaStatusCompleted {
+		t.Errorf("Expected status %s, got %s", models.SagaStatusCompleted, got)
+	}
+	if len(data.Log) != 2 {
+		t.Errorf("Expected 2 executed steps, got %v", data.Log)
+	}
+}
+
+func TestRunCompensatesInReverseOrder(t *testing.T) {
+	repo := newMemorySagaRepository()
+	orchestrator := NewOrchestrator(Definition[testData]{
+		Type:      "test",
+		Steps:     []Step[testData]{recordStep("a", false), recordStep("b", false), recordStep("c", true), recordStep("d", false)},
+		PivotStep: 3,
+	}, nil, repo)
+
+	data := &testData{}
+	id, err := orchestrator.Run(context.Background(), data)
+	if err == nil {
+		t.Fatal("Expected error from failed step")
+	}
+
+	want := []string{"do a", "do b", "undo c", "undo b", "undo a"}
+	if len(data.Log) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, data.Log)
+	}
+	for i := range want {
+		if data.Log[i] != want[i] {
+			t.Errorf("Step %d: expected %q, got %q", i, want[i], data.Log[i])
+		}
+	}
+
+	if got := repo.sagas[id].Status; got != models.SagaStatusCompensated {
+		t.Errorf("Expected status %s, got %s", models.SagaStatusCompensated, got)
+	}
+}
+
+func TestRunLeavesSagaRunningWhenStepAfterPivotFails(t *testing.T) {
+	repo := newMemorySagaRepository()
+	orchestrator := NewOrchestrator(Definition[testData]{
+		Type:      "test",
+		Steps:     []Step[testData]{recordStep("a", false), recordStep("b", true)},
+		PivotStep: 0,
+	}, nil, repo)
+
+	id, err := orchestrator.Run(context.Background(), &testData{})
+	if err != nil {
+		t.Fatalf("Expected no error after pivot, got %v", err)
+	}
+
+	saga := repo.sagas[id]
+	if saga.Status != models.SagaStatusRunning || saga.CurrentStep != 1 {
+		t.Errorf("Expected running saga at step 1, got %s at step %d", saga.Status, saga.CurrentStep)
+	}
+}
+
+func TestResumeCompensatesInterruptedSaga(t *testing.T) {
+	repo := newMemorySagaRepository()
+	repo.sagas["interrupted"] = models.Saga{
+		ID:          "interrupted",
+		Type:        "test",
+		Status:      models.SagaStatusRunning,
+		CurrentStep: 1,
+		Payload:     `{"log":["do a"]}`,
+	}
+
+	orchestrator := NewOrchestrator(Definition[testData]{
+		Type:      "test",
+		Steps:     []Step[testData]{recordStep("a", false), recordStep("b", false), recordStep("c", false)},
+		PivotStep: 2,
+	}, nil, repo)
+
+	if err := orchestrator.Resume(context.Background(), 0); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	saga := repo.sagas["interrupted"]
+	if saga.Status != models.SagaStatusCompensated {
+		t.Errorf("Expected status %s, got %s", models.SagaStatusCompensated, saga.Status)
+	}
+	if saga.Payload != `{"log":["do a","undo b","undo a"]}` {
+		t.Errorf("Unexpected payload: %s", saga.Payload)
+	}
+}
+
+// flakyStep fails the first failures times it runs and then succeeds
+func flakyStep(name string, failures int, errFor func(error) error) Step[testData] {
+	step := recordStep(name, false)
+	execute := step.Execute
+	step.Execute = func(ctx context.Context, sagaID string, data *testData) error {
+		if failures > 0 {
+			failures--
+			return errFor(errors.New(name + " failed"))
+		}
+		return execute(ctx, sagaID, data)
+	}
+	return step
+}
+
+func TestRunRetriesPivotThatMayHaveRun(t *testing.T) {
+	repo := newMemorySagaRepository()
+	unknown := func(err error) error { return err }
+	orchestrator := NewOrchestrator(Definition[testData]{
+		Type:      "test",
+		Steps:     []Step[testData]{recordStep("a", false), flakyStep("b", 1, unknown), recordStep("c", false)},
+		PivotStep: 1,
+	}, nil, repo)
+
+	id, err := orchestrator.Run(context.Background(), &testData{})
+	if err != nil {
+		t.Fatalf("Expected the pivot to be left for a retry, got %v", err)
+	}
+	saga := repo.sagas[id]
+	if saga.Status != models.SagaStatusRunning || saga.CurrentStep != 1 {
+		t.Fatalf("Expected running saga at step 1, got %s at step %d", saga.Status, saga.CurrentStep)
+	}
+
+	if err := orchestrator.Resume(context.Background(), 0); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	saga = repo.sagas[id]
+	if saga.Status != models.SagaStatusCompleted {
+		t.Errorf("Expected status %s, got %s", models.SagaStatusCompleted, saga.Status)
+	}
+	if saga.Payload != `{"log":["do a","do b","do c"]}` {
+		t.Errorf("Unexpected payload: %s", saga.Payload)
+	}
+}
+
+func TestRunCompensatesStepsBeforeRejectedPivot(t *testing.T) {
+	repo := newMemorySagaRepository()
+	orchestrator := NewOrchestrator(Definition[testData]{
+		Type:      "test",
+		Steps:     []Step[testData]{recordStep("a", false), recordStep("b", false), flakyStep("c", 1, Reject), recordStep("d", false)},
+		PivotStep: 2,
+	}, nil, repo)
+
+	data := &testData{}
+	id, err := orchestrator.Run(context.Background(), data)
+	if err == nil || !IsRejected(err) {
+		t.Fatalf("Expected the rejection of the pivot, got %v", err)
+	}
+
+	// The pivot had no effect and is not compensated
+	if got := strings.Join(data.Log, ","); got != "do a,do b,undo b,undo a" {
+		t.Errorf("Unexpected log: %s", got)
+	}
+	if got := repo.sagas[id].Status; got != models.SagaStatusCompensated {
+		t.Errorf("Expected status %s, got %s", models.SagaStatusCompensated, got)
+	}
+}
+
+func TestResumeRetriesInterruptedPivot(t *testing.T) {
+	repo := newMemorySagaRepository()
+	repo.sagas["at-pivot"] = models.Saga{
+		ID:          "at-pivot",
+		Type:        "test",
+		Status:      models.SagaStatusRunning,
+		CurrentStep: 2,
+		Payload:     `{"log":["do a","do b"]}`,
+	}
+
+	orchestrator := NewOrchestrator(Definition[testData]{
+		Type:      "test",
+		Steps:     []Step[testData]{recordStep("a", false), recordStep("b", false), recordStep("c", false), recordStep("d", false)},
+		PivotStep: 2,
+	}, nil, repo)
+
+	if err := orchestrator.Resume(context.Background(), 0); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	saga := repo.sagas["at-pivot"]
+	if saga.Status != models.SagaStatusCompleted {
+		t.Errorf("Expected status %s, got %s", models.SagaStatusCompleted, saga.Status)
+	}
+	if saga.Payload != `{"log":["do a","do b","do c","do d"]}` {
+		t.Errorf("Unexpected payload: %s", saga.Payload)
+	}
+}
+
+func TestRunUsesIDFromContext(t *testing.T) {
+	repo := newMemorySagaRepository()
+	orchestrator := NewOrchestrator(Definition[testData]{
+		Type:  "test",
+		Steps: []Step[testData]{recordStep("a", false)},
+	}, nil, repo)
+
+	id, err := orchestrator.Run(WithID(context.Background(), "known-id"), &testData{})
+	if err != nil || id != "known-id" {
+		t.Fatalf("Run() = %q, %v, want known-id", id, err)
+	}
+	if _, err := repo.FindByID(context.Background(), "known-id"); err != nil {
+		t.Errorf("saga known-id was not stored: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+)
+
+// orderExpiryLeaseKey is the Redis key of the lease that lets one replica run a sweep
+const orderExpiryLeaseKey = "scheduler:order-expiry:lease"
+
+// OrderExpiryConfig controls how often unpaid orders are swept and when they expire
+type OrderExpiryConfig struct {
+	Timeout   time.Duration
+	Interval  time.Duration
+	BatchSize int
+}
+
+// OrderExpiry voids the payments of pending orders that were not paid within
+// the timeout and cancels them, see OrderService.ExpirePendingOrders
+type OrderExpiry struct {
+	service service.OrderService
+	redis   *redis.Client
+	cfg     OrderExpiryConfig
+	owner   string
+}
+
+// NewOrderExpiry creates a new order expiry scheduler
+func NewOrderExpiry(service service.OrderService, redisClient *redis.Client, cfg OrderExpiryConfig) *OrderExpiry {
+	return &OrderExpiry{
+		service: service,
+		redis:   redisClient,
+		cfg:     cfg,
+		owner:   uuid.NewString(),
+	}
+}
+
+// Start sweeps expired orders every interval until ctx is cancelled
+func (e *OrderExpiry) Start(ctx context.Context) {
+	log.Printf("Order expiry started (timeout: %v, interval: %v, batch: %d)", e.cfg.Timeout, e.cfg.Interval, e.cfg.BatchSize)
+
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Order expiry stopped")
+			return
+		case <-ticker.C:
+			e.sweep(ctx)
+		}
+	}
+}
+
+// sweep cancels expired orders in batches while this replica holds the lease.
+// The lease is not released, it expires after one interval so that only one
+// replica sweeps per interval. The state machine still locks every order, so a
+// sweep outliving its lease stays safe.
+func (e *OrderExpiry) sweep(ctx context.Context) {
+	acquired, err := e.redis.SetNX(ctx, orderExpiryLeaseKey, e.owner, e.cfg.Interval).Result()
+	if err != nil {
+		log.Printf("Order expiry failed to acquire lease: %v", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	for {
+		cancelled, err := e.service.ExpirePendingOrders(ctx, e.cfg.Timeout, e.cfg.BatchSize)
+		if cancelled > 0 {
+			log.Printf("Order expiry cancelled %d unpaid orders", cancelled)
+		}
+		if err != nil {
+			log.Printf("Order expiry failed: %v", err)
+			return
+		}
+		if cancelled < e.cfg.BatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/service"
+)
+
+// SagaResumer periodically finishes or compensates sagas that were
+// interrupted or left running for a retry
+type SagaResumer struct {
+	service    service.OrderService
+	interval   time.Duration
+	staleAfter time.Duration
+}
+
+// NewSagaResumer creates a new saga resumer
+func NewSagaResumer(service service.OrderService, interval, staleAfter time.Duration) *SagaResumer {
+	return &SagaResumer{
+		service:    service,
+		interval:   interval,
+		staleAfter: staleAfter,
+	}
+}
+
+// Start resumes sagas right away and then every interval until ctx is
+// cancelled. Replicas may run it at the same time, every saga is claimed
+// before it is resumed.
+func (r *SagaResumer) Start(ctx context.Context) {
+	log.Printf("Saga resumer started (interval: %v, stale after: %v)", r.interval, r.staleAfter)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.service.ResumeSagas(ctx, r.staleAfter); err != nil {
+			log.Printf("Failed to resume sagas: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Saga resumer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
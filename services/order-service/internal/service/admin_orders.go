@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+	"gorm.io/gorm"
+)
+
+// Page sizes of the admin order list
+const (
+	defaultAdminOrderLimit = 20
+	maxAdminOrderLimit     = 100
+)
+
+// adminOrderCursor is the decoded form of OrderPage.NextCursor. It remembers
+// the sort it was made for so it cannot be replayed against another one.
+type adminOrderCursor struct {
+	Sort string `json:"sort"`
+	repository.OrderCursor
+}
+
+// GetOrder retrieves an order by ID without checking who owns it
+func (s *orderService) GetOrder(ctx context.Context, orderID uint) (*models.Order, error) {
+	order, err := s.repo.FindByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return order, nil
+}
+
+// ListOrders returns a page of the orders of every user matching query
+func (s *orderService) ListOrders(ctx context.Context, query *models.AdminOrderQuery) (*models.OrderPage, error) {
+	filter, err := s.orderFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// one extra order tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	orders, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	page := &models.OrderPage{Orders: orders}
+	if len(orders) > limit {
+		page.Orders = orders[:limit]
+		page.HasMore = true
+
+		last := page.Orders[limit-1]
+		page.NextCursor, err = encodeOrderCursor(adminOrderCursor{
+			Sort: sortKey(filter),
+			OrderCursor: repository.OrderCursor{
+				CreatedAt: last.CreatedAt,
+				Total:     last.Total.Amount,
+				ID:        last.ID,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// orderFilter validates query and turns it into a repository filter
+func (s *orderService) orderFilter(query *models.AdminOrderQuery) (repository.OrderFilter, error) {
+	filter := repository.OrderFilter{
+		UserID:      query.UserID,
+		ProductID:   query.ProductID,
+		Search:      query.Search,
+		CreatedFrom: query.CreatedFrom,
+		CreatedTo:   query.CreatedTo,
+		MinTotal:    query.MinTotal,
+		MaxTotal:    query.MaxTotal,
+		Currency:    strings.ToUpper(query.Currency),
+		SortBy:      repository.OrderSortCreatedAt,
+		Desc:        true,
+		Limit:       query.Limit,
+	}
+
+	for _, status := range query.Status {
+		for _, status := range strings.Split(status, ",") {
+			status = strings.TrimSpace(status)
+			if status == "" {
+				continue
+			}
+			if !s.machine.IsState(status) {
+				return filter, fmt.Errorf("invalid order status: %s", status)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return filter, errors.New("invalid date range: created_from must be before created_to")
+	}
+	if filter.MinTotal != nil && filter.MaxTotal != nil && *filter.MinTotal > *filter.MaxTotal {
+		return filter, errors.New("invalid amount range: min_total must not be greater than max_total")
+	}
+	if filter.Currency == "" && (filter.MinTotal != nil || filter.MaxTotal != nil) {
+		filter.Currency = money.DefaultCurrency
+	}
+	if filter.Currency != "" && !money.ValidCurrency(filter.Currency) {
+		return filter, fmt.Errorf("invalid currency: %s", filter.Currency)
+	}
+
+	if query.Sort != "" {
+		filter.Desc = strings.HasPrefix(query.Sort, "-")
+		if strings.TrimPrefix(query.Sort, "-") == "total" {
+			filter.SortBy = repository.OrderSortTotal
+		}
+	}
+	// totals in different currencies cannot be compared
+	if filter.SortBy == repository.OrderSortTotal && filter.Currency == "" {
+		return filter, errors.New("invalid sort: sorting by total requires a currency")
+	}
+
+	if filter.Limit < 1 {
+		filter.Limit = defaultAdminOrderLimit
+	}
+	if filter.Limit > maxAdminOrderLimit {
+		filter.Limit = maxAdminOrderLimit
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeOrderCursor(query.Cursor)
+		if err != nil || cursor.Sort != sortKey(filter) {
+			return filter, errors.New("invalid cursor")
+		}
+		filter.After = &cursor.OrderCursor
+	}
+	return filter, nil
+}
+
+// sortKey names the sort of filter, e.g. -created_at
+func sortKey(filter repository.OrderFilter) string {
+	if filter.Desc {
+		return "-" + filter.SortBy
+	}
+	return filter.SortBy
+}
+
+func encodeOrderCursor(cursor adminOrderCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeOrderCursor(encoded string) (adminOrderCursor, error) {
+	var cursor adminOrderCursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+// BulkUpdateOrderStatus moves every order in req to req.Status through the
+// order state machine. Orders are changed one by one, so one failing order does
+// not stop the others; the result of each order is reported in request order.
+func (s *orderService) BulkUpdateOrderStatus(ctx context.Context, req *models.BulkOrderStatusRequest, actor models.Actor) ([]models.BulkOrderStatusResult, error) {
+	if !s.machine.IsState(req.Status) {
+		return nil, fmt.Errorf("invalid order status: %s", req.Status)
+	}
+
+	results := make([]models.BulkOrderStatusResult, 0, len(req.OrderIDs))
+	seen := make(map[uint]bool, len(req.OrderIDs))
+	for _, orderID := range req.OrderIDs {
+		if seen[orderID] {
+			continue
+		}
+		seen[orderID] = true
+
+		result := models.BulkOrderStatusResult{OrderID: orderID}
+		order, err := s.machine.Fire(ctx, statemachine.Request{
+			OrderID: orderID,
+			To:      req.Status,
+			Actor:   actor,
+			Reason:  req.Reason,
+		})
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+			result.Status = order.Status
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+func newAdminOrderService(t *testing.T) *orderService {
+	t.Helper()
+	db := newTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	return &orderService{
+		repo:    orders,
+		db:      db,
+		machine: statemachine.NewOrderMachine(db, orders, repository.NewOrderStatusHistoryRepository(db), outboxRepo),
+	}
+}
+
+func TestOrderFilter(t *testing.T) {
+	s := newAdminOrderService(t)
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	amount := func(v int64) *int64 { return &v }
+	createdAtCursor, err := encodeOrderCursor(adminOrderCursor{Sort: "-created_at", OrderCursor: repository.OrderCursor{ID: 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		query   models.AdminOrderQuery
+		wantErr string
+		check   func(t *testing.T, filter repository.OrderFilter)
+	}{
+		{
+			name:  "defaults",
+			query: models.AdminOrderQuery{},
+			check: func(t *testing.T, filter repository.OrderFilter) {
+				if filter.SortBy != repository.OrderSortCreatedAt || !filter.Desc || filter.Limit != defaultAdminOrderLimit || filter.Currency != "" {
+					t.Errorf("filter = %+v, want newest first, %d per page, any currency", filter, defaultAdminOrderLimit)
+				}
+			},
+		},
+		{
+			name:  "statuses",
+			query: models.AdminOrderQuery{Status: []string{"processing, shipped", "cancelled"}},
+			check: func(t *testing.T, filter repository.OrderFilter) {
+				if got := strings.Join(filter.Statuses, ","); got != "processing,shipped,cancelled" {
+					t.Errorf("statuses = %s", got)
+				}
+			},
+		},
+		{name: "unknown status", query: models.AdminOrderQuery{Status: []string{"lost"}}, wantErr: "invalid order status"},
+		{name: "empty date range", query: models.AdminOrderQuery{CreatedFrom: &now, CreatedTo: &earlier}, wantErr: "invalid date range"},
+		{name: "empty amount range", query: models.AdminOrderQuery{MinTotal: amount(500), MaxTotal: amount(100)}, wantErr: "invalid amount range"},
+		{
+			name:  "amount defaults the currency",
+			query: models.AdminOrderQuery{MinTotal: amount(100)},
+			check: func(t *testing.T, filter repository.OrderFilter) {
+				if filter.Currency != money.DefaultCurrency {
+					t.Errorf("currency = %q, want %s", filter.Currency, money.DefaultCurrency)
+				}
+			},
+		},
+		{name: "malformed currency", query: models.AdminOrderQuery{Currency: "baht"}, wantErr: "invalid currency"},
+		{name: "total without currency", query: models.AdminOrderQuery{Sort: "-total"}, wantErr: "requires a currency"},
+		{
+			name:  "total with currency",
+			query: models.AdminOrderQuery{Sort: "total", Currency: "usd"},
+			check: func(t *testing.T, filter repository.OrderFilter) {
+				if filter.SortBy != repository.OrderSortTotal || filter.Desc || filter.Currency != "USD" {
+					t.Errorf("filter = %+v, want ascending totals in USD", filter)
+				}
+			},
+		},
+		{
+			name:  "limit is capped",
+			query: models.AdminOrderQuery{Limit: 1000},
+			check: func(t *testing.T, filter repository.OrderFilter) {
+				if filter.Limit != maxAdminOrderLimit {
+					t.Errorf("limit = %d, want %d", filter.Limit, maxAdminOrderLimit)
+				}
+			},
+		},
+		{
+			name:  "cursor of the same sort",
+			query: models.AdminOrderQuery{Cursor: createdAtCursor},
+			check: func(t *testing.T, filter repository.OrderFilter) {
+				if filter.After == nil || filter.After.ID != 7 {
+					t.Errorf("after = %+v, want the cursor position", filter.After)
+				}
+			},
+		},
+		{name: "cursor of another sort", query: models.AdminOrderQuery{Cursor: createdAtCursor, Sort: "created_at"}, wantErr: "invalid cursor"},
+		{name: "malformed cursor", query: models.AdminOrderQuery{Cursor: "not a cursor"}, wantErr: "invalid cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := s.orderFilter(&tt.query)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("orderFilter() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("orderFilter() error = %v", err)
+			}
+			tt.check(t, filter)
+		})
+	}
+}
+
+func TestOrderCursorRoundTrip(t *testing.T) {
+	s := newAdminOrderService(t)
+	ctx := context.Background()
+
+	// Equal totals are ordered by ID, the USD order is filtered out
+	start := time.Now().Add(-time.Hour)
+	totals := []money.Money{
+		money.New(300, "THB"), money.New(100, "THB"), money.New(300, "THB"),
+		money.New(200, "THB"), money.New(900, "USD"), money.New(500, "THB"),
+	}
+	ids := make([]uint, len(totals))
+	for i, total := range totals {
+		order := &models.Order{UserID: 1, Status: models.OrderStatusPending, Total: total, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		if err := s.db.Create(order).Error; err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = order.ID
+	}
+
+	query := &models.AdminOrderQuery{Sort: "-total", Currency: "THB", Limit: 2}
+	var got []uint
+	var cursors []string
+	for {
+		page, err := s.ListOrders(ctx, query)
+		if err != nil {
+			t.Fatalf("ListOrders() error = %v", err)
+		}
+		for _, order := range page.Orders {
+			got = append(got, order.ID)
+		}
+		if !page.HasMore {
+			break
+		}
+		cursors = append(cursors, page.NextCursor)
+		query.Cursor = page.NextCursor
+	}
+
+	want := []uint{ids[5], ids[2], ids[0], ids[3], ids[1]}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+
+	// A cursor only continues the sort it was made for
+	_, err := s.ListOrders(ctx, &models.AdminOrderQuery{Sort: "-created_at", Cursor: cursors[0]})
+	if err == nil || !strings.Contains(err.Error(), "invalid cursor") {
+		t.Errorf("ListOrders() with the cursor of another sort error = %v, want invalid cursor", err)
+	}
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/cart"
+	grpcclient "github.com/ploezy/ecommerce-platform/order-service/internal/grpc/client"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+	productpb "github.com/ploezy/ecommerce-platform/proto/product"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type CartService interface {
+	GetCart(ctx context.Context, owner cart.Owner) (*models.CartResponse, error)
+	AddItem(ctx context.Context, owner cart.Owner, req *models.AddCartItemRequest) (*models.CartResponse, error)
+	UpdateItem(ctx context.Context, owner cart.Owner, productID uint, quantity int) (*models.CartResponse, error)
+	RemoveItem(ctx context.Context, owner cart.Owner, productID uint) (*models.CartResponse, error)
+	ClearCart(ctx context.Context, owner cart.Owner) error
+	MergeGuestCart(ctx context.Context, userID uint, guestID string) error
+	Checkout(ctx context.Context, userID uint, req *models.CheckoutRequest) (*models.Order, error)
+}
+
+// ProductCatalog looks up products for carts, grpcclient.ProductClient implements it
+type ProductCatalog interface {
+	GetProduct(ctx context.Context, productID uint32) (*productpb.ProductResponse, error)
+}
+
+type cartService struct {
+	store         *cart.Store
+	orderService  OrderService
+	productClient ProductCatalog
+	maxItems      int
+}
+
+func NewCartService(store *cart.Store, orderService OrderService, productClient ProductCatalog, maxItems int) CartService {
+	return &cartService{
+		store:         store,
+		orderService:  orderService,
+		productClient: productClient,
+		maxItems:      maxItems,
+	}
+}
+
+// GetCart returns a cart priced with the current product prices and stock
+func (s *cartService) GetCart(ctx context.Context, owner cart.Owner) (*models.CartResponse, error) {
+	items, err := s.store.Items(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	return s.price(ctx, items)
+}
+
+// AddItem adds quantity of a product to a cart. The product must exist and have
+// enough stock for the new quantity in the cart.
+func (s *cartService) AddItem(ctx context.Context, owner cart.Owner, req *models.AddCartItemRequest) (*models.CartResponse, error) {
+	items, err := s.store.Items(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	current, inCart := items[req.ProductID]
+	if !inCart && len(items) >= s.maxItems {
+		return nil, fmt.Errorf("invalid cart: a cart can hold at most %d products", s.maxItems)
+	}
+
+	quantity := current + req.Quantity
+	if err := s.checkStock(ctx, req.ProductID, quantity); err != nil {
+		return nil, err
+	}
+	if err := s.store.SetQuantity(ctx, owner, req.ProductID, quantity); err != nil {
+		return nil, err
+	}
+
+	return s.GetCart(ctx, owner)
+}
+
+// UpdateItem sets the quantity of a product that is already in a cart
+func (s *cartService) UpdateItem(ctx context.Context, owner cart.Owner, productID uint, quantity int) (*models.CartResponse, error) {
+	if quantity <= 0 {
+		return nil, errors.New("invalid quantity")
+	}
+
+	items, err := s.store.Items(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := items[productID]; !ok {
+		return nil, errors.New("cart item not found")
+	}
+
+	if err := s.checkStock(ctx, productID, quantity); err != nil {
+		return nil, err
+	}
+	if err := s.store.SetQuantity(ctx, owner, productID, quantity); err != nil {
+		return nil, err
+	}
+
+	return s.GetCart(ctx, owner)
+}
+
+// RemoveItem removes a product from a cart
+func (s *cartService) RemoveItem(ctx context.Context, owner cart.Owner, productID uint) (*models.CartResponse, error) {
+	if err := s.store.RemoveItem(ctx, owner, productID); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, owner)
+}
+
+// ClearCart removes every item from a cart
+func (s *cartService) ClearCart(ctx context.Context, owner cart.Owner) error {
+	return s.store.Clear(ctx, owner)
+}
+
+// MergeGuestCart moves the items of a guest cart into the cart of userID, e.g.
+// right after the guest signed in. Quantities of products in both carts are
+// added and clamped to the current stock, products that are gone or do not fit
+// in the cart any more are dropped. Merging the same guest cart again adds
+// nothing.
+func (s *cartService) MergeGuestCart(ctx context.Context, userID uint, guestID string) error {
+	guest, err := cart.GuestOwner(guestID)
+	if err != nil {
+		return err
+	}
+
+	items, err := s.store.Items(ctx, guest)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	limits := make(map[uint]int, len(items))
+	for productID := range items {
+		stock, err := s.stock(ctx, productID)
+		if err != nil && !errors.Is(err, errProductNotFound) {
+			return err
+		}
+		limits[productID] = stock
+	}
+
+	return s.store.Merge(ctx, guest, cart.UserOwner(userID), limits, s.maxItems)
+}
+
+// Checkout creates an order from the cart of userID and clears the cart. The
+// order is priced again by CreateOrder, so it always uses the current prices
+// and promotions.
+func (s *cartService) Checkout(ctx context.Context, userID uint, checkout *models.CheckoutRequest) (*models.Order, error) {
+	owner := cart.UserOwner(userID)
+	items, err := s.store.Items(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("invalid cart: cart is empty")
+	}
+
+	priced, err := s.price(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	if !priced.Valid {
+		return nil, errors.New("invalid cart: some items are no longer available")
+	}
+
+	req := &models.CreateOrderRequest{
+		Items:             make([]models.CreateOrderItemRequest, 0, len(priced.Items)),
+		CouponCode:        checkout.CouponCode,
+		ShippingAddressID: checkout.ShippingAddressID,
+		ShippingAddress:   checkout.ShippingAddress,
+	}
+	for _, item := range priced.Items {
+		req.Items = append(req.Items, models.CreateOrderItemRequest{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	order, err := s.orderService.CreateOrder(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Clear(ctx, owner); err != nil {
+		log.Printf("Failed to clear cart of user %d after order %d: %v", userID, order.ID, err)
+	}
+
+	return order, nil
+}
+
+// price looks up the current price and stock of every cart item, ordered by product ID
+func (s *cartService) price(ctx context.Context, items map[uint]int) (*models.CartResponse, error) {
+	productIDs := make([]uint, 0, len(items))
+	for productID := range items {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Slice(productIDs, func(i, j int) bool { return productIDs[i] < productIDs[j] })
+
+	resp := &models.CartResponse{
+		Items: make([]models.CartItemResponse, 0, len(items)),
+		Total: money.Zero(money.DefaultCurrency),
+		Valid: true,
+	}
+	// the cart total takes the currency of the first available item
+	priced := false
+	for _, productID := range productIDs {
+		item := models.CartItemResponse{
+			ProductID: productID,
+			Quantity:  items[productID],
+			Available: true,
+		}
+
+		productResp, err := s.productClient.GetProduct(ctx, uint32(productID))
+		if err != nil {
+			if status.Code(err) != codes.NotFound {
+				return nil, fmt.Errorf("failed to get product %d: %w", productID, err)
+			}
+			item.Available = false
+			item.Message = "product is no longer available"
+		} else {
+			product := productResp.Product
+			item.Name = product.Name
+			item.Price = grpcclient.UnitPrice(product)
+			item.Subtotal = item.Price.Mul(int64(item.Quantity))
+			switch {
+			case int(product.Stock) < item.Quantity:
+				item.Available = false
+				item.Message = fmt.Sprintf("only %d in stock", product.Stock)
+			case !priced:
+				resp.Total = money.Zero(item.Price.Currency)
+				priced = true
+			case !item.Price.SameCurrency(resp.Total):
+				item.Available = false
+				item.Message = fmt.Sprintf("priced in %s, the cart is in %s", item.Price.Currency, resp.Total.Currency)
+			}
+		}
+
+		if item.Available {
+			resp.Total, _ = resp.Total.Add(item.Subtotal)
+		} else {
+			resp.Valid = false
+		}
+		resp.ItemCount += item.Quantity
+		resp.Items = append(resp.Items, item)
+	}
+
+	return resp, nil
+}
+
+// checkStock verifies that a product exists and has quantity in stock
+func (s *cartService) checkStock(ctx context.Context, productID uint, quantity int) error {
+	stock, err := s.stock(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if stock < quantity {
+		return fmt.Errorf("insufficient stock for product %d: %d available", productID, stock)
+	}
+	return nil
+}
+
+// errProductNotFound is returned by stock for products that do not exist
+var errProductNotFound = errors.New("product not found")
+
+// stock returns the current stock of a product
+func (s *cartService) stock(ctx context.Context, productID uint) (int, error) {
+	resp, err := s.productClient.GetProduct(ctx, uint32(productID))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return 0, fmt.Errorf("%w: %d", errProductNotFound, productID)
+		}
+		return 0, fmt.Errorf("failed to get product %d: %w", productID, err)
+	}
+	return int(resp.Product.Stock), nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/cart"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	productpb "github.com/ploezy/ecommerce-platform/proto/product"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCatalog serves products with the stock in stock, other products are not found
+type fakeCatalog struct {
+	stock map[uint]int
+}
+
+func (c *fakeCatalog) GetProduct(ctx context.Context, productID uint32) (*productpb.ProductResponse, error) {
+	stock, ok := c.stock[uint(productID)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	return &productpb.ProductResponse{Product: &productpb.Product{
+		Id:        productID,
+		Name:      fmt.Sprintf("product %d", productID),
+		Stock:     int32(stock),
+		UnitPrice: &productpb.Money{Amount: 100, Currency: "THB"},
+	}}, nil
+}
+
+func newCartFixture(t *testing.T, stock map[uint]int) (CartService, *cart.Store) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	store := cart.NewStore(client, time.Hour)
+	return NewCartService(store, nil, &fakeCatalog{stock: stock}, 3), store
+}
+
+func quantities(resp *models.CartResponse) map[uint]int {
+	items := make(map[uint]int, len(resp.Items))
+	for _, item := range resp.Items {
+		items[item.ProductID] = item.Quantity
+	}
+	return items
+}
+
+func TestAddItemChecksStockAndProductLimit(t *testing.T) {
+	carts, _ := newCartFixture(t, map[uint]int{10: 3, 11: 1, 12: 1, 13: 1})
+	ctx := context.Background()
+	owner := cart.UserOwner(1)
+
+	if _, err := carts.AddItem(ctx, owner, &models.AddCartItemRequest{ProductID: 10, Quantity: 2}); err != nil {
+		t.Fatal(err)
+	}
+	// Adding again counts what is already in the cart
+	_, err := carts.AddItem(ctx, owner, &models.AddCartItemRequest{ProductID: 10, Quantity: 2})
+	if err == nil || !strings.Contains(err.Error(), "insufficient stock") {
+		t.Errorf("AddItem() over the stock error = %v, want insufficient stock", err)
+	}
+	if _, err := carts.AddItem(ctx, owner, &models.AddCartItemRequest{ProductID: 99, Quantity: 1}); err == nil {
+		t.Error("AddItem() of an unknown product error = nil")
+	}
+
+	for _, productID := range []uint{11, 12} {
+		if _, err := carts.AddItem(ctx, owner, &models.AddCartItemRequest{ProductID: productID, Quantity: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = carts.AddItem(ctx, owner, &models.AddCartItemRequest{ProductID: 13, Quantity: 1})
+	if err == nil || !strings.Contains(err.Error(), "at most 3 products") {
+		t.Errorf("AddItem() to a full cart error = %v, want the product limit", err)
+	}
+}
+
+func TestUpdateItemChecksStock(t *testing.T) {
+	carts, _ := newCartFixture(t, map[uint]int{10: 3})
+	ctx := context.Background()
+	owner := cart.UserOwner(1)
+
+	if _, err := carts.UpdateItem(ctx, owner, 10, 1); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("UpdateItem() of a product not in the cart error = %v, want not found", err)
+	}
+	if _, err := carts.AddItem(ctx, owner, &models.AddCartItemRequest{ProductID: 10, Quantity: 1}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := carts.UpdateItem(ctx, owner, 10, 3)
+	if err != nil || quantities(resp)[10] != 3 {
+		t.Fatalf("UpdateItem() = %v, %v, want 3 of product 10", resp, err)
+	}
+	if _, err := carts.UpdateItem(ctx, owner, 10, 4); err == nil {
+		t.Error("UpdateItem() over the stock error = nil")
+	}
+}
+
+func TestMergeGuestCartClampsToStockOnce(t *testing.T) {
+	carts, store := newCartFixture(t, map[uint]int{10: 4, 11: 2})
+	ctx := context.Background()
+	guestID := cart.NewGuestID()
+	guest, _ := cart.GuestOwner(guestID)
+	user := cart.UserOwner(1)
+
+	// The guest cart was filled before the stock ran low and a product went away
+	for productID, quantity := range map[uint]int{10: 3, 11: 5, 12: 1} {
+		if err := store.SetQuantity(ctx, guest, productID, quantity); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := carts.AddItem(ctx, user, &models.AddCartItemRequest{ProductID: 10, Quantity: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := carts.MergeGuestCart(ctx, 1, guestID); err != nil {
+			t.Fatalf("MergeGuestCart() attempt %d error = %v", i+1, err)
+		}
+	}
+
+	resp, err := carts.GetCart(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fmt.Sprint(quantities(resp)), fmt.Sprint(map[uint]int{10: 4, 11: 2}); got != want {
+		t.Errorf("merged cart = %s, want %s", got, want)
+	}
+	if !resp.Valid {
+		t.Error("merged cart is invalid, want every item in stock")
+	}
+	if guestItems, err := store.Items(ctx, guest); err != nil || len(guestItems) != 0 {
+		t.Errorf("guest cart after the merge = %v, %v, want it empty", guestItems, err)
+	}
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/correlation"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/pricing"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/promotion"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/saga"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
+	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const createOrderSagaType = "create_order"
+
+// CreateOrderSagaData is the state persisted between the steps of a create order saga
+type CreateOrderSagaData struct {
+	UserID          uint                      `json:"user_id"`
+	Lines           []CreateOrderSagaLine     `json:"lines"`
+	CouponCode      string                    `json:"coupon_code,omitempty"`
+	ShippingAddress models.ShippingAddress    `json:"shipping_address"`
+	ShippingCountry string                    `json:"shipping_country,omitempty"`
+	ShippingRegion  string                    `json:"shipping_region,omitempty"`
+	Subtotal        money.Money               `json:"subtotal"`
+	Discount        money.Money               `json:"discount"`
+	ShippingFee     money.Money               `json:"shipping_fee"`
+	Tax             money.Money               `json:"tax"`
+	TaxIncluded     money.Money               `json:"tax_included"`
+	Total           money.Money               `json:"total"`
+	Discounts       []CreateOrderSagaDiscount `json:"discounts,omitempty"`
+	ReservationID   string                    `json:"reservation_id,omitempty"`
+	OrderID         uint                      `json:"order_id,omitempty"`
+	CreatedAt       time.Time                 `json:"created_at,omitempty"`
+	CorrelationID   string                    `json:"correlation_id,omitempty"`
+}
+
+// CreateOrderSagaLine is a priced order line
+type CreateOrderSagaLine struct {
+	ProductID    uint                        `json:"product_id"`
+	Quantity     int                         `json:"quantity"`
+	Price        money.Money                 `json:"unit_price"`
+	Subtotal     money.Money                 `json:"line_total"`
+	Discount     money.Money                 `json:"discount"`
+	Tax          money.Money                 `json:"tax"`
+	TaxRate      int64                       `json:"tax_rate_bps"`
+	TaxInclusive bool                        `json:"tax_inclusive"`
+	Adjustments  []CreateOrderSagaAdjustment `json:"adjustments,omitempty"`
+}
+
+// CreateOrderSagaDiscount is a promotion applied to the order
+type CreateOrderSagaDiscount struct {
+	PromotionID uint        `json:"promotion_id"`
+	Code        string      `json:"code,omitempty"`
+	Name        string      `json:"name"`
+	Amount      money.Money `json:"amount"`
+}
+
+// CreateOrderSagaAdjustment is the part of a promotion taken off one line
+type CreateOrderSagaAdjustment struct {
+	PromotionID uint        `json:"promotion_id"`
+	Amount      money.Money `json:"amount"`
+}
+
+// newCreateOrderSaga defines the create order saga:
+//
+//  1. reserve_stock       reserve stock for every line     (compensate: release the reservation)
+//  2. create_order        insert the pending order         (compensate: cancel the order)
+//  3. commit_reservation  make the stock decrement final   (pivot, retried unless rejected)
+//  4. record_event        mark the stock committed, write order.created (retried, never compensated)
+//
+// The order is returned to the customer once the pivot has been tried, even when
+// it is still being retried. Until record_event runs the order cannot be
+// cancelled or expired, so a rejected pivot is undone by releasing the
+// reservation alone.
+func (s *orderService) newCreateOrderSaga() saga.Definition[CreateOrderSagaData] {
+	return saga.Definition[CreateOrderSagaData]{
+		Type: createOrderSagaType,
+		Steps: []saga.Step[CreateOrderSagaData]{
+			{
+				Name:       "reserve_stock",
+				Execute:    s.reserveStockStep,
+				Compensate: s.releaseStockStep,
+			},
+			{
+				Name:       "create_order",
+				ExecuteTx:  s.createOrderStep,
+				Compensate: s.cancelCreatedOrderStep,
+			},
+			{
+				Name:    "commit_reservation",
+				Execute: s.commitReservationStep,
+			},
+			{
+				Name:      "record_event",
+				ExecuteTx: s.recordOrderCreatedStep,
+			},
+		},
+		PivotStep: 2,
+	}
+}
+
+// reserveStockStep prices every line, applies promotions, shipping and tax and
+// reserves the stock in product-service
+func (s *orderService) reserveStockStep(ctx context.Context, sagaID string, data *CreateOrderSagaData) error {
+	if data.ReservationID != "" {
+		return nil
+	}
+
+	if err := s.priceOrder(ctx, data); err != nil {
+		return err
+	}
+
+	resp, err := s.productClient.ReserveStock(ctx, data.stockItems(), s.reservationTTL, sagaID)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New(resp.Message)
+	}
+
+	data.ReservationID = resp.ReservationId
+	return nil
+}
+
+// priceOrder fills in the prices, discounts, shipping fee and tax of the saga
+func (s *orderService) priceOrder(ctx context.Context, data *CreateOrderSagaData) error {
+	items := make([]models.CreateOrderItemRequest, 0, len(data.Lines))
+	for _, line := range data.Lines {
+		items = append(items, models.CreateOrderItemRequest{ProductID: line.ProductID, Quantity: line.Quantity})
+	}
+
+	dest := pricing.Destination{Country: data.ShippingCountry, Region: data.ShippingRegion}
+	basket, err := s.priceBasket(ctx, data.UserID, items, data.CouponCode, dest)
+	if err != nil {
+		return err
+	}
+
+	result, breakdown := basket.Result, basket.Pricing
+	for i, line := range basket.Lines {
+		data.Lines[i].Price = line.UnitPrice
+		data.Lines[i].Subtotal = line.Subtotal()
+		data.Lines[i].Discount = result.LineDiscounts[i]
+		data.Lines[i].Tax = breakdown.Lines[i].Amount
+		data.Lines[i].TaxRate = breakdown.Lines[i].RateBasisPoints
+		data.Lines[i].TaxInclusive = breakdown.Lines[i].Inclusive
+		data.Lines[i].Adjustments = nil
+		for _, applied := range result.Applied {
+			if applied.Lines[i].IsPositive() {
+				data.Lines[i].Adjustments = append(data.Lines[i].Adjustments, CreateOrderSagaAdjustment{
+					PromotionID: applied.Promotion.ID,
+					Amount:      applied.Lines[i],
+				})
+			}
+		}
+	}
+
+	data.Subtotal = breakdown.Subtotal
+	data.Discount = breakdown.Discount
+	data.ShippingFee = breakdown.Shipping
+	data.Tax = breakdown.Tax
+	data.TaxIncluded = breakdown.TaxIncluded
+	data.Total = breakdown.Total
+	data.Discounts = make([]CreateOrderSagaDiscount, 0, len(result.Applied))
+	for _, applied := range result.Applied {
+		data.Discounts = append(data.Discounts, CreateOrderSagaDiscount{
+			PromotionID: applied.Promotion.ID,
+			Code:        applied.Promotion.Code,
+			Name:        applied.Promotion.Name,
+			Amount:      applied.Amount,
+		})
+	}
+	return nil
+}
+
+// releaseStockStep returns reserved stock. A reservation that was never made is a no-op.
+// If the process died before the reservation ID was saved the product-service sweeper
+// releases it when its TTL expires.
+func (s *orderService) releaseStockStep(ctx context.Context, sagaID string, data *CreateOrderSagaData) error {
+	if data.ReservationID == "" {
+		return nil
+	}
+
+	resp, err := s.productClient.ReleaseStock(ctx, data.ReservationID)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New(resp.Message)
+	}
+	return nil
+}
+
+// createOrderStep inserts the order in the same transaction as the saga
+// checkpoint. The promotions it uses are locked and checked again, so
+// concurrent orders cannot use a promotion more often than allowed and an order
+// cannot use one that ended or was switched off after it was priced.
+func (s *orderService) createOrderStep(ctx context.Context, tx *gorm.DB, sagaID string, data *CreateOrderSagaData) error {
+	if err := s.checkPromotions(ctx, tx, data); err != nil {
+		return err
+	}
+
+	orderItems := make([]models.OrderItem, 0, len(data.Lines))
+	for _, line := range data.Lines {
+		adjustments := make([]models.OrderItemAdjustment, 0, len(line.Adjustments))
+		for _, adjustment := range line.Adjustments {
+			adjustments = append(adjustments, models.OrderItemAdjustment{
+				PromotionID: adjustment.PromotionID,
+				Amount:      adjustment.Amount,
+			})
+		}
+		orderItems = append(orderItems, models.OrderItem{
+			ProductID:    line.ProductID,
+			Quantity:     line.Quantity,
+			Price:        line.Price,
+			Subtotal:     line.Subtotal,
+			Discount:     line.Discount,
+			Tax:          line.Tax,
+			TaxRate:      line.TaxRate,
+			TaxInclusive: line.TaxInclusive,
+			Adjustments:  adjustments,
+		})
+	}
+
+	discounts := make([]models.OrderDiscount, 0, len(data.Discounts))
+	for _, discount := range data.Discounts {
+		discounts = append(discounts, models.OrderDiscount{
+			PromotionID: discount.PromotionID,
+			Code:        discount.Code,
+			Name:        discount.Name,
+			Amount:      discount.Amount,
+		})
+	}
+
+	order := &models.Order{
+		UserID:          data.UserID,
+		Subtotal:        data.Subtotal,
+		Discount:        data.Discount,
+		ShippingFee:     data.ShippingFee,
+		Tax:             data.Tax,
+		TaxIncluded:     data.TaxIncluded,
+		Total:           data.Total,
+		ShippingAddress: data.ShippingAddress,
+		Status:          models.OrderStatusPending,
+		StockPending:    true,
+		Items:           orderItems,
+		Discounts:       discounts,
+	}
+
+	if err := tx.WithContext(ctx).Create(order).Error; err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+
+	actor := models.Actor{Type: models.ActorTypeUser, ID: data.UserID}
+	if err := s.machine.RecordCreated(correlation.NewContext(ctx, data.CorrelationID), tx, order, actor); err != nil {
+		return err
+	}
+
+	data.OrderID = order.ID
+	data.CreatedAt = order.CreatedAt
+	return nil
+}
+
+// checkPromotions locks the promotions applied to the order and fails when one
+// of them was deleted, deactivated, expired or reached a usage limit since the
+// order was priced
+func (s *orderService) checkPromotions(ctx context.Context, tx *gorm.DB, data *CreateOrderSagaData) error {
+	if len(data.Discounts) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(data.Discounts))
+	for _, discount := range data.Discounts {
+		ids = append(ids, discount.PromotionID)
+	}
+
+	repo := s.promotionRepo.WithTx(tx)
+	promotions, err := repo.LockByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to lock promotions: %w", err)
+	}
+	locked := make(map[uint]bool, len(promotions))
+	for _, p := range promotions {
+		locked[p.ID] = true
+	}
+	for _, discount := range data.Discounts {
+		if !locked[discount.PromotionID] {
+			return fmt.Errorf("invalid promotion %s: promotion no longer exists", discount.Name)
+		}
+	}
+
+	now := time.Now()
+	for i := range promotions {
+		if err := promotion.CheckAvailable(&promotions[i], now); err != nil {
+			return fmt.Errorf("invalid promotion %s: promotion %w", promotions[i].Name, err)
+		}
+		usage, err := promotionUsage(ctx, repo, &promotions[i], data.UserID)
+		if err != nil {
+			return err
+		}
+		if err := promotion.CheckUsage(&promotions[i], usage); err != nil {
+			return fmt.Errorf("invalid promotion %s: promotion %w", promotions[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// cancelCreatedOrderStep cancels the order created by the saga. Stock is returned by
+// releaseStockStep, so the cancellation effects of the state machine are skipped.
+func (s *orderService) cancelCreatedOrderStep(ctx context.Context, sagaID string, data *CreateOrderSagaData) error {
+	if data.OrderID == 0 {
+		return nil
+	}
+
+	_, err := s.machine.Fire(correlation.NewContext(ctx, data.CorrelationID), statemachine.Request{
+		OrderID:     data.OrderID,
+		To:          models.OrderStatusCancelled,
+		Actor:       models.SystemActor,
+		Reason:      "order creation failed",
+		SkipEffects: true,
+	})
+	if errors.Is(err, statemachine.ErrInvalidTransition) {
+		// Already cancelled by an earlier attempt
+		return nil
+	}
+	return err
+}
+
+// commitReservationStep makes the reserved stock permanent
+func (s *orderService) commitReservationStep(ctx context.Context, sagaID string, data *CreateOrderSagaData) error {
+	resp, err := s.productClient.CommitReservation(ctx, data.ReservationID)
+	if err != nil {
+		// A missing reservation can never be committed. Any other error may
+		// hide a commit that went through, so the orchestrator retries it.
+		if status.Code(err) == codes.NotFound {
+			return saga.Reject(err)
+		}
+		return err
+	}
+	if !resp.Success {
+		return saga.Reject(errors.New(resp.Message))
+	}
+	return nil
+}
+
+// recordOrderCreatedStep marks the stock of the order committed, which lets it
+// be cancelled, and writes the order.created event to the outbox
+func (s *orderService) recordOrderCreatedStep(ctx context.Context, tx *gorm.DB, sagaID string, data *CreateOrderSagaData) error {
+	if err := s.repo.WithTx(tx).MarkStockCommitted(ctx, data.OrderID); err != nil {
+		return fmt.Errorf("failed to mark stock committed: %w", err)
+	}
+
+	items := make([]kafka.OrderItemEvent, 0, len(data.Lines))
+	for _, line := range data.Lines {
+		items = append(items, kafka.OrderItemEvent{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+			Price:     line.Price.Major(),
+			Subtotal:  line.Subtotal.Major(),
+			UnitPrice: line.Price,
+			LineTotal: line.Subtotal,
+			Discount:  line.Discount,
+			Tax:       line.Tax,
+		})
+	}
+
+	discounts := make([]kafka.OrderDiscountEvent, 0, len(data.Discounts))
+	for _, discount := range data.Discounts {
+		discounts = append(discounts, kafka.OrderDiscountEvent{
+			PromotionID: discount.PromotionID,
+			Code:        discount.Code,
+			Name:        discount.Name,
+			Amount:      discount.Amount,
+		})
+	}
+
+	event := kafka.OrderCreatedEvent{
+		OrderID:     data.OrderID,
+		UserID:      data.UserID,
+		TotalAmount: data.Total.Major(),
+		Subtotal:    data.Subtotal,
+		Discount:    data.Discount,
+		ShippingFee: data.ShippingFee,
+		Tax:         data.Tax,
+		TaxIncluded: data.TaxIncluded,
+		Total:       data.Total,
+		Discounts:   discounts,
+		ShippingAddress: kafka.ShippingAddressEvent{
+			RecipientName: data.ShippingAddress.RecipientName,
+			Phone:         data.ShippingAddress.Phone,
+			Line1:         data.ShippingAddress.Line1,
+			Line2:         data.ShippingAddress.Line2,
+			District:      data.ShippingAddress.District,
+			City:          data.ShippingAddress.City,
+			Region:        data.ShippingAddress.Region,
+			PostalCode:    data.ShippingAddress.PostalCode,
+			Country:       data.ShippingAddress.Country,
+		},
+		Status:    models.OrderStatusPending,
+		Items:     items,
+		CreatedAt: data.CreatedAt.UTC(),
+	}
+	envelope := kafka.NewEnvelope(kafka.EventOrderCreated, kafka.OrderCreatedSchemaVersion, data.CorrelationID, event)
+
+	return s.writeOrderEvent(ctx, tx, data.OrderID, kafka.TopicOrderCreated, envelope)
+}
+
+// stockItems converts the saga lines to reservation items
+func (d *CreateOrderSagaData) stockItems() map[uint32]int32 {
+	items := make(map[uint32]int32, len(d.Lines))
+	for _, line := range d.Lines {
+		items[uint32(line.ProductID)] += int32(line.Quantity)
+	}
+	return items
+}
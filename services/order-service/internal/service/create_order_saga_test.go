@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+	"gorm.io/gorm"
+)
+
+func TestCheckPromotionsOnLockedRows(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.Promotion{}); err != nil {
+		t.Fatal(err)
+	}
+	s := &orderService{promotionRepo: repository.NewPromotionRepository(db)}
+
+	past := time.Now().Add(-time.Hour)
+	create := func(name string, endsAt *time.Time) uint {
+		t.Helper()
+		p := &models.Promotion{Name: name, Type: models.PromotionTypePercentage, PercentOff: 5, Active: true, EndsAt: endsAt}
+		if err := db.Create(p).Error; err != nil {
+			t.Fatal(err)
+		}
+		return p.ID
+	}
+	running := create("running", nil)
+	expired := create("expired", &past)
+	deactivated := create("deactivated", nil)
+	if err := db.Model(&models.Promotion{}).Where("id = ?", deactivated).Update("active", false).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		promotionID uint
+		wantErr     string
+	}{
+		{"running", running, ""},
+		{"expired", expired, "has expired"},
+		{"deactivated", deactivated, "is not active"},
+		{"missing", 999, "no longer exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &CreateOrderSagaData{
+				UserID:    1,
+				Discounts: []CreateOrderSagaDiscount{{PromotionID: running, Name: "running"}, {PromotionID: tt.promotionID, Name: tt.name}},
+			}
+			err := s.checkPromotions(context.Background(), db, data)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkPromotions() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.HasPrefix(err.Error(), "invalid promotion") {
+				t.Errorf("checkPromotions() error = %v, want an invalid promotion that %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// placeOrder runs the create_order step of the saga, leaving the stock
+// reservation of the order uncommitted as when the pivot is being retried
+func placeOrder(t *testing.T, s *orderService, createdAt time.Time) *CreateOrderSagaData {
+	t.Helper()
+	data := &CreateOrderSagaData{
+		UserID:        1,
+		Lines:         []CreateOrderSagaLine{{ProductID: 10, Quantity: 2, Price: money.New(100, "THB"), Subtotal: money.New(200, "THB")}},
+		Subtotal:      money.New(200, "THB"),
+		Total:         money.New(200, "THB"),
+		ReservationID: "reservation-1",
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return s.createOrderStep(context.Background(), tx, "saga-1", data)
+	})
+	if err != nil {
+		t.Fatalf("createOrderStep() error = %v", err)
+	}
+	if err := s.db.Model(&models.Order{}).Where("id = ?", data.OrderID).Update("created_at", createdAt).Error; err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func restockCommands(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&models.OutboxEvent{}).Where("topic = ?", statemachine.TopicRestock).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestOrderWithUncommittedStockIsNotCancelled(t *testing.T) {
+	db := newTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	s := &orderService{
+		repo:       orders,
+		outboxRepo: outboxRepo,
+		db:         db,
+		payments:   &fakePayments{},
+		machine:    statemachine.NewOrderMachine(db, orders, repository.NewOrderStatusHistoryRepository(db), outboxRepo),
+	}
+	ctx := context.Background()
+	old := time.Now().Add(-time.Hour)
+
+	// The pivot failed without being rejected: the customer already has the order
+	rejected := placeOrder(t, s, old)
+	err := s.CancelOrder(ctx, rejected.OrderID, 1, "changed my mind")
+	if !errors.Is(err, statemachine.ErrInvalidTransition) || !strings.Contains(err.Error(), "still being placed") {
+		t.Errorf("CancelOrder() error = %v, want the order still being placed", err)
+	}
+	if expired, err := s.ExpirePendingOrders(ctx, time.Minute, 10); err != nil || expired != 0 {
+		t.Errorf("ExpirePendingOrders() = %d, %v, want the order left to the saga", expired, err)
+	}
+
+	// The resumed pivot is rejected: the rollback cancels the order and
+	// releases the reservation, nothing is restocked
+	if err := s.cancelCreatedOrderStep(ctx, "saga-1", rejected); err != nil {
+		t.Fatalf("cancelCreatedOrderStep() error = %v", err)
+	}
+	order, err := orders.FindByID(ctx, rejected.OrderID)
+	if err != nil || order.Status != models.OrderStatusCancelled {
+		t.Fatalf("order after the rollback = %v, %v, want cancelled", order, err)
+	}
+	if n := restockCommands(t, db); n != 0 {
+		t.Errorf("restock commands = %d, want none besides the released reservation", n)
+	}
+
+	// Once the pivot commits the order can expire and is restocked
+	committed := placeOrder(t, s, old)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return s.recordOrderCreatedStep(ctx, tx, "saga-2", committed)
+	})
+	if err != nil {
+		t.Fatalf("recordOrderCreatedStep() error = %v", err)
+	}
+	if expired, err := s.ExpirePendingOrders(ctx, time.Minute, 10); err != nil || expired != 1 {
+		t.Errorf("ExpirePendingOrders() = %d, %v, want the committed order expired", expired, err)
+	}
+	if n := restockCommands(t, db); n != 1 {
+		t.Errorf("restock commands = %d, want 1", n)
+	}
+}
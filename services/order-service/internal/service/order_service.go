@@ -2,55 +2,108 @@ package service
 
 import (
     "context"
+    "github.com/ploezy/ecommerce-platform/order-service/internal/correlation"
     "github.com/ploezy/ecommerce-platform/order-service/internal/models"
+    "github.com/ploezy/ecommerce-platform/order-service/internal/outbox"
+    "github.com/ploezy/ecommerce-platform/order-service/internal/pricing"
     "github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+    "github.com/ploezy/ecommerce-platform/order-service/internal/saga"
+    "github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
     grpcclient "github.com/ploezy/ecommerce-platform/order-service/internal/grpc/client"
     "github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+    "github.com/ploezy/ecommerce-platform/pkg/money"
+    "encoding/json"
     "errors"
     "fmt"
+    "log"
+    "strconv"
+    "time"
     "gorm.io/gorm"
 )
 
+// CancelReasonPaymentTimeout is the cancellation reason of orders that were not paid in time
+const CancelReasonPaymentTimeout = "payment_timeout"
+
+// ErrPaymentTooLow is returned when a captured payment does not cover the order total
+var ErrPaymentTooLow = errors.New("captured amount does not cover the order total")
+
+// ErrOrderNotFound is returned for orders that do not exist
+var ErrOrderNotFound = statemachine.ErrOrderNotFound
+
+// Outcomes of a create order saga that did not create an order (yet)
+var (
+    ErrOrderNotCreated = errors.New("order was not created")
+    ErrOrderInProgress = errors.New("order is still being created")
+)
+
 type OrderService interface {
     CreateOrder(ctx context.Context, userID uint, req *models.CreateOrderRequest) (*models.Order, error)
+    GetOrderBySaga(ctx context.Context, sagaID string, userID uint) (*models.Order, error)
+    QuoteOrder(ctx context.Context, userID uint, req *models.CreateOrderRequest) (*models.OrderQuote, error)
     GetOrderByID(ctx context.Context, orderID, userID uint) (*models.Order, error)
+    GetOrder(ctx context.Context, orderID uint) (*models.Order, error)
+    ListOrders(ctx context.Context, query *models.AdminOrderQuery) (*models.OrderPage, error)
     GetUserOrders(ctx context.Context, userID uint, page, limit int) ([]models.Order, int64, error)
-    UpdateOrderStatus(ctx context.Context, orderID uint, status string) error
-    CancelOrder(ctx context.Context, orderID, userID uint) error
+    GetOrderHistory(ctx context.Context, orderID, userID uint) ([]models.OrderStatusHistory, error)
+    UpdateOrderStatus(ctx context.Context, orderID uint, status string, actor models.Actor) error
+    BulkUpdateOrderStatus(ctx context.Context, req *models.BulkOrderStatusRequest, actor models.Actor) ([]models.BulkOrderStatusResult, error)
+    CancelOrder(ctx context.Context, orderID, userID uint, reason string) error
+    MarkOrderPaid(ctx context.Context, orderID uint, paymentIntentID string, captured money.Money) error
+    RefundCapture(ctx context.Context, orderID uint, paymentIntentID, reason string) error
+    ExpirePendingOrders(ctx context.Context, timeout time.Duration, limit int) (int, error)
+    ResumeSagas(ctx context.Context, staleAfter time.Duration) error
 }
 
 type orderService struct {
     repo           repository.OrderRepository
+    outboxRepo     repository.OutboxRepository
+    promotionRepo  repository.PromotionRepository
+    pricer         *pricing.Calculator
+    machine        *statemachine.Machine
     db             *gorm.DB
+    sagaRepo       repository.SagaRepository
     userClient     *grpcclient.UserClient
     productClient  *grpcclient.ProductClient
-    kafkaProducer  *kafka.Producer
+    payments       PaymentGateway
+    createOrderSaga *saga.Orchestrator[CreateOrderSagaData]
+    reservationTTL time.Duration
 }
 
 func NewOrderService(
     repo repository.OrderRepository,
+    sagaRepo repository.SagaRepository,
+    outboxRepo repository.OutboxRepository,
+    promotionRepo repository.PromotionRepository,
+    pricer *pricing.Calculator,
+    machine *statemachine.Machine,
     db *gorm.DB,
     userClient *grpcclient.UserClient,
     productClient *grpcclient.ProductClient,
-    kafkaProducer *kafka.Producer,
+    payments PaymentGateway,
+    reservationTTL time.Duration,
 ) OrderService {
-    return &orderService{
-        repo:          repo,
-        db:            db,
-        userClient:    userClient,
-        productClient: productClient,
-        kafkaProducer: kafkaProducer,
+    s := &orderService{
+        repo:           repo,
+        outboxRepo:     outboxRepo,
+        promotionRepo:  promotionRepo,
+        pricer:         pricer,
+        machine:        machine,
+        db:             db,
+        sagaRepo:       sagaRepo,
+        userClient:     userClient,
+        productClient:  productClient,
+        payments:       payments,
+        reservationTTL: reservationTTL,
     }
+    s.createOrderSaga = saga.NewOrchestrator(s.newCreateOrderSaga(), db, sagaRepo)
+    return s
 }
 
 // GetOrderByID retrieves an order by ID with authorization check
 func (s *orderService) GetOrderByID(ctx context.Context, orderID, userID uint) (*models.Order, error) {
-    order, err := s.repo.FindByID(ctx, orderID)
+    order, err := s.GetOrder(ctx, orderID)
     if err != nil {
-        if errors.Is(err, gorm.ErrRecordNotFound) {
-            return nil, errors.New("order not found")
-        }
-        return nil, fmt.Errorf("failed to get order: %w", err)
+        return nil, err
     }
     
     if order.UserID != userID {
@@ -79,202 +132,235 @@ func (s *orderService) GetUserOrders(ctx context.Context, userID uint, page, lim
     return orders, total, nil
 }
 
-// UpdateOrderStatus updates the status of an order with validation
-func (s *orderService) UpdateOrderStatus(ctx context.Context, orderID uint, status string) error {
-    validStatuses := map[string]bool{
-        models.OrderStatusPending:    true,
-        models.OrderStatusProcessing: true,
-        models.OrderStatusShipped:    true,
-        models.OrderStatusDelivered:  true,
-        models.OrderStatusCancelled:  true,
-    }
-    
-    if !validStatuses[status] {
-        return errors.New("invalid order status")
+// GetOrderHistory returns the status transitions of an order owned by userID
+func (s *orderService) GetOrderHistory(ctx context.Context, orderID, userID uint) ([]models.OrderStatusHistory, error) {
+    if _, err := s.GetOrderByID(ctx, orderID, userID); err != nil {
+        return nil, err
     }
     
-    order, err := s.repo.FindByID(ctx, orderID)
+    history, err := s.machine.History(ctx, orderID)
     if err != nil {
-        if errors.Is(err, gorm.ErrRecordNotFound) {
-            return errors.New("order not found")
-        }
-        return fmt.Errorf("failed to get order: %w", err)
+        return nil, fmt.Errorf("failed to get order history: %w", err)
     }
     
-    if !s.isValidStatusTransition(order.Status, status) {
-        return fmt.Errorf("cannot change status from %s to %s", order.Status, status)
-    }
-    
-    if err := s.repo.UpdateStatus(ctx, orderID, status); err != nil {
-        return fmt.Errorf("failed to update order status: %w", err)
-    }
-    
-    event := kafka.OrderStatusChangedEvent{
-        OrderID:   orderID,
-        OldStatus: order.Status,
-        NewStatus: status,
-    }
-    
-    if err := s.kafkaProducer.SendOrderStatusChanged(event); err != nil {
-        fmt.Printf("Warning: failed to send kafka event: %v\n", err)
-    }
-    
-    return nil
+    return history, nil
 }
 
-// CancelOrder cancels an order and restores product stock
-func (s *orderService) CancelOrder(ctx context.Context, orderID, userID uint) error {
-    order, err := s.repo.FindByID(ctx, orderID)
-    if err != nil {
-        if errors.Is(err, gorm.ErrRecordNotFound) {
-            return errors.New("order not found")
+// UpdateOrderStatus moves an order to a new status through the order state machine
+func (s *orderService) UpdateOrderStatus(ctx context.Context, orderID uint, status string, actor models.Actor) error {
+    _, err := s.machine.Fire(ctx, statemachine.Request{
+        OrderID: orderID,
+        To:      status,
+        Actor:   actor,
+    })
+    return err
+}
+
+// MarkOrderPaid moves a pending order to processing once its payment has been
+// captured. captured is the total captured for the order so far.
+func (s *orderService) MarkOrderPaid(ctx context.Context, orderID uint, paymentIntentID string, captured money.Money) error {
+    _, err := s.machine.Fire(ctx, statemachine.Request{
+        OrderID: orderID,
+        To:      models.OrderStatusProcessing,
+        Actor:   models.SystemActor,
+        Reason:  "payment captured: " + paymentIntentID,
+        Check: func(order *models.Order) error {
+            if cmp, err := captured.Cmp(order.Total); err != nil || cmp < 0 {
+                return fmt.Errorf("%w: captured %s, total %s", ErrPaymentTooLow, captured, order.Total)
+            }
+            return nil
+        },
+        Apply: func(ctx context.Context, tx *gorm.DB, order *models.Order) error {
+            order.PaymentIntentID = paymentIntentID
+            return s.repo.WithTx(tx).SetPaymentIntent(ctx, order.ID, paymentIntentID)
+        },
+    })
+    return err
+}
+
+// RefundCapture refunds a captured payment intent that MarkOrderPaid could not
+// apply, e.g. because the order was cancelled, expired or is missing. Nothing
+// is refunded when the intent is the one that paid the order, which is a
+// redelivered capture. The refund command is keyed by the intent, so a
+// redelivery does not refund twice.
+func (s *orderService) RefundCapture(ctx context.Context, orderID uint, paymentIntentID, reason string) error {
+    return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        order, err := s.repo.WithTx(tx).FindByIDForUpdate(ctx, orderID)
+        if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+            return fmt.Errorf("failed to load order: %w", err)
         }
-        return fmt.Errorf("failed to get order: %w", err)
-    }
-    
-    if order.UserID != userID {
-        return errors.New("unauthorized: order does not belong to this user")
-    }
-    
-    if order.Status != models.OrderStatusPending {
-        return fmt.Errorf("cannot cancel order with status: %s (only pending orders can be cancelled)", order.Status)
-    }
-    
-    if err := s.repo.UpdateStatus(ctx, orderID, models.OrderStatusCancelled); err != nil {
-        return fmt.Errorf("failed to cancel order: %w", err)
-    }
-    
-    for _, item := range order.Items {
-        _, err := s.productClient.UpdateStock(ctx, uint32(item.ProductID), int32(item.Quantity))
-        if err != nil {
-            fmt.Printf("Warning: failed to restore stock for product %d: %v\n", item.ProductID, err)
+        if order != nil && order.PaymentIntentID == paymentIntentID {
+            return nil
         }
-    }
-    
-    event := kafka.OrderCancelledEvent{
+
+        return statemachine.WriteRefund(ctx, tx, s.outboxRepo, correlation.FromContext(ctx), statemachine.RefundCommand{
+            Key:             statemachine.CaptureRefundKey(paymentIntentID),
+            OrderID:         orderID,
+            PaymentIntentID: paymentIntentID,
+            Reason:          reason,
+        })
+    })
+}
+
+// CancelOrder cancels an order of userID. Stock is restored by the state machine.
+func (s *orderService) CancelOrder(ctx context.Context, orderID, userID uint, reason string) error {
+    _, err := s.machine.Fire(ctx, statemachine.Request{
         OrderID: orderID,
-        UserID:  userID,
-    }
-    
-    if err := s.kafkaProducer.SendOrderCancelled(event); err != nil {
-        fmt.Printf("Warning: failed to send kafka event: %v\n", err)
-    }
-    
-    return nil
+        To:      models.OrderStatusCancelled,
+        Actor:   models.Actor{Type: models.ActorTypeUser, ID: userID},
+        Reason:  reason,
+        Check: func(order *models.Order) error {
+            if order.UserID != userID {
+                return errors.New("unauthorized: order does not belong to this user")
+            }
+            return nil
+        },
+    })
+    return err
 }
 
-// CreateOrder creates a new order with validation and stock management
-func (s *orderService) CreateOrder(ctx context.Context, userID uint, req *models.CreateOrderRequest) (*models.Order, error) {
-    if len(req.Items) == 0 {
-        return nil, errors.New("order must have at least one item")
+// ExpirePendingOrders cancels up to limit orders that have been pending for
+// longer than timeout, through the same transition as CancelOrder. The payments
+// of each order are voided first so they cannot be captured after the cancel;
+// an order whose payment was captured already is skipped and moves to
+// processing with its payment.captured event. When the void is declined the
+// order is cancelled anyway and a late capture is refunded by RefundCapture.
+// The state machine locks each order and checks its status again before
+// cancelling it, so replicas running this at the same time never cancel an
+// order twice or cancel one that was paid in the meantime.
+func (s *orderService) ExpirePendingOrders(ctx context.Context, timeout time.Duration, limit int) (int, error) {
+    cutoff := time.Now().Add(-timeout)
+    ids, err := s.repo.FindPendingIDsCreatedBefore(ctx, cutoff, limit)
+    if err != nil {
+        return 0, fmt.Errorf("failed to find expired orders: %w", err)
     }
-    
-    tx := s.db.Begin()
-    defer func() {
-        if r := recover(); r != nil {
-            tx.Rollback()
-        }
-    }()
-    
-    var totalAmount float64
-    orderItems := make([]models.OrderItem, 0, len(req.Items))
-    
-    for _, item := range req.Items {
-        if item.Quantity <= 0 {
-            tx.Rollback()
-            return nil, fmt.Errorf("invalid quantity for product %d", item.ProductID)
-        }
-        
-        productResp, err := s.productClient.GetProduct(ctx, uint32(item.ProductID))
+
+    cancelled := 0
+    for _, id := range ids {
+        voided, err := s.payments.VoidOrderPayments(ctx, id)
         if err != nil {
-            tx.Rollback()
-            return nil, fmt.Errorf("failed to get product %d: %w", item.ProductID, err)
+            if ctx.Err() != nil {
+                return cancelled, ctx.Err()
+            }
+            // Retried by the next sweep
+            log.Printf("Failed to void payments of expired order %d: %v", id, err)
+            continue
         }
-        
-        stockResp, err := s.productClient.CheckStock(ctx, uint32(item.ProductID), int32(item.Quantity))
-        if err != nil {
-            tx.Rollback()
-            return nil, fmt.Errorf("failed to check stock for product %d: %w", item.ProductID, err)
+        if voided.Captured {
+            continue
         }
-        
-        // ใช้ CurrentStock แทน Stock
-        if !stockResp.Available {
-            tx.Rollback()
-            return nil, fmt.Errorf("product %d has insufficient stock (available: %d, requested: %d)", 
-                item.ProductID, stockResp.CurrentStock, item.Quantity)
+        if !voided.Success {
+            log.Printf("Voiding payments of expired order %d declined, cancelling it anyway: %s", id, voided.Message)
         }
-        
-        // ใช้ Price ได้เลย
-        price := productResp.Product.Price
-        subtotal := price * float64(item.Quantity)
-        totalAmount += subtotal
-        
-        orderItem := models.OrderItem{
-            ProductID: item.ProductID,
-            Quantity:  item.Quantity,
-            Price:     price,
-            Subtotal:  subtotal,
+
+        _, err = s.machine.Fire(ctx, statemachine.Request{
+            OrderID: id,
+            To:      models.OrderStatusCancelled,
+            Actor:   models.SystemActor,
+            Reason:  CancelReasonPaymentTimeout,
+        })
+        if err != nil {
+            if ctx.Err() != nil {
+                return cancelled, ctx.Err()
+            }
+            // Paid or cancelled since the query ran
+            if errors.Is(err, statemachine.ErrInvalidTransition) {
+                continue
+            }
+            log.Printf("Failed to cancel expired order %d: %v", id, err)
+            continue
         }
-        orderItems = append(orderItems, orderItem)
+        cancelled++
     }
-    
-    order := &models.Order{
-        UserID:      userID,
-        TotalAmount: totalAmount,
-        Status:      models.OrderStatusPending,
-        Items:       orderItems,
+
+    return cancelled, nil
+}
+
+// CreateOrder creates a new order through the create order saga so that stock
+// taken for earlier items is given back when a later step fails. Automatic
+// promotions and the coupon of the request are applied when the order is priced,
+// followed by the shipping fee and tax for the shipping address. The address is
+// copied onto the order.
+func (s *orderService) CreateOrder(ctx context.Context, userID uint, req *models.CreateOrderRequest) (*models.Order, error) {
+    if err := validateOrderItems(req.Items); err != nil {
+        return nil, err
     }
-    
-    if err := tx.Create(order).Error; err != nil {
-        tx.Rollback()
-        return nil, fmt.Errorf("failed to create order: %w", err)
+
+    address, err := s.shippingAddress(ctx, userID, req)
+    if err != nil {
+        return nil, err
+    }
+    if address == nil {
+        return nil, errors.New("invalid order: a shipping address is required")
+    }
+
+    dest, err := s.pricer.Destination(address.Country, address.Region)
+    if err != nil {
+        return nil, err
     }
     
-    for _, item := range orderItems {
-        _, err := s.productClient.UpdateStock(ctx, uint32(item.ProductID), -int32(item.Quantity))
-        if err != nil {
-            tx.Rollback()
-            return nil, fmt.Errorf("failed to update stock for product %d: %w", item.ProductID, err)
-        }
+    data := &CreateOrderSagaData{
+        UserID:          userID,
+        Lines:           make([]CreateOrderSagaLine, 0, len(req.Items)),
+        CouponCode:      req.CouponCode,
+        ShippingAddress: *address,
+        ShippingCountry: dest.Country,
+        ShippingRegion:  dest.Region,
+        CorrelationID:   correlation.FromContext(ctx),
     }
     
-    if err := tx.Commit().Error; err != nil {
-        return nil, fmt.Errorf("failed to commit transaction: %w", err)
+    for _, item := range req.Items {
+        data.Lines = append(data.Lines, CreateOrderSagaLine{
+            ProductID: item.ProductID,
+            Quantity:  item.Quantity,
+        })
     }
     
-    event := kafka.OrderCreatedEvent{
-        OrderID:     order.ID,
-        UserID:      order.UserID,
-        TotalAmount: order.TotalAmount,
+    if _, err := s.createOrderSaga.Run(ctx, data); err != nil {
+        return nil, err
     }
     
-    if err := s.kafkaProducer.SendOrderCreated(event); err != nil {
-        fmt.Printf("Warning: failed to send kafka event: %v\n", err)
+    order, err := s.repo.FindByID(ctx, data.OrderID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get order: %w", err)
     }
     
     return order, nil
 }
 
-// isValidStatusTransition checks if status transition is valid
-func (s *orderService) isValidStatusTransition(oldStatus, newStatus string) bool {
-    if oldStatus == newStatus {
-        return false
+// GetOrderBySaga returns the order created by the create order saga with
+// sagaID. It returns ErrOrderNotCreated when the saga never started or was
+// compensated, and ErrOrderInProgress while it is still running.
+func (s *orderService) GetOrderBySaga(ctx context.Context, sagaID string, userID uint) (*models.Order, error) {
+    sagaRecord, err := s.sagaRepo.FindByID(ctx, sagaID)
+    if err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, ErrOrderNotCreated
+        }
+        return nil, fmt.Errorf("failed to get saga: %w", err)
     }
-    
-    validTransitions := map[string][]string{
-        models.OrderStatusPending:    {models.OrderStatusProcessing, models.OrderStatusCancelled},
-        models.OrderStatusProcessing: {models.OrderStatusShipped},
-        models.OrderStatusShipped:    {models.OrderStatusDelivered},
-        models.OrderStatusDelivered:  {},
-        models.OrderStatusCancelled:  {},
+
+    switch sagaRecord.Status {
+    case models.SagaStatusCompensated:
+        return nil, ErrOrderNotCreated
+    case models.SagaStatusCompleted:
+    default:
+        return nil, ErrOrderInProgress
     }
-    
-    allowedStatuses := validTransitions[oldStatus]
-    for _, allowed := range allowedStatuses {
-        if newStatus == allowed {
-            return true
-        }
+
+    var data CreateOrderSagaData
+    if err := json.Unmarshal([]byte(sagaRecord.Payload), &data); err != nil {
+        return nil, fmt.Errorf("failed to decode saga %s: %w", sagaID, err)
     }
-    
-    return false
-}
\ No newline at end of file
+    return s.GetOrderByID(ctx, data.OrderID, userID)
+}
+
+// ResumeSagas compensates or finishes create order sagas interrupted by a restart
+func (s *orderService) ResumeSagas(ctx context.Context, staleAfter time.Duration) error {
+    return s.createOrderSaga.Resume(ctx, staleAfter)
+}
+
+// writeOrderEvent stores an order event in the outbox inside tx, keyed by order ID
+func (s *orderService) writeOrderEvent(ctx context.Context, tx *gorm.DB, orderID uint, topic string, envelope kafka.Envelope) error {
+    return outbox.Write(ctx, tx, s.outboxRepo, outbox.AggregateOrder, strconv.FormatUint(uint64(orderID), 10), topic, envelope)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+type OutboxService interface {
+	ListEvents(ctx context.Context, status string, page, limit int) ([]models.OutboxEvent, int64, error)
+	GetEvent(ctx context.Context, id uint) (*models.OutboxEvent, error)
+	ReplayEvent(ctx context.Context, id uint) (*models.OutboxEvent, error)
+}
+
+type outboxService struct {
+	repo repository.OutboxRepository
+}
+
+func NewOutboxService(repo repository.OutboxRepository) OutboxService {
+	return &outboxService{repo: repo}
+}
+
+// ListEvents lists outbox events filtered by status with pagination
+func (s *outboxService) ListEvents(ctx context.Context, status string, page, limit int) ([]models.OutboxEvent, int64, error) {
+	if status != "" &&
+		status != models.OutboxStatusPending &&
+		status != models.OutboxStatusSent &&
+		status != models.OutboxStatusFailed {
+		return nil, 0, errors.New("invalid outbox status")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	events, total, err := s.repo.FindByStatus(ctx, status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	return events, total, nil
+}
+
+// GetEvent retrieves an outbox event by ID
+func (s *outboxService) GetEvent(ctx context.Context, id uint) (*models.OutboxEvent, error) {
+	event, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("outbox event not found")
+		}
+		return nil, fmt.Errorf("failed to get outbox event: %w", err)
+	}
+	return event, nil
+}
+
+// ReplayEvent puts a failed event back in the queue so the relay publishes it again
+func (s *outboxService) ReplayEvent(ctx context.Context, id uint) (*models.OutboxEvent, error) {
+	event, err := s.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Status != models.OutboxStatusFailed {
+		return nil, fmt.Errorf("cannot replay outbox event with status: %s (only failed events can be replayed)", event.Status)
+	}
+
+	event.Status = models.OutboxStatusPending
+	event.Attempts = 0
+	event.NextAttemptAt = time.Now()
+
+	if err := s.repo.Save(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to replay outbox event: %w", err)
+	}
+	return event, nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	grpcclient "github.com/ploezy/ecommerce-platform/order-service/internal/grpc/client"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/pricing"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/promotion"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// pricedBasket is a basket priced with the current product prices, promotions,
+// shipping rates and tax rules
+type pricedBasket struct {
+	Lines   []promotion.Line
+	Names   []string
+	Weights []int
+	Result  *promotion.Result
+	Pricing *pricing.Breakdown
+}
+
+// priceBasket prices items with the current product prices, then applies every
+// automatic promotion userID may use and the coupon, if any. An unusable coupon
+// is an error, automatic promotions that do not apply are left out. Shipping
+// and tax are calculated last, on the discounted lines.
+func (s *orderService) priceBasket(ctx context.Context, userID uint, items []models.CreateOrderItemRequest, couponCode string, dest pricing.Destination) (*pricedBasket, error) {
+	basket := &pricedBasket{
+		Lines:   make([]promotion.Line, 0, len(items)),
+		Names:   make([]string, 0, len(items)),
+		Weights: make([]int, 0, len(items)),
+	}
+	for _, item := range items {
+		productResp, err := s.productClient.GetProduct(ctx, uint32(item.ProductID))
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil, fmt.Errorf("product %d not found", item.ProductID)
+			}
+			return nil, fmt.Errorf("failed to get product %d: %w", item.ProductID, err)
+		}
+
+		product := productResp.Product
+		price := grpcclient.UnitPrice(product)
+		if len(basket.Lines) > 0 && !price.SameCurrency(basket.Lines[0].UnitPrice) {
+			return nil, errors.New("invalid order: products are priced in different currencies")
+		}
+		basket.Lines = append(basket.Lines, promotion.Line{
+			ProductID: item.ProductID,
+			Category:  product.Category,
+			Quantity:  item.Quantity,
+			UnitPrice: price,
+		})
+		basket.Names = append(basket.Names, product.Name)
+		basket.Weights = append(basket.Weights, int(product.WeightGrams))
+	}
+
+	promotions, err := s.applicablePromotions(ctx, userID, basket.Lines, couponCode)
+	if err != nil {
+		return nil, err
+	}
+	basket.Result = promotion.Apply(basket.Lines, promotions)
+
+	lines := make([]pricing.Line, 0, len(basket.Lines))
+	for i, line := range basket.Lines {
+		lines = append(lines, pricing.Line{
+			Category:    line.Category,
+			Quantity:    line.Quantity,
+			WeightGrams: basket.Weights[i],
+			Subtotal:    line.Subtotal(),
+			Discount:    basket.Result.LineDiscounts[i],
+		})
+	}
+	basket.Pricing, err = s.pricer.Calculate(dest, lines)
+	if err != nil {
+		return nil, err
+	}
+	return basket, nil
+}
+
+// applicablePromotions returns the automatic promotions that apply to lines,
+// oldest first, followed by the coupon
+func (s *orderService) applicablePromotions(ctx context.Context, userID uint, lines []promotion.Line, couponCode string) ([]models.Promotion, error) {
+	now := time.Now()
+
+	automatic, err := s.promotionRepo.FindAutomatic(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotions: %w", err)
+	}
+
+	promotions := make([]models.Promotion, 0, len(automatic)+1)
+	for i := range automatic {
+		usage, err := promotionUsage(ctx, s.promotionRepo, &automatic[i], userID)
+		if err != nil {
+			return nil, err
+		}
+		if promotion.Check(&automatic[i], usage, lines, now) == nil {
+			promotions = append(promotions, automatic[i])
+		}
+	}
+
+	code := promotion.NormalizeCode(couponCode)
+	if code == "" {
+		return promotions, nil
+	}
+
+	coupon, err := s.promotionRepo.FindByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("invalid coupon %s: no such coupon", code)
+		}
+		return nil, fmt.Errorf("failed to get coupon: %w", err)
+	}
+	usage, err := promotionUsage(ctx, s.promotionRepo, coupon, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := promotion.Check(coupon, usage, lines, now); err != nil {
+		return nil, fmt.Errorf("invalid coupon %s: coupon %w", code, err)
+	}
+
+	return append(promotions, *coupon), nil
+}
+
+// promotionUsage counts the uses of a promotion by orders that are not
+// cancelled. Promotions without limits are not counted.
+func promotionUsage(ctx context.Context, repo repository.PromotionRepository, p *models.Promotion, userID uint) (promotion.Usage, error) {
+	if p.UsageLimit == 0 && p.PerUserLimit == 0 {
+		return promotion.Usage{}, nil
+	}
+	total, byUser, err := repo.CountUsage(ctx, p.ID, userID)
+	if err != nil {
+		return promotion.Usage{}, fmt.Errorf("failed to count usage of promotion %d: %w", p.ID, err)
+	}
+	return promotion.Usage{Total: total, ByUser: byUser}, nil
+}
+
+// QuoteOrder prices a basket exactly like CreateOrder would, without creating
+// an order or reserving stock. Without a shipping address the shipping fee and
+// tax are estimated for the country and region of the request.
+func (s *orderService) QuoteOrder(ctx context.Context, userID uint, req *models.CreateOrderRequest) (*models.OrderQuote, error) {
+	if err := validateOrderItems(req.Items); err != nil {
+		return nil, err
+	}
+
+	address, err := s.shippingAddress(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	country, region := req.ShippingCountry, req.ShippingRegion
+	if address != nil {
+		country, region = address.Country, address.Region
+	}
+
+	dest, err := s.pricer.Destination(country, region)
+	if err != nil {
+		return nil, err
+	}
+
+	basket, err := s.priceBasket(ctx, userID, req.Items, req.CouponCode, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	result, breakdown := basket.Result, basket.Pricing
+	quote := &models.OrderQuote{
+		Items:           make([]models.OrderQuoteItem, 0, len(basket.Lines)),
+		Discounts:       make([]models.OrderQuoteDiscount, 0, len(result.Applied)),
+		Subtotal:        breakdown.Subtotal,
+		Discount:        breakdown.Discount,
+		ShippingFee:     breakdown.Shipping,
+		Tax:             breakdown.Tax,
+		TaxIncluded:     breakdown.TaxIncluded,
+		Total:           breakdown.Total,
+		CouponCode:      promotion.NormalizeCode(req.CouponCode),
+		ShippingAddress: address,
+		ShippingCountry: dest.Country,
+		ShippingRegion:  dest.Region,
+		ShippingZone:    breakdown.ShippingZone,
+		WeightGrams:     breakdown.WeightGrams,
+	}
+	for i, line := range basket.Lines {
+		subtotal := line.Subtotal()
+		tax := breakdown.Lines[i]
+		total, _ := subtotal.Sub(result.LineDiscounts[i])
+		if !tax.Inclusive {
+			total.Amount += tax.Amount.Amount
+		}
+		quote.Items = append(quote.Items, models.OrderQuoteItem{
+			ProductID: line.ProductID,
+			Name:      basket.Names[i],
+			Quantity:  line.Quantity,
+			Price:     line.UnitPrice,
+			Subtotal:  subtotal,
+			Discount:  result.LineDiscounts[i],
+			Tax:       tax.Amount,
+			TaxRate:   tax.RateBasisPoints,
+			Total:     total,
+		})
+	}
+	for _, applied := range result.Applied {
+		quote.Discounts = append(quote.Discounts, models.OrderQuoteDiscount{
+			PromotionID: applied.Promotion.ID,
+			Code:        applied.Promotion.Code,
+			Name:        applied.Promotion.Name,
+			Amount:      applied.Amount,
+		})
+	}
+
+	return quote, nil
+}
+
+// validateOrderItems checks the items of an order request
+func validateOrderItems(items []models.CreateOrderItemRequest) error {
+	if len(items) == 0 {
+		return errors.New("order must have at least one item")
+	}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("invalid quantity for product %d", item.ProductID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/promotion"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+	"gorm.io/gorm"
+)
+
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, req *models.PromotionRequest) (*models.Promotion, error)
+	UpdatePromotion(ctx context.Context, id uint, req *models.PromotionRequest) (*models.Promotion, error)
+	GetPromotion(ctx context.Context, id uint) (*models.Promotion, error)
+	ListPromotions(ctx context.Context, page, limit int) ([]models.Promotion, int64, error)
+}
+
+type promotionService struct {
+	repo repository.PromotionRepository
+}
+
+func NewPromotionService(repo repository.PromotionRepository) PromotionService {
+	return &promotionService{repo: repo}
+}
+
+// CreatePromotion creates a coupon, or an automatic promotion when no code is given
+func (s *promotionService) CreatePromotion(ctx context.Context, req *models.PromotionRequest) (*models.Promotion, error) {
+	p := &models.Promotion{}
+	if err := s.apply(ctx, p, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return p, nil
+}
+
+// UpdatePromotion replaces a promotion. Orders that already used it keep their
+// discounts. Set Active to false to end a promotion early.
+func (s *promotionService) UpdatePromotion(ctx context.Context, id uint, req *models.PromotionRequest) (*models.Promotion, error) {
+	p, err := s.GetPromotion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(ctx, p, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to update promotion: %w", err)
+	}
+	return p, nil
+}
+
+// GetPromotion returns a promotion by ID
+func (s *promotionService) GetPromotion(ctx context.Context, id uint) (*models.Promotion, error) {
+	p, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promotion not found")
+		}
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+	return p, nil
+}
+
+// ListPromotions returns promotions, newest first
+func (s *promotionService) ListPromotions(ctx context.Context, page, limit int) ([]models.Promotion, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	promotions, total, err := s.repo.FindAll(ctx, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get promotions: %w", err)
+	}
+	return promotions, total, nil
+}
+
+// apply copies req onto p and validates the result. Codes must be unique.
+func (s *promotionService) apply(ctx context.Context, p *models.Promotion, req *models.PromotionRequest) error {
+	p.Code = promotion.NormalizeCode(req.Code)
+	p.Name = strings.TrimSpace(req.Name)
+	p.Type = req.Type
+	p.PercentOff = req.PercentOff
+	p.AmountOff = money.Zero(money.DefaultCurrency)
+	if req.AmountOff != nil {
+		p.AmountOff = *req.AmountOff
+	}
+	p.BuyQuantity = req.BuyQuantity
+	p.GetQuantity = req.GetQuantity
+	p.Category = strings.TrimSpace(req.Category)
+	p.ProductID = req.ProductID
+	p.MinSpend = money.Zero(money.DefaultCurrency)
+	if req.MinSpend != nil {
+		p.MinSpend = *req.MinSpend
+	}
+	p.UsageLimit = req.UsageLimit
+	p.PerUserLimit = req.PerUserLimit
+	p.StartsAt = req.StartsAt
+	p.EndsAt = req.EndsAt
+	p.Active = req.Active == nil || *req.Active
+
+	if err := promotion.Validate(p); err != nil {
+		return err
+	}
+
+	if p.Code != "" {
+		existing, err := s.repo.FindByCode(ctx, p.Code)
+		if err == nil && existing.ID != p.ID {
+			return fmt.Errorf("invalid promotion: code %s is already used", p.Code)
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to check promotion code: %w", err)
+		}
+	}
+	return nil
+}
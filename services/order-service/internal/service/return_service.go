@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/correlation"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/outbox"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
+	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+	paymentpb "github.com/ploezy/ecommerce-platform/proto/payment"
+	"gorm.io/gorm"
+)
+
+// ErrRefundDeclined is returned when payment-service does not refund a return
+var ErrRefundDeclined = errors.New("refund declined by payment-service")
+
+// PaymentGateway moves money of orders through payment-service,
+// client.PaymentClient implements it
+type PaymentGateway interface {
+	RefundPayment(ctx context.Context, intentID string, amount int64, reason, idempotencyKey string) (*paymentpb.PaymentIntentResponse, error)
+	VoidOrderPayments(ctx context.Context, orderID uint) (*paymentpb.VoidOrderPaymentsResponse, error)
+}
+
+type ReturnService interface {
+	RequestReturn(ctx context.Context, orderID, userID uint, req *models.CreateReturnRequest) (*models.ReturnRequest, error)
+	GetOrderReturns(ctx context.Context, orderID, userID uint) ([]models.ReturnRequest, error)
+	GetReturn(ctx context.Context, id uint) (*models.ReturnRequest, error)
+	ApproveReturn(ctx context.Context, id uint, actor models.Actor, note string) (*models.ReturnRequest, error)
+	RejectReturn(ctx context.Context, id uint, actor models.Actor, note string) (*models.ReturnRequest, error)
+	ReceiveReturn(ctx context.Context, id uint, actor models.Actor) (*models.ReturnRequest, error)
+	RefundReturn(ctx context.Context, id uint, actor models.Actor, amount *money.Money) (*models.ReturnRequest, error)
+}
+
+type returnService struct {
+	repo       repository.ReturnRepository
+	orderRepo  repository.OrderRepository
+	outboxRepo repository.OutboxRepository
+	machine    *statemachine.Machine
+	payments   PaymentGateway
+}
+
+func NewReturnService(
+	repo repository.ReturnRepository,
+	orderRepo repository.OrderRepository,
+	outboxRepo repository.OutboxRepository,
+	machine *statemachine.Machine,
+	payments PaymentGateway,
+) ReturnService {
+	return &returnService{
+		repo:       repo,
+		orderRepo:  orderRepo,
+		outboxRepo: outboxRepo,
+		machine:    machine,
+		payments:   payments,
+	}
+}
+
+// RequestReturn opens a return for some items of a delivered order owned by
+// userID. Orders with earlier returns take further returns while items are
+// left to return.
+func (s *returnService) RequestReturn(ctx context.Context, orderID, userID uint, req *models.CreateReturnRequest) (*models.ReturnRequest, error) {
+	ret := &models.ReturnRequest{
+		OrderID: orderID,
+		UserID:  userID,
+		Status:  models.ReturnStatusRequested,
+		Reason:  req.Reason,
+	}
+
+	_, err := s.machine.Fire(ctx, statemachine.Request{
+		OrderID: orderID,
+		To:      models.OrderStatusReturnRequested,
+		Actor:   models.Actor{Type: models.ActorTypeUser, ID: userID},
+		Reason:  req.Reason,
+		Data:    ret,
+		Check: func(order *models.Order) error {
+			if order.UserID != userID {
+				return errors.New("unauthorized: order does not belong to this user")
+			}
+			return nil
+		},
+		Apply: func(ctx context.Context, tx *gorm.DB, order *models.Order) error {
+			items, err := s.buildReturnItems(ctx, s.repo.WithTx(tx), order, req.Items)
+			if err != nil {
+				return err
+			}
+			ret.Items = items
+
+			if err := s.repo.WithTx(tx).Create(ctx, ret); err != nil {
+				return fmt.Errorf("failed to create return request: %w", err)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// buildReturnItems checks the requested items against the order and the
+// quantities already returned
+func (s *returnService) buildReturnItems(ctx context.Context, repo repository.ReturnRepository, order *models.Order, requested []models.CreateReturnItemRequest) ([]models.ReturnItem, error) {
+	returned, err := repo.ReturnedQuantities(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get returned quantities: %w", err)
+	}
+
+	orderItems := make(map[uint]models.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		orderItems[item.ID] = item
+	}
+
+	quantities := make(map[uint]int, len(requested))
+	for _, item := range requested {
+		if _, ok := orderItems[item.OrderItemID]; !ok {
+			return nil, fmt.Errorf("invalid order item %d for order %d", item.OrderItemID, order.ID)
+		}
+		quantities[item.OrderItemID] += item.Quantity
+	}
+
+	items := make([]models.ReturnItem, 0, len(quantities))
+	for _, orderItem := range order.Items {
+		quantity, ok := quantities[orderItem.ID]
+		if !ok {
+			continue
+		}
+
+		remaining := orderItem.Quantity - returned[orderItem.ID]
+		if quantity > remaining {
+			return nil, fmt.Errorf("cannot return %d of order item %d, only %d left to return", quantity, orderItem.ID, remaining)
+		}
+
+		items = append(items, models.ReturnItem{
+			OrderItemID: orderItem.ID,
+			ProductID:   orderItem.ProductID,
+			Quantity:    quantity,
+			// refund what was paid, after promotions
+			Price: orderItem.NetUnitPrice(),
+		})
+	}
+
+	return items, nil
+}
+
+// GetOrderReturns lists the returns of an order owned by userID
+func (s *returnService) GetOrderReturns(ctx context.Context, orderID, userID uint) ([]models.ReturnRequest, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.UserID != userID {
+		return nil, errors.New("unauthorized: order does not belong to this user")
+	}
+
+	returns, err := s.repo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get returns: %w", err)
+	}
+	return returns, nil
+}
+
+// GetReturn retrieves a return request by ID
+func (s *returnService) GetReturn(ctx context.Context, id uint) (*models.ReturnRequest, error) {
+	ret, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("return request not found")
+		}
+		return nil, fmt.Errorf("failed to get return request: %w", err)
+	}
+	return ret, nil
+}
+
+// ApproveReturn accepts a requested return so the customer can send the goods back
+func (s *returnService) ApproveReturn(ctx context.Context, id uint, actor models.Actor, note string) (*models.ReturnRequest, error) {
+	ret, err := s.GetReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.repo.UpdateStatus(ctx, id, map[string]interface{}{
+		"status":      models.ReturnStatusApproved,
+		"admin_note":  note,
+		"approved_at": time.Now(),
+		"reviewed_by": actor.ID,
+	}, models.ReturnStatusRequested)
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve return request: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("cannot approve return request with status: %s", ret.Status)
+	}
+
+	return s.GetReturn(ctx, id)
+}
+
+// RejectReturn declines a return. The order goes back to delivered, or to the
+// status its earlier returns give it.
+func (s *returnService) RejectReturn(ctx context.Context, id uint, actor models.Actor, note string) (*models.ReturnRequest, error) {
+	ret, err := s.GetReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"status":      models.ReturnStatusRejected,
+		"admin_note":  note,
+		"rejected_at": time.Now(),
+		"reviewed_by": actor.ID,
+	}
+	return s.transition(ctx, ret, actor, note, updates, []string{models.ReturnStatusRequested, models.ReturnStatusApproved}, nil)
+}
+
+// ReceiveReturn records that the goods of an approved return arrived. The state
+// machine restocks them in product-service.
+func (s *returnService) ReceiveReturn(ctx context.Context, id uint, actor models.Actor) (*models.ReturnRequest, error) {
+	ret, err := s.GetReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"status":      models.ReturnStatusReceived,
+		"received_at": time.Now(),
+	}
+	reason := fmt.Sprintf("return %d received", id)
+	return s.transition(ctx, ret, actor, reason, updates, []string{models.ReturnStatusApproved}, nil)
+}
+
+// RefundReturn refunds a received return through payment-service and records
+// the refund once payment-service has made it. Without an amount the full
+// value of the returned items is refunded. An amount without a currency is in
+// the currency of the order. The refund is keyed by the return, so retrying
+// after a failure to record it does not refund twice.
+func (s *returnService) RefundReturn(ctx context.Context, id uint, actor models.Actor, amount *money.Money) (*models.ReturnRequest, error) {
+	ret, err := s.GetReturn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Status != models.ReturnStatusReceived {
+		return nil, fmt.Errorf("cannot refund return request with status: %s", ret.Status)
+	}
+
+	value := ret.Value()
+	refund := value
+	if amount != nil {
+		refund = *amount
+		if refund.Currency == "" {
+			refund.Currency = value.Currency
+		}
+	}
+	if !refund.SameCurrency(value) || !refund.IsPositive() || refund.Amount > value.Amount {
+		return nil, fmt.Errorf("invalid refund amount: must be between %s and %s", money.New(1, value.Currency), value)
+	}
+
+	order, err := s.orderRepo.FindByID(ctx, ret.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.PaymentIntentID == "" {
+		return nil, fmt.Errorf("cannot refund return %d: order %d has no captured payment", ret.ID, order.ID)
+	}
+
+	reason := fmt.Sprintf("return %d refunded", id)
+	resp, err := s.payments.RefundPayment(ctx, order.PaymentIntentID, refund.Amount, reason, ReturnRefundKey(ret.ID))
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%w: %s", ErrRefundDeclined, resp.Message)
+	}
+
+	ret.Refund = refund
+	updates := map[string]interface{}{
+		"status":          models.ReturnStatusRefunded,
+		"refund_amount":   refund.Amount,
+		"refund_currency": refund.Currency,
+		"refunded_at":     time.Now(),
+	}
+	return s.transition(ctx, ret, actor, reason, updates, []string{models.ReturnStatusReceived}, s.publishRefunded)
+}
+
+// ReturnRefundKey is the payment-service idempotency key of the refund of a return
+func ReturnRefundKey(returnID uint) string {
+	return fmt.Sprintf("return:%d", returnID)
+}
+
+// transition updates a return from one of fromStatuses and moves its order to
+// the status all of its returns give it (see statemachine.ReturnStatus), in
+// one transaction. publish, when set, writes events of the change that do not
+// depend on the order status.
+func (s *returnService) transition(
+	ctx context.Context,
+	ret *models.ReturnRequest,
+	actor models.Actor,
+	reason string,
+	updates map[string]interface{},
+	fromStatuses []string,
+	publish func(ctx context.Context, tx *gorm.DB, order *models.Order, ret *models.ReturnRequest) error,
+) (*models.ReturnRequest, error) {
+	previous := ret.Status
+	ret.Status = updates["status"].(string)
+
+	_, err := s.machine.Fire(ctx, statemachine.Request{
+		OrderID: ret.OrderID,
+		Actor:   actor,
+		Reason:  reason,
+		Data:    ret,
+		Target: func(ctx context.Context, tx *gorm.DB, order *models.Order) (string, error) {
+			returns, err := s.repo.WithTx(tx).FindByOrderID(ctx, order.ID)
+			if err != nil {
+				return "", fmt.Errorf("failed to get returns: %w", err)
+			}
+			for i := range returns {
+				if returns[i].ID == ret.ID {
+					returns[i] = *ret
+				}
+			}
+			return statemachine.ReturnStatus(order.Items, returns), nil
+		},
+		Apply: func(ctx context.Context, tx *gorm.DB, order *models.Order) error {
+			ok, err := s.repo.WithTx(tx).UpdateStatus(ctx, ret.ID, updates, fromStatuses...)
+			if err != nil {
+				return fmt.Errorf("failed to update return request: %w", err)
+			}
+			if !ok {
+				return fmt.Errorf("cannot change return request with status: %s", previous)
+			}
+			if publish != nil {
+				return publish(ctx, tx, order, ret)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetReturn(ctx, ret.ID)
+}
+
+// publishRefunded writes order.refunded to the outbox inside tx, keyed by order
+// ID. It is also written for partial refunds that leave the order status as it is.
+func (s *returnService) publishRefunded(ctx context.Context, tx *gorm.DB, order *models.Order, ret *models.ReturnRequest) error {
+	event := kafka.OrderRefundedEvent{
+		OrderID:    order.ID,
+		UserID:     order.UserID,
+		ReturnID:   ret.ID,
+		Amount:     ret.Refund.Major(),
+		Refund:     ret.Refund,
+		FullRefund: ret.Refund.Amount >= ret.Value().Amount,
+		RefundedAt: time.Now().UTC(),
+	}
+	envelope := kafka.NewEnvelope(kafka.EventOrderRefunded, kafka.OrderRefundedSchemaVersion, correlation.FromContext(ctx), event)
+	return outbox.Write(ctx, tx, s.outboxRepo, outbox.AggregateOrder, strconv.FormatUint(uint64(order.ID), 10), kafka.TopicOrderRefunded, envelope)
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
+	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+	paymentpb "github.com/ploezy/ecommerce-platform/proto/payment"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// refundCall is one RefundPayment call of fakePayments
+type refundCall struct {
+	intentID string
+	amount   int64
+	key      string
+}
+
+// fakePayments records refunds and declines them while decline is set
+type fakePayments struct {
+	refunds []refundCall
+	decline string
+}
+
+func (p *fakePayments) RefundPayment(ctx context.Context, intentID string, amount int64, reason, idempotencyKey string) (*paymentpb.PaymentIntentResponse, error) {
+	if p.decline != "" {
+		return &paymentpb.PaymentIntentResponse{Success: false, Message: p.decline}, nil
+	}
+	p.refunds = append(p.refunds, refundCall{intentID: intentID, amount: amount, key: idempotencyKey})
+	return &paymentpb.PaymentIntentResponse{Success: true}, nil
+}
+
+func (p *fakePayments) VoidOrderPayments(ctx context.Context, orderID uint) (*paymentpb.VoidOrderPaymentsResponse, error) {
+	return &paymentpb.VoidOrderPaymentsResponse{Success: true}, nil
+}
+
+// newTestDB opens an empty in-memory SQLite database with the order tables
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	err = db.AutoMigrate(&models.Order{}, &models.OrderItem{}, &models.OrderItemAdjustment{}, &models.OrderDiscount{},
+		&models.OrderStatusHistory{}, &models.OutboxEvent{}, &models.ReturnRequest{}, &models.ReturnItem{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+type returnFixture struct {
+	db       *gorm.DB
+	orders   repository.OrderRepository
+	payments *fakePayments
+	service  ReturnService
+	order    *models.Order
+}
+
+var admin = models.Actor{Type: models.ActorTypeAdmin, ID: 9}
+
+// newReturnFixture creates a delivered order of two mugs at 100 and a teapot
+// at 300, paid by payment intent pi_1
+func newReturnFixture(t *testing.T) *returnFixture {
+	t.Helper()
+	db := newTestDB(t)
+	orders := repository.NewOrderRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	machine := statemachine.NewOrderMachine(db, orders, repository.NewOrderStatusHistoryRepository(db), outboxRepo)
+	payments := &fakePayments{}
+
+	order := &models.Order{
+		UserID:          1,
+		Status:          models.OrderStatusDelivered,
+		PaymentIntentID: "pi_1",
+		Total:           money.New(500, "THB"),
+		Items: []models.OrderItem{
+			{ProductID: 10, Quantity: 2, Price: money.New(100, "THB"), Subtotal: money.New(200, "THB")},
+			{ProductID: 11, Quantity: 1, Price: money.New(300, "THB"), Subtotal: money.New(300, "THB")},
+		},
+	}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return &returnFixture{
+		db:       db,
+		orders:   orders,
+		payments: payments,
+		service:  NewReturnService(repository.NewReturnRepository(db), orders, outboxRepo, machine, payments),
+		order:    order,
+	}
+}
+
+// receive requests, approves and receives a return of quantities by order item index
+func (f *returnFixture) receive(t *testing.T, quantities map[int]int) *models.ReturnRequest {
+	t.Helper()
+	ctx := context.Background()
+	req := &models.CreateReturnRequest{Reason: "broken"}
+	for index, quantity := range quantities {
+		req.Items = append(req.Items, models.CreateReturnItemRequest{OrderItemID: f.order.Items[index].ID, Quantity: quantity})
+	}
+
+	ret, err := f.service.RequestReturn(ctx, f.order.ID, f.order.UserID, req)
+	if err != nil {
+		t.Fatalf("RequestReturn() error = %v", err)
+	}
+	if _, err := f.service.ApproveReturn(ctx, ret.ID, admin, ""); err != nil {
+		t.Fatalf("ApproveReturn() error = %v", err)
+	}
+	ret, err = f.service.ReceiveReturn(ctx, ret.ID, admin)
+	if err != nil {
+		t.Fatalf("ReceiveReturn() error = %v", err)
+	}
+	return ret
+}
+
+func (f *returnFixture) orderStatus(t *testing.T) string {
+	t.Helper()
+	order, err := f.orders.FindByID(context.Background(), f.order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return order.Status
+}
+
+func (f *returnFixture) refundEvents(t *testing.T) int64 {
+	t.Helper()
+	var count int64
+	if err := f.db.Model(&models.OutboxEvent{}).Where("event_type = ?", kafka.EventOrderRefunded).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestTwoPartialReturns(t *testing.T) {
+	f := newReturnFixture(t)
+	ctx := context.Background()
+
+	// First return: one mug, refunded in part
+	first := f.receive(t, map[int]int{0: 1})
+	if status := f.orderStatus(t); status != models.OrderStatusReturned {
+		t.Fatalf("order status after the first return = %s, want returned", status)
+	}
+	partial := money.New(60, "THB")
+	first, err := f.service.RefundReturn(ctx, first.ID, admin, &partial)
+	if err != nil {
+		t.Fatalf("RefundReturn() error = %v", err)
+	}
+	if first.Status != models.ReturnStatusRefunded || first.Refund.Amount != 60 {
+		t.Errorf("first return = %s refunded %d, want refunded 60", first.Status, first.Refund.Amount)
+	}
+	if status := f.orderStatus(t); status != models.OrderStatusReturned {
+		t.Errorf("order status after a partial refund = %s, want returned", status)
+	}
+
+	// Second return: the other mug and the teapot, refunded in full
+	second := f.receive(t, map[int]int{0: 1, 1: 1})
+	second, err = f.service.RefundReturn(ctx, second.ID, admin, nil)
+	if err != nil {
+		t.Fatalf("RefundReturn() error = %v", err)
+	}
+	if second.Refund.Amount != 400 {
+		t.Errorf("second refund = %d, want 400", second.Refund.Amount)
+	}
+	// Everything came back, but the first refund was partial
+	if status := f.orderStatus(t); status != models.OrderStatusReturned {
+		t.Errorf("order status = %s, want returned while a refund was partial", status)
+	}
+
+	want := []refundCall{
+		{intentID: "pi_1", amount: 60, key: ReturnRefundKey(first.ID)},
+		{intentID: "pi_1", amount: 400, key: ReturnRefundKey(second.ID)},
+	}
+	if fmt.Sprint(f.payments.refunds) != fmt.Sprint(want) {
+		t.Errorf("refunds = %v, want %v", f.payments.refunds, want)
+	}
+	if count := f.refundEvents(t); count != 2 {
+		t.Errorf("wrote %d order.refunded events, want 2", count)
+	}
+
+	// Nothing is left to return
+	_, err = f.service.RequestReturn(ctx, f.order.ID, f.order.UserID, &models.CreateReturnRequest{
+		Reason: "broken",
+		Items:  []models.CreateReturnItemRequest{{OrderItemID: f.order.Items[0].ID, Quantity: 1}},
+	})
+	if err == nil {
+		t.Error("RequestReturn() error = nil with nothing left to return")
+	}
+}
+
+func TestOrderIsRefundedOnceEverythingIsRefunded(t *testing.T) {
+	f := newReturnFixture(t)
+	ctx := context.Background()
+
+	first := f.receive(t, map[int]int{1: 1})
+	if _, err := f.service.RefundReturn(ctx, first.ID, admin, nil); err != nil {
+		t.Fatal(err)
+	}
+	if status := f.orderStatus(t); status != models.OrderStatusReturned {
+		t.Fatalf("order status = %s, want returned while mugs are left", status)
+	}
+
+	second := f.receive(t, map[int]int{0: 2})
+	if _, err := f.service.RefundReturn(ctx, second.ID, admin, nil); err != nil {
+		t.Fatal(err)
+	}
+	if status := f.orderStatus(t); status != models.OrderStatusRefunded {
+		t.Errorf("order status = %s, want refunded", status)
+	}
+}
+
+func TestDeclinedRefundIsNotRecorded(t *testing.T) {
+	f := newReturnFixture(t)
+	ctx := context.Background()
+	ret := f.receive(t, map[int]int{0: 1})
+
+	f.payments.decline = "refund must be between 1 and 0"
+	if _, err := f.service.RefundReturn(ctx, ret.ID, admin, nil); !errors.Is(err, ErrRefundDeclined) {
+		t.Fatalf("RefundReturn() error = %v, want ErrRefundDeclined", err)
+	}
+	ret, err := f.service.GetReturn(ctx, ret.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Status != models.ReturnStatusReceived || !ret.Refund.IsZero() {
+		t.Errorf("return = %s refunded %s, want it received without a refund", ret.Status, ret.Refund)
+	}
+	if count := f.refundEvents(t); count != 0 {
+		t.Errorf("wrote %d order.refunded events for a declined refund", count)
+	}
+
+	// A rejected second return leaves the order returned
+	f.payments.decline = ""
+	second, err := f.service.RequestReturn(ctx, f.order.ID, f.order.UserID, &models.CreateReturnRequest{
+		Reason: "changed my mind",
+		Items:  []models.CreateReturnItemRequest{{OrderItemID: f.order.Items[1].ID, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.service.RejectReturn(ctx, second.ID, admin, "used"); err != nil {
+		t.Fatal(err)
+	}
+	if status := f.orderStatus(t); status != models.OrderStatusReturned {
+		t.Errorf("order status after the rejected return = %s, want returned", status)
+	}
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/correlation"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/outbox"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/statemachine"
+	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+	"gorm.io/gorm"
+)
+
+type ShipmentService interface {
+	CreateShipment(ctx context.Context, orderID uint, actor models.Actor, req *models.CreateShipmentRequest) (*models.Shipment, error)
+	RecordTracking(ctx context.Context, actor models.Actor, req *models.ShipmentTrackingRequest) (*models.Shipment, error)
+	GetShipment(ctx context.Context, id uint) (*models.Shipment, error)
+	GetOrderShipments(ctx context.Context, orderID, userID uint) ([]models.Shipment, error)
+}
+
+type shipmentService struct {
+	repo       repository.ShipmentRepository
+	orderRepo  repository.OrderRepository
+	outboxRepo repository.OutboxRepository
+	machine    *statemachine.Machine
+}
+
+func NewShipmentService(
+	repo repository.ShipmentRepository,
+	orderRepo repository.OrderRepository,
+	outboxRepo repository.OutboxRepository,
+	machine *statemachine.Machine,
+) ShipmentService {
+	return &shipmentService{
+		repo:       repo,
+		orderRepo:  orderRepo,
+		outboxRepo: outboxRepo,
+		machine:    machine,
+	}
+}
+
+// CreateShipment hands some items of a processing or partially shipped order
+// to a carrier. The order becomes partially_shipped or shipped depending on
+// what is left to ship.
+func (s *shipmentService) CreateShipment(ctx context.Context, orderID uint, actor models.Actor, req *models.CreateShipmentRequest) (*models.Shipment, error) {
+	now := time.Now().UTC()
+	shipment := &models.Shipment{
+		OrderID:        orderID,
+		Status:         models.ShipmentStatusShipped,
+		Carrier:        normalizeCarrier(req.Carrier),
+		TrackingNumber: strings.TrimSpace(req.TrackingNumber),
+		ShippedAt:      &now,
+	}
+	if shipment.Carrier == "" || shipment.TrackingNumber == "" {
+		return nil, errors.New("invalid shipment: carrier and tracking_number are required")
+	}
+
+	var orderStatus string
+	_, err := s.machine.Fire(ctx, statemachine.Request{
+		OrderID: orderID,
+		Actor:   actor,
+		Reason:  fmt.Sprintf("shipped with %s %s", shipment.Carrier, shipment.TrackingNumber),
+		Data:    shipment,
+		Target: func(ctx context.Context, tx *gorm.DB, order *models.Order) (string, error) {
+			if order.Status != models.OrderStatusProcessing && order.Status != models.OrderStatusPartiallyShipped {
+				return "", fmt.Errorf("cannot ship order with status: %s", order.Status)
+			}
+
+			repo := s.repo.WithTx(tx)
+			if _, err := repo.FindByTracking(ctx, shipment.Carrier, shipment.TrackingNumber); err == nil {
+				return "", fmt.Errorf("invalid shipment: %s tracking number %s is already used", shipment.Carrier, shipment.TrackingNumber)
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", fmt.Errorf("failed to check tracking number: %w", err)
+			}
+
+			shipments, err := repo.FindByOrderID(ctx, order.ID)
+			if err != nil {
+				return "", fmt.Errorf("failed to get shipments: %w", err)
+			}
+			items, err := buildShipmentItems(order, shipments, req.Items)
+			if err != nil {
+				return "", err
+			}
+			shipment.Items = items
+
+			orderStatus = statemachine.ShippingStatus(order.Items, append(shipments, *shipment))
+			return orderStatus, nil
+		},
+		Apply: func(ctx context.Context, tx *gorm.DB, order *models.Order) error {
+			if err := s.repo.WithTx(tx).Create(ctx, shipment); err != nil {
+				return fmt.Errorf("failed to create shipment: %w", err)
+			}
+			return s.publish(ctx, tx, kafka.EventShipmentCreated, kafka.ShipmentCreatedSchemaVersion, order, shipment, orderStatus, now)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetShipment(ctx, shipment.ID)
+}
+
+// buildShipmentItems checks the requested items against the order and the
+// quantities already shipped
+func buildShipmentItems(order *models.Order, shipments []models.Shipment, requested []models.CreateShipmentItemRequest) ([]models.ShipmentItem, error) {
+	shipped := make(map[uint]int, len(order.Items))
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			shipped[item.OrderItemID] += item.Quantity
+		}
+	}
+
+	orderItems := make(map[uint]models.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		orderItems[item.ID] = item
+	}
+
+	quantities := make(map[uint]int, len(requested))
+	for _, item := range requested {
+		if _, ok := orderItems[item.OrderItemID]; !ok {
+			return nil, fmt.Errorf("invalid order item %d for order %d", item.OrderItemID, order.ID)
+		}
+		quantities[item.OrderItemID] += item.Quantity
+	}
+
+	items := make([]models.ShipmentItem, 0, len(quantities))
+	for _, orderItem := range order.Items {
+		quantity, ok := quantities[orderItem.ID]
+		if !ok {
+			continue
+		}
+
+		remaining := orderItem.Quantity - shipped[orderItem.ID]
+		if quantity > remaining {
+			return nil, fmt.Errorf("cannot ship %d of order item %d, only %d left to ship", quantity, orderItem.ID, remaining)
+		}
+
+		items = append(items, models.ShipmentItem{
+			OrderItemID: orderItem.ID,
+			ProductID:   orderItem.ProductID,
+			Quantity:    quantity,
+		})
+	}
+
+	return items, nil
+}
+
+// RecordTracking stores a carrier tracking update of a shipment. Carriers
+// resend and reorder updates: an update already recorded is a no-op, the
+// shipment takes the status of its latest update and a delivered shipment stays
+// delivered. Once every shipment of a fully shipped order is delivered the order
+// becomes delivered.
+func (s *shipmentService) RecordTracking(ctx context.Context, actor models.Actor, req *models.ShipmentTrackingRequest) (*models.Shipment, error) {
+	shipment, err := s.repo.FindByTracking(ctx, normalizeCarrier(req.Carrier), strings.TrimSpace(req.TrackingNumber))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("shipment not found")
+		}
+		return nil, fmt.Errorf("failed to get shipment: %w", err)
+	}
+
+	event := &models.ShipmentTrackingEvent{
+		ShipmentID:  shipment.ID,
+		Status:      req.Status,
+		Description: strings.TrimSpace(req.Description),
+		Location:    strings.TrimSpace(req.Location),
+		OccurredAt:  req.OccurredAt.UTC(),
+	}
+
+	var recorded bool
+	var orderStatus string
+	_, err = s.machine.Fire(ctx, statemachine.Request{
+		OrderID: shipment.OrderID,
+		Actor:   actor,
+		Reason:  fmt.Sprintf("shipment %d %s", shipment.ID, req.Status),
+		Data:    shipment,
+		Target: func(ctx context.Context, tx *gorm.DB, order *models.Order) (string, error) {
+			repo := s.repo.WithTx(tx)
+			created, err := repo.AddTrackingEvent(ctx, event)
+			if err != nil {
+				return "", fmt.Errorf("failed to record tracking update: %w", err)
+			}
+			if !created {
+				return order.Status, nil
+			}
+			recorded = true
+
+			current, err := repo.FindByID(ctx, shipment.ID)
+			if err != nil {
+				return "", fmt.Errorf("failed to get shipment: %w", err)
+			}
+			updates := map[string]interface{}{}
+			status, deliveredAt := trackingStatus(current.TrackingEvents)
+			if status != current.Status {
+				updates["status"] = status
+				current.Status = status
+			}
+			if deliveredAt != nil && current.DeliveredAt == nil {
+				updates["delivered_at"] = *deliveredAt
+				current.DeliveredAt = deliveredAt
+			}
+			if len(updates) > 0 {
+				if err := repo.Update(ctx, shipment.ID, updates); err != nil {
+					return "", fmt.Errorf("failed to update shipment: %w", err)
+				}
+			}
+			*shipment = *current
+
+			// orders past delivery (returns) keep their status
+			orderStatus = order.Status
+			switch order.Status {
+			case models.OrderStatusPartiallyShipped, models.OrderStatusShipped:
+				shipments, err := repo.FindByOrderID(ctx, order.ID)
+				if err != nil {
+					return "", fmt.Errorf("failed to get shipments: %w", err)
+				}
+				orderStatus = statemachine.ShippingStatus(order.Items, shipments)
+			}
+			return orderStatus, nil
+		},
+		Apply: func(ctx context.Context, tx *gorm.DB, order *models.Order) error {
+			if !recorded {
+				return nil
+			}
+			return s.publish(ctx, tx, kafka.EventShipmentUpdated, kafka.ShipmentUpdatedSchemaVersion, order, shipment, orderStatus, event.OccurredAt)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetShipment(ctx, shipment.ID)
+}
+
+// trackingStatus returns the status of the latest tracking update, or delivered
+// and when once any update reports the delivery
+func trackingStatus(events []models.ShipmentTrackingEvent) (string, *time.Time) {
+	status := models.ShipmentStatusShipped
+	for _, event := range events {
+		if event.Status == models.ShipmentStatusDelivered {
+			deliveredAt := event.OccurredAt
+			return models.ShipmentStatusDelivered, &deliveredAt
+		}
+		status = event.Status
+	}
+	return status, nil
+}
+
+// GetShipment retrieves a shipment with its items and tracking updates
+func (s *shipmentService) GetShipment(ctx context.Context, id uint) (*models.Shipment, error) {
+	shipment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("shipment not found")
+		}
+		return nil, fmt.Errorf("failed to get shipment: %w", err)
+	}
+	return shipment, nil
+}
+
+// GetOrderShipments lists the shipments of an order owned by userID
+func (s *shipmentService) GetOrderShipments(ctx context.Context, orderID, userID uint) ([]models.Shipment, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.UserID != userID {
+		return nil, errors.New("unauthorized: order does not belong to this user")
+	}
+
+	shipments, err := s.repo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+	return shipments, nil
+}
+
+// publish writes a shipment event to the outbox inside tx, keyed by order ID so
+// it stays in order with the other events of the order
+func (s *shipmentService) publish(ctx context.Context, tx *gorm.DB, eventType string, version int, order *models.Order, shipment *models.Shipment, orderStatus string, at time.Time) error {
+	items := make([]kafka.ShipmentItemEvent, 0, len(shipment.Items))
+	for _, item := range shipment.Items {
+		items = append(items, kafka.ShipmentItemEvent{
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	event := kafka.ShipmentEvent{
+		ShipmentID:     shipment.ID,
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         shipment.Status,
+		OrderStatus:    orderStatus,
+		Items:          items,
+		ShippedAt:      shipment.ShippedAt,
+		DeliveredAt:    shipment.DeliveredAt,
+		UpdatedAt:      at,
+	}
+	envelope := kafka.NewEnvelope(eventType, version, correlation.FromContext(ctx), event)
+	return outbox.Write(ctx, tx, s.outboxRepo, outbox.AggregateOrder, strconv.FormatUint(uint64(order.ID), 10), eventType, envelope)
+}
+
+// normalizeCarrier lower-cases a carrier code so lookups by tracking number
+// do not depend on how the carrier spells itself
+func normalizeCarrier(carrier string) string {
+	return strings.ToLower(strings.TrimSpace(carrier))
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	pb "github.com/ploezy/ecommerce-platform/proto/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// shippingAddress resolves the address an order ships to: the address book
+// entry of req, the address entered in req, or the default address of userID.
+// It returns nil when none is given and the user has no default address.
+func (s *orderService) shippingAddress(ctx context.Context, userID uint, req *models.CreateOrderRequest) (*models.ShippingAddress, error) {
+	if req.ShippingAddressID != 0 && req.ShippingAddress != nil {
+		return nil, errors.New("invalid order: give either shipping_address_id or shipping_address, not both")
+	}
+
+	if in := req.ShippingAddress; in != nil {
+		address, err := s.userClient.ValidateAddress(ctx, &pb.Address{
+			RecipientName: in.RecipientName,
+			Phone:         in.Phone,
+			Line1:         in.Line1,
+			Line2:         in.Line2,
+			District:      in.District,
+			City:          in.City,
+			Region:        in.Region,
+			PostalCode:    in.PostalCode,
+			Country:       in.Country,
+		})
+		if err != nil {
+			if status.Code(err) == codes.InvalidArgument {
+				return nil, errors.New(status.Convert(err).Message())
+			}
+			return nil, fmt.Errorf("failed to validate shipping address: %w", err)
+		}
+		return snapshotAddress(address, 0), nil
+	}
+
+	address, err := s.userClient.GetAddress(ctx, uint32(userID), uint32(req.ShippingAddressID))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			if req.ShippingAddressID != 0 {
+				return nil, fmt.Errorf("invalid order: shipping address %d not found", req.ShippingAddressID)
+			}
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get shipping address: %w", err)
+	}
+	return snapshotAddress(address, uint(address.Id)), nil
+}
+
+// snapshotAddress copies an address of user-service onto an order
+func snapshotAddress(address *pb.Address, addressID uint) *models.ShippingAddress {
+	return &models.ShippingAddress{
+		AddressID:     addressID,
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		Line1:         address.Line1,
+		Line2:         address.Line2,
+		District:      address.District,
+		City:          address.City,
+		Region:        address.Region,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+	}
+}
@@ -0,0 +1,285 @@
+package statemachine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/correlation"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidTransition is returned when a status change is not allowed
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// ErrOrderNotFound is returned when the order to change does not exist
+var ErrOrderNotFound = errors.New("order not found")
+
+// Transition is a status change being applied to an order
+type Transition struct {
+	Order         *models.Order
+	From          string
+	To            string
+	Actor         models.Actor
+	Reason        string
+	CorrelationID string
+	At            time.Time
+
+	// Data carries details of the change for effects and hooks, see Request.Data
+	Data interface{}
+
+	// SkipEffects is set when the caller undoes the effects itself, see
+	// Request.SkipEffects
+	SkipEffects bool
+}
+
+// Guard decides whether a transition may happen. A non-nil error rejects it.
+type Guard func(t *Transition) error
+
+// Effect runs inside the transaction that changes the status, e.g. to write an
+// outbox event. An error rolls the transition back.
+type Effect func(ctx context.Context, tx *gorm.DB, t *Transition) error
+
+// Hook runs after the transition has been committed. Errors are logged only.
+type Hook func(ctx context.Context, t *Transition) error
+
+// Rule allows one transition and lists what happens around it
+type Rule struct {
+	From        string
+	To          string
+	Guards      []Guard
+	Effects     []Effect
+	AfterCommit []Hook
+}
+
+// Request asks the machine to move an order to a new status
+type Request struct {
+	OrderID uint
+	To      string
+	Actor   models.Actor
+	Reason  string
+
+	// Check is called with the locked order before any rule is looked up,
+	// e.g. to verify that the order belongs to the caller
+	Check func(order *models.Order) error
+
+	// Apply is called inside the transaction after the status has changed, for
+	// records that must be written together with the transition. An error
+	// rolls the transition back.
+	Apply func(ctx context.Context, tx *gorm.DB, order *models.Order) error
+
+	// Target, when set, picks the status to move to from the locked order
+	// instead of To, e.g. to derive it from records written by Apply. When it
+	// returns the current status only Apply runs and no transition is recorded.
+	Target func(ctx context.Context, tx *gorm.DB, order *models.Order) (string, error)
+
+	// Data is passed to effects and hooks as Transition.Data
+	Data interface{}
+
+	// SkipEffects records the transition without running effects and hooks.
+	// Used by rollbacks that undo the effects themselves.
+	SkipEffects bool
+}
+
+// Machine applies status transitions to orders and records their history
+type Machine struct {
+	states      map[string]bool
+	rules       map[string]map[string]Rule
+	db          *gorm.DB
+	orderRepo   repository.OrderRepository
+	historyRepo repository.OrderStatusHistoryRepository
+}
+
+// NewMachine creates a state machine for the given states and rules
+func NewMachine(
+	db *gorm.DB,
+	orderRepo repository.OrderRepository,
+	historyRepo repository.OrderStatusHistoryRepository,
+	states []string,
+	rules []Rule,
+) *Machine {
+	m := &Machine{
+		states:      make(map[string]bool, len(states)),
+		rules:       make(map[string]map[string]Rule),
+		db:          db,
+		orderRepo:   orderRepo,
+		historyRepo: historyRepo,
+	}
+
+	for _, state := range states {
+		m.states[state] = true
+	}
+	for _, rule := range rules {
+		if !m.states[rule.From] || !m.states[rule.To] {
+			panic(fmt.Sprintf("statemachine: rule %s -> %s uses an unknown state", rule.From, rule.To))
+		}
+		if m.rules[rule.From] == nil {
+			m.rules[rule.From] = make(map[string]Rule)
+		}
+		m.rules[rule.From][rule.To] = rule
+	}
+
+	return m
+}
+
+// IsState reports whether status is a known state
+func (m *Machine) IsState(status string) bool {
+	return m.states[status]
+}
+
+// CanTransition reports whether a rule allows from -> to, without checking guards
+func (m *Machine) CanTransition(from, to string) bool {
+	_, ok := m.rules[from][to]
+	return ok
+}
+
+// Fire moves an order to a new status. The order row is locked while guards and
+// effects run, so concurrent transitions of one order are serialized.
+func (m *Machine) Fire(ctx context.Context, req Request) (*models.Order, error) {
+	if req.Target == nil && !m.IsState(req.To) {
+		return nil, fmt.Errorf("invalid order status: %s", req.To)
+	}
+
+	var transition *Transition
+	var rule Rule
+	var unchanged *models.Order
+
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := m.orderRepo.WithTx(tx).FindByIDForUpdate(ctx, req.OrderID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrderNotFound
+			}
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		if req.Check != nil {
+			if err := req.Check(order); err != nil {
+				return err
+			}
+		}
+
+		if req.Target != nil {
+			to, err := req.Target(ctx, tx, order)
+			if err != nil {
+				return err
+			}
+			if to == order.Status {
+				unchanged = order
+				if req.Apply != nil {
+					return req.Apply(ctx, tx, order)
+				}
+				return nil
+			}
+			if !m.IsState(to) {
+				return fmt.Errorf("invalid order status: %s", to)
+			}
+			req.To = to
+		}
+
+		var ok bool
+		rule, ok = m.rules[order.Status][req.To]
+		if !ok {
+			return fmt.Errorf("%w: cannot change status from %s to %s", ErrInvalidTransition, order.Status, req.To)
+		}
+
+		transition = &Transition{
+			Order:         order,
+			From:          order.Status,
+			To:            req.To,
+			Actor:         req.Actor,
+			Reason:        req.Reason,
+			CorrelationID: correlation.FromContext(ctx),
+			At:            time.Now().UTC(),
+			Data:          req.Data,
+			SkipEffects:   req.SkipEffects,
+		}
+
+		for _, guard := range rule.Guards {
+			if err := guard(transition); err != nil {
+				return fmt.Errorf("%w: cannot change status from %s to %s: %v", ErrInvalidTransition, order.Status, req.To, err)
+			}
+		}
+
+		if err := m.orderRepo.WithTx(tx).UpdateStatus(ctx, order.ID, req.To); err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		if err := m.record(ctx, tx, transition); err != nil {
+			return err
+		}
+		if req.Apply != nil {
+			if err := req.Apply(ctx, tx, order); err != nil {
+				return err
+			}
+		}
+
+		if !req.SkipEffects {
+			for _, effect := range rule.Effects {
+				if err := effect(ctx, tx, transition); err != nil {
+					return err
+				}
+			}
+		}
+
+		order.Status = req.To
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unchanged != nil {
+		return unchanged, nil
+	}
+
+	if !req.SkipEffects {
+		for _, hook := range rule.AfterCommit {
+			if err := hook(ctx, transition); err != nil {
+				log.Printf("Order %d %s -> %s: after commit hook failed: %v", transition.Order.ID, transition.From, transition.To, err)
+			}
+		}
+	}
+
+	return transition.Order, nil
+}
+
+// RecordCreated writes the first history entry of a newly created order inside tx
+func (m *Machine) RecordCreated(ctx context.Context, tx *gorm.DB, order *models.Order, actor models.Actor) error {
+	return m.record(ctx, tx, &Transition{
+		Order:         order,
+		To:            order.Status,
+		Actor:         actor,
+		CorrelationID: correlation.FromContext(ctx),
+		At:            order.CreatedAt,
+	})
+}
+
+// History returns the recorded transitions of an order, oldest first
+func (m *Machine) History(ctx context.Context, orderID uint) ([]models.OrderStatusHistory, error) {
+	return m.historyRepo.FindByOrderID(ctx, orderID)
+}
+
+// record stores a transition in order_status_history
+func (m *Machine) record(ctx context.Context, tx *gorm.DB, t *Transition) error {
+	entry := &models.OrderStatusHistory{
+		OrderID:       t.Order.ID,
+		FromStatus:    t.From,
+		ToStatus:      t.To,
+		ActorType:     t.Actor.Type,
+		Reason:        t.Reason,
+		CorrelationID: t.CorrelationID,
+		CreatedAt:     t.At,
+	}
+	if t.Actor.ID != 0 {
+		actorID := t.Actor.ID
+		entry.ActorID = &actorID
+	}
+
+	if err := m.historyRepo.WithTx(tx).Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record status history: %w", err)
+	}
+	return nil
+}
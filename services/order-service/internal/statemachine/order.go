@@ -0,0 +1,367 @@
+package statemachine
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/outbox"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+	"gorm.io/gorm"
+)
+
+// NewOrderMachine returns the order lifecycle. It is the only place that
+// decides which status changes are allowed:
+//
+//	pending ──► processing ──► partially_shipped ──► shipped ──► delivered ◄──────────┐ (rejected)
+//	   │            │  │                                 ▲             │                │
+//	   └─► cancelled ◄┘  └─────────────────────────────────┘             └─► return_requested ◄─► returned ──► refunded
+//	                                                                              ▲                        │
+//	                                                                              └────────────────────────┘
+//
+// Pending orders only move to processing when payment-service reports the
+// payment as captured, and are only cancelled once the create order saga has
+// committed their stock reservation. Customers may cancel pending orders and request returns;
+// every other change is made by staff. Cancellation returns the stock of every order item to
+// product-service, receiving a return restocks the returned items; both write a
+// restock command to the outbox in the same transaction (see restock.go). Staff
+// cancels of paid orders also write a refund command (see refund.go).
+//
+// Return statuses are derived from the returns of the order (see
+// ReturnStatus). A returned or refunded order goes back to return_requested
+// for every further return while items are left to return, and it is only
+// refunded once everything is returned and refunded in full. Return
+// transitions expect the *models.ReturnRequest that changed, with its new
+// status, as Request.Data.
+//
+// Shipping statuses are derived from the shipments of the order (see
+// ShippingStatus), so those transitions expect the *models.Shipment that
+// changed as Request.Data.
+func NewOrderMachine(
+	db *gorm.DB,
+	orderRepo repository.OrderRepository,
+	historyRepo repository.OrderStatusHistoryRepository,
+	outboxRepo repository.OutboxRepository,
+) *Machine {
+	statusChanged := publishStatusChanged(outboxRepo)
+	cancelled := publishCancelled(outboxRepo)
+	restock := restockItems(outboxRepo)
+	refund := refundPayment(outboxRepo)
+	returnRequested := publishReturnRequested(outboxRepo)
+	returned := publishReturned(outboxRepo)
+	restockReturn := restockReturnedItems(outboxRepo)
+
+	return NewMachine(db, orderRepo, historyRepo,
+		[]string{
+			models.OrderStatusPending,
+			models.OrderStatusProcessing,
+			models.OrderStatusPartiallyShipped,
+			models.OrderStatusShipped,
+			models.OrderStatusDelivered,
+			models.OrderStatusCancelled,
+			models.OrderStatusReturnRequested,
+			models.OrderStatusReturned,
+			models.OrderStatusRefunded,
+		},
+		[]Rule{
+			{
+				From:    models.OrderStatusPending,
+				To:      models.OrderStatusProcessing,
+				Guards:  []Guard{systemOnly},
+				Effects: []Effect{statusChanged},
+			},
+			{
+				From:    models.OrderStatusPending,
+				To:      models.OrderStatusCancelled,
+				Guards:  []Guard{stockCommitted},
+				Effects: []Effect{cancelled, restock},
+			},
+			{
+				From:    models.OrderStatusProcessing,
+				To:      models.OrderStatusCancelled,
+				Guards:  []Guard{staffOnly},
+				Effects: []Effect{cancelled, restock, refund},
+			},
+			{
+				From:    models.OrderStatusProcessing,
+				To:      models.OrderStatusPartiallyShipped,
+				Guards:  []Guard{staffOnly, requireShipment},
+				Effects: []Effect{statusChanged},
+			},
+			{
+				From:    models.OrderStatusProcessing,
+				To:      models.OrderStatusShipped,
+				Guards:  []Guard{staffOnly, requireShipment},
+				Effects: []Effect{statusChanged},
+			},
+			{
+				From:    models.OrderStatusPartiallyShipped,
+				To:      models.OrderStatusShipped,
+				Guards:  []Guard{staffOnly, requireShipment},
+				Effects: []Effect{statusChanged},
+			},
+			{
+				From:    models.OrderStatusShipped,
+				To:      models.OrderStatusDelivered,
+				Guards:  []Guard{staffOnly, requireShipment},
+				Effects: []Effect{statusChanged},
+			},
+			{
+				From:    models.OrderStatusDelivered,
+				To:      models.OrderStatusReturnRequested,
+				Guards:  []Guard{requireReturn},
+				Effects: []Effect{returnRequested},
+			},
+			{
+				From:    models.OrderStatusReturnRequested,
+				To:      models.OrderStatusDelivered,
+				Guards:  []Guard{staffOnly, requireReturn},
+				Effects: []Effect{statusChanged},
+			},
+			{
+				From:   models.OrderStatusReturnRequested,
+				To:     models.OrderStatusReturned,
+				Guards: []Guard{staffOnly, requireReturn},
+				Effects: []Effect{
+					onReturnStatus(models.ReturnStatusReceived, returned),
+					onReturnStatus(models.ReturnStatusReceived, restockReturn),
+					onReturnStatus(models.ReturnStatusRejected, statusChanged),
+				},
+			},
+			{
+				// a further return was rejected after the earlier ones were refunded
+				From:    models.OrderStatusReturnRequested,
+				To:      models.OrderStatusRefunded,
+				Guards:  []Guard{staffOnly, requireReturn},
+				Effects: []Effect{statusChanged},
+			},
+			{
+				From:    models.OrderStatusReturned,
+				To:      models.OrderStatusReturnRequested,
+				Guards:  []Guard{requireReturn},
+				Effects: []Effect{returnRequested},
+			},
+			{
+				// the refund itself is published by the return service, see ReturnStatus
+				From:    models.OrderStatusReturned,
+				To:      models.OrderStatusRefunded,
+				Guards:  []Guard{staffOnly, requireReturn},
+				Effects: []Effect{statusChanged},
+			},
+			{
+				From:    models.OrderStatusRefunded,
+				To:      models.OrderStatusReturnRequested,
+				Guards:  []Guard{requireReturn},
+				Effects: []Effect{returnRequested},
+			},
+		},
+	)
+}
+
+// staffOnly rejects transitions requested by customers
+func staffOnly(t *Transition) error {
+	if t.Actor.Type != models.ActorTypeAdmin && t.Actor.Type != models.ActorTypeSystem {
+		return errors.New("only staff can make this change")
+	}
+	return nil
+}
+
+// systemOnly rejects transitions that are not made by order-service itself,
+// e.g. in reaction to a payment event
+func systemOnly(t *Transition) error {
+	if t.Actor.Type != models.ActorTypeSystem {
+		return errors.New("only the system can make this change")
+	}
+	return nil
+}
+
+// stockCommitted rejects cancels that would restock an order whose stock
+// reservation is not committed yet. The create order saga still owns the
+// reservation and releases it if the commit is rejected, so a restock would
+// return the stock twice. The saga's own rollback skips the effects.
+func stockCommitted(t *Transition) error {
+	if t.Order.StockPending && !t.SkipEffects {
+		return errors.New("the order is still being placed")
+	}
+	return nil
+}
+
+// requireReturn rejects return transitions that do not carry a return request
+func requireReturn(t *Transition) error {
+	if _, ok := t.Data.(*models.ReturnRequest); !ok {
+		return errors.New("a return request is required")
+	}
+	return nil
+}
+
+// requireShipment rejects shipping transitions that do not come from a shipment
+func requireShipment(t *Transition) error {
+	if _, ok := t.Data.(*models.Shipment); !ok {
+		return errors.New("cannot change shipping status directly: it follows the shipments of the order")
+	}
+	return nil
+}
+
+// onReturnStatus runs effect only for transitions whose return has status,
+// for transitions that more than one return action makes
+func onReturnStatus(status string, effect Effect) Effect {
+	return func(ctx context.Context, tx *gorm.DB, t *Transition) error {
+		if t.Data.(*models.ReturnRequest).Status != status {
+			return nil
+		}
+		return effect(ctx, tx, t)
+	}
+}
+
+// ReturnStatus derives the status of an order from its returns. It returns
+// return_requested while a return is open and delivered when every return was
+// rejected. The order is refunded once every item is returned and every
+// return is refunded in full, and returned otherwise: while a received return
+// waits for its refund, after a partial refund or while items are left.
+func ReturnStatus(items []models.OrderItem, returns []models.ReturnRequest) string {
+	returned := make(map[uint]int, len(items))
+	refundedInFull := true
+	received := false
+	for _, ret := range returns {
+		switch ret.Status {
+		case models.ReturnStatusRequested, models.ReturnStatusApproved:
+			return models.OrderStatusReturnRequested
+		case models.ReturnStatusRejected:
+			continue
+		}
+
+		received = true
+		for _, item := range ret.Items {
+			returned[item.OrderItemID] += item.Quantity
+		}
+		if ret.Status != models.ReturnStatusRefunded || ret.Refund.Amount < ret.Value().Amount {
+			refundedInFull = false
+		}
+	}
+	if !received {
+		return models.OrderStatusDelivered
+	}
+
+	if !refundedInFull {
+		return models.OrderStatusReturned
+	}
+	for _, item := range items {
+		if returned[item.ID] < item.Quantity {
+			return models.OrderStatusReturned
+		}
+	}
+	return models.OrderStatusRefunded
+}
+
+// ShippingStatus derives the status of an order from its shipments. It returns
+// "" while nothing has shipped, partially_shipped while some quantity of an
+// item has not shipped yet, delivered once every shipment is delivered and
+// shipped otherwise.
+func ShippingStatus(items []models.OrderItem, shipments []models.Shipment) string {
+	if len(shipments) == 0 {
+		return ""
+	}
+
+	shipped := make(map[uint]int, len(items))
+	delivered := true
+	for _, shipment := range shipments {
+		for _, item := range shipment.Items {
+			shipped[item.OrderItemID] += item.Quantity
+		}
+		delivered = delivered && shipment.IsDelivered()
+	}
+
+	for _, item := range items {
+		if shipped[item.ID] < item.Quantity {
+			return models.OrderStatusPartiallyShipped
+		}
+	}
+	if delivered {
+		return models.OrderStatusDelivered
+	}
+	return models.OrderStatusShipped
+}
+
+// publishStatusChanged writes order.status_changed to the outbox
+func publishStatusChanged(outboxRepo repository.OutboxRepository) Effect {
+	return func(ctx context.Context, tx *gorm.DB, t *Transition) error {
+		event := kafka.OrderStatusChangedEvent{
+			OrderID:   t.Order.ID,
+			UserID:    t.Order.UserID,
+			OldStatus: t.From,
+			NewStatus: t.To,
+			UpdatedAt: t.At,
+		}
+		envelope := kafka.NewEnvelope(kafka.EventOrderStatusChanged, kafka.OrderStatusChangedSchemaVersion, t.CorrelationID, event)
+		return writeOrderEvent(ctx, tx, outboxRepo, t.Order.ID, kafka.TopicOrderStatusChanged, envelope)
+	}
+}
+
+// publishCancelled writes order.cancelled to the outbox
+func publishCancelled(outboxRepo repository.OutboxRepository) Effect {
+	return func(ctx context.Context, tx *gorm.DB, t *Transition) error {
+		event := kafka.OrderCancelledEvent{
+			OrderID:     t.Order.ID,
+			UserID:      t.Order.UserID,
+			Reason:      t.Reason,
+			CancelledAt: t.At,
+		}
+		envelope := kafka.NewEnvelope(kafka.EventOrderCancelled, kafka.OrderCancelledSchemaVersion, t.CorrelationID, event)
+		return writeOrderEvent(ctx, tx, outboxRepo, t.Order.ID, kafka.TopicOrderCancelled, envelope)
+	}
+}
+
+// publishReturnRequested writes order.return_requested to the outbox
+func publishReturnRequested(outboxRepo repository.OutboxRepository) Effect {
+	return func(ctx context.Context, tx *gorm.DB, t *Transition) error {
+		ret := t.Data.(*models.ReturnRequest)
+		event := kafka.OrderReturnRequestedEvent{
+			OrderID:     t.Order.ID,
+			UserID:      t.Order.UserID,
+			ReturnID:    ret.ID,
+			Reason:      ret.Reason,
+			Items:       returnItemEvents(ret),
+			RequestedAt: t.At,
+		}
+		envelope := kafka.NewEnvelope(kafka.EventOrderReturnRequested, kafka.OrderReturnRequestedSchemaVersion, t.CorrelationID, event)
+		return writeOrderEvent(ctx, tx, outboxRepo, t.Order.ID, kafka.TopicOrderReturnRequested, envelope)
+	}
+}
+
+// publishReturned writes order.returned to the outbox
+func publishReturned(outboxRepo repository.OutboxRepository) Effect {
+	return func(ctx context.Context, tx *gorm.DB, t *Transition) error {
+		ret := t.Data.(*models.ReturnRequest)
+		event := kafka.OrderReturnedEvent{
+			OrderID:    t.Order.ID,
+			UserID:     t.Order.UserID,
+			ReturnID:   ret.ID,
+			Items:      returnItemEvents(ret),
+			ReceivedAt: t.At,
+		}
+		envelope := kafka.NewEnvelope(kafka.EventOrderReturned, kafka.OrderReturnedSchemaVersion, t.CorrelationID, event)
+		return writeOrderEvent(ctx, tx, outboxRepo, t.Order.ID, kafka.TopicOrderReturned, envelope)
+	}
+}
+
+// returnItemEvents converts return items to their event form
+func returnItemEvents(ret *models.ReturnRequest) []kafka.ReturnItemEvent {
+	items := make([]kafka.ReturnItemEvent, 0, len(ret.Items))
+	for _, item := range ret.Items {
+		items = append(items, kafka.ReturnItemEvent{
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			Price:       item.Price.Major(),
+			UnitPrice:   item.Price,
+		})
+	}
+	return items
+}
+
+// writeOrderEvent stores an order event in the outbox inside tx, keyed by order ID
+func writeOrderEvent(ctx context.Context, tx *gorm.DB, outboxRepo repository.OutboxRepository, orderID uint, topic string, envelope kafka.Envelope) error {
+	return outbox.Write(ctx, tx, outboxRepo, outbox.AggregateOrder, strconv.FormatUint(uint64(orderID), 10), topic, envelope)
+}
@@ -0,0 +1,175 @@
+package statemachine
+
+import (
+	"testing"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+func TestOrderMachineTransitions(t *testing.T) {
+	machine := NewOrderMachine(nil, nil, nil, nil)
+
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{models.OrderStatusPending, models.OrderStatusProcessing, true},
+		{models.OrderStatusPending, models.OrderStatusCancelled, true},
+		{models.OrderStatusProcessing, models.OrderStatusShipped, true},
+		{models.OrderStatusProcessing, models.OrderStatusPartiallyShipped, true},
+		{models.OrderStatusPartiallyShipped, models.OrderStatusShipped, true},
+		{models.OrderStatusProcessing, models.OrderStatusCancelled, true},
+		{models.OrderStatusShipped, models.OrderStatusDelivered, true},
+		{models.OrderStatusDelivered, models.OrderStatusReturnRequested, true},
+		{models.OrderStatusReturnRequested, models.OrderStatusDelivered, true},
+		{models.OrderStatusReturnRequested, models.OrderStatusReturned, true},
+		{models.OrderStatusReturned, models.OrderStatusRefunded, true},
+		{models.OrderStatusReturned, models.OrderStatusReturnRequested, true},
+		{models.OrderStatusRefunded, models.OrderStatusReturnRequested, true},
+		{models.OrderStatusReturnRequested, models.OrderStatusRefunded, true},
+		{models.OrderStatusCancelled, models.OrderStatusReturnRequested, false},
+		{models.OrderStatusDelivered, models.OrderStatusRefunded, false},
+		{models.OrderStatusShipped, models.OrderStatusReturnRequested, false},
+		{models.OrderStatusPending, models.OrderStatusShipped, false},
+		{models.OrderStatusShipped, models.OrderStatusCancelled, false},
+		{models.OrderStatusPartiallyShipped, models.OrderStatusCancelled, false},
+		{models.OrderStatusPartiallyShipped, models.OrderStatusDelivered, false},
+		{models.OrderStatusDelivered, models.OrderStatusPending, false},
+		{models.OrderStatusCancelled, models.OrderStatusPending, false},
+		{models.OrderStatusPending, models.OrderStatusPending, false},
+	}
+
+	for _, tt := range tests {
+		if got := machine.CanTransition(tt.from, tt.to); got != tt.allowed {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.allowed)
+		}
+	}
+}
+
+func TestStaffOnlyGuard(t *testing.T) {
+	customer := &Transition{Actor: models.Actor{Type: models.ActorTypeUser, ID: 1}}
+	if err := staffOnly(customer); err == nil {
+		t.Error("Expected customer to be rejected")
+	}
+
+	for _, actor := range []models.Actor{{Type: models.ActorTypeAdmin, ID: 2}, models.SystemActor} {
+		if err := staffOnly(&Transition{Actor: actor}); err != nil {
+			t.Errorf("Expected %s to be allowed, got %v", actor.Type, err)
+		}
+	}
+}
+
+func TestSystemOnlyGuard(t *testing.T) {
+	for _, actor := range []models.Actor{{Type: models.ActorTypeUser, ID: 1}, {Type: models.ActorTypeAdmin, ID: 2}} {
+		if err := systemOnly(&Transition{Actor: actor}); err == nil {
+			t.Errorf("Expected %s to be rejected", actor.Type)
+		}
+	}
+	if err := systemOnly(&Transition{Actor: models.SystemActor}); err != nil {
+		t.Errorf("Expected system to be allowed, got %v", err)
+	}
+}
+
+func TestRequireReturnGuard(t *testing.T) {
+	if err := requireReturn(&Transition{}); err == nil {
+		t.Error("Expected transition without a return request to be rejected")
+	}
+	if err := requireReturn(&Transition{Data: &models.ReturnRequest{}}); err != nil {
+		t.Errorf("Expected transition with a return request to be allowed, got %v", err)
+	}
+}
+
+func TestRequireShipmentGuard(t *testing.T) {
+	if err := requireShipment(&Transition{}); err == nil {
+		t.Error("Expected transition without a shipment to be rejected")
+	}
+	if err := requireShipment(&Transition{Data: &models.Shipment{}}); err != nil {
+		t.Errorf("Expected transition with a shipment to be allowed, got %v", err)
+	}
+}
+
+func TestShippingStatus(t *testing.T) {
+	items := []models.OrderItem{{ID: 1, Quantity: 2}, {ID: 2, Quantity: 1}}
+	shipment := func(status string, quantities map[uint]int) models.Shipment {
+		s := models.Shipment{Status: status}
+		for id, quantity := range quantities {
+			s.Items = append(s.Items, models.ShipmentItem{OrderItemID: id, Quantity: quantity})
+		}
+		return s
+	}
+
+	tests := []struct {
+		name      string
+		shipments []models.Shipment
+		want      string
+	}{
+		{"nothing shipped", nil, ""},
+		{"part of an item", []models.Shipment{
+			shipment(models.ShipmentStatusShipped, map[uint]int{1: 1, 2: 1}),
+		}, models.OrderStatusPartiallyShipped},
+		{"part delivered", []models.Shipment{
+			shipment(models.ShipmentStatusDelivered, map[uint]int{1: 2}),
+		}, models.OrderStatusPartiallyShipped},
+		{"everything shipped", []models.Shipment{
+			shipment(models.ShipmentStatusDelivered, map[uint]int{1: 2}),
+			shipment(models.ShipmentStatusInTransit, map[uint]int{2: 1}),
+		}, models.OrderStatusShipped},
+		{"everything delivered", []models.Shipment{
+			shipment(models.ShipmentStatusDelivered, map[uint]int{1: 1}),
+			shipment(models.ShipmentStatusDelivered, map[uint]int{1: 1, 2: 1}),
+		}, models.OrderStatusDelivered},
+	}
+
+	for _, tt := range tests {
+		if got := ShippingStatus(items, tt.shipments); got != tt.want {
+			t.Errorf("%s: ShippingStatus() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReturnStatus(t *testing.T) {
+	items := []models.OrderItem{{ID: 1, Quantity: 2}, {ID: 2, Quantity: 1}}
+	ret := func(status string, refund int64, quantities map[uint]int) models.ReturnRequest {
+		r := models.ReturnRequest{Status: status, Refund: money.New(refund, "THB")}
+		for id, quantity := range quantities {
+			r.Items = append(r.Items, models.ReturnItem{OrderItemID: id, Quantity: quantity, Price: money.New(100, "THB")})
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		returns []models.ReturnRequest
+		want    string
+	}{
+		{"open return", []models.ReturnRequest{
+			ret(models.ReturnStatusRefunded, 100, map[uint]int{1: 1}),
+			ret(models.ReturnStatusApproved, 0, map[uint]int{1: 1}),
+		}, models.OrderStatusReturnRequested},
+		{"every return rejected", []models.ReturnRequest{
+			ret(models.ReturnStatusRejected, 0, map[uint]int{1: 2}),
+		}, models.OrderStatusDelivered},
+		{"received, not refunded", []models.ReturnRequest{
+			ret(models.ReturnStatusReceived, 0, map[uint]int{1: 2, 2: 1}),
+		}, models.OrderStatusReturned},
+		{"part of the items refunded", []models.ReturnRequest{
+			ret(models.ReturnStatusRefunded, 100, map[uint]int{1: 1}),
+		}, models.OrderStatusReturned},
+		{"everything returned, part of the value refunded", []models.ReturnRequest{
+			ret(models.ReturnStatusRefunded, 200, map[uint]int{1: 2}),
+			ret(models.ReturnStatusRefunded, 50, map[uint]int{2: 1}),
+		}, models.OrderStatusReturned},
+		{"everything returned and refunded", []models.ReturnRequest{
+			ret(models.ReturnStatusRefunded, 100, map[uint]int{1: 1}),
+			ret(models.ReturnStatusRejected, 0, map[uint]int{2: 1}),
+			ret(models.ReturnStatusRefunded, 200, map[uint]int{1: 1, 2: 1}),
+		}, models.OrderStatusRefunded},
+	}
+
+	for _, tt := range tests {
+		if got := ReturnStatus(items, tt.returns); got != tt.want {
+			t.Errorf("%s: ReturnStatus() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
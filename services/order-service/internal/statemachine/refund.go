@@ -0,0 +1,99 @@
+package statemachine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/outbox"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+	paymentpb "github.com/ploezy/ecommerce-platform/proto/payment"
+	"gorm.io/gorm"
+)
+
+// Refund commands give captured money back when an order cannot keep it: a
+// staff cancel of a paid order, or a capture that arrives for an order that
+// was cancelled, expired or is already paid. Like restock commands they are
+// written in the transaction that decides the refund and the relay calls
+// payment-service with them until it approves. payment-service refunds an
+// idempotency key only once, so the retries are safe. The outbox row records
+// the result: sent once refunded, failed with the last error once the relay
+// gives up.
+const (
+	// TopicRefund is the outbox topic of refund commands. It is handled by
+	// RefundHandler and never published to Kafka.
+	TopicRefund = "command.payment.refund"
+	// EventRefundRequested is the event type of refund commands
+	EventRefundRequested = "payment.refund_requested"
+	// RefundSchemaVersion is the schema version of RefundCommand
+	RefundSchemaVersion = 1
+)
+
+// RefundCommand asks payment-service to refund everything left of a payment
+// intent once per Key
+type RefundCommand struct {
+	Key             string `json:"key"`
+	OrderID         uint   `json:"order_id"`
+	PaymentIntentID string `json:"payment_intent_id"`
+	Reason          string `json:"reason"`
+}
+
+// Refunder refunds captured payments, grpcclient.PaymentClient implements it
+type Refunder interface {
+	RefundPayment(ctx context.Context, intentID string, amount int64, reason, idempotencyKey string) (*paymentpb.PaymentIntentResponse, error)
+}
+
+// CancelRefundKey is the refund idempotency key of a cancelled paid order
+func CancelRefundKey(orderID uint) string {
+	return fmt.Sprintf("order:%d:cancel", orderID)
+}
+
+// CaptureRefundKey is the refund idempotency key of a capture the order could not take
+func CaptureRefundKey(paymentIntentID string) string {
+	return "capture:" + paymentIntentID
+}
+
+// refundPayment writes a command to refund the payment of a paid order
+func refundPayment(outboxRepo repository.OutboxRepository) Effect {
+	return func(ctx context.Context, tx *gorm.DB, t *Transition) error {
+		if t.Order.PaymentIntentID == "" {
+			return nil
+		}
+		return WriteRefund(ctx, tx, outboxRepo, t.CorrelationID, RefundCommand{
+			Key:             CancelRefundKey(t.Order.ID),
+			OrderID:         t.Order.ID,
+			PaymentIntentID: t.Order.PaymentIntentID,
+			Reason:          "order cancelled",
+		})
+	}
+}
+
+// WriteRefund stores a refund command in the outbox inside tx
+func WriteRefund(ctx context.Context, tx *gorm.DB, outboxRepo repository.OutboxRepository, correlationID string, command RefundCommand) error {
+	envelope := kafka.NewEnvelope(EventRefundRequested, RefundSchemaVersion, correlationID, command)
+	return outbox.Write(ctx, tx, outboxRepo, outbox.AggregateRefund, command.Key, TopicRefund, envelope)
+}
+
+// RefundHandler sends refund commands from the outbox to payment-service
+func RefundHandler(refunder Refunder) outbox.CommandHandler {
+	return func(ctx context.Context, payload json.RawMessage) error {
+		var envelope struct {
+			Data RefundCommand `json:"data"`
+		}
+		if err := json.Unmarshal(payload, &envelope); err != nil {
+			return fmt.Errorf("failed to decode refund command: %w", err)
+		}
+		command := envelope.Data
+
+		resp, err := refunder.RefundPayment(ctx, command.PaymentIntentID, 0, command.Reason, command.Key)
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			return errors.New(resp.Message)
+		}
+		return nil
+	}
+}
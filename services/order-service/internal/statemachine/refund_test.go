@@ -0,0 +1,93 @@
+package statemachine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/outbox"
+	paymentpb "github.com/ploezy/ecommerce-platform/proto/payment"
+)
+
+// fakeRefunder records refunds and declines them while decline is set
+type fakeRefunder struct {
+	intents []string
+	keys    []string
+	decline string
+}
+
+func (r *fakeRefunder) RefundPayment(ctx context.Context, intentID string, amount int64, reason, idempotencyKey string) (*paymentpb.PaymentIntentResponse, error) {
+	if r.decline != "" {
+		return &paymentpb.PaymentIntentResponse{Success: false, Message: r.decline}, nil
+	}
+	r.intents = append(r.intents, intentID)
+	r.keys = append(r.keys, idempotencyKey)
+	return &paymentpb.PaymentIntentResponse{Success: true}, nil
+}
+
+// refundCommands returns the refund commands in the outbox
+func (f *machineFixture) refundCommands(t *testing.T) []models.OutboxEvent {
+	t.Helper()
+	var events []models.OutboxEvent
+	if err := f.db.Where("topic = ?", TopicRefund).Order("id").Find(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestStaffCancelRefundsPaidOrder(t *testing.T) {
+	f := newMachineFixture(t)
+	ctx := context.Background()
+	order := f.createOrder(t, models.OrderStatusProcessing)
+	if err := f.db.Model(order).Update("payment_intent_id", "pi_1").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := f.machine.Fire(ctx, Request{
+		OrderID: order.ID,
+		To:      models.OrderStatusCancelled,
+		Actor:   models.Actor{Type: models.ActorTypeAdmin, ID: 9},
+	})
+	if err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+	commands := f.refundCommands(t)
+	if len(commands) != 1 {
+		t.Fatalf("cancel wrote %d refund commands, want 1", len(commands))
+	}
+	key := CancelRefundKey(order.ID)
+	if commands[0].AggregateType != outbox.AggregateRefund || commands[0].AggregateID != key {
+		t.Errorf("command = %s %s, want a %s command for %s", commands[0].AggregateType, commands[0].AggregateID, outbox.AggregateRefund, key)
+	}
+
+	// A declined refund stays in the outbox for the relay to retry
+	refunder := &fakeRefunder{decline: "payment-service unavailable"}
+	if err := RefundHandler(refunder)(ctx, json.RawMessage(commands[0].Payload)); err == nil {
+		t.Fatal("RefundHandler() error = nil for a declined refund")
+	}
+	refunder.decline = ""
+	if err := RefundHandler(refunder)(ctx, json.RawMessage(commands[0].Payload)); err != nil {
+		t.Fatalf("RefundHandler() error = %v", err)
+	}
+	if len(refunder.intents) != 1 || refunder.intents[0] != "pi_1" || refunder.keys[0] != key {
+		t.Errorf("refunded %v with keys %v, want pi_1 with %s", refunder.intents, refunder.keys, key)
+	}
+}
+
+func TestCancelOfUnpaidOrderIsNotRefunded(t *testing.T) {
+	f := newMachineFixture(t)
+	order := f.createOrder(t, models.OrderStatusPending)
+
+	_, err := f.machine.Fire(context.Background(), Request{
+		OrderID: order.ID,
+		To:      models.OrderStatusCancelled,
+		Actor:   models.Actor{Type: models.ActorTypeUser, ID: 1},
+	})
+	if err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+	if commands := f.refundCommands(t); len(commands) != 0 {
+		t.Errorf("cancel of an unpaid order wrote %d refund commands", len(commands))
+	}
+}
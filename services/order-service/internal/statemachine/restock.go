@@ -0,0 +1,123 @@
+package statemachine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/outbox"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/order-service/pkg/kafka"
+	pb "github.com/ploezy/ecommerce-platform/proto/product"
+	"gorm.io/gorm"
+)
+
+// Restock commands go through the outbox so stock is given back exactly once:
+// they are written in the transaction of the status change and the relay
+// calls product-service with them until it acknowledges. product-service
+// applies a reference only once, so the retries are safe.
+const (
+	// TopicRestock is the outbox topic of restock commands. It is handled by
+	// RestockHandler and never published to Kafka.
+	TopicRestock = "command.product.restock"
+	// EventRestockRequested is the event type of restock commands
+	EventRestockRequested = "product.restock_requested"
+	// RestockSchemaVersion is the schema version of RestockCommand
+	RestockSchemaVersion = 1
+)
+
+// RestockCommand asks product-service to give stock back once per Reference
+type RestockCommand struct {
+	Reference string        `json:"reference"`
+	OrderID   uint          `json:"order_id"`
+	Items     []RestockItem `json:"items"`
+}
+
+// RestockItem is the quantity of a product to give back
+type RestockItem struct {
+	ProductID uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+// Restocker gives stock back to products, grpcclient.ProductClient implements it
+type Restocker interface {
+	RestockItems(ctx context.Context, reference string, items map[uint32]int32) (*pb.RestockItemsResponse, error)
+}
+
+// CancelRestockReference is the restock reference of a cancelled order
+func CancelRestockReference(orderID uint) string {
+	return fmt.Sprintf("order:%d:cancel", orderID)
+}
+
+// ReturnRestockReference is the restock reference of a received return
+func ReturnRestockReference(returnID uint) string {
+	return fmt.Sprintf("return:%d", returnID)
+}
+
+// restockItems writes a command to give the stock of every order item back
+func restockItems(outboxRepo repository.OutboxRepository) Effect {
+	return func(ctx context.Context, tx *gorm.DB, t *Transition) error {
+		command := RestockCommand{
+			Reference: CancelRestockReference(t.Order.ID),
+			OrderID:   t.Order.ID,
+			Items:     make([]RestockItem, 0, len(t.Order.Items)),
+		}
+		for _, item := range t.Order.Items {
+			command.Items = append(command.Items, RestockItem{ProductID: item.ProductID, Quantity: item.Quantity})
+		}
+		return writeRestock(ctx, tx, outboxRepo, t, command)
+	}
+}
+
+// restockReturnedItems writes a command to give the stock of received return items back
+func restockReturnedItems(outboxRepo repository.OutboxRepository) Effect {
+	return func(ctx context.Context, tx *gorm.DB, t *Transition) error {
+		ret := t.Data.(*models.ReturnRequest)
+		command := RestockCommand{
+			Reference: ReturnRestockReference(ret.ID),
+			OrderID:   t.Order.ID,
+			Items:     make([]RestockItem, 0, len(ret.Items)),
+		}
+		for _, item := range ret.Items {
+			command.Items = append(command.Items, RestockItem{ProductID: item.ProductID, Quantity: item.Quantity})
+		}
+		return writeRestock(ctx, tx, outboxRepo, t, command)
+	}
+}
+
+// writeRestock stores a restock command in the outbox inside tx
+func writeRestock(ctx context.Context, tx *gorm.DB, outboxRepo repository.OutboxRepository, t *Transition, command RestockCommand) error {
+	if len(command.Items) == 0 {
+		return nil
+	}
+	envelope := kafka.NewEnvelope(EventRestockRequested, RestockSchemaVersion, t.CorrelationID, command)
+	return outbox.Write(ctx, tx, outboxRepo, outbox.AggregateRestock, command.Reference, TopicRestock, envelope)
+}
+
+// RestockHandler sends restock commands from the outbox to product-service
+func RestockHandler(restocker Restocker) outbox.CommandHandler {
+	return func(ctx context.Context, payload json.RawMessage) error {
+		var envelope struct {
+			Data RestockCommand `json:"data"`
+		}
+		if err := json.Unmarshal(payload, &envelope); err != nil {
+			return fmt.Errorf("failed to decode restock command: %w", err)
+		}
+		command := envelope.Data
+
+		items := make(map[uint32]int32, len(command.Items))
+		for _, item := range command.Items {
+			items[uint32(item.ProductID)] += int32(item.Quantity)
+		}
+		resp, err := restocker.RestockItems(ctx, command.Reference, items)
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			return errors.New(resp.Message)
+		}
+		return nil
+	}
+}
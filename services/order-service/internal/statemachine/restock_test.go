@@ -0,0 +1,221 @@
+package statemachine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/outbox"
+	"github.com/ploezy/ecommerce-platform/order-service/internal/repository"
+	pb "github.com/ploezy/ecommerce-platform/proto/product"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeRestocker records restock calls and fails while err is set
+type fakeRestocker struct {
+	references []string
+	items      []map[uint32]int32
+	err        error
+}
+
+func (r *fakeRestocker) RestockItems(ctx context.Context, reference string, items map[uint32]int32) (*pb.RestockItemsResponse, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.references = append(r.references, reference)
+	r.items = append(r.items, items)
+	return &pb.RestockItemsResponse{Success: true, Applied: true}, nil
+}
+
+type machineFixture struct {
+	db      *gorm.DB
+	outbox  repository.OutboxRepository
+	machine *Machine
+}
+
+func newMachineFixture(t *testing.T) *machineFixture {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	err = db.AutoMigrate(&models.Order{}, &models.OrderItem{}, &models.OrderItemAdjustment{},
+		&models.OrderStatusHistory{}, &models.OutboxEvent{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outboxRepo := repository.NewOutboxRepository(db)
+	return &machineFixture{
+		db:     db,
+		outbox: outboxRepo,
+		machine: NewOrderMachine(db, repository.NewOrderRepository(db),
+			repository.NewOrderStatusHistoryRepository(db), outboxRepo),
+	}
+}
+
+func (f *machineFixture) createOrder(t *testing.T, status string) *models.Order {
+	t.Helper()
+	order := &models.Order{
+		UserID: 1,
+		Status: status,
+		Items: []models.OrderItem{
+			{ProductID: 10, Quantity: 2},
+			{ProductID: 11, Quantity: 1},
+		},
+	}
+	if err := f.db.Create(order).Error; err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+// restockCommands returns the restock commands in the outbox
+func (f *machineFixture) restockCommands(t *testing.T) []models.OutboxEvent {
+	t.Helper()
+	var events []models.OutboxEvent
+	if err := f.db.Where("topic = ?", TopicRestock).Order("id").Find(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestCancelWritesRestockCommandInTransaction(t *testing.T) {
+	f := newMachineFixture(t)
+	ctx := context.Background()
+	order := f.createOrder(t, models.OrderStatusPending)
+
+	// A cancel that rolls back leaves no restock behind
+	_, err := f.machine.Fire(ctx, Request{
+		OrderID: order.ID,
+		To:      models.OrderStatusCancelled,
+		Actor:   models.Actor{Type: models.ActorTypeUser, ID: 1},
+		Apply: func(ctx context.Context, tx *gorm.DB, order *models.Order) error {
+			return errors.New("database unavailable")
+		},
+	})
+	if err == nil {
+		t.Fatal("Fire() error = nil, want the Apply error")
+	}
+	if commands := f.restockCommands(t); len(commands) != 0 {
+		t.Fatalf("rolled back cancel wrote %d restock commands", len(commands))
+	}
+
+	_, err = f.machine.Fire(ctx, Request{
+		OrderID: order.ID,
+		To:      models.OrderStatusCancelled,
+		Actor:   models.Actor{Type: models.ActorTypeUser, ID: 1},
+	})
+	if err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+	commands := f.restockCommands(t)
+	if len(commands) != 1 {
+		t.Fatalf("cancel wrote %d restock commands, want 1", len(commands))
+	}
+	command := commands[0]
+	reference := CancelRestockReference(order.ID)
+	if command.AggregateType != outbox.AggregateRestock || command.AggregateID != reference || command.Status != models.OutboxStatusPending {
+		t.Errorf("command = %s %s %s, want a pending %s command for %s", command.AggregateType, command.AggregateID, command.Status, outbox.AggregateRestock, reference)
+	}
+
+	// The relay hands the command to product-service with its reference
+	restocker := &fakeRestocker{}
+	if err := RestockHandler(restocker)(ctx, json.RawMessage(command.Payload)); err != nil {
+		t.Fatalf("RestockHandler() error = %v", err)
+	}
+	if len(restocker.references) != 1 || restocker.references[0] != reference {
+		t.Fatalf("restocked %v, want %s", restocker.references, reference)
+	}
+	if items := restocker.items[0]; len(items) != 2 || items[10] != 2 || items[11] != 1 {
+		t.Errorf("restocked items = %v, want product 10 x2 and product 11 x1", items)
+	}
+}
+
+func TestReturnRestockUsesReturnReference(t *testing.T) {
+	f := newMachineFixture(t)
+	ctx := context.Background()
+	order := f.createOrder(t, models.OrderStatusReturnRequested)
+	ret := &models.ReturnRequest{
+		ID:      7,
+		OrderID: order.ID,
+		Status:  models.ReturnStatusReceived,
+		Items:   []models.ReturnItem{{OrderItemID: order.Items[0].ID, ProductID: 10, Quantity: 1}},
+	}
+
+	_, err := f.machine.Fire(ctx, Request{
+		OrderID: order.ID,
+		To:      models.OrderStatusReturned,
+		Actor:   models.SystemActor,
+		Data:    ret,
+	})
+	if err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+	commands := f.restockCommands(t)
+	if len(commands) != 1 || commands[0].AggregateID != ReturnRestockReference(ret.ID) {
+		t.Fatalf("restock commands = %+v, want one for %s", commands, ReturnRestockReference(ret.ID))
+	}
+
+	restocker := &fakeRestocker{}
+	if err := RestockHandler(restocker)(ctx, json.RawMessage(commands[0].Payload)); err != nil {
+		t.Fatal(err)
+	}
+	if items := restocker.items[0]; len(items) != 1 || items[10] != 1 {
+		t.Errorf("restocked items = %v, want product 10 x1", items)
+	}
+}
+
+func TestRejectedReturnIsNotRestocked(t *testing.T) {
+	f := newMachineFixture(t)
+	order := f.createOrder(t, models.OrderStatusReturnRequested)
+
+	// A rejected return takes the order back to returned when an earlier
+	// return was received, without restocking the rejected items
+	_, err := f.machine.Fire(context.Background(), Request{
+		OrderID: order.ID,
+		To:      models.OrderStatusReturned,
+		Actor:   models.SystemActor,
+		Data: &models.ReturnRequest{
+			ID:     8,
+			Status: models.ReturnStatusRejected,
+			Items:  []models.ReturnItem{{OrderItemID: order.Items[0].ID, ProductID: 10, Quantity: 1}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+	if commands := f.restockCommands(t); len(commands) != 0 {
+		t.Errorf("rejected return wrote %d restock commands", len(commands))
+	}
+}
+
+func TestRestockHandlerFailsUntilAcknowledged(t *testing.T) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"data": RestockCommand{Reference: "order:1:cancel", Items: []RestockItem{{ProductID: 10, Quantity: 1}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restocker := &fakeRestocker{err: errors.New("product-service unavailable")}
+	if err := RestockHandler(restocker)(context.Background(), payload); err == nil {
+		t.Error("RestockHandler() error = nil while product-service is down, want an error so the relay retries")
+	}
+	if err := RestockHandler(restocker)(context.Background(), json.RawMessage("not json")); err == nil {
+		t.Error("RestockHandler() error = nil for a broken payload")
+	}
+}
@@ -1,23 +1,155 @@
 package database
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/ploezy/ecommerce-platform/order-service/internal/models"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+	"gorm.io/gorm"
 )
 
 func AutoMigrate() error {
 	log.Println("Running database migrations...")
 
+	if err := migrateMoneyColumns(DB); err != nil {
+		return err
+	}
+	// Orders from before promotions get their total as subtotal after AutoMigrate
+	backfillSubtotals := DB.Migrator().HasTable(&models.Order{}) && !DB.Migrator().HasColumn(&models.Order{}, "subtotal_amount")
+	// Orders from before shipping fees and tax get zero amounts in their own currency
+	backfillTax := DB.Migrator().HasTable(&models.Order{}) && !DB.Migrator().HasColumn(&models.Order{}, "tax_amount")
+
 	// Auto migrate models
 	err := DB.AutoMigrate(
 		&models.Order{},
 		&models.OrderItem{},
+		&models.Saga{},
+		&models.OutboxEvent{},
+		&models.OrderStatusHistory{},
+		&models.ReturnRequest{},
+		&models.ReturnItem{},
+		&models.Shipment{},
+		&models.ShipmentItem{},
+		&models.ShipmentTrackingEvent{},
+		&models.Promotion{},
+		&models.OrderDiscount{},
+		&models.OrderItemAdjustment{},
 	)	
 
 	if err != nil{
 		return err
 	}
+	if backfillSubtotals {
+		if err := backfillOrderSubtotals(DB); err != nil {
+			return err
+		}
+	}
+	if backfillTax {
+		if err := backfillOrderTaxCurrencies(DB); err != nil {
+			return err
+		}
+	}
 	log.Println("Database migration complete successfully")
 	return nil
-}
\ No newline at end of file
+}
+
+// legacyMoneyColumn is a decimal column from before amounts were stored in
+// minor units, and the prefix of the money columns that replace it
+type legacyMoneyColumn struct {
+	table  string
+	column string
+	prefix string
+}
+
+var legacyMoneyColumns = []legacyMoneyColumn{
+	{table: "orders", column: "total_amount", prefix: "total_"},
+	{table: "order_items", column: "price", prefix: "price_"},
+	{table: "order_items", column: "subtotal", prefix: "subtotal_"},
+	{table: "return_requests", column: "refund_amount", prefix: "refund_"},
+	{table: "return_items", column: "price", prefix: "price_"},
+}
+
+// migrateMoneyColumns converts the decimal amount columns of existing tables to
+// minor units with a currency column. Existing amounts are in the default
+// currency and are rounded half away from zero.
+func migrateMoneyColumns(db *gorm.DB) error {
+	factor := 1
+	for i := 0; i < money.Exponent(money.DefaultCurrency); i++ {
+		factor *= 10
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+		for _, legacy := range legacyMoneyColumns {
+			amountColumn := legacy.prefix + "amount"
+			currencyColumn := legacy.prefix + "currency"
+			if !migrator.HasTable(legacy.table) || migrator.HasColumn(legacy.table, currencyColumn) {
+				continue
+			}
+			if !migrator.HasColumn(legacy.table, legacy.column) {
+				continue
+			}
+
+			log.Printf("Converting %s.%s to minor units...", legacy.table, legacy.column)
+			var statements []string
+			if legacy.column == amountColumn {
+				statements = []string{
+					fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE bigint USING ROUND(%s * %d)", legacy.table, amountColumn, amountColumn, factor),
+				}
+			} else {
+				statements = []string{
+					fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s bigint", legacy.table, amountColumn),
+					fmt.Sprintf("UPDATE %s SET %s = ROUND(%s::numeric * %d)", legacy.table, amountColumn, legacy.column, factor),
+					fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", legacy.table, legacy.column),
+				}
+			}
+			statements = append(statements,
+				fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s varchar(3) NOT NULL DEFAULT '%s'", legacy.table, currencyColumn, money.DefaultCurrency),
+			)
+
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return fmt.Errorf("failed to convert %s.%s: %w", legacy.table, legacy.column, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// backfillOrderSubtotals sets the subtotal of orders created before promotions
+// to their total, they have no discount
+func backfillOrderSubtotals(db *gorm.DB) error {
+	log.Println("Backfilling order subtotals...")
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			"UPDATE orders SET subtotal_amount = total_amount, subtotal_currency = total_currency, discount_currency = total_currency",
+			"UPDATE order_items SET discount_currency = subtotal_currency",
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to backfill order subtotals: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// backfillOrderTaxCurrencies sets the currency of the zero shipping fee and tax
+// of orders created before they were calculated to the currency of the order
+func backfillOrderTaxCurrencies(db *gorm.DB) error {
+	log.Println("Backfilling order shipping fee and tax currencies...")
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			"UPDATE orders SET shipping_fee_currency = total_currency, tax_currency = total_currency, tax_included_currency = total_currency",
+			"UPDATE order_items SET tax_currency = subtotal_currency",
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to backfill order tax currencies: %w", err)
+			}
+		}
+		return nil
+	})
+}
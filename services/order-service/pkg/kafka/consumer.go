@@ -0,0 +1,91 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/order-service/config"
+	"github.com/segmentio/kafka-go"
+)
+
+// consumerRetryBackoff is how long a failed message waits before it is retried
+const consumerRetryBackoff = 5 * time.Second
+
+// MessageHandler processes the value of one message. A returned error is
+// retried, so handlers must skip messages they can never process.
+type MessageHandler func(ctx context.Context, value []byte) error
+
+// Consumer reads one topic as part of the order-service consumer group
+type Consumer struct {
+	reader *kafka.Reader
+}
+
+// NewConsumer creates a consumer for topic
+func NewConsumer(cfg *config.Config, topic string) *Consumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: strings.Split(cfg.KafkaBrokers, ","),
+		GroupID: cfg.KafkaConsumerGroup,
+		Topic:   topic,
+	})
+
+	return &Consumer{reader: reader}
+}
+
+// Run handles messages until ctx is cancelled. The offset of a message is only
+// committed after it was handled, so delivery is at-least-once.
+func (c *Consumer) Run(ctx context.Context, handle MessageHandler) {
+	topic := c.reader.Config().Topic
+	log.Printf("Kafka consumer started for topic [%s]", topic)
+
+	for {
+		message, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				log.Printf("Kafka consumer stopped for topic [%s]", topic)
+				return
+			}
+			log.Printf("Failed to fetch message from [%s]: %v", topic, err)
+			if !sleep(ctx, consumerRetryBackoff) {
+				return
+			}
+			continue
+		}
+
+		// Retry until handled, later messages of the partition keep their order
+		for {
+			err := handle(ctx, message.Value)
+			if err == nil {
+				break
+			}
+			log.Printf("Failed to handle message %s/%d/%d, retrying: %v", topic, message.Partition, message.Offset, err)
+			if !sleep(ctx, consumerRetryBackoff) {
+				return
+			}
+		}
+
+		if err := c.reader.CommitMessages(ctx, message); err != nil {
+			log.Printf("Failed to commit message %s/%d/%d: %v", topic, message.Partition, message.Offset, err)
+		}
+	}
+}
+
+// Close closes the Kafka reader
+func (c *Consumer) Close() error {
+	return c.reader.Close()
+}
+
+// sleep waits for d and reports false if ctx was cancelled first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -1,28 +1,136 @@
 package kafka
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
+
+// Topics for order events
+const (
+	TopicOrderCreated         = "order.created"
+	TopicOrderStatusChanged   = "order.status_changed"
+	TopicOrderCancelled       = "order.cancelled"
+	TopicOrderReturnRequested = "order.return_requested"
+	TopicOrderReturned        = "order.returned"
+	TopicOrderRefunded        = "order.refunded"
+	TopicShipmentCreated      = "order.shipment_created"
+	TopicShipmentUpdated      = "order.shipment_updated"
+)
+
+// Event types. Each event type is published on the topic of the same name.
+const (
+	EventOrderCreated         = TopicOrderCreated
+	EventOrderStatusChanged   = TopicOrderStatusChanged
+	EventOrderCancelled       = TopicOrderCancelled
+	EventOrderReturnRequested = TopicOrderReturnRequested
+	EventOrderReturned        = TopicOrderReturned
+	EventOrderRefunded        = TopicOrderRefunded
+	EventShipmentCreated      = TopicShipmentCreated
+	EventShipmentUpdated      = TopicShipmentUpdated
+)
+
+// Schema versions of the event payloads, see readme.md in this package before changing them
+const (
+	OrderCreatedSchemaVersion         = 1
+	OrderStatusChangedSchemaVersion   = 1
+	OrderCancelledSchemaVersion       = 1
+	OrderReturnRequestedSchemaVersion = 1
+	OrderReturnedSchemaVersion        = 1
+	OrderRefundedSchemaVersion        = 1
+	ShipmentCreatedSchemaVersion      = 1
+	ShipmentUpdatedSchemaVersion      = 1
+)
+
+// ProducerName identifies order-service as the producer of an event
+const ProducerName = "order-service"
+
+// Envelope wraps every event published by order-service
+type Envelope struct {
+	EventID       string      `json:"event_id"`
+	EventType     string      `json:"event_type"`
+	SchemaVersion int         `json:"schema_version"`
+	OccurredAt    time.Time   `json:"occurred_at"`
+	Producer      string      `json:"producer"`
+	CorrelationID string      `json:"correlation_id,omitempty"`
+	Data          interface{} `json:"data"`
+}
+
+// NewEnvelope wraps event data with a new event ID and the current time
+func NewEnvelope(eventType string, schemaVersion int, correlationID string, data interface{}) Envelope {
+	return Envelope{
+		EventID:       uuid.NewString(),
+		EventType:     eventType,
+		SchemaVersion: schemaVersion,
+		OccurredAt:    time.Now().UTC(),
+		Producer:      ProducerName,
+		CorrelationID: correlationID,
+		Data:          data,
+	}
+}
 
 // OrderCreatedEvent represents an order creation event
 type OrderCreatedEvent struct {
-	OrderID     uint      `json:"order_id"`
-	UserID      uint      `json:"user_id"`
-	TotalAmount float64   `json:"total_amount"`
-	Status      string    `json:"status"`
-	Items       []OrderItemEvent `json:"items"`
-	CreatedAt   time.Time `json:"created_at"`
+	OrderID uint `json:"order_id"`
+	UserID  uint `json:"user_id"`
+	// Deprecated: use Total. Kept for v1 consumers, removed in v2.
+	TotalAmount float64              `json:"total_amount"`
+	Subtotal    money.Money          `json:"subtotal"`
+	Discount    money.Money          `json:"discount"`
+	ShippingFee money.Money          `json:"shipping_fee"`
+	Tax         money.Money          `json:"tax"`
+	TaxIncluded money.Money          `json:"tax_included"`
+	Total       money.Money          `json:"total"`
+	Status      string               `json:"status"`
+	Items       []OrderItemEvent     `json:"items"`
+	Discounts   []OrderDiscountEvent `json:"discounts"`
+	// ShippingAddress is the address the order ships to, as it was when the order was placed
+	ShippingAddress ShippingAddressEvent `json:"shipping_address"`
+	CreatedAt       time.Time            `json:"created_at"`
 }
 
 // OrderItemEvent represents an order item in the event
 type OrderItemEvent struct {
-	ProductID uint    `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
-	Subtotal  float64 `json:"subtotal"`
+	ProductID uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+	// Deprecated: use UnitPrice. Kept for v1 consumers, removed in v2.
+	Price float64 `json:"price"`
+	// Deprecated: use LineTotal. Kept for v1 consumers, removed in v2.
+	Subtotal  float64     `json:"subtotal"`
+	UnitPrice money.Money `json:"unit_price"`
+	LineTotal money.Money `json:"line_total"`
+	// Discount is the part of the order discounts taken off this line
+	Discount money.Money `json:"discount"`
+	// Tax is the tax of the discounted line
+	Tax money.Money `json:"tax"`
+}
+
+// ShippingAddressEvent represents the shipping address of an order
+type ShippingAddressEvent struct {
+	RecipientName string `json:"recipient_name"`
+	Phone         string `json:"phone"`
+	Line1         string `json:"line1"`
+	Line2         string `json:"line2,omitempty"`
+	District      string `json:"district,omitempty"`
+	City          string `json:"city"`
+	Region        string `json:"region,omitempty"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+}
+
+// OrderDiscountEvent represents a promotion applied to an order
+type OrderDiscountEvent struct {
+	PromotionID uint        `json:"promotion_id"`
+	Code        string      `json:"code,omitempty"`
+	Name        string      `json:"name"`
+	Amount      money.Money `json:"amount"`
 }
 
 // OrderStatusChangedEvent represents an order status change event
 type OrderStatusChangedEvent struct {
 	OrderID   uint      `json:"order_id"`
+	UserID    uint      `json:"user_id"`
 	OldStatus string    `json:"old_status"`
 	NewStatus string    `json:"new_status"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -34,4 +142,68 @@ type OrderCancelledEvent struct {
 	UserID      uint      `json:"user_id"`
 	Reason      string    `json:"reason"`
 	CancelledAt time.Time `json:"cancelled_at"`
-}
\ No newline at end of file
+}
+
+// ReturnItemEvent represents a returned item in return events
+type ReturnItemEvent struct {
+	OrderItemID uint `json:"order_item_id"`
+	ProductID   uint `json:"product_id"`
+	Quantity    int  `json:"quantity"`
+	// Deprecated: use UnitPrice. Kept for v1 consumers, removed in v2.
+	Price     float64     `json:"price"`
+	UnitPrice money.Money `json:"unit_price"`
+}
+
+// OrderReturnRequestedEvent represents a customer return request
+type OrderReturnRequestedEvent struct {
+	OrderID     uint              `json:"order_id"`
+	UserID      uint              `json:"user_id"`
+	ReturnID    uint              `json:"return_id"`
+	Reason      string            `json:"reason"`
+	Items       []ReturnItemEvent `json:"items"`
+	RequestedAt time.Time         `json:"requested_at"`
+}
+
+// OrderReturnedEvent represents returned goods received back in the warehouse
+type OrderReturnedEvent struct {
+	OrderID    uint              `json:"order_id"`
+	UserID     uint              `json:"user_id"`
+	ReturnID   uint              `json:"return_id"`
+	Items      []ReturnItemEvent `json:"items"`
+	ReceivedAt time.Time         `json:"received_at"`
+}
+
+// OrderRefundedEvent represents a refund issued for a return
+type OrderRefundedEvent struct {
+	OrderID  uint `json:"order_id"`
+	UserID   uint `json:"user_id"`
+	ReturnID uint `json:"return_id"`
+	// Deprecated: use Refund. Kept for v1 consumers, removed in v2.
+	Amount     float64     `json:"amount"`
+	Refund     money.Money `json:"refund"`
+	FullRefund bool        `json:"full_refund"`
+	RefundedAt time.Time   `json:"refunded_at"`
+}
+
+// ShipmentItemEvent represents an order item in shipment events
+type ShipmentItemEvent struct {
+	OrderItemID uint `json:"order_item_id"`
+	ProductID   uint `json:"product_id"`
+	Quantity    int  `json:"quantity"`
+}
+
+// ShipmentEvent represents a shipment handed to a carrier or a tracking update
+// of it. OrderStatus is the status of the order after the change.
+type ShipmentEvent struct {
+	ShipmentID     uint                `json:"shipment_id"`
+	OrderID        uint                `json:"order_id"`
+	UserID         uint                `json:"user_id"`
+	Carrier        string              `json:"carrier"`
+	TrackingNumber string              `json:"tracking_number"`
+	Status         string              `json:"status"`
+	OrderStatus    string              `json:"order_status"`
+	Items          []ShipmentItemEvent `json:"items"`
+	ShippedAt      *time.Time          `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time          `json:"delivered_at,omitempty"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
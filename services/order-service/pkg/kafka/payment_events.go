@@ -0,0 +1,39 @@
+package kafka
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Topics published by payment-service that order-service consumes
+const (
+	TopicPaymentCaptured = "payment.captured"
+)
+
+// PaymentSchemaVersion is the payment event schema version order-service understands
+const PaymentSchemaVersion = 1
+
+// IncomingEnvelope is an envelope read from Kafka. Data is decoded once the
+// event type and schema version are known.
+type IncomingEnvelope struct {
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	SchemaVersion int             `json:"schema_version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Producer      string          `json:"producer"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// PaymentEvent is the payload of payment-service events. Amounts are minor units.
+type PaymentEvent struct {
+	PaymentIntentID string    `json:"payment_intent_id"`
+	OrderID         uint      `json:"order_id"`
+	UserID          uint      `json:"user_id"`
+	Amount          int64     `json:"amount"`
+	Currency        string    `json:"currency"`
+	CapturedTotal   int64     `json:"captured_total"`
+	RefundedTotal   int64     `json:"refunded_total"`
+	Reason          string    `json:"reason,omitempty"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
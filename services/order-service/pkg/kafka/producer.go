@@ -25,7 +25,7 @@ func NewProducer(cfg *config.Config) (*Producer, error) {
 	// Create Kafka writer
 	writer := &kafka.Writer{
 		Addr:         kafka.TCP(brokers...),
-		Balancer:     &kafka.LeastBytes{},
+		Balancer:     &kafka.Hash{}, // same key, same partition: keeps per-order ordering
 		BatchTimeout: 10 * time.Millisecond,
 	}
 
@@ -42,7 +42,8 @@ func GetProducer() *Producer {
 	return producer
 }
 
-// PublishEvent publishes an event to a specific topic
+// PublishEvent publishes an event to a specific topic. Messages with the same
+// key always go to the same partition.
 func (p *Producer) PublishEvent(topic string, key string, data interface{}) error {
 	// Convert data to JSON
 	payload, err := json.Marshal(data)
@@ -69,47 +70,6 @@ func (p *Producer) PublishEvent(topic string, key string, data interface{}) erro
 	return nil
 }
 
-// SendEvent is a generic method to send events to Kafka (ใช้กับ kafka-go)
-func (p *Producer) SendEvent(topic string, event interface{}) error {
-	// Convert event to JSON
-	eventJSON, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
-	}
-
-	// Create Kafka message
-	message := kafka.Message{
-		Topic: topic,
-		Value: eventJSON,
-		Time:  time.Now(),
-	}
-	
-	// Send message
-	ctx := context.Background()
-	err = p.writer.WriteMessages(ctx, message)
-	if err != nil {
-		return fmt.Errorf("failed to send message to kafka: %w", err)
-	}
-
-	log.Printf("Event sent to topic=%s\n", topic)
-	return nil
-}
-
-// SendOrderCreated sends order created event
-func (p *Producer) SendOrderCreated(event OrderCreatedEvent) error {
-	return p.SendEvent("order.created", event)
-}
-
-// SendOrderStatusChanged sends order status changed event
-func (p *Producer) SendOrderStatusChanged(event OrderStatusChangedEvent) error {
-	return p.SendEvent("order.status_changed", event)
-}
-
-// SendOrderCancelled sends order cancelled event
-func (p *Producer) SendOrderCancelled(event OrderCancelledEvent) error {
-	return p.SendEvent("order.cancelled", event)
-}
-
 // Close closes the Kafka writer
 func (p *Producer) Close() error {
 	if p.writer != nil {
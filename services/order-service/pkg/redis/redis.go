@@ -30,6 +30,15 @@ func ConnectRedis(cfg *config.Config) error {
 	return nil
 }
 
+// NewClient creates a client on another DB of the Redis server of cfg
+func NewClient(cfg *config.Config, db int) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort),
+		Password: cfg.RedisPassword,
+		DB:       db,
+	})
+}
+
 func GetClient() *redis.Client {
 	return Client
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ploezy/ecommerce-platform/payment-service/config"
+	grpcHandler "github.com/ploezy/ecommerce-platform/payment-service/internal/grpc/handler"
+	grpcServer "github.com/ploezy/ecommerce-platform/payment-service/internal/grpc/server"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/outbox"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/provider"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/service"
+	"github.com/ploezy/ecommerce-platform/payment-service/pkg/database"
+	"github.com/ploezy/ecommerce-platform/payment-service/pkg/kafka"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	log.Println("Configuration loaded successfully")
+
+	// Connect to PostgreSQL
+	db, err := database.ConnectPostgres(database.DatabaseConfig{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DBName,
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	// Run Auto Migration
+	if err := database.AutoMigrate(db); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	// Initialize payment provider
+	paymentProvider, err := provider.New(cfg.Payment.Provider)
+	if err != nil {
+		log.Fatalf("Failed to initialize payment provider: %v", err)
+	}
+	log.Printf("Payment provider: %s\n", paymentProvider.Name())
+
+	// Initialize Kafka producer
+	producer, err := kafka.NewProducer(cfg.Kafka.Brokers)
+	if err != nil {
+		log.Fatalf("Failed to initialize Kafka producer: %v", err)
+	}
+	defer producer.Close()
+
+	// Initialize layers
+	paymentRepo := repository.NewPaymentRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	paymentService := service.NewPaymentService(db, paymentRepo, outboxRepo, paymentProvider, cfg.Payment.DefaultCurrency)
+
+	// gRPC Handler
+	grpcPaymentHandler := grpcHandler.NewPaymentGRPCHandler(paymentService)
+
+	// Start gRPC Server in goroutine
+	grpcSrv := grpcServer.NewGRPCServer(grpcPaymentHandler)
+	go func() {
+		if err := grpcSrv.Start(cfg.Server.GRPCPort); err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
+	// Start outbox relay in goroutine
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relay := outbox.NewRelay(db, outboxRepo, producer, cfg.Outbox.PollInterval, cfg.Outbox.BatchSize)
+	go relay.Start(relayCtx)
+
+	// Wait for interrupt signal to gracefully shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down servers...")
+	stopRelay()
+	grpcSrv.Stop()
+	log.Println("Servers stopped gracefully")
+}
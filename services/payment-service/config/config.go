@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Server   ServerConfig
+	Database DatabaseConfig
+	Kafka    KafkaConfig
+	Payment  PaymentConfig
+	Outbox   OutboxConfig
+}
+
+type ServerConfig struct {
+	GRPCPort string
+}
+
+type DatabaseConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBName   string
+}
+
+type KafkaConfig struct {
+	Brokers string
+}
+
+type PaymentConfig struct {
+	Provider        string
+	DefaultCurrency string
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+func LoadConfig() (*Config, error) {
+	// Load .env file
+	if err := godotenv.Load(); err != nil {
+		fmt.Println("Warning: .env file not found")
+	}
+
+	config := &Config{
+		Server: ServerConfig{
+			GRPCPort: getEnv("GRPC_PORT", "9094"),
+		},
+		Database: DatabaseConfig{
+			Host:     getEnv("DB_HOST", "localhost"),
+			Port:     getEnv("DB_PORT", "5432"),
+			User:     getEnv("DB_USER", "ecom_user"),
+			Password: getEnv("DB_PASSWORD", "ecom_pass"),
+			DBName:   getEnv("DB_NAME", "ecom_db"),
+		},
+		Kafka: KafkaConfig{
+			Brokers: getEnv("KAFKA_BROKERS", "localhost:9092"),
+		},
+		Payment: PaymentConfig{
+			Provider:        getEnv("PAYMENT_PROVIDER", "mock"),
+			DefaultCurrency: getEnv("PAYMENT_DEFAULT_CURRENCY", "THB"),
+		},
+		Outbox: OutboxConfig{
+			PollInterval: getDurationEnv("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize:    getIntEnv("OUTBOX_BATCH_SIZE", 100),
+		},
+	}
+
+	if err := requirePositive(map[string]time.Duration{
+		"OUTBOX_POLL_INTERVAL": config.Outbox.PollInterval,
+	}); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// requirePositive rejects durations that are zero or negative: intervals
+// drive tickers, which panic on them, and TTLs would expire at once
+func requirePositive(durations map[string]time.Duration) error {
+	for key, duration := range durations {
+		if duration <= 0 {
+			return fmt.Errorf("%s must be positive, got %v", key, duration)
+		}
+	}
+	return nil
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+		fmt.Printf("Warning: invalid duration for %s, using default %v\n", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if number, err := strconv.Atoi(value); err == nil {
+			return number
+		}
+		fmt.Printf("Warning: invalid number for %s, using default %d\n", key, defaultValue)
+	}
+	return defaultValue
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/service"
+	pb "github.com/ploezy/ecommerce-platform/proto/payment"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PaymentGRPCHandler struct {
+	pb.UnimplementedPaymentServiceServer
+	service service.PaymentService
+}
+
+// NewPaymentGRPCHandler creates a new gRPC handler
+func NewPaymentGRPCHandler(service service.PaymentService) *PaymentGRPCHandler {
+	return &PaymentGRPCHandler{
+		service: service,
+	}
+}
+
+// CreatePaymentIntent creates a payment intent for an order
+func (h *PaymentGRPCHandler) CreatePaymentIntent(ctx context.Context, req *pb.CreatePaymentIntentRequest) (*pb.PaymentIntentResponse, error) {
+	intent, err := h.service.CreateIntent(ctx, uint(req.OrderId), uint(req.UserId), req.Amount, req.Currency)
+	return h.respond(intent, err, "payment intent created")
+}
+
+// GetPaymentIntent gets a payment intent by ID
+func (h *PaymentGRPCHandler) GetPaymentIntent(ctx context.Context, req *pb.GetPaymentIntentRequest) (*pb.PaymentIntentResponse, error) {
+	intent, err := h.service.GetIntent(ctx, req.Id)
+	return h.respond(intent, err, "payment intent retrieved")
+}
+
+// AuthorizePayment authorizes the intent amount
+func (h *PaymentGRPCHandler) AuthorizePayment(ctx context.Context, req *pb.AuthorizePaymentRequest) (*pb.PaymentIntentResponse, error) {
+	intent, err := h.service.Authorize(ctx, req.Id, req.PaymentMethod)
+	return h.respond(intent, err, "payment authorized")
+}
+
+// CapturePayment captures an authorized payment
+func (h *PaymentGRPCHandler) CapturePayment(ctx context.Context, req *pb.CapturePaymentRequest) (*pb.PaymentIntentResponse, error) {
+	intent, err := h.service.Capture(ctx, req.Id, req.Amount)
+	return h.respond(intent, err, "payment captured")
+}
+
+// VoidPayment voids an authorized payment
+func (h *PaymentGRPCHandler) VoidPayment(ctx context.Context, req *pb.VoidPaymentRequest) (*pb.PaymentIntentResponse, error) {
+	intent, err := h.service.Void(ctx, req.Id)
+	return h.respond(intent, err, "payment voided")
+}
+
+// RefundPayment refunds a captured payment
+func (h *PaymentGRPCHandler) RefundPayment(ctx context.Context, req *pb.RefundPaymentRequest) (*pb.PaymentIntentResponse, error) {
+	intent, err := h.service.Refund(ctx, req.Id, req.Amount, req.Reason, req.IdempotencyKey)
+	return h.respond(intent, err, "payment refunded")
+}
+
+// VoidOrderPayments voids the payment intents of an order that were not captured
+func (h *PaymentGRPCHandler) VoidOrderPayments(ctx context.Context, req *pb.VoidOrderPaymentsRequest) (*pb.VoidOrderPaymentsResponse, error) {
+	err := h.service.VoidOrder(ctx, uint(req.OrderId))
+	switch {
+	case err == nil:
+		return &pb.VoidOrderPaymentsResponse{Success: true, Message: "order payments voided"}, nil
+	case errors.Is(err, service.ErrPaymentCaptured):
+		return &pb.VoidOrderPaymentsResponse{Success: false, Captured: true, Message: err.Error()}, nil
+	}
+	resp, err := h.respond(nil, err, "")
+	if err != nil {
+		return nil, err
+	}
+	return &pb.VoidOrderPaymentsResponse{Success: false, Message: resp.Message}, nil
+}
+
+// respond maps service results to a response. Declines, state and amount
+// errors are returned as Success false, other errors as gRPC status errors.
+func (h *PaymentGRPCHandler) respond(intent *model.PaymentIntent, err error, message string) (*pb.PaymentIntentResponse, error) {
+	if err == nil {
+		return &pb.PaymentIntentResponse{
+			PaymentIntent: h.toProtoIntent(intent),
+			Success:       true,
+			Message:       message,
+		}, nil
+	}
+
+	switch {
+	case errors.Is(err, service.ErrPaymentNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidPaymentID):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrProviderFailure):
+		return nil, status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, service.ErrPaymentDeclined),
+		errors.Is(err, service.ErrInvalidState),
+		errors.Is(err, service.ErrInvalidAmount):
+		return &pb.PaymentIntentResponse{
+			PaymentIntent: h.toProtoIntent(intent),
+			Success:       false,
+			Message:       err.Error(),
+		}, nil
+	default:
+		return nil, status.Errorf(codes.Internal, "payment operation failed: %v", err)
+	}
+}
+
+// toProtoIntent converts a model payment intent to its proto form
+func (h *PaymentGRPCHandler) toProtoIntent(intent *model.PaymentIntent) *pb.PaymentIntent {
+	if intent == nil {
+		return nil
+	}
+
+	return &pb.PaymentIntent{
+		Id:               intent.ID,
+		OrderId:          uint32(intent.OrderID),
+		UserId:           uint32(intent.UserID),
+		Amount:           intent.Amount,
+		Currency:         intent.Currency,
+		Status:           intent.Status,
+		Provider:         intent.Provider,
+		AuthorizedAmount: intent.AuthorizedAmount,
+		CapturedAmount:   intent.CapturedAmount,
+		RefundedAmount:   intent.RefundedAmount,
+		FailureReason:    intent.FailureReason,
+		CreatedAt:        intent.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        intent.UpdatedAt.Format(time.RFC3339),
+	}
+}
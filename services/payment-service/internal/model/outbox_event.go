@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Outbox event status constants
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+)
+
+// OutboxEvent is an event written in the same transaction as the payment change
+// that caused it and published to Kafka afterwards
+type OutboxEvent struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Topic     string     `gorm:"type:varchar(100);not null" json:"topic"`
+	Key       string     `gorm:"type:varchar(100);not null" json:"key"`
+	Payload   string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status    string     `gorm:"type:varchar(20);not null;index" json:"status"`
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`
+	LastError string     `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for OutboxEvent model
+func (OutboxEvent) TableName() string {
+	return "payment_outbox_events"
+}
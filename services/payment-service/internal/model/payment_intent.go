@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// Payment intent status constants
+const (
+	PaymentStatusRequiresAuthorization = "requires_authorization"
+	PaymentStatusAuthorized            = "authorized"
+	PaymentStatusCaptured              = "captured"
+	PaymentStatusVoided                = "voided"
+	PaymentStatusRefunded              = "refunded"
+	PaymentStatusFailed                = "failed"
+)
+
+// PaymentIntent tracks the payment of one order. Amounts are in minor units.
+type PaymentIntent struct {
+	ID               string               `gorm:"type:varchar(36);primaryKey" json:"id"`
+	OrderID          uint                 `gorm:"not null;index" json:"order_id"`
+	UserID           uint                 `gorm:"not null;index" json:"user_id"`
+	Amount           int64                `gorm:"not null" json:"amount"`
+	Currency         string               `gorm:"type:varchar(3);not null" json:"currency"`
+	Status           string               `gorm:"type:varchar(30);not null;index" json:"status"`
+	Provider         string               `gorm:"type:varchar(30);not null" json:"provider"`
+	ProviderRef      string               `gorm:"type:varchar(100)" json:"provider_ref,omitempty"`
+	AuthorizedAmount int64                `gorm:"not null;default:0" json:"authorized_amount"`
+	CapturedAmount   int64                `gorm:"not null;default:0" json:"captured_amount"`
+	RefundedAmount   int64                `gorm:"not null;default:0" json:"refunded_amount"`
+	FailureReason    string               `gorm:"type:text" json:"failure_reason,omitempty"`
+	Transactions     []PaymentTransaction `gorm:"foreignKey:PaymentIntentID;constraint:OnDelete:CASCADE" json:"transactions,omitempty"`
+	CreatedAt        time.Time            `json:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at"`
+}
+
+// TableName specifies the table name for PaymentIntent model
+func (PaymentIntent) TableName() string {
+	return "payment_intents"
+}
+
+// IsOpen reports whether the intent can still take money
+func (p *PaymentIntent) IsOpen() bool {
+	return p.Status == PaymentStatusRequiresAuthorization ||
+		p.Status == PaymentStatusAuthorized ||
+		p.Status == PaymentStatusFailed
+}
+
+// Transaction type constants
+const (
+	TransactionAuthorize = "authorize"
+	TransactionCapture   = "capture"
+	TransactionVoid      = "void"
+	TransactionRefund    = "refund"
+)
+
+// PaymentTransaction records one call to the payment provider
+type PaymentTransaction struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	PaymentIntentID string    `gorm:"type:varchar(36);not null;index" json:"payment_intent_id"`
+	Type            string    `gorm:"type:varchar(20);not null" json:"type"`
+	Amount          int64     `gorm:"not null" json:"amount"`
+	Success         bool      `gorm:"not null" json:"success"`
+	ProviderRef     string    `gorm:"type:varchar(100)" json:"provider_ref,omitempty"`
+	Error           string    `gorm:"type:text" json:"error,omitempty"`
+	IdempotencyKey  string    `gorm:"type:varchar(100);index" json:"idempotency_key,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for PaymentTransaction model
+func (PaymentTransaction) TableName() string {
+	return "payment_transactions"
+}
@@ -0,0 +1,33 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/payment-service/pkg/kafka"
+	"gorm.io/gorm"
+)
+
+// Write stores an event in the outbox inside tx, so it is only published when
+// tx commits. The key is used as the Kafka message key.
+func Write(ctx context.Context, tx *gorm.DB, repo repository.OutboxRepository, key string, envelope kafka.Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	event := &model.OutboxEvent{
+		Topic:   envelope.EventType,
+		Key:     key,
+		Payload: string(data),
+		Status:  model.OutboxStatusPending,
+	}
+
+	if err := repo.WithTx(tx).Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
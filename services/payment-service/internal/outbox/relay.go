@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Publisher sends an encoded message to Kafka
+type Publisher interface {
+	Publish(ctx context.Context, topic, key string, payload []byte) error
+}
+
+// Relay publishes pending outbox events and marks them sent. Failed events
+// stay pending and are retried on the next poll.
+type Relay struct {
+	db           *gorm.DB
+	repo         repository.OutboxRepository
+	publisher    Publisher
+	pollInterval time.Duration
+	batchSize    int
+}
+
+// NewRelay creates a new outbox relay
+func NewRelay(db *gorm.DB, repo repository.OutboxRepository, publisher Publisher, pollInterval time.Duration, batchSize int) *Relay {
+	return &Relay{
+		db:           db,
+		repo:         repo,
+		publisher:    publisher,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+	}
+}
+
+// Start polls the outbox until ctx is cancelled
+func (r *Relay) Start(ctx context.Context) {
+	log.Printf("Outbox relay started (interval: %v, batch: %d)", r.pollInterval, r.batchSize)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			if err := r.publishBatch(ctx); err != nil {
+				log.Printf("Outbox relay failed: %v", err)
+			}
+		}
+	}
+}
+
+// publishBatch publishes one batch of pending events in order
+func (r *Relay) publishBatch(ctx context.Context) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := r.repo.WithTx(tx)
+
+		events, err := repo.LockPending(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		// Once an event of a key fails, later events of the key wait for the retry
+		blocked := make(map[string]bool)
+
+		for _, event := range events {
+			if blocked[event.Key] {
+				continue
+			}
+
+			if err := r.publisher.Publish(ctx, event.Topic, event.Key, []byte(event.Payload)); err != nil {
+				blocked[event.Key] = true
+				log.Printf("Outbox event %d (%s) failed: %v", event.ID, event.Topic, err)
+				if err := repo.MarkFailed(ctx, event.ID, err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := repo.MarkSent(ctx, event.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// MockProviderName is the name of the in-process mock gateway
+const MockProviderName = "mock"
+
+// Payment method tokens understood by the mock gateway
+const (
+	MockMethodApproved          = "pm_mock_approved"
+	MockMethodDeclined          = "pm_mock_declined"
+	MockMethodInsufficientFunds = "pm_mock_insufficient_funds"
+	MockMethodUnavailable       = "pm_mock_unavailable"
+)
+
+// MockProvider is a deterministic gateway for development and tests. The
+// outcome of Authorize depends only on the payment method token, references
+// are derived from the request, and nothing is stored, so results survive a
+// restart. Amount limits are enforced by the payment service, not here.
+type MockProvider struct{}
+
+// NewMockProvider creates a new mock provider
+func NewMockProvider() *MockProvider {
+	return &MockProvider{}
+}
+
+// Name returns the provider name
+func (p *MockProvider) Name() string {
+	return MockProviderName
+}
+
+// Authorize approves or declines based on the payment method token
+func (p *MockProvider) Authorize(ctx context.Context, req AuthorizeRequest) (*Result, error) {
+	reference := mockReference("auth", req.Reference)
+
+	switch req.PaymentMethod {
+	case MockMethodApproved:
+		return &Result{Reference: reference, Approved: true}, nil
+	case MockMethodDeclined:
+		return &Result{Reference: reference, DeclineReason: "card_declined"}, nil
+	case MockMethodInsufficientFunds:
+		return &Result{Reference: reference, DeclineReason: "insufficient_funds"}, nil
+	case MockMethodUnavailable:
+		return nil, ErrUnavailable
+	default:
+		return &Result{Reference: reference, DeclineReason: "invalid_payment_method"}, nil
+	}
+}
+
+// Capture approves any positive amount
+func (p *MockProvider) Capture(ctx context.Context, reference string, amount int64) (*Result, error) {
+	return mockAmountResult("capture", reference, amount), nil
+}
+
+// Void always approves
+func (p *MockProvider) Void(ctx context.Context, reference string) (*Result, error) {
+	return &Result{Reference: mockReference("void", reference), Approved: true}, nil
+}
+
+// Refund approves any positive amount
+func (p *MockProvider) Refund(ctx context.Context, reference string, amount int64) (*Result, error) {
+	return mockAmountResult("refund", reference, amount), nil
+}
+
+func mockAmountResult(operation, reference string, amount int64) *Result {
+	result := &Result{Reference: mockReference(operation, fmt.Sprintf("%s:%d", reference, amount))}
+	if amount <= 0 {
+		result.DeclineReason = "invalid_amount"
+		return result
+	}
+	result.Approved = true
+	return result
+}
+
+// mockReference derives a stable provider reference from its input
+func mockReference(operation, input string) string {
+	sum := sha256.Sum256([]byte(operation + ":" + input))
+	return "mock_" + operation + "_" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMockAuthorizeOutcomes(t *testing.T) {
+	p := NewMockProvider()
+	ctx := context.Background()
+
+	tests := []struct {
+		method   string
+		approved bool
+		reason   string
+	}{
+		{MockMethodApproved, true, ""},
+		{MockMethodDeclined, false, "card_declined"},
+		{MockMethodInsufficientFunds, false, "insufficient_funds"},
+		{"pm_unknown", false, "invalid_payment_method"},
+	}
+
+	for _, tt := range tests {
+		result, err := p.Authorize(ctx, AuthorizeRequest{Reference: "pi_1", Amount: 1000, Currency: "THB", PaymentMethod: tt.method})
+		if err != nil {
+			t.Fatalf("Authorize(%s) returned error: %v", tt.method, err)
+		}
+		if result.Approved != tt.approved || result.DeclineReason != tt.reason {
+			t.Errorf("Authorize(%s) = approved %v reason %q, want %v %q", tt.method, result.Approved, result.DeclineReason, tt.approved, tt.reason)
+		}
+	}
+
+	if _, err := p.Authorize(ctx, AuthorizeRequest{Reference: "pi_1", PaymentMethod: MockMethodUnavailable}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+}
+
+func TestMockReferencesAreDeterministic(t *testing.T) {
+	p := NewMockProvider()
+	ctx := context.Background()
+	req := AuthorizeRequest{Reference: "pi_1", Amount: 1000, Currency: "THB", PaymentMethod: MockMethodApproved}
+
+	first, _ := p.Authorize(ctx, req)
+	second, _ := p.Authorize(ctx, req)
+	if first.Reference != second.Reference {
+		t.Errorf("Expected the same reference, got %s and %s", first.Reference, second.Reference)
+	}
+
+	other, _ := p.Authorize(ctx, AuthorizeRequest{Reference: "pi_2", PaymentMethod: MockMethodApproved})
+	if other.Reference == first.Reference {
+		t.Error("Expected different references for different payments")
+	}
+}
+
+func TestMockCaptureRejectsInvalidAmount(t *testing.T) {
+	result, err := NewMockProvider().Capture(context.Background(), "mock_auth_1", 0)
+	if err != nil {
+		t.Fatalf("Capture returned error: %v", err)
+	}
+	if result.Approved {
+		t.Error("Expected capture of zero to be declined")
+	}
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnavailable is returned when the provider could not be reached. The
+// operation may be retried.
+var ErrUnavailable = errors.New("payment provider unavailable")
+
+// AuthorizeRequest asks the provider to hold an amount on a payment method
+type AuthorizeRequest struct {
+	Reference     string // Our ID for the payment, sent so retries are idempotent
+	Amount        int64  // Minor units
+	Currency      string
+	PaymentMethod string // Provider token of the payment method
+}
+
+// Result is the outcome of a provider call. A declined call is not an error.
+type Result struct {
+	Reference     string
+	Approved      bool
+	DeclineReason string
+}
+
+// Provider is a payment gateway
+type Provider interface {
+	Name() string
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Result, error)
+	Capture(ctx context.Context, reference string, amount int64) (*Result, error)
+	Void(ctx context.Context, reference string) (*Result, error)
+	Refund(ctx context.Context, reference string, amount int64) (*Result, error)
+}
+
+// New returns the provider with the given name
+func New(name string) (Provider, error) {
+	switch name {
+	case MockProviderName:
+		return NewMockProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider: %s", name)
+	}
+}
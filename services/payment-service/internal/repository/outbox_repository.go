@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/model"
+	"gorm.io/gorm"
+)
+
+// OutboxRepository defines data access for outbox events
+type OutboxRepository interface {
+	WithTx(tx *gorm.DB) OutboxRepository
+	Create(ctx context.Context, event *model.OutboxEvent) error
+	LockPending(ctx context.Context, limit int) ([]model.OutboxEvent, error)
+	MarkSent(ctx context.Context, id uint) error
+	MarkFailed(ctx context.Context, id uint, lastError string) error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepositoryImpl{db: db}
+}
+
+// WithTx returns a repository that runs its queries inside tx
+func (r *outboxRepositoryImpl) WithTx(tx *gorm.DB) OutboxRepository {
+	return &outboxRepositoryImpl{db: tx}
+}
+
+// Create inserts a new outbox event
+func (r *outboxRepositoryImpl) Create(ctx context.Context, event *model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// LockPending locks the oldest pending events. Rows locked by another relay
+// are skipped, so several replicas can run the relay at once.
+func (r *outboxRepositoryImpl) LockPending(ctx context.Context, limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ?", model.OutboxStatusPending).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// MarkSent marks an event as published
+func (r *outboxRepositoryImpl) MarkSent(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     model.OutboxStatusSent,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": "",
+			"sent_at":    time.Now(),
+		}).Error
+}
+
+// MarkFailed records a failed publish attempt, the event stays pending
+func (r *outboxRepositoryImpl) MarkFailed(ctx context.Context, id uint, lastError string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": lastError,
+		}).Error
+}
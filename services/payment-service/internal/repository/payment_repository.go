@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/model"
+	"gorm.io/gorm"
+)
+
+// PaymentRepository defines data access for payment intents and their transactions
+type PaymentRepository interface {
+	WithTx(tx *gorm.DB) PaymentRepository
+	Create(ctx context.Context, intent *model.PaymentIntent) error
+	Save(ctx context.Context, intent *model.PaymentIntent) error
+	FindByID(ctx context.Context, id string) (*model.PaymentIntent, error)
+	FindByIDForUpdate(ctx context.Context, id string) (*model.PaymentIntent, error)
+	FindOpenByOrderID(ctx context.Context, orderID uint) (*model.PaymentIntent, error)
+	FindByOrderID(ctx context.Context, orderID uint) ([]model.PaymentIntent, error)
+	CreateTransaction(ctx context.Context, txn *model.PaymentTransaction) error
+	FindSuccessfulTransaction(ctx context.Context, intentID, txnType, idempotencyKey string) (*model.PaymentTransaction, error)
+}
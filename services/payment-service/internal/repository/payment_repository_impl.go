@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type paymentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPaymentRepository creates a new payment repository
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepositoryImpl{db: db}
+}
+
+// WithTx returns a repository that runs its queries inside tx
+func (r *paymentRepositoryImpl) WithTx(tx *gorm.DB) PaymentRepository {
+	return &paymentRepositoryImpl{db: tx}
+}
+
+// Create inserts a new payment intent
+func (r *paymentRepositoryImpl) Create(ctx context.Context, intent *model.PaymentIntent) error {
+	return r.db.WithContext(ctx).Create(intent).Error
+}
+
+// Save updates a payment intent without touching its transactions
+func (r *paymentRepositoryImpl) Save(ctx context.Context, intent *model.PaymentIntent) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(intent).Error
+}
+
+// FindByID finds a payment intent with its transactions
+func (r *paymentRepositoryImpl) FindByID(ctx context.Context, id string) (*model.PaymentIntent, error) {
+	var intent model.PaymentIntent
+	err := r.db.WithContext(ctx).
+		Preload("Transactions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		First(&intent, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// FindByIDForUpdate finds a payment intent and locks it until the transaction ends
+func (r *paymentRepositoryImpl) FindByIDForUpdate(ctx context.Context, id string) (*model.PaymentIntent, error) {
+	var intent model.PaymentIntent
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&intent, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// FindOpenByOrderID finds the payment intent of an order that can still take money
+func (r *paymentRepositoryImpl) FindOpenByOrderID(ctx context.Context, orderID uint) (*model.PaymentIntent, error) {
+	var intent model.PaymentIntent
+	err := r.db.WithContext(ctx).
+		Where("order_id = ? AND status IN ?", orderID, []string{
+			model.PaymentStatusRequiresAuthorization,
+			model.PaymentStatusAuthorized,
+			model.PaymentStatusFailed,
+		}).
+		Order("created_at DESC").
+		First(&intent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// FindByOrderID finds every payment intent of an order, oldest first
+func (r *paymentRepositoryImpl) FindByOrderID(ctx context.Context, orderID uint) ([]model.PaymentIntent, error) {
+	var intents []model.PaymentIntent
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&intents).Error
+	return intents, err
+}
+
+// CreateTransaction records a provider call
+func (r *paymentRepositoryImpl) CreateTransaction(ctx context.Context, txn *model.PaymentTransaction) error {
+	return r.db.WithContext(ctx).Create(txn).Error
+}
+
+// FindSuccessfulTransaction finds the approved transaction of a type that was
+// made with idempotencyKey
+func (r *paymentRepositoryImpl) FindSuccessfulTransaction(ctx context.Context, intentID, txnType, idempotencyKey string) (*model.PaymentTransaction, error) {
+	var txn model.PaymentTransaction
+	err := r.db.WithContext(ctx).
+		Where("payment_intent_id = ? AND type = ? AND idempotency_key = ? AND success = ?", intentID, txnType, idempotencyKey, true).
+		First(&txn).Error
+	if err != nil {
+		return nil, err
+	}
+	return &txn, nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/model"
+)
+
+// Payment service errors. Declines and state errors are business failures,
+// the caller may show them to the customer.
+var (
+	ErrPaymentNotFound  = errors.New("payment intent not found")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrInvalidState     = errors.New("invalid payment state")
+	ErrPaymentDeclined  = errors.New("payment declined")
+	ErrProviderFailure  = errors.New("payment provider failure")
+	ErrInvalidPaymentID = errors.New("payment intent id is required")
+	ErrPaymentCaptured  = errors.New("payment already captured")
+)
+
+type PaymentService interface {
+	CreateIntent(ctx context.Context, orderID, userID uint, amount int64, currency string) (*model.PaymentIntent, error)
+	GetIntent(ctx context.Context, id string) (*model.PaymentIntent, error)
+	Authorize(ctx context.Context, id, paymentMethod string) (*model.PaymentIntent, error)
+	Capture(ctx context.Context, id string, amount int64) (*model.PaymentIntent, error)
+	Void(ctx context.Context, id string) (*model.PaymentIntent, error)
+	VoidOrder(ctx context.Context, orderID uint) error
+	Refund(ctx context.Context, id string, amount int64, reason, idempotencyKey string) (*model.PaymentIntent, error)
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/outbox"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/provider"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/payment-service/pkg/kafka"
+	"gorm.io/gorm"
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type paymentService struct {
+	db              *gorm.DB
+	repo            repository.PaymentRepository
+	outboxRepo      repository.OutboxRepository
+	provider        provider.Provider
+	defaultCurrency string
+}
+
+// NewPaymentService creates a new payment service
+func NewPaymentService(
+	db *gorm.DB,
+	repo repository.PaymentRepository,
+	outboxRepo repository.OutboxRepository,
+	provider provider.Provider,
+	defaultCurrency string,
+) PaymentService {
+	return &paymentService{
+		db:              db,
+		repo:            repo,
+		outboxRepo:      outboxRepo,
+		provider:        provider,
+		defaultCurrency: defaultCurrency,
+	}
+}
+
+// CreateIntent creates a payment intent for an order. If the order already has
+// an open intent for the same amount it is returned instead, so retries are safe.
+func (s *paymentService) CreateIntent(ctx context.Context, orderID, userID uint, amount int64, currency string) (*model.PaymentIntent, error) {
+	if orderID == 0 {
+		return nil, errors.New("order id is required")
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: must be greater than zero", ErrInvalidAmount)
+	}
+
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = s.defaultCurrency
+	}
+	if !currencyPattern.MatchString(currency) {
+		return nil, fmt.Errorf("invalid currency: %s", currency)
+	}
+
+	existing, err := s.repo.FindOpenByOrderID(ctx, orderID)
+	if err == nil {
+		if existing.Amount != amount || existing.Currency != currency {
+			return nil, fmt.Errorf("%w: order %d already has an open payment intent for %d %s", ErrInvalidState, orderID, existing.Amount, existing.Currency)
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find payment intent: %w", err)
+	}
+
+	intent := &model.PaymentIntent{
+		ID:       uuid.NewString(),
+		OrderID:  orderID,
+		UserID:   userID,
+		Amount:   amount,
+		Currency: currency,
+		Status:   model.PaymentStatusRequiresAuthorization,
+		Provider: s.provider.Name(),
+	}
+	if err := s.repo.Create(ctx, intent); err != nil {
+		return nil, fmt.Errorf("failed to create payment intent: %w", err)
+	}
+
+	return intent, nil
+}
+
+// GetIntent retrieves a payment intent with its transactions
+func (s *paymentService) GetIntent(ctx context.Context, id string) (*model.PaymentIntent, error) {
+	if id == "" {
+		return nil, ErrInvalidPaymentID
+	}
+
+	intent, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment intent: %w", err)
+	}
+	return intent, nil
+}
+
+// Authorize holds the full intent amount on a payment method. A declined
+// intent can be authorized again with another payment method.
+func (s *paymentService) Authorize(ctx context.Context, id, paymentMethod string) (*model.PaymentIntent, error) {
+	return s.operate(ctx, id, func(op *operation) error {
+		intent := op.intent
+		if intent.Status != model.PaymentStatusRequiresAuthorization && intent.Status != model.PaymentStatusFailed {
+			return fmt.Errorf("%w: cannot authorize payment with status %s", ErrInvalidState, intent.Status)
+		}
+
+		result, err := s.provider.Authorize(op.ctx, provider.AuthorizeRequest{
+			Reference:     intent.ID,
+			Amount:        intent.Amount,
+			Currency:      intent.Currency,
+			PaymentMethod: paymentMethod,
+		})
+		approved, err := op.record(model.TransactionAuthorize, intent.Amount, result, err)
+		if err != nil {
+			return err
+		}
+		if !approved {
+			if result != nil {
+				intent.Status = model.PaymentStatusFailed
+				intent.FailureReason = result.DeclineReason
+				return op.publish(kafka.TopicPaymentFailed, intent.Amount, result.DeclineReason)
+			}
+			return nil
+		}
+
+		intent.Status = model.PaymentStatusAuthorized
+		intent.ProviderRef = result.Reference
+		intent.AuthorizedAmount = intent.Amount
+		intent.FailureReason = ""
+		return op.publish(kafka.TopicPaymentAuthorized, intent.Amount, "")
+	})
+}
+
+// Capture takes an authorized amount. amount 0 captures everything authorized,
+// a smaller amount captures partially and releases the rest.
+func (s *paymentService) Capture(ctx context.Context, id string, amount int64) (*model.PaymentIntent, error) {
+	return s.operate(ctx, id, func(op *operation) error {
+		intent := op.intent
+		if intent.Status != model.PaymentStatusAuthorized {
+			return fmt.Errorf("%w: cannot capture payment with status %s", ErrInvalidState, intent.Status)
+		}
+
+		if amount == 0 {
+			amount = intent.AuthorizedAmount
+		}
+		if amount < 0 || amount > intent.AuthorizedAmount {
+			return fmt.Errorf("%w: capture must be between 1 and %d", ErrInvalidAmount, intent.AuthorizedAmount)
+		}
+
+		result, err := s.provider.Capture(op.ctx, intent.ProviderRef, amount)
+		approved, err := op.record(model.TransactionCapture, amount, result, err)
+		if err != nil || !approved {
+			return err
+		}
+
+		intent.Status = model.PaymentStatusCaptured
+		intent.CapturedAmount = amount
+		return op.publish(kafka.TopicPaymentCaptured, amount, "")
+	})
+}
+
+// Void cancels an authorization that was not captured
+func (s *paymentService) Void(ctx context.Context, id string) (*model.PaymentIntent, error) {
+	return s.operate(ctx, id, func(op *operation) error {
+		intent := op.intent
+		if intent.Status != model.PaymentStatusAuthorized {
+			return fmt.Errorf("%w: cannot void payment with status %s", ErrInvalidState, intent.Status)
+		}
+
+		result, err := s.provider.Void(op.ctx, intent.ProviderRef)
+		approved, err := op.record(model.TransactionVoid, intent.AuthorizedAmount, result, err)
+		if err != nil || !approved {
+			return err
+		}
+
+		intent.Status = model.PaymentStatusVoided
+		return op.publish(kafka.TopicPaymentVoided, intent.AuthorizedAmount, "")
+	})
+}
+
+// VoidOrder voids every payment intent of an order that was not captured, so
+// none can be authorized or captured any more: authorizations are released
+// with the provider, intents without one are closed. Nothing is voided and
+// ErrPaymentCaptured is returned when an intent of the order was captured.
+func (s *paymentService) VoidOrder(ctx context.Context, orderID uint) error {
+	intents, err := s.repo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to find payment intents: %w", err)
+	}
+	for _, intent := range intents {
+		if !intent.IsOpen() && intent.Status != model.PaymentStatusVoided {
+			return fmt.Errorf("%w: payment intent %s is %s", ErrPaymentCaptured, intent.ID, intent.Status)
+		}
+	}
+
+	for _, intent := range intents {
+		if !intent.IsOpen() {
+			continue
+		}
+		// The intent is checked again under its lock, a capture may have won the race
+		_, err := s.operate(ctx, intent.ID, func(op *operation) error {
+			intent := op.intent
+			switch intent.Status {
+			case model.PaymentStatusVoided:
+				return nil
+			case model.PaymentStatusRequiresAuthorization, model.PaymentStatusFailed:
+				intent.Status = model.PaymentStatusVoided
+				return nil
+			case model.PaymentStatusCaptured, model.PaymentStatusRefunded:
+				return fmt.Errorf("%w: payment intent %s is %s", ErrPaymentCaptured, intent.ID, intent.Status)
+			}
+
+			result, err := s.provider.Void(op.ctx, intent.ProviderRef)
+			approved, err := op.record(model.TransactionVoid, intent.AuthorizedAmount, result, err)
+			if err != nil || !approved {
+				return err
+			}
+
+			intent.Status = model.PaymentStatusVoided
+			return op.publish(kafka.TopicPaymentVoided, intent.AuthorizedAmount, "")
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Refund returns captured money. amount 0 refunds everything not yet refunded.
+// The intent becomes refunded once the whole captured amount is refunded. A
+// refund with the idempotency key of an approved refund returns the intent
+// without refunding again.
+func (s *paymentService) Refund(ctx context.Context, id string, amount int64, reason, idempotencyKey string) (*model.PaymentIntent, error) {
+	return s.operate(ctx, id, func(op *operation) error {
+		intent := op.intent
+		if idempotencyKey != "" {
+			earlier, err := s.repo.WithTx(op.tx).FindSuccessfulTransaction(op.ctx, intent.ID, model.TransactionRefund, idempotencyKey)
+			if err == nil {
+				if amount != 0 && amount != earlier.Amount {
+					return fmt.Errorf("%w: idempotency key %s was used for a refund of %d", ErrInvalidAmount, idempotencyKey, earlier.Amount)
+				}
+				return nil
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("failed to find earlier refund: %w", err)
+			}
+			op.idempotencyKey = idempotencyKey
+		}
+
+		if intent.Status != model.PaymentStatusCaptured {
+			return fmt.Errorf("%w: cannot refund payment with status %s", ErrInvalidState, intent.Status)
+		}
+
+		remaining := intent.CapturedAmount - intent.RefundedAmount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount < 0 || amount > remaining {
+			return fmt.Errorf("%w: refund must be between 1 and %d", ErrInvalidAmount, remaining)
+		}
+
+		result, err := s.provider.Refund(op.ctx, intent.ProviderRef, amount)
+		approved, err := op.record(model.TransactionRefund, amount, result, err)
+		if err != nil || !approved {
+			return err
+		}
+
+		intent.RefundedAmount += amount
+		if intent.RefundedAmount == intent.CapturedAmount {
+			intent.Status = model.PaymentStatusRefunded
+		}
+		return op.publish(kafka.TopicPaymentRefunded, amount, reason)
+	})
+}
+
+// operation is one provider call on a locked payment intent
+type operation struct {
+	ctx    context.Context
+	tx     *gorm.DB
+	s      *paymentService
+	intent *model.PaymentIntent
+
+	// idempotencyKey is stored on the transaction record
+	idempotencyKey string
+
+	// failure is returned to the caller after the transaction commits, so
+	// declines and provider errors are recorded instead of rolled back
+	failure error
+}
+
+// operate locks a payment intent, runs fn and saves the intent, its
+// transaction record and its events in one transaction. The lock serializes
+// operations on one intent, so an amount cannot be captured or refunded twice.
+func (s *paymentService) operate(ctx context.Context, id string, fn func(op *operation) error) (*model.PaymentIntent, error) {
+	if id == "" {
+		return nil, ErrInvalidPaymentID
+	}
+
+	var op *operation
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		intent, err := s.repo.WithTx(tx).FindByIDForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPaymentNotFound
+			}
+			return fmt.Errorf("failed to get payment intent: %w", err)
+		}
+
+		op = &operation{ctx: ctx, tx: tx, s: s, intent: intent}
+		if err := fn(op); err != nil {
+			return err
+		}
+
+		if err := s.repo.WithTx(tx).Save(ctx, intent); err != nil {
+			return fmt.Errorf("failed to save payment intent: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	intent, err := s.GetIntent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return intent, op.failure
+}
+
+// record stores the outcome of a provider call and reports whether it was approved
+func (op *operation) record(txnType string, amount int64, result *provider.Result, callErr error) (bool, error) {
+	txn := &model.PaymentTransaction{
+		PaymentIntentID: op.intent.ID,
+		Type:            txnType,
+		Amount:          amount,
+		IdempotencyKey:  op.idempotencyKey,
+	}
+
+	switch {
+	case callErr != nil:
+		txn.Error = callErr.Error()
+		op.failure = fmt.Errorf("%w: %v", ErrProviderFailure, callErr)
+	case !result.Approved:
+		txn.ProviderRef = result.Reference
+		txn.Error = result.DeclineReason
+		op.failure = fmt.Errorf("%w: %s", ErrPaymentDeclined, result.DeclineReason)
+	default:
+		txn.ProviderRef = result.Reference
+		txn.Success = true
+	}
+
+	if err := op.s.repo.WithTx(op.tx).CreateTransaction(op.ctx, txn); err != nil {
+		return false, fmt.Errorf("failed to record payment transaction: %w", err)
+	}
+	return txn.Success, nil
+}
+
+// publish writes a payment event for the intent to the outbox, keyed by order ID
+func (op *operation) publish(topic string, amount int64, reason string) error {
+	intent := op.intent
+	event := kafka.PaymentEvent{
+		PaymentIntentID: intent.ID,
+		OrderID:         intent.OrderID,
+		UserID:          intent.UserID,
+		Amount:          amount,
+		Currency:        intent.Currency,
+		CapturedTotal:   intent.CapturedAmount,
+		RefundedTotal:   intent.RefundedAmount,
+		Reason:          reason,
+		OccurredAt:      time.Now().UTC(),
+	}
+	key := fmt.Sprintf("%d", intent.OrderID)
+	return outbox.Write(op.ctx, op.tx, op.s.outboxRepo, key, kafka.NewEnvelope(topic, event))
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/provider"
+	"github.com/ploezy/ecommerce-platform/payment-service/internal/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestService(t *testing.T) (PaymentService, *gorm.DB) {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&model.PaymentIntent{}, &model.PaymentTransaction{}, &model.OutboxEvent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewPaymentService(db, repository.NewPaymentRepository(db), repository.NewOutboxRepository(db), provider.NewMockProvider(), "THB")
+	return service, db
+}
+
+// capturedIntent creates an intent of amount for order 1 and captures it
+func capturedIntent(t *testing.T, service PaymentService, amount int64) *model.PaymentIntent {
+	t.Helper()
+	ctx := context.Background()
+	intent, err := service.CreateIntent(ctx, 1, 1, amount, "THB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authorize(ctx, intent.ID, provider.MockMethodApproved); err != nil {
+		t.Fatal(err)
+	}
+	intent, err = service.Capture(ctx, intent.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return intent
+}
+
+func TestRefundWithIdempotencyKeyRefundsOnce(t *testing.T) {
+	service, db := newTestService(t)
+	ctx := context.Background()
+	intent := capturedIntent(t, service, 1000)
+
+	for i := 0; i < 2; i++ {
+		refunded, err := service.Refund(ctx, intent.ID, 300, "return 1", "return:1")
+		if err != nil {
+			t.Fatalf("Refund() attempt %d error = %v", i+1, err)
+		}
+		if refunded.RefundedAmount != 300 || refunded.Status != model.PaymentStatusCaptured {
+			t.Fatalf("after attempt %d refunded %d with status %s, want 300 and captured", i+1, refunded.RefundedAmount, refunded.Status)
+		}
+	}
+
+	// The key of another return refunds again
+	refunded, err := service.Refund(ctx, intent.ID, 200, "return 2", "return:2")
+	if err != nil || refunded.RefundedAmount != 500 {
+		t.Fatalf("Refund() with another key = %v, refunded %d, want 500", err, refunded.RefundedAmount)
+	}
+
+	// A key cannot be reused for another amount
+	if _, err := service.Refund(ctx, intent.ID, 400, "return 1", "return:1"); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Refund() reusing a key for another amount error = %v, want ErrInvalidAmount", err)
+	}
+
+	var refunds int64
+	db.Model(&model.PaymentTransaction{}).Where("type = ? AND success = ?", model.TransactionRefund, true).Count(&refunds)
+	if refunds != 2 {
+		t.Errorf("recorded %d approved refunds, want 2", refunds)
+	}
+}
+
+func TestRefundWithKeyAnswersAfterFullRefund(t *testing.T) {
+	service, _ := newTestService(t)
+	ctx := context.Background()
+	intent := capturedIntent(t, service, 1000)
+
+	if _, err := service.Refund(ctx, intent.ID, 0, "order cancelled", "order:1:cancel"); err != nil {
+		t.Fatal(err)
+	}
+	// The intent is refunded now, the retry is still answered
+	refunded, err := service.Refund(ctx, intent.ID, 0, "order cancelled", "order:1:cancel")
+	if err != nil || refunded.Status != model.PaymentStatusRefunded || refunded.RefundedAmount != 1000 {
+		t.Errorf("retried Refund() = %v, status %s, refunded %d, want the refunded intent", err, refunded.Status, refunded.RefundedAmount)
+	}
+	if _, err := service.Refund(ctx, intent.ID, 0, "order cancelled", ""); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("Refund() without a key error = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestVoidOrderClosesOpenIntents(t *testing.T) {
+	service, _ := newTestService(t)
+	ctx := context.Background()
+	intent, err := service.CreateIntent(ctx, 1, 1, 1000, "THB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authorize(ctx, intent.ID, provider.MockMethodApproved); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := service.VoidOrder(ctx, 1); err != nil {
+			t.Fatalf("VoidOrder() attempt %d error = %v", i+1, err)
+		}
+	}
+	voided, err := service.GetIntent(ctx, intent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if voided.Status != model.PaymentStatusVoided {
+		t.Errorf("intent status = %s, want voided", voided.Status)
+	}
+	if _, err := service.Capture(ctx, intent.ID, 0); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("Capture() after VoidOrder error = %v, want ErrInvalidState", err)
+	}
+
+	// An intent without authorization cannot be authorized any more
+	unpaid, err := service.CreateIntent(ctx, 2, 1, 1000, "THB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.VoidOrder(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authorize(ctx, unpaid.ID, provider.MockMethodApproved); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("Authorize() after VoidOrder error = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestVoidOrderKeepsCapturedPayment(t *testing.T) {
+	service, _ := newTestService(t)
+	intent := capturedIntent(t, service, 1000)
+
+	if err := service.VoidOrder(context.Background(), 1); !errors.Is(err, ErrPaymentCaptured) {
+		t.Fatalf("VoidOrder() error = %v, want ErrPaymentCaptured", err)
+	}
+	captured, err := service.GetIntent(context.Background(), intent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if captured.Status != model.PaymentStatusCaptured {
+		t.Errorf("intent status = %s, want captured", captured.Status)
+	}
+}
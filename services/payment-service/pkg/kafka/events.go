@@ -0,0 +1,62 @@
+package kafka
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Topics for payment events
+const (
+	TopicPaymentAuthorized = "payment.authorized"
+	TopicPaymentCaptured   = "payment.captured"
+	TopicPaymentVoided     = "payment.voided"
+	TopicPaymentRefunded   = "payment.refunded"
+	TopicPaymentFailed     = "payment.failed"
+)
+
+// PaymentSchemaVersion is the schema version of every payment event payload
+const PaymentSchemaVersion = 1
+
+// ProducerName identifies payment-service as the producer of an event
+const ProducerName = "payment-service"
+
+// Envelope wraps every event published by payment-service. It has the same
+// fields as the order-service envelope.
+type Envelope struct {
+	EventID       string      `json:"event_id"`
+	EventType     string      `json:"event_type"`
+	SchemaVersion int         `json:"schema_version"`
+	OccurredAt    time.Time   `json:"occurred_at"`
+	Producer      string      `json:"producer"`
+	CorrelationID string      `json:"correlation_id,omitempty"`
+	Data          interface{} `json:"data"`
+}
+
+// NewEnvelope wraps event data with a new event ID and the current time.
+// The event type is the topic name.
+func NewEnvelope(topic string, data interface{}) Envelope {
+	return Envelope{
+		EventID:       uuid.NewString(),
+		EventType:     topic,
+		SchemaVersion: PaymentSchemaVersion,
+		OccurredAt:    time.Now().UTC(),
+		Producer:      ProducerName,
+		Data:          data,
+	}
+}
+
+// PaymentEvent is the payload of every payment event. Amount is the amount of
+// the operation, the totals are the state of the intent after it. All amounts
+// are minor units.
+type PaymentEvent struct {
+	PaymentIntentID string    `json:"payment_intent_id"`
+	OrderID         uint      `json:"order_id"`
+	UserID          uint      `json:"user_id"`
+	Amount          int64     `json:"amount"`
+	Currency        string    `json:"currency"`
+	CapturedTotal   int64     `json:"captured_total"`
+	RefundedTotal   int64     `json:"refunded_total"`
+	Reason          string    `json:"reason,omitempty"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type Producer struct {
+	writer *kafka.Writer
+}
+
+// NewProducer creates a Kafka producer for a comma separated list of brokers
+func NewProducer(brokers string) (*Producer, error) {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(strings.Split(brokers, ",")...),
+		Balancer:     &kafka.Hash{}, // same key, same partition: keeps per-order ordering
+		BatchTimeout: 10 * time.Millisecond,
+	}
+
+	log.Println("Kafka producer initialized successfully")
+	return &Producer{writer: writer}, nil
+}
+
+// Publish writes an already encoded message. Messages with the same key always
+// go to the same partition.
+func (p *Producer) Publish(ctx context.Context, topic, key string, payload []byte) error {
+	err := p.writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(key),
+		Value: payload,
+		Time:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+
+	log.Printf("Event published to topic [%s] with key [%s]", topic, key)
+	return nil
+}
+
+// Close closes the Kafka writer
+func (p *Producer) Close() error {
+	if p.writer != nil {
+		return p.writer.Close()
+	}
+	return nil
+}
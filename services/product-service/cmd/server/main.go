@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
@@ -12,9 +13,9 @@ import (
 	"github.com/ploezy/ecommerce-platform/product-service/internal/middleware"
 	"github.com/ploezy/ecommerce-platform/product-service/internal/repository"
 	"github.com/ploezy/ecommerce-platform/product-service/internal/service"
-	"github.com/ploezy/ecommerce-platform/product-service/pkg/auth"
 	"github.com/ploezy/ecommerce-platform/product-service/pkg/database"
 	"github.com/ploezy/ecommerce-platform/product-service/pkg/redis"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
 	"syscall"
 )
 // @title Product Service API
@@ -66,11 +67,28 @@ func main() {
 	}
 	log.Printf("Redis connected: %v\n", redisClient != nil)
 
-	// Initialize JWT helper
-	jwtHelper := auth.NewJWTHelper(cfg.JWT.Secret)
+	// Access tokens are verified locally against the JWKS of user-service.
+	// Revoked tokens are read from the denylist user-service keeps in Redis.
+	denylistClient := redisClient
+	if cfg.JWT.DenylistRedisDB != cfg.Redis.DB {
+		denylistClient, err = redis.ConnectRedis(redis.RedisConfig{
+			Host:     cfg.Redis.Host,
+			Port:     cfg.Redis.Port,
+			Password: cfg.Redis.Password,
+			DB:       cfg.JWT.DenylistRedisDB,
+		})
+		if err != nil {
+			log.Fatalf("Failed to connect to Redis token denylist: %v", err)
+		}
+	}
+	jwks := token.NewRemoteKeySet(cfg.JWT.JWKSURL, cfg.JWT.JWKSCacheTTL)
+	if err := jwks.Refresh(context.Background()); err != nil {
+		log.Printf("Warning: failed to fetch JWKS from %s, retrying on first request: %v", cfg.JWT.JWKSURL, err)
+	}
+	verifier := token.NewVerifier(jwks, redis.NewTokenDenylist(denylistClient))
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtHelper)
+	authMiddleware := middleware.NewAuthMiddleware(verifier, cfg.JWT.RequireAdminMFA)
 
 	// Initialize cache service
 	cacheService := redis.NewCacheService(redisClient)
@@ -78,12 +96,14 @@ func main() {
 	// Initialize layers
 	productRepo := repository.NewProductRepository(db)
 	productService := service.NewProductService(productRepo, cacheService)
+	stockRepo := repository.NewStockRepository(db)
+	stockService := service.NewStockService(stockRepo, cacheService, cfg.Stock.ReservationTTL)
 
 	// HTTP Handler
 	httpHandler := handler.NewProductHandler(productService)
 
 	// gRPC Handler
-	grpcProductHandler := grpcHandler.NewProductGRPCHandler(productService)
+	grpcProductHandler := grpcHandler.NewProductGRPCHandler(productService, stockService)
 
 	// Start gRPC Server in goroutine
 	grpcSrv := grpcServer.NewGRPCServer(grpcProductHandler)
@@ -93,6 +113,11 @@ func main() {
 		}
 	}()
 
+	// Start reservation sweeper in goroutine
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	sweeper := service.NewReservationSweeper(stockService, cfg.Stock.ReservationSweepInterval)
+	go sweeper.Start(sweeperCtx)
+
 	// Setup HTTP router
 	router := handler.SetupRouter(httpHandler, authMiddleware)
 
@@ -122,6 +147,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down servers...")
+	stopSweeper()
 	grpcSrv.Stop()
 	log.Println("Servers stopped gracefully")
 }
\ No newline at end of file
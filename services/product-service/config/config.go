@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +14,7 @@ type Config struct {
 	Database DatabaseConfig
 	Redis    RedisConfig
 	JWT      JWTConfig
+	Stock    StockConfig
 }
 
 type ServerConfig struct {
@@ -34,8 +37,20 @@ type RedisConfig struct {
 	DB       int
 }
 
+// JWTConfig locates the keys that verify access tokens. Tokens are signed by
+// user-service and checked locally against its JWKS.
 type JWTConfig struct {
-	Secret string
+	JWKSURL      string
+	JWKSCacheTTL time.Duration
+	// DenylistRedisDB is the Redis DB where user-service keeps revoked tokens
+	DenylistRedisDB int
+	// RequireAdminMFA refuses admin tokens whose session started without MFA
+	RequireAdminMFA bool
+}
+
+type StockConfig struct {
+	ReservationTTL           time.Duration
+	ReservationSweepInterval time.Duration
 }
 
 func LoadConfig() (*Config, error) {
@@ -63,16 +78,72 @@ func LoadConfig() (*Config, error) {
 			DB:       0,
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "your-secret-key"),
+			JWKSURL:         getEnv("JWKS_URL", "http://localhost:8081/.well-known/jwks.json"),
+			JWKSCacheTTL:    getDurationEnv("JWKS_CACHE_TTL", 5*time.Minute),
+			DenylistRedisDB: getIntEnv("TOKEN_DENYLIST_REDIS_DB", 0),
+			RequireAdminMFA: getBoolEnv("ADMIN_REQUIRE_MFA", false),
+		},
+		Stock: StockConfig{
+			ReservationTTL:           getDurationEnv("STOCK_RESERVATION_TTL", 15*time.Minute),
+			ReservationSweepInterval: getDurationEnv("STOCK_RESERVATION_SWEEP_INTERVAL", 30*time.Second),
 		},
 	}
 
+	if err := requirePositive(map[string]time.Duration{
+		"JWKS_CACHE_TTL":                   config.JWT.JWKSCacheTTL,
+		"STOCK_RESERVATION_TTL":            config.Stock.ReservationTTL,
+		"STOCK_RESERVATION_SWEEP_INTERVAL": config.Stock.ReservationSweepInterval,
+	}); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
+// requirePositive rejects durations that are zero or negative: intervals
+// drive tickers, which panic on them, and TTLs would expire at once
+func requirePositive(durations map[string]time.Duration) error {
+	for key, duration := range durations {
+		if duration <= 0 {
+			return fmt.Errorf("%s must be positive, got %v", key, duration)
+		}
+	}
+	return nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+		fmt.Printf("Warning: invalid duration for %s, using default %v\n", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+		fmt.Printf("Warning: invalid integer for %s, using default %d\n", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+		fmt.Printf("Warning: invalid boolean for %s, using default %t\n", key, defaultValue)
+	}
+	return defaultValue
+}
@@ -450,7 +450,6 @@ const docTemplate = `{
             "required": [
                 "category",
                 "name",
-                "price",
                 "stock"
             ],
             "properties": {
@@ -477,13 +476,17 @@ const docTemplate = `{
                     "example": "iPhone 15 Pro Max"
                 },
                 "price": {
-                    "type": "number",
-                    "example": 45900
+                    "$ref": "#/definitions/money.Money"
                 },
                 "stock": {
                     "type": "integer",
                     "minimum": 0,
                     "example": 50
+                },
+                "weight_grams": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 221
                 }
             }
         },
@@ -543,8 +546,7 @@ const docTemplate = `{
                     "example": "iPhone 15 Pro Max"
                 },
                 "price": {
-                    "type": "number",
-                    "example": 45900
+                    "$ref": "#/definitions/money.Money"
                 },
                 "stock": {
                     "type": "integer",
@@ -553,6 +555,10 @@ const docTemplate = `{
                 "updated_at": {
                     "type": "string",
                     "example": "2025-11-07 15:30:00"
+                },
+                "weight_grams": {
+                    "type": "integer",
+                    "example": 221
                 }
             }
         },
@@ -582,13 +588,30 @@ const docTemplate = `{
                     "example": "iPhone 15 Pro Max"
                 },
                 "price": {
-                    "type": "number",
-                    "example": 43900
+                    "$ref": "#/definitions/money.Money"
                 },
                 "stock": {
                     "type": "integer",
                     "minimum": 0,
                     "example": 45
+                },
+                "weight_grams": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 221
+                }
+            }
+        },
+        "money.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 4590000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "THB"
                 }
             }
         }
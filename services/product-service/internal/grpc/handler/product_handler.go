@@ -2,11 +2,15 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/ploezy/ecommerce-platform/pkg/money"
 	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/product-service/internal/repository"
 	"github.com/ploezy/ecommerce-platform/product-service/internal/service"
-	pb "github.com/ploezy/ecommerce-platform/product-service/proto"
+	pb "github.com/ploezy/ecommerce-platform/proto/product"
 
 	"github.com/lib/pq"
 	"google.golang.org/grpc/codes"
@@ -15,13 +19,15 @@ import (
 
 type ProductGRPCHandler struct {
 	pb.UnimplementedProductServiceServer
-	service service.ProductService
+	service      service.ProductService
+	stockService service.StockService
 }
 
 // NewProductGRPCHandler creates a new gRPC handler
-func NewProductGRPCHandler(service service.ProductService) *ProductGRPCHandler {
+func NewProductGRPCHandler(service service.ProductService, stockService service.StockService) *ProductGRPCHandler {
 	return &ProductGRPCHandler{
-		service: service,
+		service:      service,
+		stockService: stockService,
 	}
 }
 
@@ -31,14 +37,18 @@ func (h *ProductGRPCHandler) CreateProduct(ctx context.Context, req *pb.CreatePr
 	serviceReq := &model.CreateProductRequest{
 		Name:        req.Name,
 		Description: req.Description,
-		Price:       req.Price,
+		Price:       fromProtoPrice(req.UnitPrice, req.Price),
 		Stock:       int(req.Stock),
 		Category:    req.Category,
+		WeightGrams: int(req.WeightGrams),
 		Images:      pq.StringArray(req.Images),
 	}
 
 	product, err := h.service.CreateProduct(ctx, serviceReq)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidPrice) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		return nil, status.Errorf(codes.Internal, "failed to create product: %v", err)
 	}
 
@@ -51,7 +61,7 @@ func (h *ProductGRPCHandler) CreateProduct(ctx context.Context, req *pb.CreatePr
 func (h *ProductGRPCHandler) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.ProductResponse, error) {
 	product, err := h.service.GetProductByID(ctx, uint(req.Id))
 	if err != nil {
-		if err.Error() == "product not found" {
+		if errors.Is(err, repository.ErrProductNotFound) {
 			return nil, status.Errorf(codes.NotFound, "product not found")
 		}
 		return nil, status.Errorf(codes.Internal, "failed to get product: %v", err)
@@ -101,17 +111,28 @@ func (h *ProductGRPCHandler) UpdateProduct(ctx context.Context, req *pb.UpdatePr
 	serviceReq := &model.UpdateProductRequest{
 		Name:        req.Name,
 		Description: req.Description,
-		Price:       req.Price,
 		Stock:       int(req.Stock),
 		Category:    req.Category,
 		Images:      pq.StringArray(req.Images),
 	}
 
+	if req.UnitPrice != nil || req.Price > 0 {
+		price := fromProtoPrice(req.UnitPrice, req.Price)
+		serviceReq.Price = &price
+	}
+	if req.WeightGrams > 0 {
+		weight := int(req.WeightGrams)
+		serviceReq.WeightGrams = &weight
+	}
+
 	product, err := h.service.UpdateProduct(ctx, uint(req.Id), serviceReq)
 	if err != nil {
-		if err.Error() == "product not found" {
+		if errors.Is(err, repository.ErrProductNotFound) {
 			return nil, status.Errorf(codes.NotFound, "product not found")
 		}
+		if errors.Is(err, service.ErrInvalidPrice) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		return nil, status.Errorf(codes.Internal, "failed to update product: %v", err)
 	}
 
@@ -124,7 +145,7 @@ func (h *ProductGRPCHandler) UpdateProduct(ctx context.Context, req *pb.UpdatePr
 func (h *ProductGRPCHandler) DeleteProduct(ctx context.Context, req *pb.DeleteProductRequest) (*pb.DeleteProductResponse, error) {
 	err := h.service.DeleteProduct(ctx, uint(req.Id))
 	if err != nil {
-		if err.Error() == "product not found" {
+		if errors.Is(err, repository.ErrProductNotFound) {
 			return nil, status.Errorf(codes.NotFound, "product not found")
 		}
 		return nil, status.Errorf(codes.Internal, "failed to delete product: %v", err)
@@ -176,9 +197,11 @@ func (h *ProductGRPCHandler) toProtoProduct(p *model.ProductResponse) *pb.Produc
 		Id:          uint32(p.ID),
 		Name:        p.Name,
 		Description: p.Description,
-		Price:       p.Price,
+		Price:       p.Price.Major(),
+		UnitPrice:   &pb.Money{Amount: p.Price.Amount, Currency: p.Price.Currency},
 		Stock:       int32(p.Stock),
 		Category:    p.Category,
+		WeightGrams: int32(p.WeightGrams),
 		Images:      p.Images,
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
@@ -211,53 +234,125 @@ func (h *ProductGRPCHandler) CheckStock(ctx context.Context, req *pb.CheckStockR
 	}, nil
 }
 
-// UpdateStock updates product stock
+// UpdateStock updates product stock with a single conditional update
 func (h *ProductGRPCHandler) UpdateStock(ctx context.Context, req *pb.UpdateStockRequest) (*pb.UpdateStockResponse, error) {
-	// Get current product
-	product, err := h.service.GetProductByID(ctx, uint(req.ProductId))
+	newStock, err := h.stockService.AdjustStock(ctx, uint(req.ProductId), int(req.Quantity))
+	if err != nil {
+		return nil, stockError("failed to update stock", err)
+	}
+
+	return &pb.UpdateStockResponse{
+		Success:  true,
+		NewStock: int32(newStock),
+		Message:  "stock updated successfully",
+	}, nil
+}
+
+// ReserveStock reserves stock for all items or none of them
+func (h *ProductGRPCHandler) ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.ReserveStockResponse, error) {
+	items := make([]model.StockItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, model.StockItem{
+			ProductID: uint(item.ProductId),
+			Quantity:  int(item.Quantity),
+		})
+	}
+
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+	reservation, err := h.stockService.ReserveStock(ctx, items, ttl, req.Reference)
 	if err != nil {
-		return &pb.UpdateStockResponse{
-			Success:  false,
-			NewStock: 0,
-			Message:  fmt.Sprintf("product not found: %v", err),
+		return nil, stockError("failed to reserve stock", err)
+	}
+
+	return &pb.ReserveStockResponse{
+		Success:       true,
+		ReservationId: reservation.ID,
+		ExpiresAt:     reservation.ExpiresAt.Format(time.RFC3339),
+		Message:       "stock reserved successfully",
+	}, nil
+}
+
+// ReleaseStock releases a reservation and returns its stock
+func (h *ProductGRPCHandler) ReleaseStock(ctx context.Context, req *pb.ReleaseStockRequest) (*pb.ReleaseStockResponse, error) {
+	if err := h.stockService.ReleaseStock(ctx, req.ReservationId); err != nil {
+		if errors.Is(err, repository.ErrReservationNotFound) {
+			return nil, status.Errorf(codes.NotFound, "reservation not found")
+		}
+		return &pb.ReleaseStockResponse{
+			Success: false,
+			Message: err.Error(),
 		}, nil
 	}
 
-	// Calculate new stock
-	newStock := int(product.Stock) + int(req.Quantity)
-	
-	// Validate stock cannot be negative
-	if newStock < 0 {
-		return &pb.UpdateStockResponse{
-			Success:  false,
-			NewStock: int32(product.Stock),
-			Message:  fmt.Sprintf("insufficient stock: current %d, requested change %d", product.Stock, req.Quantity),
+	return &pb.ReleaseStockResponse{
+		Success: true,
+		Message: "stock released successfully",
+	}, nil
+}
+
+// CommitReservation commits a reservation so its stock is permanently taken
+func (h *ProductGRPCHandler) CommitReservation(ctx context.Context, req *pb.CommitReservationRequest) (*pb.CommitReservationResponse, error) {
+	if err := h.stockService.CommitReservation(ctx, req.ReservationId); err != nil {
+		if errors.Is(err, repository.ErrReservationNotFound) {
+			return nil, status.Errorf(codes.NotFound, "reservation not found")
+		}
+		return &pb.CommitReservationResponse{
+			Success: false,
+			Message: err.Error(),
 		}, nil
 	}
 
-	// Create update request with new stock
-	updateReq := &model.UpdateProductRequest{
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-		Stock:       newStock,
-		Category:    product.Category,
-		Images:      product.Images,
+	return &pb.CommitReservationResponse{
+		Success: true,
+		Message: "reservation committed successfully",
+	}, nil
+}
+
+// RestockItems returns stock to products once per reference
+func (h *ProductGRPCHandler) RestockItems(ctx context.Context, req *pb.RestockItemsRequest) (*pb.RestockItemsResponse, error) {
+	items := make([]model.StockItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, model.StockItem{
+			ProductID: uint(item.ProductId),
+			Quantity:  int(item.Quantity),
+		})
 	}
 
-	// Update product
-	_, err = h.service.UpdateProduct(ctx, uint(req.ProductId), updateReq)
+	applied, err := h.stockService.RestockItems(ctx, req.Reference, items)
 	if err != nil {
-		return &pb.UpdateStockResponse{
-			Success:  false,
-			NewStock: int32(product.Stock),
-			Message:  fmt.Sprintf("failed to update stock: %v", err),
-		}, nil
+		return nil, stockError("failed to restock items", err)
 	}
 
-	return &pb.UpdateStockResponse{
-		Success:  true,
-		NewStock: int32(newStock),
-		Message:  "stock updated successfully",
+	message := "stock restocked successfully"
+	if !applied {
+		message = "stock was already restocked"
+	}
+	return &pb.RestockItemsResponse{
+		Success: true,
+		Applied: applied,
+		Message: message,
 	}, nil
-}
\ No newline at end of file
+}
+
+// stockError maps stock service errors to gRPC status codes
+func stockError(action string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrProductNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrInsufficientStock):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrInvalidStockRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", action, err)
+	}
+}
+
+// fromProtoPrice returns unit_price, or the deprecated float price in the
+// default currency for clients that do not send unit_price yet
+func fromProtoPrice(unitPrice *pb.Money, price float64) money.Money {
+	if unitPrice != nil {
+		return money.New(unitPrice.Amount, unitPrice.Currency)
+	}
+	return money.FromMajor(price, money.DefaultCurrency)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/product-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/product-service/internal/service"
+	pb "github.com/ploezy/ecommerce-platform/proto/product"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStockService fails every call with err
+type fakeStockService struct {
+	service.StockService
+	err error
+}
+
+func (s *fakeStockService) AdjustStock(ctx context.Context, productID uint, delta int) (int, error) {
+	return 0, s.err
+}
+
+func (s *fakeStockService) ReserveStock(ctx context.Context, items []model.StockItem, ttl time.Duration, reference string) (*model.StockReservation, error) {
+	return nil, s.err
+}
+
+func TestStockErrorCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"missing product", fmt.Errorf("%w: 7", repository.ErrProductNotFound), codes.NotFound},
+		{"insufficient stock", fmt.Errorf("%w for product 7 (requested 3)", repository.ErrInsufficientStock), codes.FailedPrecondition},
+		{"invalid request", fmt.Errorf("%w: reservation must have at least one item", service.ErrInvalidStockRequest), codes.InvalidArgument},
+		{"database failure", errors.New("connection refused"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewProductGRPCHandler(nil, &fakeStockService{err: tt.err})
+
+			_, err := h.UpdateStock(context.Background(), &pb.UpdateStockRequest{ProductId: 7, Quantity: -3})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("UpdateStock() code = %v, want %v", got, tt.want)
+			}
+			_, err = h.ReserveStock(context.Background(), &pb.ReserveStockRequest{Items: []*pb.StockItem{{ProductId: 7, Quantity: 3}}})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("ReserveStock() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"log"
 	"net"
 	grpcHandler "github.com/ploezy/ecommerce-platform/product-service/internal/grpc/handler"
-	pb "github.com/ploezy/ecommerce-platform/product-service/proto"
+	pb "github.com/ploezy/ecommerce-platform/proto/product"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/product-service/internal/repository"
 	"github.com/ploezy/ecommerce-platform/product-service/internal/service"
 )
 
@@ -41,6 +43,10 @@ func (h * ProductHandler) CreateProduct(c *gin.Context){
 	}
 	product, err := h.service.CreateProduct(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidPrice) {
+			ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		ErrorResponse(c,http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -70,7 +76,7 @@ func (h * ProductHandler) GetProductByID(c *gin.Context){
 
 	product, err := h.service.GetProductByID(c.Request.Context(), uint(id))
 	if err != nil {
-		if err.Error() == "product not found" {
+		if errors.Is(err, repository.ErrProductNotFound) {
 			ErrorResponse(c, http.StatusNotFound, err.Error())
 			return
 		}
@@ -136,10 +142,14 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 
 	product, err := h.service.UpdateProduct(c.Request.Context(), uint(id), &req)
 	if err != nil {
-		if err.Error() == "product not found" {
+		if errors.Is(err, repository.ErrProductNotFound) {
 			ErrorResponse(c, http.StatusNotFound, err.Error())
 			return
 		}
+		if errors.Is(err, service.ErrInvalidPrice) {
+			ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -171,7 +181,7 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	}
 
 	if err := h.service.DeleteProduct(c.Request.Context(), uint(id)); err != nil {
-		if err.Error() == "product not found" {
+		if errors.Is(err, repository.ErrProductNotFound) {
 			ErrorResponse(c, http.StatusNotFound, err.Error())
 			return
 		}
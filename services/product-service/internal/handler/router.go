@@ -4,6 +4,7 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
 	"github.com/ploezy/ecommerce-platform/product-service/internal/middleware"
 )
 
@@ -36,10 +37,11 @@ func SetupRouter(productHandler *ProductHandler, authMiddleware *middleware.Auth
 			products.GET("/search", productHandler.SearchProducts)    // GET /api/v1/products/search
 			products.GET("/:id", productHandler.GetProductByID)       // GET /api/v1/products/:id
 
-			// Protected routes (authentication + admin role required)
+			// Protected routes (authentication + product:write permission required)
 			protected := products.Group("")
 			protected.Use(authMiddleware.Authenticate())
-			protected.Use(authMiddleware.RequireAdmin())
+			protected.Use(authz.RequirePermission(authz.ProductWrite))
+			protected.Use(authMiddleware.RequireMFA())
 			{
 				protected.POST("", productHandler.CreateProduct)      // POST /api/v1/products
 				protected.PUT("/:id", productHandler.UpdateProduct)   // PUT /api/v1/products/:id
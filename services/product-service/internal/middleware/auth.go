@@ -5,16 +5,19 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/ploezy/ecommerce-platform/product-service/pkg/auth"
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
 )
 
 type AuthMiddleware struct {
-	jwtHelper *auth.JWTHelper
+	verifier *token.Verifier
+	// requireAdminMFA makes RequireMFA refuse sessions without MFA
+	requireAdminMFA bool
 }
 
 // NewAuthMiddleware creates a new auth middleware
-func NewAuthMiddleware(jwtHelper *auth.JWTHelper) *AuthMiddleware{
-	return &AuthMiddleware{jwtHelper: jwtHelper}
+func NewAuthMiddleware(verifier *token.Verifier, requireAdminMFA bool) *AuthMiddleware{
+	return &AuthMiddleware{verifier: verifier, requireAdminMFA: requireAdminMFA}
 }
 
 // Authenticate middleware to verify JWT token
@@ -41,8 +44,8 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		// Validate token
-		claims, err := m.jwtHelper.ValidateToken(tokenString)
+		// Verify token against the JWKS of user-service
+		claims, err := m.verifier.Verify(c.Request.Context(), tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
@@ -56,33 +59,19 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
+		authz.SetClaims(c, claims)
 
 		c.Next()
 	}
 }
 
-// RequireAdmin middleware to check if user is admin
-func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "User role not found",
-			})
-			c.Abort()
-			return
+// RequireMFA refuses tokens whose session started without MFA when
+// requireAdminMFA is set. The amr claim tells how the session started.
+func (m *AuthMiddleware) RequireMFA() gin.HandlerFunc {
+	if !m.requireAdminMFA {
+		return func(c *gin.Context) {
+			c.Next()
 		}
-
-		if role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{
-				"success": false,
-				"error":   "Access denied. Admin role required",
-			})
-			c.Abort()
-			return
-		}
-
-		c.Next()
 	}
-}
\ No newline at end of file
+	return authz.RequireAuthMethod(token.AuthMethodMFA)
+}
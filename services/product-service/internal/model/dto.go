@@ -1,14 +1,18 @@
 package model
 
-import "github.com/lib/pq"
+import (
+	"github.com/lib/pq"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
+)
 
 // CreateProductRequest is the request for creating a product
 type CreateProductRequest struct {
 	Name        string         `json:"name" binding:"required" example:"iPhone 15 Pro Max"`
 	Description string         `json:"description" example:"Latest Apple flagship smartphone"`
-	Price       float64        `json:"price" binding:"required,gt=0" example:"45900"`
+	Price       money.Money    `json:"price"`
 	Stock       int            `json:"stock" binding:"required,gte=0" example:"50"`
 	Category    string         `json:"category" binding:"required" example:"Electronics"`
+	WeightGrams int            `json:"weight_grams" binding:"gte=0" example:"221"`
 	Images      pq.StringArray `json:"images" swaggertype:"array,string" example:"image1.jpg,image2.jpg"`
 }
 
@@ -16,9 +20,10 @@ type CreateProductRequest struct {
 type UpdateProductRequest struct {
 	Name        string         `json:"name" example:"iPhone 15 Pro Max"`
 	Description string         `json:"description" example:"Updated description"`
-	Price       float64        `json:"price" binding:"omitempty,gt=0" example:"43900"`
+	Price       *money.Money   `json:"price"`
 	Stock       int            `json:"stock" binding:"omitempty,gte=0" example:"45"`
 	Category    string         `json:"category" example:"Electronics"`
+	WeightGrams *int           `json:"weight_grams" binding:"omitempty,gte=0" example:"221"`
 	Images      pq.StringArray `json:"images" swaggertype:"array,string" example:"image1.jpg,image2.jpg"`
 }
 
@@ -27,9 +32,10 @@ type ProductResponse struct {
 	ID          uint           `json:"id" example:"1"`
 	Name        string         `json:"name" example:"iPhone 15 Pro Max"`
 	Description string         `json:"description" example:"Latest Apple flagship smartphone"`
-	Price       float64        `json:"price" example:"45900"`
+	Price       money.Money    `json:"price"`
 	Stock       int            `json:"stock" example:"50"`
 	Category    string         `json:"category" example:"Electronics"`
+	WeightGrams int            `json:"weight_grams" example:"221"`
 	Images      pq.StringArray `json:"images" swaggertype:"array,string" example:"image1.jpg,image2.jpg"`
 	CreatedAt   string         `json:"created_at" example:"2025-11-07 15:30:00"`
 	UpdatedAt   string         `json:"updated_at" example:"2025-11-07 15:30:00"`
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/lib/pq"
+	"github.com/ploezy/ecommerce-platform/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -11,9 +12,10 @@ type Product struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	Name        string         `gorm:"size:255;not null" json:"name" binding:"required"`
 	Description string         `gorm:"type:text" json:"description"`
-	Price       float64        `gorm:"not null" json:"price" binding:"required,gt=0"`
+	Price       money.Money    `gorm:"embedded;embeddedPrefix:price_" json:"price"`
 	Stock       int            `gorm:"not null;default:0" json:"stock" binding:"required,gte=0"`
 	Category    string         `gorm:"size:100" json:"category" binding:"required"`
+	WeightGrams int            `gorm:"not null;default:0" json:"weight_grams"`
 	Images      pq.StringArray `gorm:"type:text[]" json:"images"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// Stock reservation status constants
+const (
+	ReservationStatusReserved  = "reserved"
+	ReservationStatusCommitted = "committed"
+	ReservationStatusReleased  = "released"
+	ReservationStatusExpired   = "expired"
+)
+
+// StockReservation holds stock taken out of products until it is committed or released
+type StockReservation struct {
+	ID        string                 `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	Reference string                 `gorm:"size:100;index" json:"reference"`
+	Status    string                 `gorm:"type:varchar(20);not null;index" json:"status"`
+	ExpiresAt time.Time              `gorm:"not null;index" json:"expires_at"`
+	Items     []StockReservationItem `gorm:"foreignKey:ReservationID;constraint:OnDelete:CASCADE" json:"items"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// TableName specifies the table name for StockReservation model
+func (StockReservation) TableName() string {
+	return "stock_reservations"
+}
+
+// StockReservationItem is a single product line of a reservation
+type StockReservationItem struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	ReservationID string `gorm:"type:varchar(36);not null;index" json:"reservation_id"`
+	ProductID     uint   `gorm:"not null;index" json:"product_id"`
+	Quantity      int    `gorm:"not null" json:"quantity"`
+}
+
+// TableName specifies the table name for StockReservationItem model
+func (StockReservationItem) TableName() string {
+	return "stock_reservation_items"
+}
+
+// StockRestock records a reference whose stock was given back, so a retried
+// restock does not add the stock twice
+type StockRestock struct {
+	Reference string    `gorm:"primaryKey;type:varchar(100)" json:"reference"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for StockRestock model
+func (StockRestock) TableName() string {
+	return "stock_restocks"
+}
+
+// StockItem is a product and quantity pair used when reserving stock
+type StockItem struct {
+	ProductID uint
+	Quantity  int
+}
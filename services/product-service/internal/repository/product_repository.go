@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
 )
 
+// ErrProductNotFound is returned when a product does not exist or is deleted
+var ErrProductNotFound = errors.New("product not found")
+
 type ProductRepository interface {
 	Create(ctx context.Context, product *model.Product) error
 	FindByID(ctx context.Context, id uint) (*model.Product, error)
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
+)
+
+var (
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrReservationNotFound   = errors.New("reservation not found")
+	ErrReservationNotActive  = errors.New("reservation is not active")
+	ErrReservationHasExpired = errors.New("reservation has expired")
+)
+
+type StockRepository interface {
+	AdjustStock(ctx context.Context, productID uint, delta int) (int, error)
+	Reserve(ctx context.Context, reservation *model.StockReservation) error
+	Release(ctx context.Context, reservationID string, status string) (*model.StockReservation, error)
+	Commit(ctx context.Context, reservationID string) (*model.StockReservation, error)
+	FindExpiredIDs(ctx context.Context, before time.Time, limit int) ([]string, error)
+	Restock(ctx context.Context, reference string, items []model.StockItem) (bool, error)
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stockRepository struct {
+	db *gorm.DB
+}
+
+// NewStockRepository creates a new stock repository
+func NewStockRepository(db *gorm.DB) StockRepository {
+	return &stockRepository{db: db}
+}
+
+// AdjustStock changes stock by delta with a conditional update so stock never goes negative
+func (r *stockRepository) AdjustStock(ctx context.Context, productID uint, delta int) (int, error) {
+	var product model.Product
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Product{}).
+			Where("id = ? AND stock + ? >= 0", productID, delta).
+			Update("stock", gorm.Expr("stock + ?", delta))
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if err := tx.First(&product, productID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %d", ErrProductNotFound, productID)
+			}
+			return err
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: current %d, requested change %d", ErrInsufficientStock, product.Stock, delta)
+		}
+		return nil
+	})
+	if err != nil {
+		return product.Stock, err
+	}
+	return product.Stock, nil
+}
+
+// Reserve decrements stock for every item and stores the reservation in one transaction
+func (r *stockRepository) Reserve(ctx context.Context, reservation *model.StockReservation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range reservation.Items {
+			result := tx.Model(&model.Product{}).
+				Where("id = ? AND stock >= ?", item.ProductID, item.Quantity).
+				Update("stock", gorm.Expr("stock - ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return r.reserveFailure(tx, item)
+			}
+		}
+
+		return tx.Create(reservation).Error
+	})
+}
+
+// Release returns the reserved stock and moves the reservation to the given status
+func (r *stockRepository) Release(ctx context.Context, reservationID string, status string) (*model.StockReservation, error) {
+	var reservation model.StockReservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.lockReservation(tx, reservationID, &reservation); err != nil {
+			return err
+		}
+		if reservation.Status != model.ReservationStatusReserved {
+			return ErrReservationNotActive
+		}
+
+		for _, item := range reservation.Items {
+			err := tx.Model(&model.Product{}).
+				Unscoped().
+				Where("id = ?", item.ProductID).
+				Update("stock", gorm.Expr("stock + ?", item.Quantity)).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		reservation.Status = status
+		return tx.Model(&reservation).Update("status", status).Error
+	})
+	if err != nil {
+		return &reservation, err
+	}
+	return &reservation, nil
+}
+
+// Commit marks a reservation as committed so its stock is never returned
+func (r *stockRepository) Commit(ctx context.Context, reservationID string) (*model.StockReservation, error) {
+	var reservation model.StockReservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.lockReservation(tx, reservationID, &reservation); err != nil {
+			return err
+		}
+		if reservation.Status != model.ReservationStatusReserved {
+			return ErrReservationNotActive
+		}
+		if time.Now().After(reservation.ExpiresAt) {
+			return ErrReservationHasExpired
+		}
+
+		reservation.Status = model.ReservationStatusCommitted
+		return tx.Model(&reservation).Update("status", model.ReservationStatusCommitted).Error
+	})
+	if err != nil {
+		return &reservation, err
+	}
+	return &reservation, nil
+}
+
+// FindExpiredIDs finds reservations that are still held after their expiry time
+func (r *stockRepository) FindExpiredIDs(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&model.StockReservation{}).
+		Where("status = ? AND expires_at < ?", model.ReservationStatusReserved, before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Restock adds the stock of items back and records reference in one
+// transaction. It returns false without changing stock when reference was
+// restocked before.
+func (r *stockRepository) Restock(ctx context.Context, reference string, items []model.StockItem) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.StockRestock{Reference: reference})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		for _, item := range items {
+			result := tx.Model(&model.Product{}).
+				Unscoped().
+				Where("id = ?", item.ProductID).
+				Update("stock", gorm.Expr("stock + ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("%w: %d", ErrProductNotFound, item.ProductID)
+			}
+		}
+
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// reserveFailure tells a product that does not exist apart from one that has
+// too little stock for item
+func (r *stockRepository) reserveFailure(tx *gorm.DB, item model.StockReservationItem) error {
+	var count int64
+	if err := tx.Model(&model.Product{}).Where("id = ?", item.ProductID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %d", ErrProductNotFound, item.ProductID)
+	}
+	return fmt.Errorf("%w for product %d (requested %d)", ErrInsufficientStock, item.ProductID, item.Quantity)
+}
+
+// lockReservation loads a reservation with its items and locks the row until the transaction ends
+func (r *stockRepository) lockReservation(tx *gorm.DB, reservationID string, reservation *model.StockReservation) error {
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(reservation, "id = ?", reservationID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReservationNotFound
+		}
+		return err
+	}
+
+	return tx.Where("reservation_id = ?", reservationID).Find(&reservation.Items).Error
+}
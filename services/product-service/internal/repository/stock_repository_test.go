@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens an empty SQLite database with the product tables. A single
+// connection makes concurrent transactions queue up like row locks would.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared&_busy_timeout=5000", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&model.Product{}, &model.StockReservation{}, &model.StockReservationItem{}, &model.StockRestock{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func createProduct(t *testing.T, db *gorm.DB, stock int) *model.Product {
+	t.Helper()
+	product := &model.Product{Name: "Mug", Category: "kitchen", Stock: stock}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatal(err)
+	}
+	return product
+}
+
+func stockOf(t *testing.T, db *gorm.DB, productID uint) int {
+	t.Helper()
+	var product model.Product
+	if err := db.First(&product, productID).Error; err != nil {
+		t.Fatal(err)
+	}
+	return product.Stock
+}
+
+func newReservation(id string, expiresAt time.Time, items ...model.StockReservationItem) *model.StockReservation {
+	for i := range items {
+		items[i].ReservationID = id
+	}
+	return &model.StockReservation{
+		ID:        id,
+		Status:    model.ReservationStatusReserved,
+		ExpiresAt: expiresAt,
+		Items:     items,
+	}
+}
+
+func TestAdjustStockNeverGoesNegative(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewStockRepository(db)
+	product := createProduct(t, db, 5)
+	ctx := context.Background()
+
+	stock, err := repo.AdjustStock(ctx, product.ID, -3)
+	if err != nil || stock != 2 {
+		t.Fatalf("AdjustStock(-3) = %d, %v, want 2", stock, err)
+	}
+	stock, err = repo.AdjustStock(ctx, product.ID, -3)
+	if !errors.Is(err, ErrInsufficientStock) || stock != 2 {
+		t.Errorf("AdjustStock(-3) = %d, %v, want 2 and ErrInsufficientStock", stock, err)
+	}
+	if _, err := repo.AdjustStock(ctx, product.ID+1, 1); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("AdjustStock() of a missing product error = %v, want ErrProductNotFound", err)
+	}
+	if got := stockOf(t, db, product.ID); got != 2 {
+		t.Errorf("stock = %d, want 2", got)
+	}
+}
+
+func TestConcurrentReservationsDoNotOversell(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewStockRepository(db)
+	product := createProduct(t, db, 5)
+
+	const buyers = 20
+	var wg sync.WaitGroup
+	errs := make([]error, buyers)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reservation := newReservation(fmt.Sprintf("res-%d", i), time.Now().Add(time.Minute),
+				model.StockReservationItem{ProductID: product.ID, Quantity: 1})
+			errs[i] = repo.Reserve(context.Background(), reservation)
+		}(i)
+	}
+	wg.Wait()
+
+	reserved := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			reserved++
+		case !errors.Is(err, ErrInsufficientStock):
+			t.Errorf("Reserve() error = %v, want ErrInsufficientStock", err)
+		}
+	}
+	if reserved != 5 {
+		t.Errorf("%d reservations succeeded, want 5", reserved)
+	}
+	if got := stockOf(t, db, product.ID); got != 0 {
+		t.Errorf("stock = %d, want 0", got)
+	}
+}
+
+func TestReserveIsAllOrNothing(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewStockRepository(db)
+	mug := createProduct(t, db, 5)
+	plate := createProduct(t, db, 1)
+	ctx := context.Background()
+
+	reservation := newReservation("res-1", time.Now().Add(time.Minute),
+		model.StockReservationItem{ProductID: mug.ID, Quantity: 2},
+		model.StockReservationItem{ProductID: plate.ID, Quantity: 2})
+	if err := repo.Reserve(ctx, reservation); !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("Reserve() error = %v, want ErrInsufficientStock", err)
+	}
+	if got := stockOf(t, db, mug.ID); got != 5 {
+		t.Errorf("stock of the first product = %d, want 5 after the rollback", got)
+	}
+
+	reservation = newReservation("res-2", time.Now().Add(time.Minute),
+		model.StockReservationItem{ProductID: mug.ID, Quantity: 1},
+		model.StockReservationItem{ProductID: plate.ID + 100, Quantity: 1})
+	if err := repo.Reserve(ctx, reservation); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("Reserve() of a missing product error = %v, want ErrProductNotFound", err)
+	}
+}
+
+func TestReleaseAndCommitOnlyOnce(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewStockRepository(db)
+	product := createProduct(t, db, 5)
+	ctx := context.Background()
+
+	released := newReservation("released", time.Now().Add(time.Minute), model.StockReservationItem{ProductID: product.ID, Quantity: 2})
+	committed := newReservation("committed", time.Now().Add(time.Minute), model.StockReservationItem{ProductID: product.ID, Quantity: 1})
+	for _, reservation := range []*model.StockReservation{released, committed} {
+		if err := repo.Reserve(ctx, reservation); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := repo.Release(ctx, "released", model.ReservationStatusReleased); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := repo.Release(ctx, "released", model.ReservationStatusReleased); !errors.Is(err, ErrReservationNotActive) {
+		t.Errorf("second Release() error = %v, want ErrReservationNotActive", err)
+	}
+	if _, err := repo.Commit(ctx, "committed"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if _, err := repo.Release(ctx, "committed", model.ReservationStatusReleased); !errors.Is(err, ErrReservationNotActive) {
+		t.Errorf("Release() of a committed reservation error = %v, want ErrReservationNotActive", err)
+	}
+	if _, err := repo.Commit(ctx, "missing"); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("Commit() of a missing reservation error = %v, want ErrReservationNotFound", err)
+	}
+
+	// The released stock is back, the committed stock stays taken
+	if got := stockOf(t, db, product.ID); got != 4 {
+		t.Errorf("stock = %d, want 4", got)
+	}
+}
+
+func TestCommitRejectsExpiredReservation(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewStockRepository(db)
+	product := createProduct(t, db, 5)
+	ctx := context.Background()
+
+	reservation := newReservation("late", time.Now().Add(-time.Second), model.StockReservationItem{ProductID: product.ID, Quantity: 1})
+	if err := repo.Reserve(ctx, reservation); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Commit(ctx, "late"); !errors.Is(err, ErrReservationHasExpired) {
+		t.Errorf("Commit() error = %v, want ErrReservationHasExpired", err)
+	}
+
+	ids, err := repo.FindExpiredIDs(ctx, time.Now(), 10)
+	if err != nil || len(ids) != 1 || ids[0] != "late" {
+		t.Errorf("FindExpiredIDs() = %v, %v, want [late]", ids, err)
+	}
+}
+
+func TestRestockAppliesReferenceOnce(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewStockRepository(db)
+	mug := createProduct(t, db, 1)
+	cup := createProduct(t, db, 0)
+	ctx := context.Background()
+	items := []model.StockItem{{ProductID: mug.ID, Quantity: 2}, {ProductID: cup.ID, Quantity: 3}}
+
+	if applied, err := repo.Restock(ctx, "order:1:cancel", items); err != nil || !applied {
+		t.Fatalf("Restock() = %v, %v, want applied", applied, err)
+	}
+	// A retry of the same command must not add the stock again
+	if applied, err := repo.Restock(ctx, "order:1:cancel", items); err != nil || applied {
+		t.Fatalf("second Restock() = %v, %v, want not applied", applied, err)
+	}
+	if stockOf(t, db, mug.ID) != 3 || stockOf(t, db, cup.ID) != 3 {
+		t.Errorf("stock = %d and %d, want 3 and 3", stockOf(t, db, mug.ID), stockOf(t, db, cup.ID))
+	}
+
+	// An unknown product rolls the whole restock back so it can be retried
+	_, err := repo.Restock(ctx, "order:2:cancel", []model.StockItem{{ProductID: mug.ID, Quantity: 1}, {ProductID: 999, Quantity: 1}})
+	if !errors.Is(err, ErrProductNotFound) {
+		t.Fatalf("Restock() with an unknown product error = %v, want ErrProductNotFound", err)
+	}
+	if stockOf(t, db, mug.ID) != 3 {
+		t.Errorf("stock = %d after a failed restock, want 3", stockOf(t, db, mug.ID))
+	}
+	var count int64
+	db.Model(&model.StockRestock{}).Where("reference = ?", "order:2:cancel").Count(&count)
+	if count != 0 {
+		t.Error("failed restock was recorded")
+	}
+}
@@ -2,10 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
 )
 
+// ErrInvalidPrice is returned for a price that is not positive or has an
+// unknown currency
+var ErrInvalidPrice = errors.New("invalid price")
+
 type ProductService interface {
 	CreateProduct(ctx context.Context, req *model.CreateProductRequest) (*model.ProductResponse, error)
 	GetProductByID(ctx context.Context, id uint) (*model.ProductResponse, error)
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/ploezy/ecommerce-platform/pkg/money"
 	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
 	"github.com/ploezy/ecommerce-platform/product-service/internal/repository"
 	"github.com/ploezy/ecommerce-platform/product-service/pkg/redis"
@@ -28,18 +29,26 @@ func NewProductService(repo repository.ProductRepository, cache *redis.CacheServ
 }
 
 const (
-	productCacheKeyPrefix = "product:"
+	// v2: prices are cached as money amounts in minor units
+	// v3: products carry their shipping weight
+	productCacheKeyPrefix = "product:v3:"
 	productCacheTTL       = 10 * time.Minute
 )
 
 // CreateProduct creates a new product
 func (s *productService) CreateProduct(ctx context.Context, req *model.CreateProductRequest) (*model.ProductResponse, error) {
+	price, err := validatePrice(req.Price)
+	if err != nil {
+		return nil, err
+	}
+
 	product := &model.Product{
 		Name:        req.Name,
 		Description: req.Description,
-		Price:       req.Price,
+		Price:       price,
 		Stock:       req.Stock,
 		Category:    req.Category,
+		WeightGrams: req.WeightGrams,
 		Images:      req.Images,
 	}
 
@@ -68,7 +77,7 @@ func (s *productService) GetProductByID(ctx context.Context, id uint) (*model.Pr
 	product, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, repository.ErrProductNotFound
 		}
 		return nil, err
 	}
@@ -125,7 +134,7 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, req *model.
 	product, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, repository.ErrProductNotFound
 		}
 		return nil, err
 	}
@@ -137,8 +146,12 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, req *model.
 	if req.Description != "" {
 		product.Description = req.Description
 	}
-	if req.Price > 0 {
-		product.Price = req.Price
+	if req.Price != nil {
+		price, err := validatePrice(*req.Price)
+		if err != nil {
+			return nil, err
+		}
+		product.Price = price
 	}
 	if req.Stock >= 0 {
 		product.Stock = req.Stock
@@ -146,6 +159,9 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, req *model.
 	if req.Category != "" {
 		product.Category = req.Category
 	}
+	if req.WeightGrams != nil {
+		product.WeightGrams = *req.WeightGrams
+	}
 	if len(req.Images) > 0 {
 		product.Images = req.Images
 	}
@@ -171,7 +187,7 @@ func (s *productService) DeleteProduct(ctx context.Context, id uint) error {
 	_, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("product not found")
+			return repository.ErrProductNotFound
 		}
 		return err
 	}
@@ -225,6 +241,23 @@ func (s *productService) SearchProducts(ctx context.Context, keyword string, pag
 	}, nil
 }
 
+// validatePrice checks that a price is positive and has a valid currency.
+// Prices without a currency are in the default currency.
+func validatePrice(price money.Money) (money.Money, error) {
+	if price.Currency == "" {
+		price.Currency = money.DefaultCurrency
+	}
+	price = money.New(price.Amount, price.Currency)
+
+	if !money.ValidCurrency(price.Currency) {
+		return money.Money{}, fmt.Errorf("%w: unknown currency %q", ErrInvalidPrice, price.Currency)
+	}
+	if !price.IsPositive() {
+		return money.Money{}, fmt.Errorf("%w: amount must be greater than 0", ErrInvalidPrice)
+	}
+	return price, nil
+}
+
 // Helper function to convert Product to ProductResponse
 func (s *productService) toProductResponse(product *model.Product) *model.ProductResponse {
 	return &model.ProductResponse{
@@ -234,6 +267,7 @@ func (s *productService) toProductResponse(product *model.Product) *model.Produc
 		Price:       product.Price,
 		Stock:       product.Stock,
 		Category:    product.Category,
+		WeightGrams: product.WeightGrams,
 		Images:      product.Images,
 		CreatedAt:   product.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:   product.UpdatedAt.Format("2006-01-02 15:04:05"),
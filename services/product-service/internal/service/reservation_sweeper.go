@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ReservationSweeper periodically releases stock reservations that were never committed
+type ReservationSweeper struct {
+	service  StockService
+	interval time.Duration
+}
+
+// NewReservationSweeper creates a new reservation sweeper
+func NewReservationSweeper(service StockService, interval time.Duration) *ReservationSweeper {
+	return &ReservationSweeper{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Start runs the sweeper until ctx is cancelled
+func (s *ReservationSweeper) Start(ctx context.Context) {
+	log.Printf("Reservation sweeper started (interval: %v)", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Reservation sweeper stopped")
+			return
+		case <-ticker.C:
+			released, err := s.service.ReleaseExpiredReservations(ctx)
+			if err != nil {
+				log.Printf("Failed to release expired reservations: %v", err)
+				continue
+			}
+			if released > 0 {
+				log.Printf("Released %d expired stock reservations", released)
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
+)
+
+// ErrInvalidStockRequest is returned for a reservation without items or with
+// a quantity that is not positive
+var ErrInvalidStockRequest = errors.New("invalid stock request")
+
+type StockService interface {
+	AdjustStock(ctx context.Context, productID uint, delta int) (int, error)
+	ReserveStock(ctx context.Context, items []model.StockItem, ttl time.Duration, reference string) (*model.StockReservation, error)
+	ReleaseStock(ctx context.Context, reservationID string) error
+	CommitReservation(ctx context.Context, reservationID string) error
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+	RestockItems(ctx context.Context, reference string, items []model.StockItem) (bool, error)
+}
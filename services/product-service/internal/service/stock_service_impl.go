@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/product-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/product-service/pkg/redis"
+)
+
+type stockService struct {
+	repo       repository.StockRepository
+	cache      *redis.CacheService
+	defaultTTL time.Duration
+}
+
+// NewStockService creates a new stock service
+func NewStockService(repo repository.StockRepository, cache *redis.CacheService, defaultTTL time.Duration) StockService {
+	return &stockService{
+		repo:       repo,
+		cache:      cache,
+		defaultTTL: defaultTTL,
+	}
+}
+
+const expiredReservationBatchSize = 100
+
+// AdjustStock atomically increases or decreases the stock of a product
+func (s *stockService) AdjustStock(ctx context.Context, productID uint, delta int) (int, error) {
+	newStock, err := s.repo.AdjustStock(ctx, productID, delta)
+	if err != nil {
+		return newStock, err
+	}
+
+	s.clearProductCache(ctx, productID)
+	return newStock, nil
+}
+
+// ReserveStock takes stock for all items in one transaction and returns the reservation
+func (s *stockService) ReserveStock(ctx context.Context, items []model.StockItem, ttl time.Duration, reference string) (*model.StockReservation, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: reservation must have at least one item", ErrInvalidStockRequest)
+	}
+	if ttl <= 0 {
+		ttl = s.defaultTTL
+	}
+
+	// Merge duplicate products and lock rows in a stable order to avoid deadlocks
+	quantities := make(map[uint]int)
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity for product %d must be greater than 0", ErrInvalidStockRequest, item.ProductID)
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	reservation := &model.StockReservation{
+		ID:        uuid.NewString(),
+		Reference: reference,
+		Status:    model.ReservationStatusReserved,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	for productID, quantity := range quantities {
+		reservation.Items = append(reservation.Items, model.StockReservationItem{
+			ReservationID: reservation.ID,
+			ProductID:     productID,
+			Quantity:      quantity,
+		})
+	}
+	sort.Slice(reservation.Items, func(i, j int) bool {
+		return reservation.Items[i].ProductID < reservation.Items[j].ProductID
+	})
+
+	if err := s.repo.Reserve(ctx, reservation); err != nil {
+		return nil, err
+	}
+
+	for _, item := range reservation.Items {
+		s.clearProductCache(ctx, item.ProductID)
+	}
+
+	log.Printf("Stock reserved: reservation=%s items=%d expires_at=%s", reservation.ID, len(reservation.Items), reservation.ExpiresAt.Format(time.RFC3339))
+	return reservation, nil
+}
+
+// ReleaseStock returns the stock of a reservation. Releasing twice is not an error.
+func (s *stockService) ReleaseStock(ctx context.Context, reservationID string) error {
+	reservation, err := s.repo.Release(ctx, reservationID, model.ReservationStatusReleased)
+	if err != nil {
+		if errors.Is(err, repository.ErrReservationNotActive) &&
+			(reservation.Status == model.ReservationStatusReleased || reservation.Status == model.ReservationStatusExpired) {
+			return nil
+		}
+		if errors.Is(err, repository.ErrReservationNotActive) {
+			return fmt.Errorf("cannot release reservation with status: %s", reservation.Status)
+		}
+		return err
+	}
+
+	for _, item := range reservation.Items {
+		s.clearProductCache(ctx, item.ProductID)
+	}
+
+	log.Printf("Stock released: reservation=%s", reservationID)
+	return nil
+}
+
+// CommitReservation makes the reserved stock permanent. Committing twice is not an error.
+func (s *stockService) CommitReservation(ctx context.Context, reservationID string) error {
+	reservation, err := s.repo.Commit(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrReservationNotActive) && reservation.Status == model.ReservationStatusCommitted {
+			return nil
+		}
+		if errors.Is(err, repository.ErrReservationNotActive) {
+			return fmt.Errorf("cannot commit reservation with status: %s", reservation.Status)
+		}
+		return err
+	}
+
+	log.Printf("Stock reservation committed: reservation=%s", reservationID)
+	return nil
+}
+
+// ReleaseExpiredReservations releases every reservation whose TTL has passed
+func (s *stockService) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	released := 0
+	for {
+		ids, err := s.repo.FindExpiredIDs(ctx, time.Now(), expiredReservationBatchSize)
+		if err != nil {
+			return released, err
+		}
+
+		for _, id := range ids {
+			reservation, err := s.repo.Release(ctx, id, model.ReservationStatusExpired)
+			if err != nil {
+				// Another replica may have released or committed it first
+				if errors.Is(err, repository.ErrReservationNotActive) {
+					continue
+				}
+				return released, err
+			}
+
+			for _, item := range reservation.Items {
+				s.clearProductCache(ctx, item.ProductID)
+			}
+			released++
+		}
+
+		if len(ids) < expiredReservationBatchSize {
+			return released, nil
+		}
+	}
+}
+
+// RestockItems gives the stock of items back once per reference. Restocking a
+// reference again is not an error and returns false.
+func (s *stockService) RestockItems(ctx context.Context, reference string, items []model.StockItem) (bool, error) {
+	if reference == "" {
+		return false, fmt.Errorf("%w: restock must have a reference", ErrInvalidStockRequest)
+	}
+	if len(items) == 0 {
+		return false, fmt.Errorf("%w: restock must have at least one item", ErrInvalidStockRequest)
+	}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return false, fmt.Errorf("%w: quantity for product %d must be greater than 0", ErrInvalidStockRequest, item.ProductID)
+		}
+	}
+
+	applied, err := s.repo.Restock(ctx, reference, items)
+	if err != nil {
+		return false, err
+	}
+	if !applied {
+		log.Printf("Stock already restocked: reference=%s", reference)
+		return false, nil
+	}
+
+	for _, item := range items {
+		s.clearProductCache(ctx, item.ProductID)
+	}
+
+	log.Printf("Stock restocked: reference=%s items=%d", reference, len(items))
+	return true, nil
+}
+
+// clearProductCache removes a cached product so the next read sees the new stock
+func (s *stockService) clearProductCache(ctx context.Context, productID uint) {
+	cacheKey := fmt.Sprintf("%s%d", productCacheKeyPrefix, productID)
+	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+		log.Printf("Failed to clear cache for product %d: %v", productID, err)
+	}
+}
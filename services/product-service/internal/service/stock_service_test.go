@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/product-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/product-service/pkg/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type stockFixture struct {
+	db      *gorm.DB
+	service StockService
+	product *model.Product
+}
+
+func newStockFixture(t *testing.T, stock int) *stockFixture {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared&_busy_timeout=5000", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&model.Product{}, &model.StockReservation{}, &model.StockReservationItem{}, &model.StockRestock{}); err != nil {
+		t.Fatal(err)
+	}
+
+	product := &model.Product{Name: "Mug", Category: "kitchen", Stock: stock}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	cache := redis.NewCacheService(goredis.NewClient(&goredis.Options{Addr: miniredis.RunT(t).Addr()}))
+	return &stockFixture{
+		db:      db,
+		service: NewStockService(repository.NewStockRepository(db), cache, time.Minute),
+		product: product,
+	}
+}
+
+func (f *stockFixture) stock(t *testing.T) int {
+	t.Helper()
+	var product model.Product
+	if err := f.db.First(&product, f.product.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	return product.Stock
+}
+
+func (f *stockFixture) reserve(t *testing.T, quantity int, ttl time.Duration) *model.StockReservation {
+	t.Helper()
+	reservation, err := f.service.ReserveStock(context.Background(), []model.StockItem{{ProductID: f.product.ID, Quantity: quantity}}, ttl, "order-1")
+	if err != nil {
+		t.Fatalf("ReserveStock() error = %v", err)
+	}
+	return reservation
+}
+
+func TestReserveStockValidatesItems(t *testing.T) {
+	f := newStockFixture(t, 5)
+	ctx := context.Background()
+
+	if _, err := f.service.ReserveStock(ctx, nil, 0, ""); !errors.Is(err, ErrInvalidStockRequest) {
+		t.Errorf("ReserveStock() without items error = %v, want ErrInvalidStockRequest", err)
+	}
+	items := []model.StockItem{{ProductID: f.product.ID, Quantity: 0}}
+	if _, err := f.service.ReserveStock(ctx, items, 0, ""); !errors.Is(err, ErrInvalidStockRequest) {
+		t.Errorf("ReserveStock() with a zero quantity error = %v, want ErrInvalidStockRequest", err)
+	}
+
+	// Lines of the same product are reserved together
+	items = []model.StockItem{{ProductID: f.product.ID, Quantity: 3}, {ProductID: f.product.ID, Quantity: 3}}
+	if _, err := f.service.ReserveStock(ctx, items, 0, ""); !errors.Is(err, repository.ErrInsufficientStock) {
+		t.Errorf("ReserveStock() of 6 error = %v, want ErrInsufficientStock", err)
+	}
+	if got := f.stock(t); got != 5 {
+		t.Errorf("stock = %d, want 5", got)
+	}
+}
+
+func TestReleaseStockIsIdempotent(t *testing.T) {
+	f := newStockFixture(t, 5)
+	ctx := context.Background()
+	reservation := f.reserve(t, 2, 0)
+
+	for i := 0; i < 2; i++ {
+		if err := f.service.ReleaseStock(ctx, reservation.ID); err != nil {
+			t.Fatalf("ReleaseStock() #%d error = %v", i+1, err)
+		}
+	}
+	if got := f.stock(t); got != 5 {
+		t.Errorf("stock = %d, want 5 after releasing twice", got)
+	}
+	if err := f.service.CommitReservation(ctx, reservation.ID); err == nil || !strings.Contains(err.Error(), "cannot commit") {
+		t.Errorf("CommitReservation() of a released reservation error = %v", err)
+	}
+}
+
+func TestCommitReservationIsIdempotent(t *testing.T) {
+	f := newStockFixture(t, 5)
+	ctx := context.Background()
+	reservation := f.reserve(t, 2, 0)
+
+	for i := 0; i < 2; i++ {
+		if err := f.service.CommitReservation(ctx, reservation.ID); err != nil {
+			t.Fatalf("CommitReservation() #%d error = %v", i+1, err)
+		}
+	}
+	if err := f.service.ReleaseStock(ctx, reservation.ID); err == nil || !strings.Contains(err.Error(), "cannot release") {
+		t.Errorf("ReleaseStock() of a committed reservation error = %v", err)
+	}
+	if got := f.stock(t); got != 3 {
+		t.Errorf("stock = %d, want 3", got)
+	}
+}
+
+func TestReleaseExpiredReservations(t *testing.T) {
+	f := newStockFixture(t, 5)
+	ctx := context.Background()
+	expired := f.reserve(t, 2, time.Millisecond)
+	active := f.reserve(t, 1, time.Hour)
+	time.Sleep(5 * time.Millisecond)
+
+	released, err := f.service.ReleaseExpiredReservations(ctx)
+	if err != nil || released != 1 {
+		t.Fatalf("ReleaseExpiredReservations() = %d, %v, want 1", released, err)
+	}
+	if got := f.stock(t); got != 4 {
+		t.Errorf("stock = %d, want 4", got)
+	}
+
+	// A late commit of the expired reservation fails, releasing it is a no-op
+	if err := f.service.CommitReservation(ctx, expired.ID); err == nil {
+		t.Error("CommitReservation() of an expired reservation succeeded")
+	}
+	if err := f.service.ReleaseStock(ctx, expired.ID); err != nil {
+		t.Errorf("ReleaseStock() of an expired reservation error = %v", err)
+	}
+	if err := f.service.CommitReservation(ctx, active.ID); err != nil {
+		t.Errorf("CommitReservation() of an active reservation error = %v", err)
+	}
+	if released, _ := f.service.ReleaseExpiredReservations(ctx); released != 0 {
+		t.Errorf("second ReleaseExpiredReservations() = %d, want 0", released)
+	}
+}
+
+func TestReservationSweeperReleasesExpiredStock(t *testing.T) {
+	f := newStockFixture(t, 5)
+	f.reserve(t, 2, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewReservationSweeper(f.service, 5*time.Millisecond).Start(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for f.stock(t) != 5 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if got := f.stock(t); got != 5 {
+		t.Errorf("stock = %d, want 5 after the sweeper ran", got)
+	}
+}
+
+func TestRestockItems(t *testing.T) {
+	f := newStockFixture(t, 5)
+	ctx := context.Background()
+	items := []model.StockItem{{ProductID: f.product.ID, Quantity: 2}}
+
+	for _, tt := range []struct {
+		reference string
+		items     []model.StockItem
+	}{
+		{reference: "", items: items},
+		{reference: "order:1:cancel"},
+		{reference: "order:1:cancel", items: []model.StockItem{{ProductID: f.product.ID, Quantity: 0}}},
+	} {
+		if _, err := f.service.RestockItems(ctx, tt.reference, tt.items); !errors.Is(err, ErrInvalidStockRequest) {
+			t.Errorf("RestockItems(%q, %v) error = %v, want ErrInvalidStockRequest", tt.reference, tt.items, err)
+		}
+	}
+
+	if applied, err := f.service.RestockItems(ctx, "order:1:cancel", items); err != nil || !applied {
+		t.Fatalf("RestockItems() = %v, %v, want applied", applied, err)
+	}
+	if applied, err := f.service.RestockItems(ctx, "order:1:cancel", items); err != nil || applied {
+		t.Fatalf("repeated RestockItems() = %v, %v, want not applied", applied, err)
+	}
+	if got := f.stock(t); got != 7 {
+		t.Errorf("stock = %d, want 7", got)
+	}
+}
@@ -1,49 +0,0 @@
-package auth
-
-import (
-	"errors"
-	"fmt"
-	"strings"
-
-	"github.com/golang-jwt/jwt/v5"
-)
-
-type JWTClaims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	jwt.RegisteredClaims
-}
-
-type JWTHelper struct {
-	secretKey string
-}
-
-// NewJWTHelper creates a new JWT helper
-func NewJWTHelper(secretKey string) *JWTHelper {
-	return &JWTHelper{secretKey: secretKey}
-}
-
-// ValidateToken validates a JWT token and returns claims
-func (j *JWTHelper) ValidateToken(tokenString string) (*JWTClaims, error) {
-	// Remove "Bearer " prefix if present
-	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
-
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(j.secretKey), nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
-		return claims, nil
-	}
-
-	return nil, errors.New("invalid token")
-}
\ No newline at end of file
@@ -1,17 +1,27 @@
 package database
 
 import (
+	"fmt"
 	"log"
 
+	"github.com/ploezy/ecommerce-platform/pkg/money"
 	"github.com/ploezy/ecommerce-platform/product-service/internal/model"
 	"gorm.io/gorm"
 )
 
 func AutoMigrate(db *gorm.DB) error {
 	log.Println("Starting database migration...")
-	
+
+	if err := migrateProductPrice(db); err != nil {
+		log.Printf("Migration failed: %v", err)
+		return err
+	}
+
 	err := db.AutoMigrate(
 		&model.Product{},
+		&model.StockReservation{},
+		&model.StockReservationItem{},
+		&model.StockRestock{},
 	)
 	if err != nil{
 		log.Printf("Migration failed: %v", err)
@@ -20,4 +30,34 @@ func AutoMigrate(db *gorm.DB) error {
 
 	log.Println("Database migration completed successfully")
 	return nil
-}
\ No newline at end of file
+}
+
+// migrateProductPrice converts the float price column of products created
+// before prices were stored in minor units. Existing prices are in the default
+// currency and are rounded half away from zero.
+func migrateProductPrice(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&model.Product{}, "price") {
+		return nil
+	}
+
+	log.Println("Converting product prices to minor units...")
+	factor := 1
+	for i := 0; i < money.Exponent(money.DefaultCurrency); i++ {
+		factor *= 10
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			"ALTER TABLE products ADD COLUMN IF NOT EXISTS price_amount bigint",
+			"ALTER TABLE products ADD COLUMN IF NOT EXISTS price_currency varchar(3)",
+			fmt.Sprintf("UPDATE products SET price_amount = ROUND(price::numeric * %d), price_currency = '%s'", factor, money.DefaultCurrency),
+			"ALTER TABLE products DROP COLUMN price",
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to convert product prices: %w", err)
+			}
+		}
+		return nil
+	})
+}
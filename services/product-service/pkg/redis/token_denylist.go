@@ -0,0 +1,28 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenDenylist reads the access tokens revoked by user-service, which keeps
+// them in Redis until they expire
+type TokenDenylist struct {
+	client *redis.Client
+}
+
+// NewTokenDenylist creates a denylist reader on the Redis DB of the denylist
+func NewTokenDenylist(client *redis.Client) *TokenDenylist {
+	return &TokenDenylist{client: client}
+}
+
+// IsRevoked implements token.RevocationChecker
+func (d *TokenDenylist) IsRevoked(ctx context.Context, tokenID, sessionID string) (bool, error) {
+	n, err := d.client.Exists(ctx, token.DenylistKeys(tokenID, sessionID)...).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
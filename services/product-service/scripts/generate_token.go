@@ -1,54 +1,104 @@
 package main
 
+// Prints an admin and a customer access token for trying out the protected
+// routes locally. Tokens are signed with a signing key of user-service, so
+// run user-service with the same key in JWT_SIGNING_KEYS:
+//
+//	openssl genpkey -algorithm ed25519 -out signing.pem
+//	go run scripts/generate_token.go -key signing.pem
+
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"flag"
 	"fmt"
+	"log"
+	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
 )
 
-type JWTClaims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	jwt.RegisteredClaims
-}
-
 func main() {
-	secretKey := "your-super-secret-jwt-key-change-this-in-production"
+	keyFile := flag.String("key", "", "PEM file of a signing key published by user-service")
+	ttl := flag.Duration("ttl", 24*time.Hour, "lifetime of the tokens")
+	flag.Parse()
 
-	// Admin token
-	adminClaims := JWTClaims{
-		UserID: 1,
-		Email:  "admin@example.com",
-		Role:   "admin",
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	if *keyFile == "" {
+		log.Fatal("-key is required")
+	}
+	signer, err := loadKey(*keyFile)
+	if err != nil {
+		log.Fatalf("Failed to load signing key: %v", err)
+	}
+	jwk, err := token.NewJWK(signer.Public())
+	if err != nil {
+		log.Fatalf("Unsupported signing key: %v", err)
 	}
 
-	adminToken := jwt.NewWithClaims(jwt.SigningMethodHS256, adminClaims)
-	adminTokenString, _ := adminToken.SignedString([]byte(secretKey))
-
-	fmt.Println("Admin Token (valid for 24 hours):")
-	fmt.Println(adminTokenString)
+	fmt.Printf("Admin Token (valid for %s):\n", *ttl)
+	// The admin token passes ADMIN_REQUIRE_MFA as if the admin logged in with MFA
+	fmt.Println(sign(signer, jwk, 1, "admin@example.com", "admin", []string{authz.ProductWrite}, []string{token.AuthMethodPassword, token.AuthMethodOTP, token.AuthMethodMFA}, *ttl))
 	fmt.Println()
 
-	// User token (not admin)
-	userClaims := JWTClaims{
-		UserID: 2,
-		Email:  "user@example.com",
-		Role:   "user",
+	fmt.Printf("User Token (valid for %s):\n", *ttl)
+	fmt.Println(sign(signer, jwk, 2, "user@example.com", "customer", nil, []string{token.AuthMethodPassword}, *ttl))
+}
+
+func loadKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in %s", path)
+	}
+
+	var key interface{}
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+func sign(signer crypto.Signer, jwk token.JWK, userID uint, email, role string, permissions, authMethods []string, ttl time.Duration) string {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	claims := &token.Claims{
+		UserID:      userID,
+		Email:       email,
+		Role:        role,
+		Permissions: permissions,
+		AuthMethods: authMethods,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ID:        hex.EncodeToString(id),
+			Issuer:    token.Issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	userToken := jwt.NewWithClaims(jwt.SigningMethodHS256, userClaims)
-	userTokenString, _ := userToken.SignedString([]byte(secretKey))
-
-	fmt.Println("User Token (valid for 24 hours):")
-	fmt.Println(userTokenString)
-}
\ No newline at end of file
+	t := jwt.NewWithClaims(jwt.GetSigningMethod(jwk.Alg), claims)
+	t.Header["kid"] = jwk.Kid
+	signed, err := t.SignedString(signer)
+	if err != nil {
+		log.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"log"
 	"net"
+	"net/netip"
 
 	"github.com/gin-gonic/gin"
-	pb "github.com/ploezy/ecommerce-platform/user-service/proto/user"
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
+	pb "github.com/ploezy/ecommerce-platform/proto/user"
 	usergrpc "github.com/ploezy/ecommerce-platform/user-service/internal/grpc"
 	"github.com/ploezy/ecommerce-platform/user-service/config"
 	"github.com/ploezy/ecommerce-platform/user-service/internal/handler"
@@ -13,7 +15,10 @@ import (
 	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
 	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
 	"github.com/ploezy/ecommerce-platform/user-service/internal/service"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
 	"github.com/ploezy/ecommerce-platform/user-service/pkg/database"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/mailer"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/redis"
 	"google.golang.org/grpc"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -53,38 +58,144 @@ func main() {
 	}
 
 	//Auto migrate
-	err = db.AutoMigrate(&model.User{})
+	err = db.AutoMigrate(&model.User{}, &model.Address{}, &model.RefreshToken{}, &model.UserToken{}, &model.SecurityEvent{}, &model.UserMFA{}, &model.MFARecoveryCode{}, &model.Permission{}, &model.Role{})
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	// Create the built-in roles and the permissions the services check
+	roleRepo := repository.NewRoleRepository(db)
+	if err := roleRepo.Seed(model.DefaultPermissions(), model.DefaultRoles()); err != nil {
+		log.Fatal("Failed to seed roles:", err)
+	}
+
+	// Connect to Redis, revoked tokens, failed logins and MFA challenges are kept there
+	redisClient, err := redis.NewRedisClient(cfg.RedisHost, cfg.RedisPort, cfg.RedisPassword, cfg.RedisDB)
+	if err != nil {
+		log.Fatal("Failed to connect to redis:", err)
+	}
+	defer redisClient.Close()
+
+	// Load the keys that sign access tokens
+	keys, err := loadSigningKeys(cfg)
+	if err != nil {
+		log.Fatal("Failed to load signing keys:", err)
+	}
+
+	// TOTP secrets are sealed with MFA_ENCRYPTION_KEY
+	secrets, err := auth.NewSecretBox(cfg.MFAEncryptionKey)
+	if err != nil {
+		log.Fatal("Failed to load MFA encryption key:", err)
+	}
+	if secrets == nil {
+		log.Println("MFA_ENCRYPTION_KEY is not set, TOTP secrets are stored unencrypted")
+	}
+	mfaPolicy := service.MFAPolicy{RequiredRoles: cfg.MFARequiredRoles}
+
 	// Initialize layers
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
-	userHandler := handler.NewUserHandler(userService, cfg.JWTSecret)
+	securityEvents := repository.NewSecurityEventRepository(db)
+	tokenService := service.NewTokenService(
+		userRepo,
+		repository.NewRefreshTokenRepository(db),
+		repository.NewTokenDenylist(redisClient),
+		roleRepo,
+		keys,
+		cfg.AccessTokenTTL,
+		cfg.RefreshTokenTTL,
+		mfaPolicy,
+	)
+	mail, err := mailer.New(mailer.Config{
+		Driver:       cfg.MailDriver,
+		From:         cfg.MailFrom,
+		SMTPHost:     cfg.SMTPHost,
+		SMTPPort:     cfg.SMTPPort,
+		SMTPUsername: cfg.SMTPUsername,
+		SMTPPassword: cfg.SMTPPassword,
+		Dir:          cfg.MailDir,
+	})
+	if err != nil {
+		log.Fatal("Failed to set up mailer:", err)
+	}
+	accountService := service.NewAccountService(userRepo, repository.NewUserTokenRepository(db), tokenService, mail, service.AccountConfig{
+		BaseURL:              cfg.AppBaseURL,
+		PasswordResetTTL:     cfg.PasswordResetTTL,
+		EmailVerificationTTL: cfg.EmailVerificationTTL,
+	})
+	loginGuard := service.NewLoginGuard(userRepo, repository.NewLoginAttemptStore(redisClient), securityEvents, service.LoginPolicy{
+		Window:          cfg.LoginFailureWindow,
+		DelayAfter:      int64(cfg.LoginDelayAfter),
+		DelayBase:       cfg.LoginDelayBase,
+		DelayMax:        cfg.LoginDelayMax,
+		LockoutAfter:    int64(cfg.LoginLockoutAfter),
+		LockoutDuration: cfg.LoginLockoutDuration,
+		IPMaxFailures:   int64(cfg.LoginIPMaxFailures),
+	})
+	mfaService := service.NewMFAService(userRepo, repository.NewMFARepository(db), repository.NewMFAChallengeStore(redisClient), securityEvents, secrets, service.MFAConfig{
+		Issuer:               cfg.MFAIssuer,
+		ChallengeTTL:         cfg.MFAChallengeTTL,
+		MaxChallengeFailures: int64(cfg.MFAMaxChallengeFailures),
+		Policy:               mfaPolicy,
+	})
+	userService := service.NewUserService(userRepo, tokenService, accountService, loginGuard, mfaService, cfg.RequireEmailVerification)
+	userHandler := handler.NewUserHandler(userService, tokenService)
+	accountHandler := handler.NewAccountHandler(accountService)
+	mfaHandler := handler.NewMFAHandler(mfaService)
+	addressRepo := repository.NewAddressRepository(db)
+	addressService := service.NewAddressService(addressRepo)
+	addressHandler := handler.NewAddressHandler(addressService)
+	jwksHandler := handler.NewJWKSHandler(keys)
+	roleService := service.NewRoleService(roleRepo, userRepo, tokenService, securityEvents)
+	adminService := service.NewAdminService(userRepo, tokenService, roleService, loginGuard, securityEvents)
+	adminHandler := handler.NewAdminHandler(adminService)
+	roleHandler := handler.NewRoleHandler(roleService)
 	
+	trustedProxies, err := usergrpc.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal("Failed to parse trusted proxies:", err)
+	}
+
 	// Start gRPC Server in goroutine
-	go startGRPCServer(userService, cfg.JWTSecret, cfg.GRPCPort)
+	go startGRPCServer(userService, addressService, adminService, tokenService, trustedProxies, cfg.GRPCPort)
 
 	// Start REST API Server
-	startRESTServer(userHandler, cfg)
+	startRESTServer(userHandler, accountHandler, mfaHandler, addressHandler, adminHandler, roleHandler, jwksHandler, tokenService, cfg)
+}
+
+// loadSigningKeys loads the keys configured in JWT_SIGNING_KEYS, or generates
+// a key that lasts until the process exits when none are configured
+func loadSigningKeys(cfg *config.Config) (*auth.KeyRing, error) {
+	if len(cfg.JWTSigningKeys) == 0 {
+		log.Println("JWT_SIGNING_KEYS is not set")
+		return auth.GenerateKeyRing()
+	}
+	keys, err := auth.LoadKeyRing(cfg.JWTSigningKeys)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Signing access tokens with key %s (%s)", keys.Active().ID, keys.Active().Method.Alg())
+	return keys, nil
 }
-func startGRPCServer(userService service.UserService, jwtSecret string, grpcPort string) {
+func startGRPCServer(userService service.UserService, addressService service.AddressService, adminService service.AdminService, tokenService service.TokenService, trustedProxies []netip.Prefix, grpcPort string) {
 	lis, err := net.Listen("tcp", ":"+grpcPort)
 	if err != nil {
 		log.Fatalf("Failed to listen gRPC: %v", err)
 	}
 
 	grpcServer := grpc.NewServer()
-	pb.RegisterUserServiceServer(grpcServer, usergrpc.NewUserGRPCServer(userService, jwtSecret))
+	pb.RegisterUserServiceServer(grpcServer, usergrpc.NewUserGRPCServer(userService, addressService, adminService, tokenService, trustedProxies))
 
 	log.Printf("gRPC Server running on port %s", grpcPort) 
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve gRPC: %v", err)
 	}
 }
-func startRESTServer(userHandler *handler.UserHandler, cfg *config.Config) {
+func startRESTServer(userHandler *handler.UserHandler, accountHandler *handler.AccountHandler, mfaHandler *handler.MFAHandler, addressHandler *handler.AddressHandler, adminHandler *handler.AdminHandler, roleHandler *handler.RoleHandler, jwksHandler *handler.JWKSHandler, tokenService service.TokenService, cfg *config.Config) {
 	r := gin.Default()
+	// Only the proxies in front of the port may set the client IP
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Failed to set trusted proxies:", err)
+	}
 	// Swagger route
 	
 	// Swagger documentation with custom config
@@ -94,18 +205,66 @@ func startRESTServer(userHandler *handler.UserHandler, cfg *config.Config) {
 		url,
 		ginSwagger.DefaultModelsExpandDepth(-1), // ซ่อน Models section
 	))
+	// Public keys of the access tokens, for services that verify them locally
+	r.GET("/.well-known/jwks.json", jwksHandler.JWKS)
+
 	// Public Routes
 	api := r.Group("/api/v1")
 	{
 		api.POST("/register", userHandler.Register)
 		api.POST("/login", userHandler.Login)
+		api.POST("/login/mfa", userHandler.LoginMFA)
+		api.POST("/token/refresh", userHandler.RefreshToken)
+		api.POST("/password/forgot", accountHandler.ForgotPassword)
+		api.POST("/password/reset", accountHandler.ResetPassword)
+		api.POST("/email/verify", accountHandler.VerifyEmail)
 	}
 
 	// Protected Routes
 	protected := r.Group("/api/v1")
-	protected.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+	protected.Use(middleware.AuthMiddleware(tokenService))
 	{
 		protected.GET("/profile", userHandler.GetProfile)
+		protected.POST("/logout", userHandler.Logout)
+		protected.POST("/email/verify/resend", accountHandler.ResendVerification)
+
+		protected.GET("/mfa", mfaHandler.GetMFA)
+		protected.POST("/mfa/enroll", mfaHandler.EnrollMFA)
+		protected.POST("/mfa/activate", mfaHandler.ActivateMFA)
+		protected.POST("/mfa/disable", mfaHandler.DisableMFA)
+		protected.POST("/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+
+		protected.GET("/addresses", addressHandler.ListAddresses)
+		protected.POST("/addresses", addressHandler.CreateAddress)
+		protected.GET("/addresses/:id", addressHandler.GetAddress)
+		protected.PUT("/addresses/:id", addressHandler.UpdateAddress)
+		protected.DELETE("/addresses/:id", addressHandler.DeleteAddress)
+		protected.POST("/addresses/:id/default", addressHandler.SetDefaultAddress)
+	}
+
+	// Admin Routes (each route requires a permission)
+	admin := r.Group("/api/v1/admin")
+	admin.Use(middleware.AuthMiddleware(tokenService))
+	{
+		canReadUsers := authz.RequirePermission(authz.UserRead)
+		canWriteUsers := authz.RequirePermission(authz.UserWrite)
+		admin.GET("/users", canReadUsers, adminHandler.ListUsers)
+		admin.GET("/users/:id", canReadUsers, adminHandler.GetUser)
+		admin.GET("/users/:id/audit-log", canReadUsers, adminHandler.GetUserAuditLog)
+		admin.DELETE("/users/:id", canWriteUsers, adminHandler.DeleteUser)
+		admin.POST("/users/:id/restore", canWriteUsers, adminHandler.RestoreUser)
+		admin.POST("/users/:id/suspend", canWriteUsers, adminHandler.SuspendUser)
+		admin.POST("/users/:id/reactivate", canWriteUsers, adminHandler.ReactivateUser)
+		admin.POST("/users/:id/logout", canWriteUsers, adminHandler.LogoutUser)
+		admin.POST("/users/:id/unlock", canWriteUsers, adminHandler.UnlockUser)
+
+		canWriteRoles := authz.RequirePermission(authz.RoleWrite)
+		admin.GET("/roles", canWriteRoles, roleHandler.ListRoles)
+		admin.POST("/roles", canWriteRoles, roleHandler.CreateRole)
+		admin.PUT("/roles/:name", canWriteRoles, roleHandler.UpdateRole)
+		admin.DELETE("/roles/:name", canWriteRoles, roleHandler.DeleteRole)
+		admin.GET("/permissions", canWriteRoles, roleHandler.ListPermissions)
+		admin.PUT("/users/:id/role", canWriteRoles, roleHandler.AssignRole)
 	}
 
 	log.Printf("REST API Server running on port %s", cfg.ServerPort)
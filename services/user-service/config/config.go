@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,7 +18,59 @@ type Config struct {
 	DBName     string
 	ServerPort string
 	GRPCPort   string
-	JWTSecret  string
+
+	// TrustedProxies are the IPs or CIDR ranges of the proxies in front of
+	// the REST and gRPC ports. Only their X-Forwarded-For is used to find
+	// the client IP; with none the address of the connection is used.
+	TrustedProxies []string
+
+	// Tokens
+	// JWTSigningKeys are PEM files of the RSA or Ed25519 keys that sign
+	// access tokens. The first key signs; the others are only published.
+	JWTSigningKeys  []string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// Login throttling. Failures are counted for LoginFailureWindow; after
+	// LoginDelayAfter failures an account waits LoginDelayBase, doubling up
+	// to LoginDelayMax, and after LoginLockoutAfter failures it is locked.
+	LoginFailureWindow   time.Duration
+	LoginDelayAfter      int
+	LoginDelayBase       time.Duration
+	LoginDelayMax        time.Duration
+	LoginLockoutAfter    int
+	LoginLockoutDuration time.Duration
+	LoginIPMaxFailures   int
+
+	// Multi-factor authentication
+	// MFARequiredRoles are the roles whose sessions get no permissions
+	// without MFA. MFAEncryptionKey seals TOTP secrets at rest.
+	MFAIssuer               string
+	MFAChallengeTTL         time.Duration
+	MFAMaxChallengeFailures int
+	MFARequiredRoles        []string
+	MFAEncryptionKey        string
+
+	// Account recovery and email verification
+	AppBaseURL               string
+	PasswordResetTTL         time.Duration
+	EmailVerificationTTL     time.Duration
+	RequireEmailVerification bool
+
+	// Mail
+	MailDriver   string
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// Redis
+	RedisHost     string
+	RedisPort     string
+	RedisPassword string
+	RedisDB       int
 }
 
 func LoadConfig() *Config {
@@ -31,7 +86,44 @@ func LoadConfig() *Config {
 		DBName:     getEnv("DB_NAME", "ecom_db"),
 		ServerPort: getEnv("SERVER_PORT", "8081"),
 		GRPCPort:   getEnv("GRPC_PORT", "50051"),
-		JWTSecret:  getEnv("JWT_SECRET", "secret"),
+
+		TrustedProxies: getListEnv("TRUSTED_PROXIES"),
+
+		JWTSigningKeys:  getListEnv("JWT_SIGNING_KEYS"),
+		AccessTokenTTL:  getDurationEnv("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getDurationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		LoginFailureWindow:   getDurationEnv("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		LoginDelayAfter:      getIntEnv("LOGIN_DELAY_AFTER", 3),
+		LoginDelayBase:       getDurationEnv("LOGIN_DELAY_BASE", time.Second),
+		LoginDelayMax:        getDurationEnv("LOGIN_DELAY_MAX", 30*time.Second),
+		LoginLockoutAfter:    getIntEnv("LOGIN_LOCKOUT_AFTER", 10),
+		LoginLockoutDuration: getDurationEnv("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		LoginIPMaxFailures:   getIntEnv("LOGIN_IP_MAX_FAILURES", 100),
+
+		MFAIssuer:               getEnv("MFA_ISSUER", "E-Commerce"),
+		MFAChallengeTTL:         getDurationEnv("MFA_CHALLENGE_TTL", 5*time.Minute),
+		MFAMaxChallengeFailures: getIntEnv("MFA_MAX_CHALLENGE_FAILURES", 5),
+		MFARequiredRoles:        getListEnv("MFA_REQUIRED_ROLES"),
+		MFAEncryptionKey:        getEnv("MFA_ENCRYPTION_KEY", ""),
+
+		AppBaseURL:               getEnv("APP_BASE_URL", "http://localhost:3000"),
+		PasswordResetTTL:         getDurationEnv("PASSWORD_RESET_TTL", time.Hour),
+		EmailVerificationTTL:     getDurationEnv("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+		RequireEmailVerification: getBoolEnv("REQUIRE_EMAIL_VERIFICATION", false),
+
+		MailDriver:   getEnv("MAIL_DRIVER", "file"),
+		MailFrom:     getEnv("MAIL_FROM", "no-reply@example.com"),
+		MailDir:      getEnv("MAIL_DIR", "mail"),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		RedisHost:     getEnv("REDIS_HOST", "localhost"),
+		RedisPort:     getEnv("REDIS_PORT", "6379"),
+		RedisPassword: getEnv("REDIS_PASSWORD", ""),
+		RedisDB:       getIntEnv("REDIS_DB", 0),
 	}
 }
 
@@ -40,4 +132,51 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			log.Printf("Invalid duration for %s: %v, using default %s", key, err, defaultValue)
+			return defaultValue
+		}
+		return d
+	}
+	return defaultValue
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			log.Printf("Invalid integer for %s: %v, using default %d", key, err, defaultValue)
+			return defaultValue
+		}
+		return n
+	}
+	return defaultValue
+}
+
+// getListEnv returns the comma separated values of key
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Printf("Invalid boolean for %s: %v, using default %t", key, err, defaultValue)
+			return defaultValue
+		}
+		return b
+	}
+	return defaultValue
+}
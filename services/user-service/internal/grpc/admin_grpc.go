@@ -0,0 +1,171 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
+	pb "github.com/ploezy/ecommerce-platform/proto/user"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/service"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (s *UserGRPCServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	if _, err := s.authorize(ctx, authz.UserRead); err != nil {
+		return nil, err
+	}
+
+	page, err := s.adminService.ListUsers(service.UserQuery{
+		Search:   req.Search,
+		Role:     req.Role,
+		Status:   req.Status,
+		Page:     int(req.Page),
+		PageSize: int(req.PageSize),
+	})
+	if err != nil {
+		return nil, adminError(err)
+	}
+
+	resp := &pb.ListUsersResponse{
+		Users:    make([]*pb.UserResponse, 0, len(page.Users)),
+		Total:    page.Total,
+		Page:     int32(page.Page),
+		PageSize: int32(page.PageSize),
+	}
+	for i := range page.Users {
+		resp.Users = append(resp.Users, toProtoUser(&page.Users[i]))
+	}
+	return resp, nil
+}
+
+func (s *UserGRPCServer) ChangeUserRole(ctx context.Context, req *pb.ChangeUserRoleRequest) (*pb.UserResponse, error) {
+	claims, err := s.authorize(ctx, authz.RoleWrite)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.adminService.ChangeRole(uint(req.UserId), req.Role, claims.UserID)
+	if err != nil {
+		return nil, adminError(err)
+	}
+	return toProtoUser(user), nil
+}
+
+func (s *UserGRPCServer) SuspendUser(ctx context.Context, req *pb.SuspendUserRequest) (*pb.UserResponse, error) {
+	claims, err := s.authorize(ctx, authz.UserWrite)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.adminService.Suspend(uint(req.UserId), req.Reason, claims.UserID)
+	if err != nil {
+		return nil, adminError(err)
+	}
+	return toProtoUser(user), nil
+}
+
+func (s *UserGRPCServer) ReactivateUser(ctx context.Context, req *pb.AdminUserRequest) (*pb.UserResponse, error) {
+	claims, err := s.authorize(ctx, authz.UserWrite)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.adminService.Reactivate(uint(req.UserId), claims.UserID)
+	if err != nil {
+		return nil, adminError(err)
+	}
+	return toProtoUser(user), nil
+}
+
+func (s *UserGRPCServer) DeleteUser(ctx context.Context, req *pb.AdminUserRequest) (*pb.AdminUserResponse, error) {
+	claims, err := s.authorize(ctx, authz.UserWrite)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.adminService.Delete(uint(req.UserId), claims.UserID); err != nil {
+		return nil, adminError(err)
+	}
+	return &pb.AdminUserResponse{Success: true}, nil
+}
+
+func (s *UserGRPCServer) RestoreUser(ctx context.Context, req *pb.AdminUserRequest) (*pb.UserResponse, error) {
+	claims, err := s.authorize(ctx, authz.UserWrite)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.adminService.Restore(uint(req.UserId), claims.UserID)
+	if err != nil {
+		return nil, adminError(err)
+	}
+	return toProtoUser(user), nil
+}
+
+func (s *UserGRPCServer) LogoutUser(ctx context.Context, req *pb.AdminUserRequest) (*pb.AdminUserResponse, error) {
+	claims, err := s.authorize(ctx, authz.UserWrite)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.adminService.ForceLogout(uint(req.UserId), claims.UserID); err != nil {
+		return nil, adminError(err)
+	}
+	return &pb.AdminUserResponse{Success: true}, nil
+}
+
+// authenticate checks the access token in the authorization metadata and
+// returns its claims
+func (s *UserGRPCServer) authenticate(ctx context.Context) (*auth.Claims, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata required")
+	}
+
+	claims, err := s.tokenService.Validate(strings.TrimPrefix(values[0], "Bearer "))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+	return claims, nil
+}
+
+// authorize checks the access token in the authorization metadata and that
+// it was granted permission. It returns the claims of the admin.
+func (s *UserGRPCServer) authorize(ctx context.Context, permission string) (*auth.Claims, error) {
+	claims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !claims.HasPermission(permission) {
+		return nil, status.Error(codes.PermissionDenied, "forbidden: missing permission "+permission)
+	}
+	return claims, nil
+}
+
+// adminError maps admin service errors to gRPC status codes
+func adminError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return status.Error(codes.NotFound, err.Error())
+	case strings.Contains(err.Error(), "invalid"):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case strings.Contains(err.Error(), "cannot"):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "admin request failed: %v", err)
+	}
+}
+
+func toProtoUser(user *model.User) *pb.UserResponse {
+	return &pb.UserResponse{
+		Id:              uint32(user.ID),
+		Email:           user.Email,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Role:            user.Role,
+		Status:          user.Status(),
+		EmailVerified:   user.EmailVerified,
+		SuspendedReason: user.SuspendedReason,
+		CreatedAt:       user.CreatedAt.Format(time.RFC3339),
+	}
+}
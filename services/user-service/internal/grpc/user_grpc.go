@@ -2,27 +2,60 @@ package grpc
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
 
-	pb "github.com/ploezy/ecommerce-platform/user-service/proto/user"
+	pb "github.com/ploezy/ecommerce-platform/proto/user"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
 	"github.com/ploezy/ecommerce-platform/user-service/internal/service"
-	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+	grpclib "google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 type UserGRPCServer struct {
 	pb.UnimplementedUserServiceServer
-	service   service.UserService
-	jwtSecret string
+	service        service.UserService
+	addressService service.AddressService
+	adminService   service.AdminService
+	tokenService   service.TokenService
+	trustedProxies []netip.Prefix
 }
 
-func NewUserGRPCServer(service service.UserService, jwtSecret string) *UserGRPCServer {
+// NewUserGRPCServer creates the server. x-forwarded-for is only read from calls
+// that come from one of trustedProxies.
+func NewUserGRPCServer(service service.UserService, addressService service.AddressService, adminService service.AdminService, tokenService service.TokenService, trustedProxies []netip.Prefix) *UserGRPCServer {
 	return &UserGRPCServer{
-		service:   service,
-		jwtSecret: jwtSecret,
+		service:        service,
+		addressService: addressService,
+		adminService:   adminService,
+		tokenService:   tokenService,
+		trustedProxies: trustedProxies,
 	}
 }
 
+// ParseTrustedProxies parses proxy addresses given as IPs or CIDR ranges
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
 func (s *UserGRPCServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
 	user, err := s.service.Register(req.Email, req.Password, req.FirstName, req.LastName)
 	if err != nil {
@@ -40,18 +73,110 @@ func (s *UserGRPCServer) Register(ctx context.Context, req *pb.RegisterRequest)
 }
 
 func (s *UserGRPCServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
-	token, user, err := s.service.Login(req.Email, req.Password, s.jwtSecret)
+	result, err := s.service.Login(req.Email, req.Password, s.clientIP(ctx))
+	if err != nil {
+		return nil, loginError(ctx, err)
+	}
+	if result.MFAChallenge != nil {
+		return &pb.LoginResponse{
+			UserId:      uint32(result.User.ID),
+			Email:       result.User.Email,
+			Role:        result.User.Role,
+			Message:     "MFA code required",
+			ExpiresIn:   result.MFAChallenge.ExpiresIn,
+			MfaRequired: true,
+			MfaToken:    result.MFAChallenge.Token,
+		}, nil
+	}
+	return toLoginResponse(result), nil
+}
+
+func (s *UserGRPCServer) LoginMFA(ctx context.Context, req *pb.LoginMFARequest) (*pb.LoginResponse, error) {
+	result, err := s.service.LoginMFA(req.MfaToken, req.Code, s.clientIP(ctx))
 	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid credentials: %v", err)
+		return nil, loginError(ctx, err)
 	}
+	return toLoginResponse(result), nil
+}
 
+func toLoginResponse(result *service.LoginResult) *pb.LoginResponse {
 	return &pb.LoginResponse{
-		Token:   token,
-		UserId:  uint32(user.ID),
-		Email:   user.Email,
-		Role:    user.Role,
-		Message: "Login successful",
-	}, nil
+		Token:                 result.Tokens.AccessToken,
+		UserId:                uint32(result.User.ID),
+		Email:                 result.User.Email,
+		Role:                  result.User.Role,
+		Message:               "Login successful",
+		RefreshToken:          result.Tokens.RefreshToken,
+		ExpiresIn:             result.Tokens.ExpiresIn,
+		MfaEnrollmentRequired: result.MFAEnrollmentRequired,
+	}
+}
+
+// loginError maps a failed login to a gRPC status. Blocked logins tell in the
+// retry-after trailer how many seconds to wait.
+func loginError(ctx context.Context, err error) error {
+	if blocked, ok := service.IsLoginBlocked(err); ok {
+		_ = grpclib.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.FormatInt(blocked.RetryAfterSeconds(), 10)))
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	switch {
+	case strings.Contains(err.Error(), "not verified"), strings.Contains(err.Error(), "suspended"):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case strings.Contains(err.Error(), "invalid"):
+		return status.Errorf(codes.Unauthenticated, "invalid credentials: %v", err)
+	}
+	return status.Errorf(codes.Internal, "failed to login: %v", err)
+}
+
+// clientIP is the address of the client that called. Calls from a trusted
+// proxy take it from x-forwarded-for: the last address that is not a trusted
+// proxy, as earlier ones can be set by the client. Calls from anywhere else
+// cannot choose the IP their failed logins are counted against.
+func (s *UserGRPCServer) clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	ip := p.Addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !s.trustedProxy(ip) {
+		return ip
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var hops []string
+	for _, forwarded := range md.Get("x-forwarded-for") {
+		for _, hop := range strings.Split(forwarded, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	client := ip
+	for i := len(hops) - 1; i >= 0; i-- {
+		if _, err := netip.ParseAddr(hops[i]); err != nil {
+			return ip
+		}
+		client = hops[i]
+		if !s.trustedProxy(client) {
+			break
+		}
+	}
+	return client
+}
+
+func (s *UserGRPCServer) trustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, proxy := range s.trustedProxies {
+		if proxy.Contains(addr) {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *UserGRPCServer) GetUserByID(ctx context.Context, req *pb.GetUserByIDRequest) (*pb.UserResponse, error) {
@@ -60,13 +185,7 @@ func (s *UserGRPCServer) GetUserByID(ctx context.Context, req *pb.GetUserByIDReq
 		return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
 	}
 
-	return &pb.UserResponse{
-		Id:        uint32(user.ID),
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Role:      user.Role,
-	}, nil
+	return toProtoUser(user), nil
 }
 
 func (s *UserGRPCServer) GetUserByEmail(ctx context.Context, req *pb.GetUserByEmailRequest) (*pb.UserResponse, error) {
@@ -75,25 +194,173 @@ func (s *UserGRPCServer) GetUserByEmail(ctx context.Context, req *pb.GetUserByEm
 		return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
 	}
 
-	return &pb.UserResponse{
-		Id:        uint32(user.ID),
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Role:      user.Role,
-	}, nil
+	return toProtoUser(user), nil
 }
 
+// ValidateToken reports whether an access token is valid and not revoked. A
+// token is reported invalid when the denylist cannot be checked.
 func (s *UserGRPCServer) ValidateToken(ctx context.Context, req *pb.ValidateTokenRequest) (*pb.ValidateTokenResponse, error) {
-	claims, err := auth.ValidateToken(req.Token, s.jwtSecret)
+	claims, err := s.tokenService.Validate(req.Token)
 	if err != nil {
 		return &pb.ValidateTokenResponse{Valid: false}, nil
 	}
 
 	return &pb.ValidateTokenResponse{
-		Valid:  true,
-		UserId: uint32(claims.UserID),
-		Email:  claims.Email,
-		Role:   claims.Role,
+		Valid:       true,
+		UserId:      uint32(claims.UserID),
+		Email:       claims.Email,
+		Role:        claims.Role,
+		Permissions: claims.Permissions,
+		Amr:         claims.AuthMethods,
 	}, nil
-}
\ No newline at end of file
+}
+
+func (s *UserGRPCServer) ListAddresses(ctx context.Context, req *pb.ListAddressesRequest) (*pb.ListAddressesResponse, error) {
+	userID, err := s.addressOwner(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := s.addressService.List(userID)
+	if err != nil {
+		return nil, addressError(err)
+	}
+
+	resp := &pb.ListAddressesResponse{Addresses: make([]*pb.Address, 0, len(addresses))}
+	for i := range addresses {
+		resp.Addresses = append(resp.Addresses, toProtoAddress(&addresses[i]))
+	}
+	return resp, nil
+}
+
+// GetAddress returns an address of the user, or the default address when address_id is 0
+func (s *UserGRPCServer) GetAddress(ctx context.Context, req *pb.GetAddressRequest) (*pb.Address, error) {
+	userID, err := s.addressOwner(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	address, err := s.addressService.Get(userID, uint(req.AddressId))
+	if err != nil {
+		return nil, addressError(err)
+	}
+	return toProtoAddress(address), nil
+}
+
+func (s *UserGRPCServer) CreateAddress(ctx context.Context, req *pb.CreateAddressRequest) (*pb.Address, error) {
+	userID, err := s.addressOwner(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	address, err := s.addressService.Create(userID, fromProtoAddress(req.Address))
+	if err != nil {
+		return nil, addressError(err)
+	}
+	return toProtoAddress(address), nil
+}
+
+func (s *UserGRPCServer) UpdateAddress(ctx context.Context, req *pb.UpdateAddressRequest) (*pb.Address, error) {
+	userID, err := s.addressOwner(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	address, err := s.addressService.Update(userID, uint(req.AddressId), fromProtoAddress(req.Address))
+	if err != nil {
+		return nil, addressError(err)
+	}
+	return toProtoAddress(address), nil
+}
+
+func (s *UserGRPCServer) DeleteAddress(ctx context.Context, req *pb.DeleteAddressRequest) (*pb.DeleteAddressResponse, error) {
+	userID, err := s.addressOwner(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addressService.Delete(userID, uint(req.AddressId)); err != nil {
+		return nil, addressError(err)
+	}
+	return &pb.DeleteAddressResponse{Success: true}, nil
+}
+
+func (s *UserGRPCServer) SetDefaultAddress(ctx context.Context, req *pb.SetDefaultAddressRequest) (*pb.Address, error) {
+	userID, err := s.addressOwner(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	address, err := s.addressService.SetDefault(userID, uint(req.AddressId))
+	if err != nil {
+		return nil, addressError(err)
+	}
+	return toProtoAddress(address), nil
+}
+
+// ValidateAddress checks and normalizes an address without saving it, for
+// addresses entered at checkout
+func (s *UserGRPCServer) ValidateAddress(ctx context.Context, req *pb.ValidateAddressRequest) (*pb.Address, error) {
+	address := fromProtoAddress(req.Address)
+	if err := s.addressService.Normalize(address); err != nil {
+		return nil, addressError(err)
+	}
+	return toProtoAddress(address), nil
+}
+
+// addressOwner authenticates the caller of an address request and returns the
+// user whose addresses it reads or changes, the user of the access token. A
+// request for another user is rejected, user_id 0 means the caller.
+func (s *UserGRPCServer) addressOwner(ctx context.Context, requested uint32) (uint, error) {
+	claims, err := s.authenticate(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if requested != 0 && uint(requested) != claims.UserID {
+		return 0, status.Error(codes.PermissionDenied, "forbidden: cannot access the addresses of another user")
+	}
+	return claims.UserID, nil
+}
+
+// addressError maps address service errors to gRPC status codes
+func addressError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return status.Error(codes.NotFound, err.Error())
+	case strings.Contains(err.Error(), "invalid"):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "address request failed: %v", err)
+	}
+}
+
+func toProtoAddress(address *model.Address) *pb.Address {
+	return &pb.Address{
+		Id:            uint32(address.ID),
+		UserId:        uint32(address.UserID),
+		Label:         address.Label,
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		Line1:         address.Line1,
+		Line2:         address.Line2,
+		District:      address.District,
+		City:          address.City,
+		Region:        address.Region,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+		IsDefault:     address.IsDefault,
+	}
+}
+
+func fromProtoAddress(address *pb.Address) *model.Address {
+	if address == nil {
+		return &model.Address{}
+	}
+	return &model.Address{
+		Label:         address.Label,
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		Line1:         address.Line1,
+		Line2:         address.Line2,
+		District:      address.District,
+		City:          address.City,
+		Region:        address.Region,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+		IsDefault:     address.IsDefault,
+	}
+}
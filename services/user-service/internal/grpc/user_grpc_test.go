@@ -0,0 +1,172 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	pb "github.com/ploezy/ecommerce-platform/proto/user"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/service"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// fakeTokens accepts the tokens in users, mapped to their user ID
+type fakeTokens struct {
+	service.TokenService
+	users map[string]uint
+}
+
+func (t *fakeTokens) Validate(accessToken string) (*auth.Claims, error) {
+	userID, ok := t.users[accessToken]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return &auth.Claims{UserID: userID}, nil
+}
+
+// fakeAddresses records the user of every address change
+type fakeAddresses struct {
+	service.AddressService
+	users []uint
+}
+
+func (a *fakeAddresses) Create(userID uint, input *model.Address) (*model.Address, error) {
+	a.users = append(a.users, userID)
+	input.UserID = userID
+	return input, nil
+}
+
+func (a *fakeAddresses) Delete(userID, id uint) error {
+	a.users = append(a.users, userID)
+	return nil
+}
+
+func (a *fakeAddresses) List(userID uint) ([]model.Address, error) {
+	a.users = append(a.users, userID)
+	return []model.Address{{UserID: userID}}, nil
+}
+
+func (a *fakeAddresses) Get(userID, id uint) (*model.Address, error) {
+	a.users = append(a.users, userID)
+	return &model.Address{ID: id, UserID: userID}, nil
+}
+
+// fakeUsers records the IPs that logins are counted against and fails them
+type fakeUsers struct {
+	service.UserService
+	ips []string
+}
+
+func (u *fakeUsers) Login(email, password, ip string) (*service.LoginResult, error) {
+	u.ips = append(u.ips, ip)
+	return nil, errors.New("invalid email or password")
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestAddressChangesTakeTheUserOfTheToken(t *testing.T) {
+	addresses := &fakeAddresses{}
+	s := NewUserGRPCServer(nil, addresses, nil, &fakeTokens{users: map[string]uint{"alice": 1}}, nil)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  *pb.CreateAddressRequest
+		code codes.Code
+	}{
+		{"no token", context.Background(), &pb.CreateAddressRequest{UserId: 1}, codes.Unauthenticated},
+		{"invalid token", withToken("mallory"), &pb.CreateAddressRequest{UserId: 1}, codes.Unauthenticated},
+		{"another user", withToken("alice"), &pb.CreateAddressRequest{UserId: 2}, codes.PermissionDenied},
+		{"own user", withToken("alice"), &pb.CreateAddressRequest{UserId: 1}, codes.OK},
+		{"user from token", withToken("alice"), &pb.CreateAddressRequest{}, codes.OK},
+	}
+	for _, tt := range tests {
+		_, err := s.CreateAddress(tt.ctx, tt.req)
+		if code := status.Code(err); code != tt.code {
+			t.Errorf("%s: CreateAddress() code = %v, want %v (%v)", tt.name, code, tt.code, err)
+		}
+	}
+	if len(addresses.users) != 2 || addresses.users[0] != 1 || addresses.users[1] != 1 {
+		t.Errorf("created addresses for users %v, want [1 1]", addresses.users)
+	}
+
+	if _, err := s.DeleteAddress(withToken("alice"), &pb.DeleteAddressRequest{UserId: 2, AddressId: 5}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("DeleteAddress() for another user error = %v, want PermissionDenied", err)
+	}
+}
+
+func TestAddressReadsTakeTheUserOfTheToken(t *testing.T) {
+	addresses := &fakeAddresses{}
+	s := NewUserGRPCServer(nil, addresses, nil, &fakeTokens{users: map[string]uint{"alice": 1}}, nil)
+
+	if _, err := s.GetAddress(context.Background(), &pb.GetAddressRequest{UserId: 1, AddressId: 5}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("GetAddress() without a token error = %v, want Unauthenticated", err)
+	}
+	if _, err := s.GetAddress(withToken("alice"), &pb.GetAddressRequest{UserId: 2, AddressId: 5}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("GetAddress() for another user error = %v, want PermissionDenied", err)
+	}
+	if _, err := s.ListAddresses(withToken("alice"), &pb.ListAddressesRequest{UserId: 2}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListAddresses() for another user error = %v, want PermissionDenied", err)
+	}
+
+	if address, err := s.GetAddress(withToken("alice"), &pb.GetAddressRequest{UserId: 1, AddressId: 5}); err != nil || address.UserId != 1 {
+		t.Errorf("GetAddress() of own address = %v, %v", address, err)
+	}
+	if resp, err := s.ListAddresses(withToken("alice"), &pb.ListAddressesRequest{}); err != nil || len(resp.Addresses) != 1 {
+		t.Errorf("ListAddresses() from the token = %v, %v", resp, err)
+	}
+	if len(addresses.users) != 2 || addresses.users[0] != 1 || addresses.users[1] != 1 {
+		t.Errorf("read addresses of users %v, want [1 1]", addresses.users)
+	}
+}
+
+func TestLoginCountsTheIPOfTheConnection(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		peer      string
+		forwarded []string
+		want      string
+	}{
+		{"direct", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"spoofed header", "203.0.113.7:5000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"through a proxy", "10.1.2.3:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed header through a proxy", "10.1.2.3:5000", []string{"192.0.2.9, 198.51.100.1"}, "198.51.100.1"},
+		{"through two proxies", "10.1.2.3:5000", []string{"198.51.100.1", "192.168.1.1"}, "198.51.100.1"},
+		{"garbage through a proxy", "10.1.2.3:5000", []string{"not an ip"}, "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		users := &fakeUsers{}
+		s := NewUserGRPCServer(users, nil, nil, nil, proxies)
+
+		addr, err := net.ResolveTCPAddr("tcp", tt.peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+		if tt.forwarded != nil {
+			ctx = metadata.NewIncomingContext(ctx, metadata.MD{"x-forwarded-for": tt.forwarded})
+		}
+
+		s.Login(ctx, &pb.LoginRequest{Email: "alice@example.com", Password: "wrong"})
+		if len(users.ips) != 1 || users.ips[0] != tt.want {
+			t.Errorf("%s: failed login counted against %v, want %s", tt.name, users.ips, tt.want)
+		}
+	}
+
+	if _, err := ParseTrustedProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("ParseTrustedProxies() of an invalid range error = nil")
+	}
+}
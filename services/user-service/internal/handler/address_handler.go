@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/service"
+)
+
+type AddressHandler struct {
+	service service.AddressService
+}
+
+func NewAddressHandler(service service.AddressService) *AddressHandler {
+	return &AddressHandler{service: service}
+}
+
+type AddressRequest struct {
+	Label         string `json:"label" binding:"max=50" example:"Home"`
+	RecipientName string `json:"recipient_name" binding:"required,max=100" example:"Somchai Jaidee"`
+	Phone         string `json:"phone" binding:"required,max=20" example:"+66812345678"`
+	Line1         string `json:"line1" binding:"required,max=255" example:"99/1 Sukhumvit Road"`
+	Line2         string `json:"line2" binding:"max=255" example:"Unit 12B"`
+	District      string `json:"district" binding:"max=100" example:"Khlong Toei"`
+	City          string `json:"city" binding:"required,max=100" example:"Bangkok"`
+	Region        string `json:"region" binding:"max=6" example:"TH-10"`
+	PostalCode    string `json:"postal_code" binding:"required,max=10" example:"10110"`
+	Country       string `json:"country" binding:"required,len=2" example:"TH"`
+	IsDefault     bool   `json:"is_default"`
+}
+
+func (r *AddressRequest) toModel() *model.Address {
+	return &model.Address{
+		Label:         r.Label,
+		RecipientName: r.RecipientName,
+		Phone:         r.Phone,
+		Line1:         r.Line1,
+		Line2:         r.Line2,
+		District:      r.District,
+		City:          r.City,
+		Region:        r.Region,
+		PostalCode:    r.PostalCode,
+		Country:       r.Country,
+		IsDefault:     r.IsDefault,
+	}
+}
+
+// addressErrorStatus maps address service errors to HTTP status codes
+func addressErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// addressID parses the :id path parameter
+func addressID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid address id"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ListAddresses godoc
+// @Summary List addresses
+// @Description List the address book of the current user, the default address first
+// @Tags Address
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Addresses retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /addresses [get]
+func (h *AddressHandler) ListAddresses(c *gin.Context) {
+	addresses, err := h.service.List(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Addresses retrieved successfully",
+		"addresses": addresses,
+	})
+}
+
+// CreateAddress godoc
+// @Summary Add address
+// @Description Add an address to the address book of the current user. The first address becomes the default.
+// @Description Postal codes are checked against the format of the country.
+// @Tags Address
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AddressRequest true "Address"
+// @Success 201 {object} map[string]interface{} "Address created successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /addresses [post]
+func (h *AddressHandler) CreateAddress(c *gin.Context) {
+	var req AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	address, err := h.service.Create(c.GetUint("user_id"), req.toModel())
+	if err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Address created successfully",
+		"address": address,
+	})
+}
+
+// GetAddress godoc
+// @Summary Get address
+// @Description Get an address of the current user
+// @Tags Address
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Address ID"
+// @Success 200 {object} map[string]interface{} "Address retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Address not found"
+// @Router /addresses/{id} [get]
+func (h *AddressHandler) GetAddress(c *gin.Context) {
+	id, ok := addressID(c)
+	if !ok {
+		return
+	}
+
+	address, err := h.service.Get(c.GetUint("user_id"), id)
+	if err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Address retrieved successfully",
+		"address": address,
+	})
+}
+
+// UpdateAddress godoc
+// @Summary Update address
+// @Description Replace an address of the current user. Orders keep the address they were placed with.
+// @Tags Address
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Address ID"
+// @Param request body AddressRequest true "Address"
+// @Success 200 {object} map[string]interface{} "Address updated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Address not found"
+// @Router /addresses/{id} [put]
+func (h *AddressHandler) UpdateAddress(c *gin.Context) {
+	id, ok := addressID(c)
+	if !ok {
+		return
+	}
+
+	var req AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	address, err := h.service.Update(c.GetUint("user_id"), id, req.toModel())
+	if err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Address updated successfully",
+		"address": address,
+	})
+}
+
+// DeleteAddress godoc
+// @Summary Delete address
+// @Description Delete an address of the current user. When it was the default, the most recently updated address becomes the default.
+// @Tags Address
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Address ID"
+// @Success 200 {object} map[string]interface{} "Address deleted successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Address not found"
+// @Router /addresses/{id} [delete]
+func (h *AddressHandler) DeleteAddress(c *gin.Context) {
+	id, ok := addressID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.GetUint("user_id"), id); err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Address deleted successfully"})
+}
+
+// SetDefaultAddress godoc
+// @Summary Set default address
+// @Description Make an address the default address of the current user
+// @Tags Address
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Address ID"
+// @Success 200 {object} map[string]interface{} "Default address updated successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Address not found"
+// @Router /addresses/{id}/default [post]
+func (h *AddressHandler) SetDefaultAddress(c *gin.Context) {
+	id, ok := addressID(c)
+	if !ok {
+		return
+	}
+
+	address, err := h.service.SetDefault(c.GetUint("user_id"), id)
+	if err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Default address updated successfully",
+		"address": address,
+	})
+}
@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/service"
+)
+
+type AdminHandler struct {
+	service service.AdminService
+}
+
+func NewAdminHandler(service service.AdminService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"max=255" example:"Chargeback fraud under review"`
+}
+
+// adminErrorStatus maps admin service errors to HTTP status codes
+func adminErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "cannot"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// userID parses the :id path parameter
+func userID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description List users by ID, a page at a time. search matches part of the email address, first name or last name.
+// @Description Deleted users are only listed with status=deleted. Requires the user:read permission.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Part of the email address or name"
+// @Param role query string false "Role"
+// @Param status query string false "active, suspended or deleted"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Users per page, at most 100" default(20)
+// @Success 200 {object} map[string]interface{} "Users retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	result, err := h.service.ListUsers(service.UserQuery{
+		Search:   c.Query("search"),
+		Role:     c.Query("role"),
+		Status:   c.Query("status"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Users retrieved successfully",
+		"users":   result.Users,
+		"pagination": gin.H{
+			"page":        result.Page,
+			"page_size":   result.PageSize,
+			"total":       result.Total,
+			"total_pages": (int(result.Total) + result.PageSize - 1) / result.PageSize,
+		},
+	})
+}
+
+// GetUser godoc
+// @Summary Get user
+// @Description Get a user, including a deleted user. Requires the user:read permission.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Router /admin/users/{id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	id, ok := userID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.GetUser(id)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User retrieved successfully",
+		"user":    user,
+		"status":  user.Status(),
+	})
+}
+
+// GetUserAuditLog godoc
+// @Summary Get user audit log
+// @Description Get the latest 100 security events of a user, newest first. Events with an actor_id are actions of an admin.
+// @Description Requires the user:read permission.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "Audit log retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Router /admin/users/{id}/audit-log [get]
+func (h *AdminHandler) GetUserAuditLog(c *gin.Context) {
+	id, ok := userID(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.service.AuditLog(id)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Audit log retrieved successfully",
+		"events":  events,
+	})
+}
+
+// SuspendUser godoc
+// @Summary Suspend user
+// @Description Stop a user from logging in and sign the user out of every session. Requires the user:write permission.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body SuspendUserRequest false "Reason"
+// @Success 200 {object} map[string]interface{} "User suspended successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Router /admin/users/{id}/suspend [post]
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	id, ok := userID(c)
+	if !ok {
+		return
+	}
+
+	var req SuspendUserRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	user, err := h.service.Suspend(id, req.Reason, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User suspended successfully",
+		"user":    user,
+	})
+}
+
+// ReactivateUser godoc
+// @Summary Reactivate user
+// @Description Lift the suspension of a user. Requires the user:write permission.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User reactivated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Router /admin/users/{id}/reactivate [post]
+func (h *AdminHandler) ReactivateUser(c *gin.Context) {
+	id, ok := userID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.Reactivate(id, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User reactivated successfully",
+		"user":    user,
+	})
+}
+
+// DeleteUser godoc
+// @Summary Delete user
+// @Description Soft-delete a user and sign the user out of every session. A deleted user can be restored. Requires the user:write permission.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Router /admin/users/{id} [delete]
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	id, ok := userID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id, c.GetUint("user_id")); err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
+
+// RestoreUser godoc
+// @Summary Restore user
+// @Description Restore a soft-deleted user. Requires the user:write permission.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User restored successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Router /admin/users/{id}/restore [post]
+func (h *AdminHandler) RestoreUser(c *gin.Context) {
+	id, ok := userID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.Restore(id, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User restored successfully",
+		"user":    user,
+	})
+}
+
+// LogoutUser godoc
+// @Summary Force logout
+// @Description Sign a user out of every session. Access tokens already issued stop working at once. Requires the user:write permission.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User logged out successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Router /admin/users/{id}/logout [post]
+func (h *AdminHandler) LogoutUser(c *gin.Context) {
+	id, ok := userID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.ForceLogout(id, c.GetUint("user_id")); err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User logged out successfully"})
+}
+
+// UnlockUser godoc
+// @Summary Unlock user
+// @Description Lift the lockout and login delay of a user locked out by failed logins. Requires the user:write permission.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{} "User unlocked successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Router /admin/users/{id}/unlock [post]
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	id, ok := userID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Unlock(id, c.GetUint("user_id")); err != nil {
+		c.JSON(adminErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked successfully"})
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+)
+
+// jwksMaxAge is how long clients may cache the JWK set, in seconds. A key must
+// be published at least this long before it becomes the active key.
+const jwksMaxAge = "300"
+
+type JWKSHandler struct {
+	keys *auth.KeyRing
+}
+
+func NewJWKSHandler(keys *auth.KeyRing) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// JWKS serves the public keys that verify access tokens at
+// /.well-known/jwks.json, outside the /api/v1 base path. Verifiers look keys
+// up by the kid header of a token.
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age="+jwksMaxAge)
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
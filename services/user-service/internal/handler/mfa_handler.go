@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/service"
+)
+
+type MFAHandler struct {
+	service service.MFAService
+}
+
+func NewMFAHandler(service service.MFAService) *MFAHandler {
+	return &MFAHandler{service: service}
+}
+
+type MFACodeRequest struct {
+	// Code is a code of the authenticator, or a recovery code where accepted
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+// mfaErrorStatus maps MFA service errors to HTTP status codes
+func mfaErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "cannot"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetMFA godoc
+// @Summary MFA status
+// @Description Report whether MFA is enabled for the current user and whether the policy requires it
+// @Tags MFA
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "MFA status"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /mfa [get]
+func (h *MFAHandler) GetMFA(c *gin.Context) {
+	enabled, err := h.service.Enabled(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(mfaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":  enabled,
+		"required": h.service.Required(c.GetString("role")),
+	})
+}
+
+// EnrollMFA godoc
+// @Summary Start MFA enrolment
+// @Description Create a TOTP secret for the current user. Add it to an authenticator app, by hand or by scanning
+// @Description otpauth_uri as a QR code, then activate MFA with a code. Enrolling again replaces a secret that was not activated.
+// @Tags MFA
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{} "Secret and otpauth URI"
+// @Failure 400 {object} map[string]interface{} "MFA already enabled"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /mfa/enroll [post]
+func (h *MFAHandler) EnrollMFA(c *gin.Context) {
+	enrollment, err := h.service.Enroll(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(mfaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Add the secret to your authenticator app, then activate MFA with a code",
+		"secret":      enrollment.Secret,
+		"otpauth_uri": enrollment.URI,
+	})
+}
+
+// ActivateMFA godoc
+// @Summary Activate MFA
+// @Description Enable MFA with a code of the enrolled authenticator. The response holds the recovery codes,
+// @Description which are not shown again. Log in again to get a session with MFA.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "Code of the authenticator"
+// @Success 200 {object} map[string]interface{} "MFA enabled with recovery codes"
+// @Failure 400 {object} map[string]interface{} "Invalid code or not enrolled"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /mfa/activate [post]
+func (h *MFAHandler) ActivateMFA(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.service.Activate(c.GetUint("user_id"), req.Code)
+	if err != nil {
+		c.JSON(mfaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "MFA enabled successfully",
+		"recovery_codes": codes,
+	})
+}
+
+// DisableMFA godoc
+// @Summary Disable MFA
+// @Description Disable MFA with a code of the authenticator or a recovery code
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "Code of the authenticator or recovery code"
+// @Success 200 {object} map[string]interface{} "MFA disabled successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid code"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /mfa/disable [post]
+func (h *MFAHandler) DisableMFA(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Disable(c.GetUint("user_id"), req.Code); err != nil {
+		c.JSON(mfaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "MFA disabled successfully"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace the recovery codes of the current user. Earlier codes stop working.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "Code of the authenticator or recovery code"
+// @Success 200 {object} map[string]interface{} "New recovery codes"
+// @Failure 400 {object} map[string]interface{} "Invalid code"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(c.GetUint("user_id"), req.Code)
+	if err != nil {
+		c.JSON(mfaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Recovery codes regenerated successfully",
+		"recovery_codes": codes,
+	})
+}
@@ -2,20 +2,23 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ploezy/ecommerce-platform/user-service/internal/service"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
 )
 
 type UserHandler struct {
 	service service.UserService
-	jwtSecret string
+	tokens  service.TokenService
 }
 
-func NewUserHandler(service service.UserService,jwtSecret string) *UserHandler{
+func NewUserHandler(service service.UserService, tokens service.TokenService) *UserHandler{
 	return &UserHandler{
 		service: service,
-		jwtSecret: jwtSecret,
+		tokens:  tokens,
 	}
 }
 
@@ -31,6 +34,16 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required" example:"password123"`
 }
 
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	// Code is a code of the authenticator or a recovery code
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 // Register godoc
 // @Summary Register a new user
 // @Description Register a new user with email and password
@@ -60,13 +73,19 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary Login user
-// @Description Login with email and password to get JWT token
+// @Description Login with email and password to get a short-lived JWT access token and a refresh token.
+// @Description Users with MFA get mfa_required and an mfa_token to complete the login with at /login/mfa instead.
+// @Description Repeated failures make the account wait before the next attempt and then lock it for a while;
+// @Description Retry-After tells how long.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "Login Request"
 // @Success 200 {object} map[string]interface{} "Login successful with token"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Email not verified"
+// @Failure 423 {object} map[string]interface{} "Account locked after too many failed logins"
+// @Failure 429 {object} map[string]interface{} "Too many login attempts"
 // @Router /login [post]
 func (h *UserHandler) Login (c *gin.Context){
 	var req LoginRequest
@@ -74,18 +93,148 @@ func (h *UserHandler) Login (c *gin.Context){
 		c.JSON(http.StatusBadRequest, gin.H{"error":err.Error()})
 		return
 	}
-	token,user, err := h.service.Login(req.Email,req.Password,h.jwtSecret)
+	result, err := h.service.Login(req.Email,req.Password,c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusBadRequest ,gin.H{"error":err.Error()})
+		loginError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK , gin.H{
-		"message":"Login successful",
-		"token":   token,
-		"user":    user,
+	if result.MFAChallenge != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "MFA code required",
+			"mfa_required": true,
+			"mfa_token":    result.MFAChallenge.Token,
+			"expires_in":   result.MFAChallenge.ExpiresIn,
+		})
+		return
+	}
+	response := gin.H{
+		"message":       "Login successful",
+		"token":         result.Tokens.AccessToken,
+		"refresh_token": result.Tokens.RefreshToken,
+		"expires_in":    result.Tokens.ExpiresIn,
+		"user":          result.User,
+	}
+	if result.MFAEnrollmentRequired {
+		response["mfa_enrollment_required"] = true
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// LoginMFA godoc
+// @Summary Complete login with MFA
+// @Description Exchange the mfa_token of a login and a code of the authenticator, or a recovery code, for the tokens.
+// @Description Wrong codes count as failed logins.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body LoginMFARequest true "Login MFA Request"
+// @Success 200 {object} map[string]interface{} "Login successful with token"
+// @Failure 401 {object} map[string]interface{} "Invalid code or expired mfa_token"
+// @Failure 423 {object} map[string]interface{} "Account locked after too many failed logins"
+// @Failure 429 {object} map[string]interface{} "Too many login attempts"
+// @Router /login/mfa [post]
+func (h *UserHandler) LoginMFA(c *gin.Context) {
+	var req LoginMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.LoginMFA(req.MFAToken, req.Code, c.ClientIP())
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"token":         result.Tokens.AccessToken,
+		"refresh_token": result.Tokens.RefreshToken,
+		"expires_in":    result.Tokens.ExpiresIn,
+		"user":          result.User,
 	})
 }
 
+// loginError responds to a failed login
+func loginError(c *gin.Context, err error) {
+	if blocked, ok := service.IsLoginBlocked(err); ok {
+		status := http.StatusTooManyRequests
+		if blocked.Locked {
+			status = http.StatusLocked
+		}
+		c.Header("Retry-After", strconv.FormatInt(blocked.RetryAfterSeconds(), 10))
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	status := http.StatusInternalServerError
+	switch {
+	case strings.Contains(err.Error(), "not verified"), strings.Contains(err.Error(), "suspended"):
+		status = http.StatusForbidden
+	case strings.Contains(err.Error(), "invalid"):
+		status = http.StatusUnauthorized
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and refresh token. A refresh token can be used once;
+// @Description using it again revokes every token of the session.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "Refresh Token Request"
+// @Success 200 {object} map[string]interface{} "Token refreshed successfully"
+// @Failure 400 {object} map[string]interface{} "Bad Request"
+// @Failure 401 {object} map[string]interface{} "Invalid, expired or reused refresh token"
+// @Router /token/refresh [post]
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.tokens.Refresh(req.RefreshToken)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Token refreshed successfully",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the access token and every refresh token of the current session
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Logged out successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Router /logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	value, _ := c.Get("claims")
+	claims, ok := value.(*auth.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.tokens.Logout(claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
 // GetProfile godoc
 // @Summary Get user profile
 // @Description Get current user profile (requires authentication)
@@ -5,10 +5,16 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
 	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
 )
 
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// TokenValidator validates access tokens, including whether they were revoked
+type TokenValidator interface {
+	Validate(accessToken string) (*auth.Claims, error)
+}
+
+func AuthMiddleware(validator TokenValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -27,7 +33,7 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		
 		token := parts[1]
 		// Validate token
-		claims, err := auth.ValidateToken(token,jwtSecret)
+		claims, err := validator.Validate(token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error":"Invalid or expired token"})
 			c.Abort()
@@ -38,7 +44,8 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		c.Set("user_id",claims.UserID)
 		c.Set("email",claims.Email)
 		c.Set("role",claims.Role)
+		authz.SetClaims(c,claims)
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Address is an entry in the address book of a user. A user with addresses has
+// exactly one default address.
+type Address struct {
+	ID            uint   `gorm:"primarykey" json:"id"`
+	UserID        uint   `gorm:"not null;index;uniqueIndex:idx_user_addresses_default,where:is_default = true AND deleted_at IS NULL" json:"user_id"`
+	Label         string `gorm:"type:varchar(50)" json:"label"`
+	RecipientName string `gorm:"type:varchar(100);not null" json:"recipient_name"`
+	Phone         string `gorm:"type:varchar(20);not null" json:"phone"`
+	Line1         string `gorm:"type:varchar(255);not null" json:"line1"`
+	Line2         string `gorm:"type:varchar(255)" json:"line2"`
+	District      string `gorm:"type:varchar(100)" json:"district"`
+	City          string `gorm:"type:varchar(100);not null" json:"city"`
+	// Region is an ISO 3166-2 subdivision code such as TH-10
+	Region     string         `gorm:"type:varchar(6)" json:"region"`
+	PostalCode string         `gorm:"type:varchar(10);not null" json:"postal_code"`
+	Country    string         `gorm:"type:varchar(2);not null" json:"country"`
+	IsDefault  bool           `gorm:"not null;default:false" json:"is_default"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (Address) TableName() string {
+	return "user_addresses"
+}
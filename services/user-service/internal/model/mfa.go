@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// UserMFA is the TOTP authenticator of a user. It is pending until the user
+// proves the authenticator works by entering a code.
+type UserMFA struct {
+	ID     uint `gorm:"primarykey" json:"-"`
+	UserID uint `gorm:"not null;uniqueIndex" json:"user_id"`
+	// Secret is the TOTP secret, sealed when MFA_ENCRYPTION_KEY is set
+	Secret    string     `gorm:"type:varchar(255);not null" json:"-"`
+	EnabledAt *time.Time `json:"enabled_at,omitempty"`
+	// LastUsedStep is the TOTP step of the last accepted code. Codes of that
+	// step or earlier are refused so they can't be replayed.
+	LastUsedStep int64     `gorm:"not null;default:0" json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// IsEnabled reports whether the user has to enter a code to log in
+func (m *UserMFA) IsEnabled() bool {
+	return m.EnabledAt != nil
+}
+
+// MFARecoveryCode is a single-use code that replaces a TOTP code when the
+// authenticator is lost. Only the SHA-256 hash of the code is stored.
+type MFARecoveryCode struct {
+	ID        uint   `gorm:"primarykey"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"type:varchar(64);not null;uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
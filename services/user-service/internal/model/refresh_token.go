@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// RefreshToken is a rotating refresh token. Only the SHA-256 hash of the token
+// is stored. Every refresh replaces the token with a new one in the same
+// family; the family is the login session the tokens descend from.
+type RefreshToken struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	FamilyID  string    `gorm:"type:varchar(32);not null;index" json:"family_id"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	// AuthMethods are the comma separated amr values of the login the family
+	// started with, carried over to every access token of the session
+	AuthMethods string `gorm:"type:varchar(64)" json:"auth_methods,omitempty"`
+	// RotatedAt is set once the token has been exchanged for a new one. A
+	// rotated token that is presented again has been stolen or replayed.
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the token can still be exchanged at now
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RotatedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
+)
+
+// Built-in roles. New users get RoleCustomer; RoleAdmin is granted every
+// permission in DefaultPermissions.
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+)
+
+// Permission allows an action, such as product:write. The services check
+// permissions, never role names.
+type Permission struct {
+	ID          uint      `gorm:"primarykey" json:"-"`
+	Name        string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"name"`
+	Description string    `gorm:"type:varchar(255)" json:"description"`
+	CreatedAt   time.Time `json:"-"`
+}
+
+// Role is a named set of permissions. A user has one role.
+type Role struct {
+	ID          uint         `gorm:"primarykey" json:"-"`
+	Name        string       `gorm:"type:varchar(50);not null;uniqueIndex" json:"name"`
+	Description string       `gorm:"type:varchar(255)" json:"description"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// PermissionNames returns the names of the permissions of the role
+func (r *Role) PermissionNames() []string {
+	names := make([]string, len(r.Permissions))
+	for i, permission := range r.Permissions {
+		names[i] = permission.Name
+	}
+	return names
+}
+
+// IsBuiltinRole reports whether name is a role that can't be deleted
+func IsBuiltinRole(name string) bool {
+	return name == RoleCustomer || name == RoleAdmin
+}
+
+// DefaultPermissions are the permissions the services check. They are
+// created at startup.
+func DefaultPermissions() []Permission {
+	return []Permission{
+		{Name: authz.ProductWrite, Description: "Create, update and delete products"},
+		{Name: authz.OrderRead, Description: "View the orders of every user"},
+		{Name: authz.OrderWrite, Description: "Change order status and ship orders"},
+		{Name: authz.ShipmentWrite, Description: "Record shipment tracking"},
+		{Name: authz.ReturnWrite, Description: "Approve, receive and refund returns"},
+		{Name: authz.PromotionWrite, Description: "Manage promotions"},
+		{Name: authz.OutboxWrite, Description: "Inspect and replay outbox events"},
+		{Name: authz.UserRead, Description: "View users and security events"},
+		{Name: authz.UserWrite, Description: "Manage users"},
+		{Name: authz.RoleWrite, Description: "Manage roles and assign them to users"},
+	}
+}
+
+// DefaultRoles are the built-in roles, created at startup. Customers need no
+// permission to act on their own orders and addresses.
+func DefaultRoles() []Role {
+	return []Role{
+		{Name: RoleCustomer, Description: "Shops and manages their own account"},
+		{Name: RoleAdmin, Description: "Has every permission"},
+	}
+}
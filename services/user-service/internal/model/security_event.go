@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Types of security event
+const (
+	SecurityEventAccountLocked    = "account_locked"
+	SecurityEventAccountUnlocked  = "account_unlocked"
+	SecurityEventIPBlocked        = "ip_blocked"
+	SecurityEventSuspiciousLogin  = "suspicious_login"
+	SecurityEventMFAEnabled       = "mfa_enabled"
+	SecurityEventMFADisabled      = "mfa_disabled"
+	SecurityEventRecoveryCodeUsed = "mfa_recovery_code_used"
+	SecurityEventRoleChanged      = "role_changed"
+	SecurityEventUserSuspended    = "user_suspended"
+	SecurityEventUserReactivated  = "user_reactivated"
+	SecurityEventUserDeleted      = "user_deleted"
+	SecurityEventUserRestored     = "user_restored"
+	SecurityEventSessionsRevoked  = "sessions_revoked"
+)
+
+// SecurityEvent records something about an account that an administrator may
+// need to look into. Events with an ActorID are the audit log of the actions
+// of administrators.
+type SecurityEvent struct {
+	ID     uint  `gorm:"primarykey" json:"id"`
+	UserID *uint `gorm:"index" json:"user_id,omitempty"`
+	// ActorID is the administrator who caused the event, if any
+	ActorID   *uint     `json:"actor_id,omitempty"`
+	Type      string    `gorm:"type:varchar(32);not null;index" json:"type"`
+	Email     string    `gorm:"type:varchar(255)" json:"email,omitempty"`
+	IP        string    `gorm:"type:varchar(45)" json:"ip,omitempty"`
+	Detail    string    `gorm:"type:text" json:"detail,omitempty"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
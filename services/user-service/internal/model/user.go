@@ -7,13 +7,40 @@ import (
 )
 
 type User struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	Email     string         `gorm:"uniqueIndex;not null" json:"email"`
-	Password  string         `gorm:"not null" json:"-"`
-	FirstName string         `json:"first_name"`
-	LastName  string         `json:"last_name"`
-	Role      string         `gorm:"default:'customer'" json:"role"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
-}
\ No newline at end of file
+	ID        uint   `gorm:"primarykey" json:"id"`
+	Email     string `gorm:"uniqueIndex;not null" json:"email"`
+	Password  string `gorm:"not null" json:"-"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Role      string `gorm:"default:'customer'" json:"role"`
+	// EmailVerified is set once the user followed a verification or password
+	// reset link sent to Email
+	EmailVerified   bool       `gorm:"not null;default:false" json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// SuspendedAt is set while an admin has suspended the account. Suspended
+	// users can't log in or refresh their tokens.
+	SuspendedAt     *time.Time     `gorm:"index" json:"suspended_at,omitempty"`
+	SuspendedReason string         `gorm:"type:varchar(255)" json:"suspended_reason,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string"`
+}
+
+// Account statuses, as filtered on by admins
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusDeleted   = "deleted"
+)
+
+// Status is whether the account is active, suspended or soft-deleted
+func (u *User) Status() string {
+	switch {
+	case u.DeletedAt.Valid:
+		return UserStatusDeleted
+	case u.SuspendedAt != nil:
+		return UserStatusSuspended
+	default:
+		return UserStatusActive
+	}
+}
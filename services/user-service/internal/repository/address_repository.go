@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type AddressRepository interface {
+	Create(address *model.Address) error
+	Update(address *model.Address) error
+	Delete(address *model.Address) error
+	FindByID(userID, id uint) (*model.Address, error)
+	FindDefault(userID uint) (*model.Address, error)
+	FindByUserID(userID uint) ([]model.Address, error)
+	CountByUserID(userID uint) (int64, error)
+}
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+func NewAddressRepository(db *gorm.DB) AddressRepository {
+	return &addressRepository{db: db}
+}
+
+// Create inserts address. A default address replaces the current default.
+func (r *addressRepository) Create(address *model.Address) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := clearDefault(tx, address.UserID, 0); err != nil {
+				return err
+			}
+		}
+		return tx.Create(address).Error
+	})
+}
+
+// Update saves address. A default address replaces the current default.
+func (r *addressRepository) Update(address *model.Address) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := clearDefault(tx, address.UserID, address.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Save(address).Error
+	})
+}
+
+// Delete removes address. When it was the default the most recently updated
+// remaining address becomes the default.
+func (r *addressRepository) Delete(address *model.Address) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(address).Error; err != nil {
+			return err
+		}
+		if !address.IsDefault {
+			return nil
+		}
+
+		var next model.Address
+		err := tx.Where("user_id = ?", address.UserID).Order("updated_at DESC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+func (r *addressRepository) FindByID(userID, id uint) (*model.Address, error) {
+	var address model.Address
+	err := r.db.Where("user_id = ?", userID).First(&address, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("address not found")
+		}
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *addressRepository) FindDefault(userID uint) (*model.Address, error) {
+	var address model.Address
+	err := r.db.Where("user_id = ? AND is_default", userID).First(&address).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("address not found")
+		}
+		return nil, err
+	}
+	return &address, nil
+}
+
+// FindByUserID returns the addresses of a user, the default first
+func (r *addressRepository) FindByUserID(userID uint) ([]model.Address, error) {
+	var addresses []model.Address
+	err := r.db.Where("user_id = ?", userID).Order("is_default DESC, id").Find(&addresses).Error
+	return addresses, err
+}
+
+func (r *addressRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Address{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// clearDefault unsets the default address of a user, except for keepID
+func clearDefault(tx *gorm.DB, userID, keepID uint) error {
+	return tx.Model(&model.Address{}).
+		Where("user_id = ? AND is_default AND id <> ?", userID, keepID).
+		Update("is_default", false).Error
+}
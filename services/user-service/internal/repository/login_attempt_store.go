@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	loginFailuresAccountPrefix = "auth:login:fail:account:"
+	loginFailuresIPPrefix      = "auth:login:fail:ip:"
+	loginDelayPrefix           = "auth:login:delay:"
+	loginLockPrefix            = "auth:login:lock:"
+)
+
+// LoginStatus is what a LoginAttemptStore knows about an account and an IP
+type LoginStatus struct {
+	AccountFailures int64
+	IPFailures      int64
+	// IPFailuresTTL is how long the failures of the IP are still counted
+	IPFailuresTTL time.Duration
+	// Delay is how long the account has to wait before the next attempt
+	Delay time.Duration
+	// Locked is how long the account stays locked
+	Locked time.Duration
+}
+
+// LoginAttemptStore counts failed logins per account and per IP in Redis.
+// Counters expire a window after the first failure.
+type LoginAttemptStore interface {
+	Status(account, ip string) (*LoginStatus, error)
+	RecordFailure(account, ip string, window time.Duration) (accountFailures, ipFailures int64, err error)
+	Delay(account string, ttl time.Duration) error
+	Lock(account string, ttl time.Duration) error
+	Reset(account string) error
+}
+
+type loginAttemptStore struct {
+	client *redis.Client
+}
+
+func NewLoginAttemptStore(client *redis.Client) LoginAttemptStore {
+	return &loginAttemptStore{client: client}
+}
+
+func (s *loginAttemptStore) Status(account, ip string) (*LoginStatus, error) {
+	ctx := context.Background()
+	pipe := s.client.Pipeline()
+	accountFailures := pipe.Get(ctx, loginFailuresAccountPrefix+account)
+	ipFailures := pipe.Get(ctx, loginFailuresIPPrefix+ip)
+	ipTTL := pipe.PTTL(ctx, loginFailuresIPPrefix+ip)
+	delay := pipe.PTTL(ctx, loginDelayPrefix+account)
+	locked := pipe.PTTL(ctx, loginLockPrefix+account)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	status := &LoginStatus{
+		IPFailuresTTL: positive(ipTTL.Val()),
+		Delay:         positive(delay.Val()),
+		Locked:        positive(locked.Val()),
+	}
+	status.AccountFailures, _ = accountFailures.Int64()
+	status.IPFailures, _ = ipFailures.Int64()
+	return status, nil
+}
+
+func (s *loginAttemptStore) RecordFailure(account, ip string, window time.Duration) (int64, int64, error) {
+	ctx := context.Background()
+	var accountFailures, ipFailures *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		accountFailures = pipe.Incr(ctx, loginFailuresAccountPrefix+account)
+		pipe.ExpireNX(ctx, loginFailuresAccountPrefix+account, window)
+		ipFailures = pipe.Incr(ctx, loginFailuresIPPrefix+ip)
+		pipe.ExpireNX(ctx, loginFailuresIPPrefix+ip, window)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return accountFailures.Val(), ipFailures.Val(), nil
+}
+
+func (s *loginAttemptStore) Delay(account string, ttl time.Duration) error {
+	return s.client.Set(context.Background(), loginDelayPrefix+account, 1, ttl).Err()
+}
+
+func (s *loginAttemptStore) Lock(account string, ttl time.Duration) error {
+	return s.client.Set(context.Background(), loginLockPrefix+account, 1, ttl).Err()
+}
+
+// Reset clears the failures, delay and lock of an account. The failures of
+// the IP keep counting.
+func (s *loginAttemptStore) Reset(account string) error {
+	return s.client.Del(context.Background(),
+		loginFailuresAccountPrefix+account,
+		loginDelayPrefix+account,
+		loginLockPrefix+account,
+	).Err()
+}
+
+// positive turns the negative PTTL of missing keys and keys without an expiry
+// into 0
+func positive(ttl time.Duration) time.Duration {
+	if ttl < 0 {
+		return 0
+	}
+	return ttl
+}
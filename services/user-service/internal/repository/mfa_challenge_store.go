@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const mfaChallengePrefix = "auth:mfa:challenge:"
+
+// MFAChallengeStore keeps the challenges of logins that passed the password
+// and wait for a second factor. Challenges are stored under the hash of their
+// token.
+type MFAChallengeStore interface {
+	Create(hash string, userID uint, ttl time.Duration) error
+	Find(hash string) (uint, error)
+	// Fail counts a wrong code and returns the number of wrong codes so far
+	Fail(hash string) (int64, error)
+	// Delete removes a challenge and reports whether it existed, so that of
+	// concurrent requests only one completes the login
+	Delete(hash string) (bool, error)
+}
+
+type mfaChallengeStore struct {
+	client *redis.Client
+}
+
+func NewMFAChallengeStore(client *redis.Client) MFAChallengeStore {
+	return &mfaChallengeStore{client: client}
+}
+
+func (s *mfaChallengeStore) Create(hash string, userID uint, ttl time.Duration) error {
+	ctx := context.Background()
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, mfaChallengePrefix+hash, "user_id", userID, "failures", 0)
+		pipe.Expire(ctx, mfaChallengePrefix+hash, ttl)
+		return nil
+	})
+	return err
+}
+
+func (s *mfaChallengeStore) Find(hash string) (uint, error) {
+	value, err := s.client.HGet(context.Background(), mfaChallengePrefix+hash, "user_id").Result()
+	if err == redis.Nil {
+		return 0, errors.New("mfa challenge not found")
+	}
+	if err != nil {
+		return 0, err
+	}
+	userID, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(userID), nil
+}
+
+func (s *mfaChallengeStore) Fail(hash string) (int64, error) {
+	ctx := context.Background()
+	var failures *redis.IntCmd
+	var ttl *redis.DurationCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		failures = pipe.HIncrBy(ctx, mfaChallengePrefix+hash, "failures", 1)
+		ttl = pipe.PTTL(ctx, mfaChallengePrefix+hash)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	// The challenge expired before the increment recreated it
+	if ttl.Val() < 0 {
+		s.client.Del(ctx, mfaChallengePrefix+hash)
+		return 0, errors.New("mfa challenge not found")
+	}
+	return failures.Val(), nil
+}
+
+func (s *mfaChallengeStore) Delete(hash string) (bool, error) {
+	deleted, err := s.client.Del(context.Background(), mfaChallengePrefix+hash).Result()
+	return deleted == 1, err
+}
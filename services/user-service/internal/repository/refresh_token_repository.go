@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"gorm.io/gorm"
+)
+
+// ErrRefreshTokenReused is returned by Rotate when the token was already
+// rotated, e.g. by a concurrent refresh with the same token
+var ErrRefreshTokenReused = errors.New("refresh token already used")
+
+type RefreshTokenRepository interface {
+	Create(token *model.RefreshToken) error
+	FindByHash(hash string) (*model.RefreshToken, error)
+	Rotate(current, next *model.RefreshToken) error
+	RevokeFamily(familyID string) error
+	RevokeByUser(userID uint) ([]string, error)
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(token *model.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByHash(hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refresh token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate marks current as rotated and inserts next in one transaction. Only one
+// of several concurrent rotations of the same token succeeds, the others get
+// ErrRefreshTokenReused.
+func (r *refreshTokenRepository) Rotate(current, next *model.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", current.ID).
+			Update("rotated_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+		current.RotatedAt = &now
+		return tx.Create(next).Error
+	})
+}
+
+// RevokeFamily revokes every token of a family that is not revoked yet
+func (r *refreshTokenRepository) RevokeFamily(familyID string) error {
+	return r.db.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeByUser revokes every active token of a user and returns the families
+// they belonged to
+func (r *refreshTokenRepository) RevokeByUser(userID uint) ([]string, error) {
+	var families []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+			Distinct().Pluck("family_id", &families).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", time.Now()).Error
+	})
+	return families, err
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleRepository interface {
+	List() ([]model.Role, error)
+	FindByName(name string) (*model.Role, error)
+	Create(role *model.Role) error
+	// Update saves the description of role and replaces its permissions
+	Update(role *model.Role) error
+	Delete(role *model.Role) error
+	CountUsers(name string) (int64, error)
+	// UserIDs returns the IDs of the users that have the role
+	UserIDs(name string) ([]uint, error)
+	ListPermissions() ([]model.Permission, error)
+	FindPermissions(names []string) ([]model.Permission, error)
+	PermissionsForRole(name string) ([]string, error)
+	// Seed creates missing permissions and roles, and grants the admin role
+	// every permission
+	Seed(permissions []model.Permission, roles []model.Role) error
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) List() ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name")
+	}).Order("name").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) FindByName(name string) (*model.Role, error) {
+	var role model.Role
+	err := r.db.Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name")
+	}).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) Create(role *model.Role) error {
+	return r.db.Create(role).Error
+}
+
+func (r *roleRepository) Update(role *model.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Update("description", role.Description).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Association("Permissions").Replace(role.Permissions)
+	})
+}
+
+func (r *roleRepository) Delete(role *model.Role) error {
+	return r.db.Select("Permissions").Delete(role).Error
+}
+
+func (r *roleRepository) CountUsers(name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
+
+func (r *roleRepository) UserIDs(name string) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.User{}).Where("role = ?", name).Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *roleRepository) ListPermissions() ([]model.Permission, error) {
+	var permissions []model.Permission
+	err := r.db.Order("name").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *roleRepository) FindPermissions(names []string) ([]model.Permission, error) {
+	var permissions []model.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	err := r.db.Where("name IN ?", names).Order("name").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *roleRepository) PermissionsForRole(name string) ([]string, error) {
+	var names []string
+	err := r.db.Model(&model.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name = ?", name).
+		Order("permissions.name").
+		Pluck("permissions.name", &names).Error
+	return names, err
+}
+
+func (r *roleRepository) Seed(permissions []model.Permission, roles []model.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&permissions).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("Permissions").Create(&roles).Error; err != nil {
+			return err
+		}
+
+		var admin model.Role
+		if err := tx.Where("name = ?", model.RoleAdmin).First(&admin).Error; err != nil {
+			return err
+		}
+		var all []model.Permission
+		if err := tx.Find(&all).Error; err != nil {
+			return err
+		}
+		return tx.Model(&admin).Association("Permissions").Append(all)
+	})
+}
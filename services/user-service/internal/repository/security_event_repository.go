@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type SecurityEventRepository interface {
+	Create(event *model.SecurityEvent) error
+	// FindByUserID returns the latest events of a user, newest first
+	FindByUserID(userID uint, limit int) ([]model.SecurityEvent, error)
+}
+
+type securityEventRepository struct {
+	db *gorm.DB
+}
+
+func NewSecurityEventRepository(db *gorm.DB) SecurityEventRepository {
+	return &securityEventRepository{db: db}
+}
+
+func (r *securityEventRepository) Create(event *model.SecurityEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *securityEventRepository) FindByUserID(userID uint, limit int) ([]model.SecurityEvent, error) {
+	var events []model.SecurityEvent
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(limit).Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+)
+
+// TokenDenylist keeps revoked access tokens in Redis until they would have
+// expired anyway. Services that verify tokens locally read the same keys. A token is revoked on its own by its ID (jti), or together
+// with every other token of its session by the session ID (sid).
+type TokenDenylist interface {
+	RevokeToken(tokenID string, ttl time.Duration) error
+	RevokeSession(sessionID string, ttl time.Duration) error
+	IsRevoked(tokenID, sessionID string) (bool, error)
+}
+
+type tokenDenylist struct {
+	client *redis.Client
+}
+
+func NewTokenDenylist(client *redis.Client) TokenDenylist {
+	return &tokenDenylist{client: client}
+}
+
+func (d *tokenDenylist) RevokeToken(tokenID string, ttl time.Duration) error {
+	return d.revoke(token.DenylistTokenKey(tokenID), ttl)
+}
+
+func (d *tokenDenylist) RevokeSession(sessionID string, ttl time.Duration) error {
+	return d.revoke(token.DenylistSessionKey(sessionID), ttl)
+}
+
+func (d *tokenDenylist) revoke(key string, ttl time.Duration) error {
+	if ttl <= 0 {
+		// Already expired, nothing left to revoke
+		return nil
+	}
+	return d.client.Set(context.Background(), key, 1, ttl).Err()
+}
+
+// IsRevoked reports whether the token or its session was revoked
+func (d *tokenDenylist) IsRevoked(tokenID, sessionID string) (bool, error) {
+	n, err := d.client.Exists(context.Background(), token.DenylistKeys(tokenID, sessionID)...).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
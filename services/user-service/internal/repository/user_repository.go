@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
 	"gorm.io/gorm"
@@ -11,6 +12,25 @@ type UserRepository interface {
 	Create(user *model.User) error
 	FindByEmail(email string) (*model.User, error)
 	FindbyId(id uint) (*model.User,error)
+	Update(user *model.User) error
+	// List returns a page of the users matching filter and how many match
+	List(filter UserFilter) ([]model.User, int64, error)
+	// FindByIDWithDeleted finds a user even when it was soft-deleted
+	FindByIDWithDeleted(id uint) (*model.User, error)
+	Delete(user *model.User) error
+	Restore(user *model.User) error
+}
+
+// UserFilter selects the users returned by List
+type UserFilter struct {
+	// Search matches part of the email address, first name or last name
+	Search string
+	Role   string
+	// Status is one of the model.UserStatus values; empty matches the users
+	// that are not deleted
+	Status string
+	Offset int
+	Limit  int
 }
 
 type userRepository  struct {
@@ -37,6 +57,10 @@ func (r *userRepository) FindByEmail(email string) (*model.User, error){
 	return &user,nil
 }
 
+func (r *userRepository) Update(user *model.User) error {
+	return r.db.Save(user).Error
+}
+
 func (r * userRepository) FindbyId(id uint) (*model.User, error){
 
 	var user model.User
@@ -49,3 +73,58 @@ func (r * userRepository) FindbyId(id uint) (*model.User, error){
 	}
 	return &user,nil
 }
+
+func (r *userRepository) List(filter UserFilter) ([]model.User, int64, error) {
+	query := r.db.Model(&model.User{})
+	switch filter.Status {
+	case model.UserStatusActive:
+		query = query.Where("suspended_at IS NULL")
+	case model.UserStatusSuspended:
+		query = query.Where("suspended_at IS NOT NULL")
+	case model.UserStatusDeleted:
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		pattern := "%" + likeEscaper.Replace(search) + "%"
+		query = query.Where("email ILIKE ? OR first_name ILIKE ? OR last_name ILIKE ? OR first_name || ' ' || last_name ILIKE ?",
+			pattern, pattern, pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []model.User
+	err := query.Order("id").Offset(filter.Offset).Limit(filter.Limit).Find(&users).Error
+	return users, total, err
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *userRepository) FindByIDWithDeleted(id uint) (*model.User, error) {
+	var user model.User
+	err := r.db.Unscoped().First(&user, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) Delete(user *model.User) error {
+	return r.db.Delete(user).Error
+}
+
+func (r *userRepository) Restore(user *model.User) error {
+	if err := r.db.Unscoped().Model(user).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	user.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
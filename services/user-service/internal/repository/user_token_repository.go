@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"gorm.io/gorm"
+)
+
+type UserTokenRepository interface {
+	Create(token *model.UserToken) error
+	Consume(purpose, hash string) (*model.UserToken, error)
+	ResetPassword(hash, passwordHash string) (*model.UserToken, error)
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+// Create saves token and invalidates the unused tokens the user has for the
+// same purpose, so that only the latest link works
+func (r *userTokenRepository) Create(token *model.UserToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// Consume marks the unexpired, unused token with hash as used and returns it.
+// Of concurrent requests with the same token only one succeeds.
+func (r *userTokenRepository) Consume(purpose, hash string) (*model.UserToken, error) {
+	return consume(r.db, purpose, hash)
+}
+
+// ResetPassword consumes the password reset token with hash and sets the
+// password of its user to passwordHash, marking the email address verified.
+// Both happen in one transaction, so the token stays usable when the user
+// cannot be updated.
+func (r *userTokenRepository) ResetPassword(hash, passwordHash string) (*model.UserToken, error) {
+	var token *model.UserToken
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		token, err = consume(tx, model.TokenPurposePasswordReset, hash)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.User{}).
+			Where("id = ?", token.UserID).
+			Updates(map[string]interface{}{
+				"password":          passwordHash,
+				"email_verified":    true,
+				"email_verified_at": gorm.Expr("COALESCE(email_verified_at, ?)", time.Now()),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("user not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func consume(db *gorm.DB, purpose, hash string) (*model.UserToken, error) {
+	now := time.Now()
+	result := db.Model(&model.UserToken{}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", hash, purpose, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("token not found")
+	}
+
+	var token model.UserToken
+	if err := db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
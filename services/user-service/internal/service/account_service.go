@@ -0,0 +1,199 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AccountConfig configures the links mailed by AccountService
+type AccountConfig struct {
+	// BaseURL is the storefront URL the links in emails point to
+	BaseURL              string
+	PasswordResetTTL     time.Duration
+	EmailVerificationTTL time.Duration
+}
+
+// AccountService recovers accounts and verifies email addresses with
+// single-use tokens sent by mail
+type AccountService interface {
+	ForgotPassword(email string) error
+	ResetPassword(token, password string) error
+	SendVerification(user *model.User) error
+	ResendVerification(userID uint) error
+	VerifyEmail(token string) (*model.User, error)
+}
+
+type accountService struct {
+	userRepo  repository.UserRepository
+	tokenRepo repository.UserTokenRepository
+	tokens    TokenService
+	mailer    mailer.Mailer
+	config    AccountConfig
+}
+
+func NewAccountService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.UserTokenRepository,
+	tokens TokenService,
+	mailer mailer.Mailer,
+	config AccountConfig,
+) AccountService {
+	return &accountService{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		tokens:    tokens,
+		mailer:    mailer,
+		config:    config,
+	}
+}
+
+// ForgotPassword mails a password reset link. It succeeds for unknown email
+// addresses too, so that it cannot be used to find out who has an account.
+func (s *accountService) ForgotPassword(email string) error {
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(email))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issue(user.ID, model.TokenPurposePasswordReset, s.config.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(`Hi %s,
+
+Someone asked to reset the password of your account. To choose a new
+password, open this link within %s:
+
+%s
+
+If it was not you, ignore this email; your password has not changed.
+`, user.FirstName, s.config.PasswordResetTTL, s.link("/reset-password", token)),
+	})
+}
+
+// ResetPassword sets a new password with a password reset token and signs the
+// user out of every session. Following the link proves the user owns the
+// email address, so it is marked verified as well. The token is only used up
+// together with the password change, so a failed reset can be tried again.
+func (s *accountService) ResetPassword(token, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	used, err := s.tokenRepo.ResetPassword(auth.HashToken(token), string(hashedPassword))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.New("invalid or expired password reset token")
+		}
+		return err
+	}
+
+	// The new password is saved, so the reset has succeeded even when the
+	// sessions cannot be revoked. They are logged to be signed out by an admin.
+	if err := s.tokens.RevokeUserSessions(used.UserID); err != nil {
+		log.Printf("Failed to revoke the sessions of user %d after a password reset: %v", used.UserID, err)
+	}
+	return nil
+}
+
+// SendVerification mails an email verification link to user
+func (s *accountService) SendVerification(user *model.User) error {
+	token, err := s.issue(user.ID, model.TokenPurposeEmailVerification, s.config.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(`Hi %s,
+
+Please confirm your email address by opening this link within %s:
+
+%s
+`, user.FirstName, s.config.EmailVerificationTTL, s.link("/verify-email", token)),
+	})
+}
+
+// ResendVerification mails a new verification link to a user who has not
+// verified their email address yet
+func (s *accountService) ResendVerification(userID uint) error {
+	user, err := s.userRepo.FindbyId(userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return errors.New("cannot resend verification: email already verified")
+	}
+	return s.SendVerification(user)
+}
+
+// VerifyEmail marks the email address of the owner of token verified
+func (s *accountService) VerifyEmail(token string) (*model.User, error) {
+	used, err := s.tokenRepo.Consume(model.TokenPurposeEmailVerification, auth.HashToken(token))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.New("invalid or expired verification token")
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindbyId(used.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.EmailVerified {
+		markVerified(user)
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// issue stores a new token for purpose, which invalidates the earlier ones
+func (s *accountService) issue(userID uint, purpose string, ttl time.Duration) (string, error) {
+	token, err := auth.NewOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	err = s.tokenRepo.Create(&model.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	log.Printf("Issued %s token for user %d", purpose, userID)
+	return token, nil
+}
+
+func (s *accountService) link(path, token string) string {
+	return strings.TrimRight(s.config.BaseURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+func markVerified(user *model.User) {
+	if user.EmailVerified {
+		return
+	}
+	now := time.Now()
+	user.EmailVerified = true
+	user.EmailVerifiedAt = &now
+}
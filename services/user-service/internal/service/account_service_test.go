@@ -0,0 +1,210 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeUserTokenRepository struct {
+	tokens []*model.UserToken
+	users  *fakeUserRepository
+}
+
+func (r *fakeUserTokenRepository) Create(token *model.UserToken) error {
+	now := time.Now()
+	for _, existing := range r.tokens {
+		if existing.UserID == token.UserID && existing.Purpose == token.Purpose && existing.UsedAt == nil {
+			existing.UsedAt = &now
+		}
+	}
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeUserTokenRepository) Consume(purpose, hash string) (*model.UserToken, error) {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.TokenHash == hash && token.Purpose == purpose && token.UsedAt == nil && now.Before(token.ExpiresAt) {
+			token.UsedAt = &now
+			return token, nil
+		}
+	}
+	return nil, errors.New("token not found")
+}
+
+func (r *fakeUserTokenRepository) ResetPassword(hash, passwordHash string) (*model.UserToken, error) {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.TokenHash == hash && token.Purpose == model.TokenPurposePasswordReset && token.UsedAt == nil && now.Before(token.ExpiresAt) {
+			user, err := r.users.FindbyId(token.UserID)
+			if err != nil {
+				return nil, err
+			}
+			user.Password = passwordHash
+			markVerified(user)
+			token.UsedAt = &now
+			return token, nil
+		}
+	}
+	return nil, errors.New("token not found")
+}
+
+type accountFixture struct {
+	users   *fakeUserRepository
+	tokens  *fakeUserTokenRepository
+	mail    *mailer.MemoryMailer
+	session TokenService
+	account AccountService
+}
+
+func newAccountFixture(t *testing.T) *accountFixture {
+	keys, err := auth.GenerateKeyRing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &accountFixture{
+		users: &fakeUserRepository{users: map[uint]*model.User{}},
+		mail:  mailer.NewMemoryMailer(),
+	}
+	f.tokens = &fakeUserTokenRepository{users: f.users}
+	f.session = NewTokenService(f.users, &fakeRefreshTokenRepository{}, &fakeTokenDenylist{revoked: map[string]bool{}}, testPermissions, keys, 15*time.Minute, 24*time.Hour, MFAPolicy{})
+	f.account = NewAccountService(f.users, f.tokens, f.session, f.mail, AccountConfig{
+		BaseURL:              "https://shop.example.com/",
+		PasswordResetTTL:     time.Hour,
+		EmailVerificationTTL: time.Hour,
+	})
+	return f
+}
+
+var linkToken = regexp.MustCompile(`\?token=(\S+)`)
+
+// lastToken returns the token in the link of the last mail sent
+func (f *accountFixture) lastToken(t *testing.T) string {
+	t.Helper()
+	messages := f.mail.Messages()
+	if len(messages) == 0 {
+		t.Fatal("no mail sent")
+	}
+	match := linkToken.FindStringSubmatch(messages[len(messages)-1].Body)
+	if match == nil {
+		t.Fatalf("no link in mail %q", messages[len(messages)-1].Body)
+	}
+	token, _ := url.QueryUnescape(match[1])
+	return token
+}
+
+func TestRegisterSendsVerificationAndLoginRequiresIt(t *testing.T) {
+	f := newAccountFixture(t)
+	events := &fakeSecurityEventRepository{}
+	guard := NewLoginGuard(f.users, newFakeLoginAttemptStore(), events, LoginPolicy{})
+	mfa := NewMFAService(f.users, newFakeMFARepository(), newFakeMFAChallengeStore(), events, nil, MFAConfig{})
+	users := NewUserService(f.users, f.session, f.account, guard, mfa, true)
+
+	if _, err := users.Register("new@example.com", "password123", "New", "User"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if _, err := users.Login("new@example.com", "password123", "203.0.113.7"); err == nil || !strings.Contains(err.Error(), "not verified") {
+		t.Fatalf("Login() before verification error = %v, want not verified", err)
+	}
+
+	user, err := f.account.VerifyEmail(f.lastToken(t))
+	if err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+	if !user.EmailVerified || user.EmailVerifiedAt == nil {
+		t.Errorf("VerifyEmail() user = %+v, want verified", user)
+	}
+	if _, err := users.Login("new@example.com", "password123", "203.0.113.7"); err != nil {
+		t.Errorf("Login() after verification error = %v", err)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	f := newAccountFixture(t)
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	user := &model.User{Email: "user@example.com", Password: string(hashed)}
+	f.users.Create(user)
+	session, _ := f.session.Issue(user, []string{token.AuthMethodPassword})
+
+	// Unknown addresses get the same answer and no mail
+	if err := f.account.ForgotPassword("nobody@example.com"); err != nil {
+		t.Fatalf("ForgotPassword() of unknown email error = %v", err)
+	}
+	if n := len(f.mail.Messages()); n != 0 {
+		t.Fatalf("ForgotPassword() of unknown email sent %d mails", n)
+	}
+
+	if err := f.account.ForgotPassword("user@example.com"); err != nil {
+		t.Fatalf("ForgotPassword() error = %v", err)
+	}
+	first := f.lastToken(t)
+	f.account.ForgotPassword("user@example.com")
+	token := f.lastToken(t)
+
+	if err := f.account.ResetPassword(first, "new-password"); err == nil {
+		t.Error("ResetPassword() accepted a token replaced by a newer one")
+	}
+	if err := f.account.ResetPassword(token, "new-password"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if err := f.account.ResetPassword(token, "other-password"); err == nil {
+		t.Error("ResetPassword() accepted a used token")
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("new-password")) != nil {
+		t.Error("ResetPassword() did not change the password")
+	}
+	if !user.EmailVerified {
+		t.Error("ResetPassword() did not mark the email verified")
+	}
+	if _, err := f.session.Refresh(session.RefreshToken); err == nil {
+		t.Error("ResetPassword() left an existing session signed in")
+	}
+}
+
+func TestFailedResetKeepsTheToken(t *testing.T) {
+	f := newAccountFixture(t)
+	user := &model.User{Email: "user@example.com"}
+	f.users.Create(user)
+	f.account.ForgotPassword("user@example.com")
+	token := f.lastToken(t)
+
+	// The user cannot be updated, so the token stays unused
+	user.DeletedAt.Valid = true
+	if err := f.account.ResetPassword(token, "new-password"); err == nil {
+		t.Fatal("ResetPassword() of a deleted user succeeded")
+	}
+	user.DeletedAt.Valid = false
+
+	// The password is changed even when the sessions cannot be revoked
+	account := NewAccountService(f.users, f.tokens, unrevokableTokens{f.session}, f.mail, AccountConfig{})
+	if err := account.ResetPassword(token, "new-password"); err != nil {
+		t.Fatalf("ResetPassword() after a failed reset error = %v", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("new-password")) != nil {
+		t.Error("ResetPassword() did not change the password")
+	}
+}
+
+func TestExpiredTokenIsRejected(t *testing.T) {
+	f := newAccountFixture(t)
+	user := &model.User{Email: "user@example.com"}
+	f.users.Create(user)
+
+	f.account.SendVerification(user)
+	f.tokens.tokens[0].ExpiresAt = time.Now().Add(-time.Second)
+
+	if _, err := f.account.VerifyEmail(f.lastToken(t)); err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("VerifyEmail() with expired token error = %v, want invalid", err)
+	}
+}
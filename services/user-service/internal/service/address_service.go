@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
+)
+
+type AddressService interface {
+	List(userID uint) ([]model.Address, error)
+	Get(userID, id uint) (*model.Address, error)
+	Create(userID uint, input *model.Address) (*model.Address, error)
+	Update(userID, id uint, input *model.Address) (*model.Address, error)
+	Delete(userID, id uint) error
+	SetDefault(userID, id uint) (*model.Address, error)
+	Normalize(address *model.Address) error
+}
+
+type addressService struct {
+	repo repository.AddressRepository
+}
+
+func NewAddressService(repo repository.AddressRepository) AddressService {
+	return &addressService{repo: repo}
+}
+
+func (s *addressService) List(userID uint) ([]model.Address, error) {
+	return s.repo.FindByUserID(userID)
+}
+
+// Get returns an address of the user, or the default address when id is 0
+func (s *addressService) Get(userID, id uint) (*model.Address, error) {
+	if id == 0 {
+		return s.repo.FindDefault(userID)
+	}
+	return s.repo.FindByID(userID, id)
+}
+
+// Create adds an address to the address book. The first address is always the default.
+func (s *addressService) Create(userID uint, input *model.Address) (*model.Address, error) {
+	if err := s.Normalize(input); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	address := &model.Address{UserID: userID, IsDefault: input.IsDefault || count == 0}
+	copyAddress(address, input)
+	if err := s.repo.Create(address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// Update replaces an address. The default address stays the default until
+// another address is made the default.
+func (s *addressService) Update(userID, id uint, input *model.Address) (*model.Address, error) {
+	if err := s.Normalize(input); err != nil {
+		return nil, err
+	}
+
+	address, err := s.repo.FindByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	copyAddress(address, input)
+	address.IsDefault = address.IsDefault || input.IsDefault
+	if err := s.repo.Update(address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+func (s *addressService) Delete(userID, id uint) error {
+	address, err := s.repo.FindByID(userID, id)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(address)
+}
+
+func (s *addressService) SetDefault(userID, id uint) (*model.Address, error) {
+	address, err := s.repo.FindByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if address.IsDefault {
+		return address, nil
+	}
+
+	address.IsDefault = true
+	if err := s.repo.Update(address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// Normalize trims address, upper-cases its codes and validates the postal
+// code and region for its country
+func (s *addressService) Normalize(address *model.Address) error {
+	address.Label = strings.TrimSpace(address.Label)
+	address.RecipientName = strings.TrimSpace(address.RecipientName)
+	address.Phone = strings.TrimSpace(address.Phone)
+	address.Line1 = strings.TrimSpace(address.Line1)
+	address.Line2 = strings.TrimSpace(address.Line2)
+	address.District = strings.TrimSpace(address.District)
+	address.City = strings.TrimSpace(address.City)
+	address.Country = strings.ToUpper(strings.TrimSpace(address.Country))
+	address.Region = strings.ToUpper(strings.TrimSpace(address.Region))
+
+	if address.RecipientName == "" || address.Phone == "" || address.Line1 == "" || address.City == "" {
+		return errors.New("invalid address: recipient_name, phone, line1 and city are required")
+	}
+
+	postalCode, err := NormalizePostalCode(address.Country, address.PostalCode)
+	if err != nil {
+		return err
+	}
+	address.PostalCode = postalCode
+
+	if address.Region != "" && (!regionFormat.MatchString(address.Region) || !strings.HasPrefix(address.Region, address.Country+"-")) {
+		return fmt.Errorf("invalid address: region %s is not a subdivision of %s", address.Region, address.Country)
+	}
+	return nil
+}
+
+// copyAddress copies the editable fields of src onto dst
+func copyAddress(dst, src *model.Address) {
+	dst.Label = src.Label
+	dst.RecipientName = src.RecipientName
+	dst.Phone = src.Phone
+	dst.Line1 = src.Line1
+	dst.Line2 = src.Line2
+	dst.District = src.District
+	dst.City = src.City
+	dst.Region = src.Region
+	dst.PostalCode = src.PostalCode
+	dst.Country = src.Country
+}
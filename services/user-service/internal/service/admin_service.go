@@ -0,0 +1,248 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+	// auditLogLimit is how many events of a user AuditLog returns
+	auditLogLimit = 100
+)
+
+// UserQuery selects a page of users for admins
+type UserQuery struct {
+	// Search matches part of the email address or name
+	Search string
+	Role   string
+	// Status is active, suspended or deleted; empty lists every user that is
+	// not deleted
+	Status   string
+	Page     int
+	PageSize int
+}
+
+// UserPage is a page of users and how many users match in total
+type UserPage struct {
+	Users    []model.User `json:"users"`
+	Total    int64        `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+}
+
+// AdminService lets admins manage the accounts of other users. Every change
+// is recorded as a security event with the admin as its actor.
+type AdminService interface {
+	ListUsers(query UserQuery) (*UserPage, error)
+	// GetUser finds a user, including soft-deleted users
+	GetUser(id uint) (*model.User, error)
+	ChangeRole(id uint, role string, actorID uint) (*model.User, error)
+	// Suspend stops a user from logging in and revokes every session
+	Suspend(id uint, reason string, actorID uint) (*model.User, error)
+	Reactivate(id, actorID uint) (*model.User, error)
+	// Delete soft-deletes a user and revokes every session
+	Delete(id, actorID uint) error
+	Restore(id, actorID uint) (*model.User, error)
+	// ForceLogout revokes every session of a user
+	ForceLogout(id, actorID uint) error
+	Unlock(id, actorID uint) error
+	// AuditLog returns the latest security events of a user, newest first
+	AuditLog(id uint) ([]model.SecurityEvent, error)
+}
+
+type adminService struct {
+	users  repository.UserRepository
+	tokens TokenService
+	roles  RoleService
+	guard  LoginGuard
+	events repository.SecurityEventRepository
+}
+
+func NewAdminService(users repository.UserRepository, tokens TokenService, roles RoleService, guard LoginGuard, events repository.SecurityEventRepository) AdminService {
+	return &adminService{
+		users:  users,
+		tokens: tokens,
+		roles:  roles,
+		guard:  guard,
+		events: events,
+	}
+}
+
+func (s *adminService) ListUsers(query UserQuery) (*UserPage, error) {
+	switch query.Status {
+	case "", model.UserStatusActive, model.UserStatusSuspended, model.UserStatusDeleted:
+	default:
+		return nil, errors.New("invalid status: use active, suspended or deleted")
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = defaultUserPageSize
+	}
+	if query.PageSize > maxUserPageSize {
+		query.PageSize = maxUserPageSize
+	}
+
+	users, total, err := s.users.List(repository.UserFilter{
+		Search: query.Search,
+		Role:   query.Role,
+		Status: query.Status,
+		Offset: (query.Page - 1) * query.PageSize,
+		Limit:  query.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if users == nil {
+		users = []model.User{}
+	}
+	return &UserPage{Users: users, Total: total, Page: query.Page, PageSize: query.PageSize}, nil
+}
+
+func (s *adminService) GetUser(id uint) (*model.User, error) {
+	return s.users.FindByIDWithDeleted(id)
+}
+
+func (s *adminService) ChangeRole(id uint, role string, actorID uint) (*model.User, error) {
+	return s.roles.AssignRole(id, role, actorID)
+}
+
+func (s *adminService) Suspend(id uint, reason string, actorID uint) (*model.User, error) {
+	if id == actorID {
+		return nil, errors.New("cannot suspend your own account")
+	}
+	user, err := s.users.FindbyId(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.SuspendedAt != nil {
+		return nil, errors.New("cannot suspend user: already suspended")
+	}
+
+	// The suspension is saved first, so no session can be opened after the
+	// sessions are revoked. It is undone when they cannot be revoked, as other
+	// services only check the denylist.
+	now := time.Now()
+	user.SuspendedAt = &now
+	user.SuspendedReason = strings.TrimSpace(reason)
+	if err := s.users.Update(user); err != nil {
+		return nil, err
+	}
+	if err := s.tokens.RevokeUserSessions(user.ID); err != nil {
+		user.SuspendedAt = nil
+		user.SuspendedReason = ""
+		if undoErr := s.users.Update(user); undoErr != nil {
+			log.Printf("Failed to undo the suspension of user %d: %v", user.ID, undoErr)
+		}
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	s.record(user, actorID, model.SecurityEventUserSuspended, user.SuspendedReason)
+	return user, nil
+}
+
+func (s *adminService) Reactivate(id, actorID uint) (*model.User, error) {
+	user, err := s.users.FindbyId(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.SuspendedAt == nil {
+		return nil, errors.New("cannot reactivate user: not suspended")
+	}
+
+	user.SuspendedAt = nil
+	user.SuspendedReason = ""
+	if err := s.users.Update(user); err != nil {
+		return nil, err
+	}
+	s.record(user, actorID, model.SecurityEventUserReactivated, "")
+	return user, nil
+}
+
+func (s *adminService) Delete(id, actorID uint) error {
+	if id == actorID {
+		return errors.New("cannot delete your own account")
+	}
+	user, err := s.users.FindbyId(id)
+	if err != nil {
+		return err
+	}
+
+	// Deleted first for the same reason as in Suspend
+	if err := s.users.Delete(user); err != nil {
+		return err
+	}
+	if err := s.tokens.RevokeUserSessions(user.ID); err != nil {
+		if undoErr := s.users.Restore(user); undoErr != nil {
+			log.Printf("Failed to undo the deletion of user %d: %v", user.ID, undoErr)
+		}
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	s.record(user, actorID, model.SecurityEventUserDeleted, "")
+	return nil
+}
+
+func (s *adminService) Restore(id, actorID uint) (*model.User, error) {
+	user, err := s.users.FindByIDWithDeleted(id)
+	if err != nil {
+		return nil, err
+	}
+	if !user.DeletedAt.Valid {
+		return nil, errors.New("cannot restore user: not deleted")
+	}
+
+	if err := s.users.Restore(user); err != nil {
+		return nil, err
+	}
+	s.record(user, actorID, model.SecurityEventUserRestored, "")
+	return user, nil
+}
+
+func (s *adminService) ForceLogout(id, actorID uint) error {
+	user, err := s.users.FindbyId(id)
+	if err != nil {
+		return err
+	}
+	if err := s.tokens.RevokeUserSessions(user.ID); err != nil {
+		return err
+	}
+	s.record(user, actorID, model.SecurityEventSessionsRevoked, "")
+	return nil
+}
+
+func (s *adminService) Unlock(id, actorID uint) error {
+	return s.guard.Unlock(id, actorID)
+}
+
+func (s *adminService) AuditLog(id uint) ([]model.SecurityEvent, error) {
+	if _, err := s.users.FindByIDWithDeleted(id); err != nil {
+		return nil, err
+	}
+	events, err := s.events.FindByUserID(id, auditLogLimit)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []model.SecurityEvent{}
+	}
+	return events, nil
+}
+
+// record adds an action of an admin on user to the audit log
+func (s *adminService) record(user *model.User, actorID uint, eventType, detail string) {
+	recordSecurityEvent(s.events, &model.SecurityEvent{
+		UserID:  &user.ID,
+		ActorID: &actorID,
+		Type:    eventType,
+		Email:   user.Email,
+		Detail:  detail,
+	})
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+)
+
+// adminID is the admin acting in the tests, who is not a user of the fixture
+const adminID = 1
+
+func newAdminFixture(t *testing.T) (*loginFixture, AdminService) {
+	f := newLoginFixture(t, LoginPolicy{}, MFAPolicy{})
+	return f, NewAdminService(f.repo, f.tokens, nil, f.guard, f.events)
+}
+
+func TestSuspendedUserCannotLogIn(t *testing.T) {
+	f, admin := newAdminFixture(t)
+	result, err := f.users.Login("user@example.com", "password123", "203.0.113.7")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if _, err := admin.Suspend(f.user.ID, "fraud", f.user.ID); err == nil || !strings.Contains(err.Error(), "cannot") {
+		t.Errorf("Suspend() of own account error = %v", err)
+	}
+	user, err := admin.Suspend(f.user.ID, " fraud ", adminID)
+	if err != nil {
+		t.Fatalf("Suspend() error = %v", err)
+	}
+	if user.Status() != model.UserStatusSuspended || user.SuspendedReason != "fraud" {
+		t.Errorf("user = %s %q, want suspended for fraud", user.Status(), user.SuspendedReason)
+	}
+
+	if _, err := f.tokens.Validate(result.Tokens.AccessToken); err == nil {
+		t.Error("Validate() accepted a token of a suspended user")
+	}
+	if _, err := f.tokens.Refresh(result.Tokens.RefreshToken); err == nil {
+		t.Error("Refresh() succeeded for a suspended user")
+	}
+	if err := f.login("password123"); err == nil || !strings.Contains(err.Error(), "suspended") {
+		t.Errorf("Login() of a suspended user error = %v", err)
+	}
+
+	if _, err := admin.Reactivate(f.user.ID, adminID); err != nil {
+		t.Fatalf("Reactivate() error = %v", err)
+	}
+	if err := f.login("password123"); err != nil {
+		t.Errorf("Login() after Reactivate() error = %v", err)
+	}
+
+	want := []string{model.SecurityEventUserSuspended, model.SecurityEventUserReactivated}
+	if types := f.events.types(); strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %v, want %v", types, want)
+	}
+	for _, event := range f.events.events {
+		if event.ActorID == nil || *event.ActorID != adminID {
+			t.Errorf("%s actor = %v, want %d", event.Type, event.ActorID, adminID)
+		}
+	}
+}
+
+// unrevokableTokens fails to revoke sessions
+type unrevokableTokens struct {
+	TokenService
+}
+
+func (unrevokableTokens) RevokeUserSessions(userID uint) error {
+	return errors.New("denylist unavailable")
+}
+
+// orderedTokens records whether the user was already suspended or deleted
+// when the sessions were revoked
+type orderedTokens struct {
+	TokenService
+	user    *model.User
+	applied []bool
+}
+
+func (o *orderedTokens) RevokeUserSessions(userID uint) error {
+	o.applied = append(o.applied, o.user.SuspendedAt != nil || o.user.DeletedAt.Valid)
+	return o.TokenService.RevokeUserSessions(userID)
+}
+
+func TestSessionsAreRevokedAfterSuspendAndDelete(t *testing.T) {
+	f := newLoginFixture(t, LoginPolicy{}, MFAPolicy{})
+	tokens := &orderedTokens{TokenService: f.tokens, user: f.user}
+	admin := NewAdminService(f.repo, tokens, nil, f.guard, f.events)
+
+	if _, err := admin.Suspend(f.user.ID, "fraud", adminID); err != nil {
+		t.Fatalf("Suspend() error = %v", err)
+	}
+	f.user.SuspendedAt = nil
+	if err := admin.Delete(f.user.ID, adminID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if fmt.Sprint(tokens.applied) != "[true true]" {
+		t.Errorf("applied before revoking = %v, want both changes saved first", tokens.applied)
+	}
+}
+
+func TestSuspendFailsWhenSessionsStay(t *testing.T) {
+	f := newLoginFixture(t, LoginPolicy{}, MFAPolicy{})
+	admin := NewAdminService(f.repo, unrevokableTokens{f.tokens}, nil, f.guard, f.events)
+
+	if _, err := admin.Suspend(f.user.ID, "fraud", adminID); err == nil || !strings.Contains(err.Error(), "revoke") {
+		t.Errorf("Suspend() error = %v, want the failed revocation", err)
+	}
+	if f.user.SuspendedAt != nil || len(f.events.events) != 0 {
+		t.Errorf("user suspended at %v with events %v, want no change", f.user.SuspendedAt, f.events.types())
+	}
+	if err := admin.Delete(f.user.ID, adminID); err == nil || f.user.DeletedAt.Valid {
+		t.Errorf("Delete() error = %v with deleted %v, want the user kept", err, f.user.DeletedAt.Valid)
+	}
+}
+
+func TestValidateRefusesTokensOfSuspendedUsers(t *testing.T) {
+	f := newLoginFixture(t, LoginPolicy{}, MFAPolicy{})
+	result, err := f.users.Login("user@example.com", "password123", "203.0.113.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A session the denylist missed
+	now := time.Now()
+	f.user.SuspendedAt = &now
+	if _, err := f.tokens.Validate(result.Tokens.AccessToken); err == nil || !strings.Contains(err.Error(), "suspended") {
+		t.Errorf("Validate() of a suspended user error = %v, want suspended", err)
+	}
+	f.user.SuspendedAt = nil
+	f.user.DeletedAt.Valid = true
+	if _, err := f.tokens.Validate(result.Tokens.AccessToken); err == nil {
+		t.Error("Validate() accepted a token of a deleted user")
+	}
+}
+
+func TestDeleteAndRestoreUser(t *testing.T) {
+	f, admin := newAdminFixture(t)
+
+	if err := admin.Delete(f.user.ID, adminID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := f.login("password123"); err == nil {
+		t.Error("Login() of a deleted user succeeded")
+	}
+	if page, _ := admin.ListUsers(UserQuery{}); page.Total != 0 {
+		t.Errorf("ListUsers() total = %d, want deleted users left out", page.Total)
+	}
+	if page, _ := admin.ListUsers(UserQuery{Status: model.UserStatusDeleted}); page.Total != 1 {
+		t.Errorf("ListUsers(deleted) total = %d, want 1", page.Total)
+	}
+	if user, err := admin.GetUser(f.user.ID); err != nil || user.Status() != model.UserStatusDeleted {
+		t.Errorf("GetUser() of a deleted user = %v, %v", user, err)
+	}
+
+	if _, err := admin.Restore(f.user.ID, adminID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if _, err := admin.Restore(f.user.ID, adminID); err == nil || !strings.Contains(err.Error(), "not deleted") {
+		t.Errorf("Restore() of a user that is not deleted error = %v", err)
+	}
+	if err := f.login("password123"); err != nil {
+		t.Errorf("Login() after Restore() error = %v", err)
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	f, admin := newAdminFixture(t)
+	for _, name := range []string{"Ann", "Bob", "Annette"} {
+		_ = f.repo.Create(&model.User{Email: strings.ToLower(name) + "@example.com", FirstName: name, Role: model.RoleCustomer})
+	}
+
+	page, err := admin.ListUsers(UserQuery{Search: "Ann", PageSize: 1, Page: 2})
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	if page.Total != 2 || len(page.Users) != 1 || page.Users[0].FirstName != "Annette" {
+		t.Errorf("ListUsers() = %d users of %d, want Annette of 2", len(page.Users), page.Total)
+	}
+	if _, err := admin.ListUsers(UserQuery{Status: "banned"}); err == nil || !strings.Contains(err.Error(), "invalid status") {
+		t.Errorf("ListUsers() with an unknown status error = %v", err)
+	}
+}
+
+func TestForceLogoutIsAudited(t *testing.T) {
+	f, admin := newAdminFixture(t)
+	result, err := f.users.Login("user@example.com", "password123", "203.0.113.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := admin.ForceLogout(f.user.ID, adminID); err != nil {
+		t.Fatalf("ForceLogout() error = %v", err)
+	}
+	if _, err := f.tokens.Validate(result.Tokens.AccessToken); err == nil {
+		t.Error("Validate() accepted a token after ForceLogout()")
+	}
+
+	events, err := admin.AuditLog(f.user.ID)
+	if err != nil {
+		t.Fatalf("AuditLog() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Type != model.SecurityEventSessionsRevoked {
+		t.Errorf("AuditLog() = %+v, want sessions_revoked", events)
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
+)
+
+// LoginPolicy sets how LoginGuard reacts to failed logins. Failures are
+// counted for Window after the first one.
+type LoginPolicy struct {
+	Window time.Duration
+	// DelayAfter failures of an account make it wait DelayBase before the
+	// next attempt, doubling with every further failure up to DelayMax
+	DelayAfter int64
+	DelayBase  time.Duration
+	DelayMax   time.Duration
+	// LockoutAfter failures of an account lock it for LockoutDuration
+	LockoutAfter    int64
+	LockoutDuration time.Duration
+	// IPMaxFailures failures from an IP, over any account, block the IP
+	// until its failures expire
+	IPMaxFailures int64
+}
+
+// LoginBlockedError is returned for a login that is refused before the
+// password is checked
+type LoginBlockedError struct {
+	// Locked is set when the account is locked rather than throttled
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	retry := time.Duration(e.RetryAfterSeconds()) * time.Second
+	if e.Locked {
+		return fmt.Sprintf("account locked: too many failed login attempts, try again in %s", retry)
+	}
+	return fmt.Sprintf("too many login attempts: try again in %s", retry)
+}
+
+// RetryAfterSeconds is RetryAfter in whole seconds, at least 1
+func (e *LoginBlockedError) RetryAfterSeconds() int64 {
+	seconds := int64(math.Ceil(e.RetryAfter.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// LoginGuard protects logins against password guessing. Login calls Check
+// before the password is checked and Failed or Succeeded after.
+type LoginGuard interface {
+	Check(email, ip string) error
+	Failed(email, ip string) error
+	Succeeded(user *model.User, ip string) error
+	// Unlock lifts the lockout and delay of a user on behalf of an admin
+	Unlock(userID, adminID uint) error
+}
+
+type loginGuard struct {
+	users    repository.UserRepository
+	attempts repository.LoginAttemptStore
+	events   repository.SecurityEventRepository
+	policy   LoginPolicy
+}
+
+func NewLoginGuard(users repository.UserRepository, attempts repository.LoginAttemptStore, events repository.SecurityEventRepository, policy LoginPolicy) LoginGuard {
+	return &loginGuard{
+		users:    users,
+		attempts: attempts,
+		events:   events,
+		policy:   policy,
+	}
+}
+
+// Check refuses the login while the account is locked or delayed, or the IP
+// is blocked. It fails closed when the attempts can't be read.
+func (g *loginGuard) Check(email, ip string) error {
+	status, err := g.attempts.Status(loginAccount(email), ip)
+	if err != nil {
+		return fmt.Errorf("failed to check login attempts: %w", err)
+	}
+
+	switch {
+	case status.Locked > 0:
+		return &LoginBlockedError{Locked: true, RetryAfter: status.Locked}
+	case g.policy.IPMaxFailures > 0 && status.IPFailures >= g.policy.IPMaxFailures:
+		return &LoginBlockedError{RetryAfter: status.IPFailuresTTL}
+	case status.Delay > 0:
+		return &LoginBlockedError{RetryAfter: status.Delay}
+	}
+	return nil
+}
+
+// Failed counts a failed login, whether or not the account exists, and
+// delays or locks the account once it failed too often
+func (g *loginGuard) Failed(email, ip string) error {
+	account := loginAccount(email)
+	accountFailures, ipFailures, err := g.attempts.RecordFailure(account, ip, g.policy.Window)
+	if err != nil {
+		return fmt.Errorf("failed to record login attempt: %w", err)
+	}
+
+	if g.policy.IPMaxFailures > 0 && ipFailures == g.policy.IPMaxFailures {
+		g.record(&model.SecurityEvent{
+			Type:   model.SecurityEventIPBlocked,
+			Email:  account,
+			IP:     ip,
+			Detail: fmt.Sprintf("%d failed logins within %s", ipFailures, g.policy.Window),
+		})
+	}
+
+	switch {
+	case g.policy.LockoutAfter > 0 && accountFailures >= g.policy.LockoutAfter:
+		if err := g.attempts.Lock(account, g.policy.LockoutDuration); err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		event := &model.SecurityEvent{
+			Type:   model.SecurityEventAccountLocked,
+			Email:  account,
+			IP:     ip,
+			Detail: fmt.Sprintf("%d failed logins, locked for %s", accountFailures, g.policy.LockoutDuration),
+		}
+		if user, err := g.users.FindByEmail(email); err == nil {
+			event.UserID = &user.ID
+		}
+		g.record(event)
+	case g.policy.DelayAfter > 0 && accountFailures >= g.policy.DelayAfter:
+		if err := g.attempts.Delay(account, g.delay(accountFailures)); err != nil {
+			return fmt.Errorf("failed to delay login: %w", err)
+		}
+	}
+	return nil
+}
+
+// Succeeded clears the failures of the account. A login that succeeds after
+// it was delayed, or from an IP with many failures, is recorded as suspicious.
+func (g *loginGuard) Succeeded(user *model.User, ip string) error {
+	account := loginAccount(user.Email)
+	status, err := g.attempts.Status(account, ip)
+	if err != nil {
+		return fmt.Errorf("failed to check login attempts: %w", err)
+	}
+
+	var reasons []string
+	if g.policy.DelayAfter > 0 && status.AccountFailures >= g.policy.DelayAfter {
+		reasons = append(reasons, fmt.Sprintf("%d failed logins for the account", status.AccountFailures))
+	}
+	if g.policy.DelayAfter > 0 && status.IPFailures >= g.policy.DelayAfter {
+		reasons = append(reasons, fmt.Sprintf("%d failed logins from the IP", status.IPFailures))
+	}
+	if len(reasons) > 0 {
+		g.record(&model.SecurityEvent{
+			UserID: &user.ID,
+			Type:   model.SecurityEventSuspiciousLogin,
+			Email:  account,
+			IP:     ip,
+			Detail: "login succeeded after " + strings.Join(reasons, " and "),
+		})
+	}
+
+	if status.AccountFailures > 0 {
+		if err := g.attempts.Reset(account); err != nil {
+			return fmt.Errorf("failed to reset login attempts: %w", err)
+		}
+	}
+	return nil
+}
+
+func (g *loginGuard) Unlock(userID, adminID uint) error {
+	user, err := g.users.FindbyId(userID)
+	if err != nil {
+		return err
+	}
+
+	account := loginAccount(user.Email)
+	if err := g.attempts.Reset(account); err != nil {
+		return fmt.Errorf("failed to unlock account: %w", err)
+	}
+	g.record(&model.SecurityEvent{
+		UserID:  &user.ID,
+		ActorID: &adminID,
+		Type:    model.SecurityEventAccountUnlocked,
+		Email:   account,
+	})
+	return nil
+}
+
+// delay is how long an account waits after its nth failure
+func (g *loginGuard) delay(failures int64) time.Duration {
+	delay := g.policy.DelayBase
+	for n := g.policy.DelayAfter; n < failures && delay < g.policy.DelayMax; n++ {
+		delay *= 2
+	}
+	if g.policy.DelayMax > 0 && delay > g.policy.DelayMax {
+		return g.policy.DelayMax
+	}
+	return delay
+}
+
+func (g *loginGuard) record(event *model.SecurityEvent) {
+	recordSecurityEvent(g.events, event)
+}
+
+// recordSecurityEvent stores event. Losing an event must not fail the request
+// that caused it.
+func recordSecurityEvent(events repository.SecurityEventRepository, event *model.SecurityEvent) {
+	log.Printf("Security event %s: user=%s account=%s ip=%s %s", event.Type, userIDString(event.UserID), event.Email, event.IP, event.Detail)
+	if err := events.Create(event); err != nil {
+		log.Printf("Failed to record security event %s: %v", event.Type, err)
+	}
+}
+
+func userIDString(id *uint) string {
+	if id == nil {
+		return "-"
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// loginAccount is the key failures are counted under, so that the case of the
+// email address can't be used to get more attempts
+func loginAccount(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// IsLoginBlocked returns the LoginBlockedError in err, if any
+func IsLoginBlocked(err error) (*LoginBlockedError, bool) {
+	var blocked *LoginBlockedError
+	ok := errors.As(err, &blocked)
+	return blocked, ok
+}
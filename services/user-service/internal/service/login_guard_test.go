@@ -0,0 +1,231 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeLoginAttemptStore keeps attempts in memory. Nothing expires; tests
+// clear delays to let time pass.
+type fakeLoginAttemptStore struct {
+	accountFailures map[string]int64
+	ipFailures      map[string]int64
+	delays          map[string]time.Duration
+	locks           map[string]time.Duration
+}
+
+func newFakeLoginAttemptStore() *fakeLoginAttemptStore {
+	return &fakeLoginAttemptStore{
+		accountFailures: map[string]int64{},
+		ipFailures:      map[string]int64{},
+		delays:          map[string]time.Duration{},
+		locks:           map[string]time.Duration{},
+	}
+}
+
+func (s *fakeLoginAttemptStore) Status(account, ip string) (*repository.LoginStatus, error) {
+	return &repository.LoginStatus{
+		AccountFailures: s.accountFailures[account],
+		IPFailures:      s.ipFailures[ip],
+		IPFailuresTTL:   time.Minute,
+		Delay:           s.delays[account],
+		Locked:          s.locks[account],
+	}, nil
+}
+
+func (s *fakeLoginAttemptStore) RecordFailure(account, ip string, window time.Duration) (int64, int64, error) {
+	s.accountFailures[account]++
+	s.ipFailures[ip]++
+	return s.accountFailures[account], s.ipFailures[ip], nil
+}
+
+func (s *fakeLoginAttemptStore) Delay(account string, ttl time.Duration) error {
+	s.delays[account] = ttl
+	return nil
+}
+
+func (s *fakeLoginAttemptStore) Lock(account string, ttl time.Duration) error {
+	s.locks[account] = ttl
+	return nil
+}
+
+func (s *fakeLoginAttemptStore) Reset(account string) error {
+	delete(s.accountFailures, account)
+	delete(s.delays, account)
+	delete(s.locks, account)
+	return nil
+}
+
+type fakeSecurityEventRepository struct {
+	events []*model.SecurityEvent
+}
+
+func (r *fakeSecurityEventRepository) Create(event *model.SecurityEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *fakeSecurityEventRepository) FindByUserID(userID uint, limit int) ([]model.SecurityEvent, error) {
+	var events []model.SecurityEvent
+	for i := len(r.events) - 1; i >= 0 && len(events) < limit; i-- {
+		if r.events[i].UserID != nil && *r.events[i].UserID == userID {
+			events = append(events, *r.events[i])
+		}
+	}
+	return events, nil
+}
+
+func (r *fakeSecurityEventRepository) types() []string {
+	var types []string
+	for _, event := range r.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+type loginFixture struct {
+	user     *model.User
+	attempts *fakeLoginAttemptStore
+	events   *fakeSecurityEventRepository
+	guard    LoginGuard
+	mfaRepo  *fakeMFARepository
+	mfa      MFAService
+	tokens   TokenService
+	repo     *fakeUserRepository
+	users    UserService
+}
+
+func newLoginFixture(t *testing.T, policy LoginPolicy, mfaPolicy MFAPolicy) *loginFixture {
+	keys, err := auth.GenerateKeyRing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &loginFixture{
+		user:     &model.User{ID: 7, Email: "user@example.com", Password: string(hash), Role: model.RoleCustomer},
+		attempts: newFakeLoginAttemptStore(),
+		events:   &fakeSecurityEventRepository{},
+		mfaRepo:  newFakeMFARepository(),
+	}
+	repo := &fakeUserRepository{users: map[uint]*model.User{f.user.ID: f.user}}
+	f.repo = repo
+	tokens := NewTokenService(repo, &fakeRefreshTokenRepository{}, &fakeTokenDenylist{revoked: map[string]bool{}}, testPermissions, keys, 15*time.Minute, 24*time.Hour, mfaPolicy)
+	f.guard = NewLoginGuard(repo, f.attempts, f.events, policy)
+	f.tokens = tokens
+	f.mfa = NewMFAService(repo, f.mfaRepo, newFakeMFAChallengeStore(), f.events, nil, MFAConfig{
+		Issuer:               "Shop",
+		ChallengeTTL:         5 * time.Minute,
+		MaxChallengeFailures: 3,
+		Policy:               mfaPolicy,
+	})
+	f.users = NewUserService(repo, tokens, nil, f.guard, f.mfa, false)
+	return f
+}
+
+func (f *loginFixture) login(password string) error {
+	_, err := f.users.Login("user@example.com", password, "203.0.113.7")
+	return err
+}
+
+func TestLoginDelaysThenLocksAccount(t *testing.T) {
+	f := newLoginFixture(t, LoginPolicy{
+		Window:          15 * time.Minute,
+		DelayAfter:      2,
+		DelayBase:       time.Second,
+		DelayMax:        time.Minute,
+		LockoutAfter:    4,
+		LockoutDuration: 15 * time.Minute,
+	}, MFAPolicy{})
+
+	for i := 0; i < 2; i++ {
+		if err := f.login("wrong"); err == nil || err.Error() != "invalid email or password" {
+			t.Fatalf("Login() attempt %d error = %v, want invalid email or password", i+1, err)
+		}
+	}
+	// The right password has to wait out the delay too
+	if blocked, ok := IsLoginBlocked(f.login("password123")); !ok || blocked.Locked || blocked.RetryAfter != time.Second {
+		t.Fatalf("Login() while delayed = %+v, want a delay of 1s", blocked)
+	}
+
+	for i := 0; i < 2; i++ {
+		delete(f.attempts.delays, "user@example.com")
+		_ = f.login("wrong")
+	}
+	blocked, ok := IsLoginBlocked(f.login("password123"))
+	if !ok || !blocked.Locked || blocked.RetryAfter != 15*time.Minute {
+		t.Fatalf("Login() while locked = %+v, want locked for 15m", blocked)
+	}
+	if types := f.events.types(); len(types) != 1 || types[0] != model.SecurityEventAccountLocked || *f.events.events[0].UserID != f.user.ID {
+		t.Fatalf("events = %v, want account_locked for user %d", types, f.user.ID)
+	}
+
+	if err := f.guard.Unlock(f.user.ID, 1); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	if err := f.login("password123"); err != nil {
+		t.Fatalf("Login() after unlock error = %v", err)
+	}
+	// The IP that guessed is still suspicious after the unlock
+	want := []string{model.SecurityEventAccountLocked, model.SecurityEventAccountUnlocked, model.SecurityEventSuspiciousLogin}
+	if types := f.events.types(); len(types) != len(want) || types[1] != want[1] || types[2] != want[2] {
+		t.Errorf("events = %v, want %v", types, want)
+	}
+}
+
+func TestLoginAfterFailuresIsSuspicious(t *testing.T) {
+	f := newLoginFixture(t, LoginPolicy{Window: 15 * time.Minute, DelayAfter: 3, DelayBase: time.Second, DelayMax: time.Minute}, MFAPolicy{})
+
+	// Changing the case of the email doesn't reset the count
+	for _, email := range []string{"user@example.com", "User@Example.com", "USER@EXAMPLE.COM"} {
+		_, _ = f.users.Login(email, "wrong", "203.0.113.7")
+	}
+	delete(f.attempts.delays, "user@example.com")
+	if err := f.login("password123"); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if types := f.events.types(); len(types) != 1 || types[0] != model.SecurityEventSuspiciousLogin {
+		t.Fatalf("events = %v, want suspicious_login", types)
+	}
+	if failures := f.attempts.accountFailures["user@example.com"]; failures != 0 {
+		t.Errorf("account failures after login = %d, want 0", failures)
+	}
+	if failures := f.attempts.ipFailures["203.0.113.7"]; failures != 3 {
+		t.Errorf("IP failures after login = %d, want 3", failures)
+	}
+}
+
+func TestLoginBlocksIP(t *testing.T) {
+	f := newLoginFixture(t, LoginPolicy{Window: 15 * time.Minute, IPMaxFailures: 3}, MFAPolicy{})
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		_, _ = f.users.Login(email, "wrong", "203.0.113.7")
+	}
+	if _, ok := IsLoginBlocked(f.login("password123")); !ok {
+		t.Fatal("Login() from blocked IP succeeded")
+	}
+	if _, err := f.users.Login("user@example.com", "password123", "198.51.100.1"); err != nil {
+		t.Errorf("Login() from another IP error = %v", err)
+	}
+	if types := f.events.types(); len(types) != 1 || types[0] != model.SecurityEventIPBlocked {
+		t.Errorf("events = %v, want ip_blocked", types)
+	}
+}
+
+func TestLoginDelay(t *testing.T) {
+	g := &loginGuard{policy: LoginPolicy{DelayAfter: 3, DelayBase: time.Second, DelayMax: 30 * time.Second}}
+	tests := map[int64]time.Duration{3: time.Second, 4: 2 * time.Second, 6: 8 * time.Second, 8: 30 * time.Second, 1000: 30 * time.Second}
+	for failures, want := range tests {
+		if got := g.delay(failures); got != want {
+			t.Errorf("delay(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
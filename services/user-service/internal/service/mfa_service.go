@@ -0,0 +1,319 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/totp"
+)
+
+// recoveryCodeCount is the number of recovery codes a user gets at a time
+const recoveryCodeCount = 10
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// MFAPolicy decides who has to use MFA. Sessions of a role in RequiredRoles
+// that started without MFA get no permissions.
+type MFAPolicy struct {
+	RequiredRoles []string
+}
+
+// Requires reports whether users with role have to use MFA
+func (p MFAPolicy) Requires(role string) bool {
+	for _, required := range p.RequiredRoles {
+		if required == role {
+			return true
+		}
+	}
+	return false
+}
+
+// MFAConfig configures MFAService
+type MFAConfig struct {
+	// Issuer is the name authenticator apps show for the account
+	Issuer string
+	// ChallengeTTL is how long a login has to enter a code after the password
+	ChallengeTTL time.Duration
+	// MaxChallengeFailures wrong codes end a challenge; the login starts over
+	MaxChallengeFailures int64
+	Policy               MFAPolicy
+}
+
+// MFAEnrollment is a new TOTP secret for the user to add to an authenticator
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// MFAChallenge is handed out when the password was right and a code is still
+// needed. Token is exchanged for the session together with the code.
+type MFAChallenge struct {
+	Token     string `json:"mfa_token"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
+// MFAService enrols TOTP authenticators and checks the second factor of logins
+type MFAService interface {
+	// Enroll starts enrolment with a new secret. MFA is enabled once Activate
+	// gets a code of it.
+	Enroll(userID uint) (*MFAEnrollment, error)
+	// Activate enables MFA and returns the recovery codes, which are shown once
+	Activate(userID uint, code string) ([]string, error)
+	Disable(userID uint, code string) error
+	RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
+	Enabled(userID uint) (bool, error)
+	// Required reports whether the policy requires MFA for role
+	Required(role string) bool
+	Challenge(user *model.User) (*MFAChallenge, error)
+	// ChallengeUser returns the user a challenge was issued to
+	ChallengeUser(challengeToken string) (*model.User, error)
+	// CompleteChallenge checks a TOTP or recovery code for a challenge and
+	// ends the challenge when it is right
+	CompleteChallenge(challengeToken string, user *model.User, code string) error
+}
+
+type mfaService struct {
+	users      repository.UserRepository
+	repo       repository.MFARepository
+	challenges repository.MFAChallengeStore
+	events     repository.SecurityEventRepository
+	secrets    *auth.SecretBox
+	config     MFAConfig
+}
+
+// NewMFAService returns an MFAService that seals TOTP secrets with secrets,
+// which may be nil to store them in plain text
+func NewMFAService(
+	users repository.UserRepository,
+	repo repository.MFARepository,
+	challenges repository.MFAChallengeStore,
+	events repository.SecurityEventRepository,
+	secrets *auth.SecretBox,
+	config MFAConfig,
+) MFAService {
+	return &mfaService{
+		users:      users,
+		repo:       repo,
+		challenges: challenges,
+		events:     events,
+		secrets:    secrets,
+		config:     config,
+	}
+}
+
+func (s *mfaService) Enroll(userID uint) (*MFAEnrollment, error) {
+	user, err := s.users.FindbyId(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled, err := s.Enabled(userID); err != nil {
+		return nil, err
+	} else if enabled {
+		return nil, errors.New("cannot enroll: mfa is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.secrets.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(&model.UserMFA{UserID: userID, Secret: sealed}); err != nil {
+		return nil, err
+	}
+	return &MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.config.Issuer, user.Email, secret),
+	}, nil
+}
+
+func (s *mfaService) Activate(userID uint, code string) ([]string, error) {
+	mfa, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.New("cannot activate: enroll first")
+		}
+		return nil, err
+	}
+	if mfa.IsEnabled() {
+		return nil, errors.New("cannot activate: mfa is already enabled")
+	}
+
+	secret, err := s.secrets.Open(mfa.Secret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return nil, errors.New("invalid code")
+	}
+
+	now := time.Now()
+	mfa.EnabledAt = &now
+	mfa.LastUsedStep = step
+	if err := s.repo.Save(mfa); err != nil {
+		return nil, err
+	}
+	codes, err := s.newRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	s.record(userID, model.SecurityEventMFAEnabled)
+	return codes, nil
+}
+
+func (s *mfaService) Disable(userID uint, code string) error {
+	if err := s.verify(userID, code); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(userID); err != nil {
+		return err
+	}
+	s.record(userID, model.SecurityEventMFADisabled)
+	return nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	if err := s.verify(userID, code); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(userID)
+}
+
+func (s *mfaService) Enabled(userID uint) (bool, error) {
+	mfa, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return false, nil
+		}
+		return false, err
+	}
+	return mfa.IsEnabled(), nil
+}
+
+func (s *mfaService) Required(role string) bool {
+	return s.config.Policy.Requires(role)
+}
+
+func (s *mfaService) Challenge(user *model.User) (*MFAChallenge, error) {
+	token, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.challenges.Create(auth.HashToken(token), user.ID, s.config.ChallengeTTL); err != nil {
+		return nil, err
+	}
+	return &MFAChallenge{Token: token, ExpiresIn: int64(s.config.ChallengeTTL.Seconds())}, nil
+}
+
+func (s *mfaService) ChallengeUser(challengeToken string) (*model.User, error) {
+	userID, err := s.challenges.Find(auth.HashToken(challengeToken))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.New("invalid mfa token: expired or already used")
+		}
+		return nil, err
+	}
+	return s.users.FindbyId(userID)
+}
+
+func (s *mfaService) CompleteChallenge(challengeToken string, user *model.User, code string) error {
+	hash := auth.HashToken(challengeToken)
+	if err := s.verify(user.ID, code); err != nil {
+		if !strings.Contains(err.Error(), "invalid") {
+			return err
+		}
+		failures, failErr := s.challenges.Fail(hash)
+		if failErr == nil && failures >= s.config.MaxChallengeFailures {
+			_, _ = s.challenges.Delete(hash)
+		}
+		return err
+	}
+
+	// Another request may have completed the challenge with the same code
+	deleted, err := s.challenges.Delete(hash)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("invalid mfa token: expired or already used")
+	}
+	return nil
+}
+
+// verify checks a TOTP code, or else a recovery code, of a user with MFA
+// enabled. Both can be used once.
+func (s *mfaService) verify(userID uint, code string) error {
+	mfa, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.New("invalid code: mfa is not enabled")
+		}
+		return err
+	}
+	if !mfa.IsEnabled() {
+		return errors.New("invalid code: mfa is not enabled")
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		secret, err := s.secrets.Open(mfa.Secret)
+		if err != nil {
+			return err
+		}
+		step, ok := totp.Validate(secret, code, time.Now())
+		if !ok || step <= mfa.LastUsedStep {
+			return errors.New("invalid code")
+		}
+		return s.repo.UseStep(userID, step)
+	}
+
+	if err := s.repo.UseRecoveryCode(userID, auth.HashToken(normalizeRecoveryCode(code))); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.New("invalid code")
+		}
+		return err
+	}
+	s.record(userID, model.SecurityEventRecoveryCodeUsed)
+	return nil
+}
+
+// newRecoveryCodes replaces the recovery codes of a user and returns the new
+// ones, formatted as xxxxx-xxxxx
+func (s *mfaService) newRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		code := recoveryCodeEncoding.EncodeToString(b)[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = auth.HashToken(code)
+	}
+	if err := s.repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *mfaService) record(userID uint, eventType string) {
+	recordSecurityEvent(s.events, &model.SecurityEvent{UserID: &userID, Type: eventType})
+}
+
+// normalizeRecoveryCode lets recovery codes be typed without the dash and in
+// any case
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/totp"
+)
+
+type fakeMFARepository struct {
+	mfa map[uint]*model.UserMFA
+	// recoveryCodes maps code hashes to whether they were used
+	recoveryCodes map[string]bool
+}
+
+func newFakeMFARepository() *fakeMFARepository {
+	return &fakeMFARepository{mfa: map[uint]*model.UserMFA{}, recoveryCodes: map[string]bool{}}
+}
+
+func (r *fakeMFARepository) FindByUserID(userID uint) (*model.UserMFA, error) {
+	if mfa, ok := r.mfa[userID]; ok {
+		copied := *mfa
+		return &copied, nil
+	}
+	return nil, errors.New("mfa not found")
+}
+
+func (r *fakeMFARepository) Save(mfa *model.UserMFA) error {
+	copied := *mfa
+	r.mfa[mfa.UserID] = &copied
+	return nil
+}
+
+func (r *fakeMFARepository) Delete(userID uint) error {
+	delete(r.mfa, userID)
+	r.recoveryCodes = map[string]bool{}
+	return nil
+}
+
+func (r *fakeMFARepository) UseStep(userID uint, step int64) error {
+	if r.mfa[userID].LastUsedStep >= step {
+		return errors.New("invalid code: already used")
+	}
+	r.mfa[userID].LastUsedStep = step
+	return nil
+}
+
+func (r *fakeMFARepository) ReplaceRecoveryCodes(userID uint, hashes []string) error {
+	r.recoveryCodes = map[string]bool{}
+	for _, hash := range hashes {
+		r.recoveryCodes[hash] = false
+	}
+	return nil
+}
+
+func (r *fakeMFARepository) UseRecoveryCode(userID uint, hash string) error {
+	if used, ok := r.recoveryCodes[hash]; !ok || used {
+		return errors.New("recovery code not found")
+	}
+	r.recoveryCodes[hash] = true
+	return nil
+}
+
+type fakeMFAChallenge struct {
+	userID   uint
+	failures int64
+}
+
+type fakeMFAChallengeStore struct {
+	challenges map[string]*fakeMFAChallenge
+}
+
+func newFakeMFAChallengeStore() *fakeMFAChallengeStore {
+	return &fakeMFAChallengeStore{challenges: map[string]*fakeMFAChallenge{}}
+}
+
+func (s *fakeMFAChallengeStore) Create(hash string, userID uint, ttl time.Duration) error {
+	s.challenges[hash] = &fakeMFAChallenge{userID: userID}
+	return nil
+}
+
+func (s *fakeMFAChallengeStore) Find(hash string) (uint, error) {
+	if challenge, ok := s.challenges[hash]; ok {
+		return challenge.userID, nil
+	}
+	return 0, errors.New("mfa challenge not found")
+}
+
+func (s *fakeMFAChallengeStore) Fail(hash string) (int64, error) {
+	challenge, ok := s.challenges[hash]
+	if !ok {
+		return 0, errors.New("mfa challenge not found")
+	}
+	challenge.failures++
+	return challenge.failures, nil
+}
+
+func (s *fakeMFAChallengeStore) Delete(hash string) (bool, error) {
+	_, ok := s.challenges[hash]
+	delete(s.challenges, hash)
+	return ok, nil
+}
+
+// enrollMFA enables MFA for the user of f and returns the secret and the
+// recovery codes
+func (f *loginFixture) enrollMFA(t *testing.T) (string, []string) {
+	t.Helper()
+	enrollment, err := f.mfa.Enroll(f.user.ID)
+	if err != nil {
+		t.Fatalf("Enroll() error = %v", err)
+	}
+	if !strings.HasPrefix(enrollment.URI, "otpauth://totp/Shop:user@example.com?") {
+		t.Errorf("Enroll() URI = %s", enrollment.URI)
+	}
+	code, _ := totp.Code(enrollment.Secret, totp.Step(time.Now()))
+	recoveryCodes, err := f.mfa.Activate(f.user.ID, code)
+	if err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+	return enrollment.Secret, recoveryCodes
+}
+
+// challenge logs in with the right password and returns the MFA challenge
+func (f *loginFixture) challenge(t *testing.T) string {
+	t.Helper()
+	result, err := f.users.Login("user@example.com", "password123", "203.0.113.7")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if result.MFAChallenge == nil || result.Tokens != nil {
+		t.Fatalf("Login() = %+v, want an MFA challenge and no tokens", result)
+	}
+	return result.MFAChallenge.Token
+}
+
+func TestLoginMFA(t *testing.T) {
+	f := newLoginFixture(t, LoginPolicy{Window: 15 * time.Minute, LockoutAfter: 10, LockoutDuration: time.Minute}, MFAPolicy{})
+	secret, _ := f.enrollMFA(t)
+
+	challenge := f.challenge(t)
+	if _, err := f.users.LoginMFA(challenge, "000000", "203.0.113.7"); err == nil || !strings.Contains(err.Error(), "invalid code") {
+		t.Fatalf("LoginMFA() with a wrong code error = %v, want invalid code", err)
+	}
+	if failures := f.attempts.accountFailures["user@example.com"]; failures != 1 {
+		t.Errorf("account failures after a wrong code = %d, want 1", failures)
+	}
+
+	// The code used to activate can't be used again
+	code, _ := totp.Code(secret, totp.Step(time.Now())+1)
+	result, err := f.users.LoginMFA(challenge, code, "203.0.113.7")
+	if err != nil {
+		t.Fatalf("LoginMFA() error = %v", err)
+	}
+	claims, err := f.tokens.Validate(result.Tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !claims.HasAuthMethod(token.AuthMethodMFA) || !claims.HasAuthMethod(token.AuthMethodPassword) {
+		t.Errorf("amr = %v, want pwd and mfa", claims.AuthMethods)
+	}
+
+	if _, err := f.users.LoginMFA(challenge, code, "203.0.113.7"); err == nil {
+		t.Error("LoginMFA() completed a challenge twice")
+	}
+	if _, err := f.users.LoginMFA(f.challenge(t), code, "203.0.113.7"); err == nil {
+		t.Error("LoginMFA() accepted a code twice")
+	}
+}
+
+func TestLoginMFAWithRecoveryCode(t *testing.T) {
+	f := newLoginFixture(t, LoginPolicy{Window: 15 * time.Minute}, MFAPolicy{})
+	_, recoveryCodes := f.enrollMFA(t)
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("Activate() returned %d recovery codes, want %d", len(recoveryCodes), recoveryCodeCount)
+	}
+
+	code := strings.ToUpper(strings.ReplaceAll(recoveryCodes[3], "-", ""))
+	if _, err := f.users.LoginMFA(f.challenge(t), code, "203.0.113.7"); err != nil {
+		t.Fatalf("LoginMFA() with a recovery code error = %v", err)
+	}
+	if _, err := f.users.LoginMFA(f.challenge(t), recoveryCodes[3], "203.0.113.7"); err == nil {
+		t.Error("LoginMFA() accepted a recovery code twice")
+	}
+}
+
+func TestMFAChallengeEndsAfterFailures(t *testing.T) {
+	f := newLoginFixture(t, LoginPolicy{Window: 15 * time.Minute}, MFAPolicy{})
+	secret, _ := f.enrollMFA(t)
+
+	challenge := f.challenge(t)
+	for i := 0; i < 3; i++ {
+		_, _ = f.users.LoginMFA(challenge, "000000", "203.0.113.7")
+	}
+	code, _ := totp.Code(secret, totp.Step(time.Now())+1)
+	if _, err := f.users.LoginMFA(challenge, code, "203.0.113.7"); err == nil || !strings.Contains(err.Error(), "invalid mfa token") {
+		t.Errorf("LoginMFA() after 3 wrong codes error = %v, want invalid mfa token", err)
+	}
+}
+
+func TestMFAPolicyWithholdsPermissions(t *testing.T) {
+	f := newLoginFixture(t, LoginPolicy{Window: 15 * time.Minute}, MFAPolicy{RequiredRoles: []string{model.RoleAdmin}})
+	f.user.Role = model.RoleAdmin
+
+	result, err := f.users.Login("user@example.com", "password123", "203.0.113.7")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if !result.MFAEnrollmentRequired {
+		t.Error("Login() of an admin without MFA did not require enrolment")
+	}
+	claims, _ := f.tokens.Validate(result.Tokens.AccessToken)
+	if len(claims.Permissions) != 0 {
+		t.Errorf("permissions without MFA = %v, want none", claims.Permissions)
+	}
+
+	secret, _ := f.enrollMFA(t)
+	code, _ := totp.Code(secret, totp.Step(time.Now())+1)
+	result, err = f.users.LoginMFA(f.challenge(t), code, "203.0.113.7")
+	if err != nil {
+		t.Fatalf("LoginMFA() error = %v", err)
+	}
+	claims, _ = f.tokens.Validate(result.Tokens.AccessToken)
+	if len(claims.Permissions) == 0 {
+		t.Error("permissions with MFA = none, want the permissions of admin")
+	}
+
+	// Refreshed tokens keep the MFA of the session
+	refreshed, err := f.tokens.Refresh(result.Tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	claims, _ = f.tokens.Validate(refreshed.AccessToken)
+	if !claims.HasAuthMethod(token.AuthMethodMFA) || len(claims.Permissions) == 0 {
+		t.Errorf("refreshed claims = %+v, want mfa and permissions", claims)
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// postalCodeFormats are the postal code formats of the countries we ship to
+var postalCodeFormats = map[string]*regexp.Regexp{
+	"TH": regexp.MustCompile(`^[1-9]\d{4}$`),
+	"MY": regexp.MustCompile(`^\d{5}$`),
+	"SG": regexp.MustCompile(`^\d{6}$`),
+	"VN": regexp.MustCompile(`^\d{6}$`),
+	"LA": regexp.MustCompile(`^\d{5}$`),
+	"KH": regexp.MustCompile(`^\d{5,6}$`),
+	"JP": regexp.MustCompile(`^\d{3}-\d{4}$`),
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? \d[A-Z]{2}$`),
+}
+
+// regionFormat matches ISO 3166-2 subdivision codes such as TH-10
+var regionFormat = regexp.MustCompile(`^[A-Z]{2}-[A-Z0-9]{1,3}$`)
+
+// NormalizePostalCode upper-cases and trims code and checks it against the
+// format of country
+func NormalizePostalCode(country, code string) (string, error) {
+	format, ok := postalCodeFormats[country]
+	if !ok {
+		return "", fmt.Errorf("invalid address: country %s is not supported", country)
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !format.MatchString(code) {
+		return "", fmt.Errorf("invalid address: %q is not a valid postal code in %s", code, country)
+	}
+	return code, nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import "testing"
+
+func TestNormalizePostalCode(t *testing.T) {
+	tests := []struct {
+		country, code, want string
+		valid               bool
+	}{
+		{"TH", " 10110 ", "10110", true},
+		{"TH", "01234", "", false},
+		{"TH", "1011", "", false},
+		{"JP", "100-0001", "100-0001", true},
+		{"US", "94105-1234", "94105-1234", true},
+		{"GB", "sw1a 1aa", "SW1A 1AA", true},
+		{"SG", "01895", "", false},
+		{"XX", "12345", "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizePostalCode(tt.country, tt.code)
+		if tt.valid && (err != nil || got != tt.want) {
+			t.Errorf("NormalizePostalCode(%s, %q) = %q, %v, want %q", tt.country, tt.code, got, err, tt.want)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("NormalizePostalCode(%s, %q) = %q, want an error", tt.country, tt.code, got)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
+)
+
+// roleName matches role names such as support or catalog_manager
+var roleName = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+// PermissionSource looks up the permissions granted to a role
+type PermissionSource interface {
+	PermissionsForRole(role string) ([]string, error)
+}
+
+// RoleService manages roles and their permissions, and assigns them to users
+type RoleService interface {
+	List() ([]model.Role, error)
+	Get(name string) (*model.Role, error)
+	Create(name, description string, permissions []string) (*model.Role, error)
+	Update(name, description string, permissions []string) (*model.Role, error)
+	Delete(name string) error
+	ListPermissions() ([]model.Permission, error)
+	// AssignRole changes the role of a user and signs the user out, so that
+	// the permissions of the new role apply from the next login
+	AssignRole(userID uint, role string, actorID uint) (*model.User, error)
+}
+
+type roleService struct {
+	repo   repository.RoleRepository
+	users  repository.UserRepository
+	tokens TokenService
+	events repository.SecurityEventRepository
+}
+
+func NewRoleService(repo repository.RoleRepository, users repository.UserRepository, tokens TokenService, events repository.SecurityEventRepository) RoleService {
+	return &roleService{
+		repo:   repo,
+		users:  users,
+		tokens: tokens,
+		events: events,
+	}
+}
+
+func (s *roleService) List() ([]model.Role, error) {
+	return s.repo.List()
+}
+
+func (s *roleService) Get(name string) (*model.Role, error) {
+	return s.repo.FindByName(name)
+}
+
+func (s *roleService) Create(name, description string, permissions []string) (*model.Role, error) {
+	name = strings.TrimSpace(name)
+	if !roleName.MatchString(name) {
+		return nil, fmt.Errorf("invalid role name %q: use lower case letters, digits and underscores", name)
+	}
+	if _, err := s.repo.FindByName(name); err == nil {
+		return nil, errors.New("cannot create role: " + name + " already exists")
+	}
+
+	granted, err := s.permissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+	role := &model.Role{Name: name, Description: strings.TrimSpace(description), Permissions: granted}
+	if err := s.repo.Create(role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// Update replaces the description and permissions of a role. Users that have
+// the role are signed out when its permissions change, as by AssignRole. The
+// admin role keeps role:write, so roles can always be managed.
+func (s *roleService) Update(name, description string, permissions []string) (*model.Role, error) {
+	role, err := s.repo.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	granted, err := s.permissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+	if name == model.RoleAdmin && !hasPermission(granted, authz.RoleWrite) {
+		return nil, errors.New("cannot update role: " + name + " must keep " + authz.RoleWrite)
+	}
+
+	changed := !samePermissions(role.Permissions, granted)
+	role.Description = strings.TrimSpace(description)
+	role.Permissions = granted
+	if err := s.repo.Update(role); err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := s.signOutUsers(role.Name); err != nil {
+			return nil, err
+		}
+	}
+	return role, nil
+}
+
+// signOutUsers revokes the sessions of every user that has role
+func (s *roleService) signOutUsers(role string) error {
+	ids, err := s.repo.UserIDs(role)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := s.tokens.RevokeUserSessions(id); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *roleService) Delete(name string) error {
+	if model.IsBuiltinRole(name) {
+		return errors.New("cannot delete role: " + name + " is built in")
+	}
+	role, err := s.repo.FindByName(name)
+	if err != nil {
+		return err
+	}
+	users, err := s.repo.CountUsers(name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return fmt.Errorf("cannot delete role: %d users have it", users)
+	}
+	return s.repo.Delete(role)
+}
+
+func (s *roleService) ListPermissions() ([]model.Permission, error) {
+	return s.repo.ListPermissions()
+}
+
+func (s *roleService) AssignRole(userID uint, role string, actorID uint) (*model.User, error) {
+	if _, err := s.repo.FindByName(role); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.New("invalid role: " + role + " does not exist")
+		}
+		return nil, err
+	}
+	if userID == actorID {
+		return nil, errors.New("cannot change your own role")
+	}
+
+	user, err := s.users.FindbyId(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	previous := user.Role
+	user.Role = role
+	if err := s.users.Update(user); err != nil {
+		return nil, err
+	}
+	if err := s.tokens.RevokeUserSessions(user.ID); err != nil {
+		return nil, err
+	}
+	recordSecurityEvent(s.events, &model.SecurityEvent{
+		UserID:  &user.ID,
+		ActorID: &actorID,
+		Type:    model.SecurityEventRoleChanged,
+		Email:   user.Email,
+		Detail:  previous + " -> " + role,
+	})
+	return user, nil
+}
+
+func hasPermission(permissions []model.Permission, name string) bool {
+	for _, permission := range permissions {
+		if permission.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// samePermissions reports whether a and b hold the same permissions
+func samePermissions(a, b []model.Permission) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, permission := range a {
+		if !hasPermission(b, permission.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// permissions looks up permissions by name and fails on unknown names
+func (s *roleService) permissions(names []string) ([]model.Permission, error) {
+	found, err := s.repo.FindPermissions(names)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(found))
+	for _, permission := range found {
+		known[permission.Name] = true
+	}
+	for _, name := range names {
+		if !known[name] {
+			return nil, errors.New("invalid permission: " + name + " does not exist")
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+)
+
+type fakeRoleRepository struct {
+	roles       map[string]*model.Role
+	permissions []model.Permission
+	users       *fakeUserRepository
+}
+
+func (r *fakeRoleRepository) List() ([]model.Role, error) {
+	var roles []model.Role
+	for _, role := range r.roles {
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
+
+func (r *fakeRoleRepository) FindByName(name string) (*model.Role, error) {
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, errors.New("role not found")
+	}
+	copied := *role
+	return &copied, nil
+}
+
+func (r *fakeRoleRepository) Create(role *model.Role) error {
+	r.roles[role.Name] = role
+	return nil
+}
+
+func (r *fakeRoleRepository) Update(role *model.Role) error {
+	r.roles[role.Name] = role
+	return nil
+}
+
+func (r *fakeRoleRepository) Delete(role *model.Role) error {
+	delete(r.roles, role.Name)
+	return nil
+}
+
+func (r *fakeRoleRepository) CountUsers(name string) (int64, error) {
+	var count int64
+	for _, user := range r.users.users {
+		if user.Role == name {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeRoleRepository) UserIDs(name string) ([]uint, error) {
+	var ids []uint
+	for _, user := range r.users.users {
+		if user.Role == name {
+			ids = append(ids, user.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeRoleRepository) ListPermissions() ([]model.Permission, error) {
+	return r.permissions, nil
+}
+
+func (r *fakeRoleRepository) FindPermissions(names []string) ([]model.Permission, error) {
+	var found []model.Permission
+	for _, permission := range r.permissions {
+		for _, name := range names {
+			if permission.Name == name {
+				found = append(found, permission)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeRoleRepository) PermissionsForRole(name string) ([]string, error) {
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, nil
+	}
+	return role.PermissionNames(), nil
+}
+
+func (r *fakeRoleRepository) Seed(permissions []model.Permission, roles []model.Role) error {
+	return nil
+}
+
+type roleFixture struct {
+	admin    *model.User
+	customer *model.User
+	repo     *fakeRoleRepository
+	events   *fakeSecurityEventRepository
+	tokens   TokenService
+	roles    RoleService
+}
+
+func newRoleFixture(t *testing.T) *roleFixture {
+	keys, err := auth.GenerateKeyRing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &roleFixture{
+		admin:    &model.User{ID: 1, Email: "admin@example.com", Role: model.RoleAdmin},
+		customer: &model.User{ID: 2, Email: "user@example.com", Role: model.RoleCustomer},
+		events:   &fakeSecurityEventRepository{},
+	}
+	users := &fakeUserRepository{users: map[uint]*model.User{f.admin.ID: f.admin, f.customer.ID: f.customer}}
+	f.repo = &fakeRoleRepository{
+		roles: map[string]*model.Role{
+			model.RoleCustomer: {Name: model.RoleCustomer},
+			model.RoleAdmin:    {Name: model.RoleAdmin, Permissions: model.DefaultPermissions()},
+		},
+		permissions: model.DefaultPermissions(),
+		users:       users,
+	}
+	f.tokens = NewTokenService(users, &fakeRefreshTokenRepository{}, &fakeTokenDenylist{revoked: map[string]bool{}}, f.repo, keys, 15*time.Minute, 24*time.Hour, MFAPolicy{})
+	f.roles = NewRoleService(f.repo, users, f.tokens, f.events)
+	return f
+}
+
+func TestCreateRoleGrantsPermissions(t *testing.T) {
+	f := newRoleFixture(t)
+
+	if _, err := f.roles.Create("support", "", []string{"user:read", "user:fly"}); err == nil || !strings.Contains(err.Error(), "invalid permission") {
+		t.Fatalf("Create() with an unknown permission error = %v", err)
+	}
+	if _, err := f.roles.Create("Support!", "", nil); err == nil || !strings.Contains(err.Error(), "invalid role name") {
+		t.Fatalf("Create() with an invalid name error = %v", err)
+	}
+	if _, err := f.roles.Create("support", "Customer support", []string{"user:read", "order:read"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := f.roles.Create("support", "", nil); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("Create() of an existing role error = %v", err)
+	}
+
+	if _, err := f.roles.AssignRole(f.customer.ID, "support", f.admin.ID); err != nil {
+		t.Fatalf("AssignRole() error = %v", err)
+	}
+	issued, err := f.tokens.Issue(f.customer, []string{token.AuthMethodPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := f.tokens.Validate(issued.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(claims.Permissions, ",") != "order:read,user:read" {
+		t.Errorf("permissions = %v, want user:read and order:read", claims.Permissions)
+	}
+}
+
+func TestAssignRoleSignsUserOut(t *testing.T) {
+	f := newRoleFixture(t)
+	issued, _ := f.tokens.Issue(f.customer, []string{token.AuthMethodPassword})
+
+	if _, err := f.roles.AssignRole(f.admin.ID, model.RoleCustomer, f.admin.ID); err == nil || !strings.Contains(err.Error(), "cannot") {
+		t.Errorf("AssignRole() of own role error = %v", err)
+	}
+	if _, err := f.roles.AssignRole(f.customer.ID, "ghost", f.admin.ID); err == nil || !strings.Contains(err.Error(), "invalid role") {
+		t.Errorf("AssignRole() of an unknown role error = %v", err)
+	}
+
+	user, err := f.roles.AssignRole(f.customer.ID, model.RoleAdmin, f.admin.ID)
+	if err != nil {
+		t.Fatalf("AssignRole() error = %v", err)
+	}
+	if user.Role != model.RoleAdmin {
+		t.Errorf("role = %q, want %q", user.Role, model.RoleAdmin)
+	}
+	if _, err := f.tokens.Validate(issued.AccessToken); err == nil {
+		t.Error("Validate() accepted a token issued before the role changed")
+	}
+	if types := f.events.types(); len(types) != 1 || types[0] != model.SecurityEventRoleChanged {
+		t.Errorf("events = %v, want %s", types, model.SecurityEventRoleChanged)
+	}
+}
+
+func TestUpdateRoleSignsItsUsersOut(t *testing.T) {
+	f := newRoleFixture(t)
+	admin, _ := f.tokens.Issue(f.admin, []string{token.AuthMethodPassword})
+	customer, _ := f.tokens.Issue(f.customer, []string{token.AuthMethodPassword})
+
+	if _, err := f.roles.Update(model.RoleAdmin, "", []string{"user:read"}); err == nil || !strings.Contains(err.Error(), "must keep") {
+		t.Fatalf("Update() dropping role:write from admin error = %v", err)
+	}
+	if _, err := f.tokens.Validate(admin.AccessToken); err != nil {
+		t.Errorf("Validate() after a refused update error = %v", err)
+	}
+
+	// Only the description changes, sessions stay
+	if _, err := f.roles.Update(model.RoleCustomer, "Shoppers", nil); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := f.tokens.Validate(customer.AccessToken); err != nil {
+		t.Errorf("Validate() after a description change error = %v", err)
+	}
+
+	if _, err := f.roles.Update(model.RoleCustomer, "Shoppers", []string{"order:read"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := f.tokens.Validate(customer.AccessToken); err == nil {
+		t.Error("Validate() accepted a token issued before the role changed")
+	}
+	if _, err := f.tokens.Validate(admin.AccessToken); err != nil {
+		t.Errorf("Validate() of a user with another role error = %v", err)
+	}
+}
+
+func TestDeleteRole(t *testing.T) {
+	f := newRoleFixture(t)
+	if _, err := f.roles.Create("support", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.roles.AssignRole(f.customer.ID, "support", f.admin.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.roles.Delete(model.RoleAdmin); err == nil || !strings.Contains(err.Error(), "built in") {
+		t.Errorf("Delete() of a built-in role error = %v", err)
+	}
+	if err := f.roles.Delete("support"); err == nil || !strings.Contains(err.Error(), "users have it") {
+		t.Errorf("Delete() of a role in use error = %v", err)
+	}
+	if _, err := f.roles.AssignRole(f.customer.ID, model.RoleCustomer, f.admin.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.roles.Delete("support"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+}
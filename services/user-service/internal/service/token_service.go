@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+)
+
+// TokenPair is an access token with the refresh token to renew it
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is the lifetime of the access token in seconds
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// TokenService issues short-lived access tokens and rotating refresh tokens,
+// and revokes them
+type TokenService interface {
+	// Issue starts a session for a user who authenticated with authMethods,
+	// the amr values of the tokens
+	Issue(user *model.User, authMethods []string) (*TokenPair, error)
+	Refresh(refreshToken string) (*TokenPair, error)
+	Logout(claims *auth.Claims) error
+	RevokeUserSessions(userID uint) error
+	Validate(accessToken string) (*auth.Claims, error)
+}
+
+type tokenService struct {
+	userRepo    repository.UserRepository
+	tokenRepo   repository.RefreshTokenRepository
+	denylist    repository.TokenDenylist
+	permissions PermissionSource
+	keys        *auth.KeyRing
+	verifier    *token.Verifier
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+	mfaPolicy   MFAPolicy
+}
+
+func NewTokenService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.RefreshTokenRepository,
+	denylist repository.TokenDenylist,
+	permissions PermissionSource,
+	keys *auth.KeyRing,
+	accessTTL, refreshTTL time.Duration,
+	mfaPolicy MFAPolicy,
+) TokenService {
+	return &tokenService{
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		denylist:    denylist,
+		permissions: permissions,
+		keys:        keys,
+		verifier:    token.NewVerifier(keys, nil),
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+		mfaPolicy:   mfaPolicy,
+	}
+}
+
+// Issue starts a new session for user
+func (s *tokenService) Issue(user *model.User, authMethods []string) (*TokenPair, error) {
+	familyID, err := auth.NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, stored, err := s.newRefreshToken(user.ID, familyID, authMethods)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.Create(stored); err != nil {
+		return nil, err
+	}
+	return s.pair(user, familyID, refreshToken, authMethods)
+}
+
+// Refresh exchanges a refresh token for a new token pair. The refresh token
+// can be used once: presenting it again revokes the whole session, since
+// either the client or an attacker holds a copy of a token that was rotated.
+func (s *tokenService) Refresh(refreshToken string) (*TokenPair, error) {
+	current, err := s.tokenRepo.FindByHash(auth.HashToken(refreshToken))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	if current.RotatedAt != nil {
+		return nil, s.reused(current)
+	}
+	if !current.IsActive(time.Now()) {
+		return nil, errors.New("invalid refresh token: expired or revoked")
+	}
+
+	user, err := s.userRepo.FindbyId(current.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token: user not found")
+	}
+	if user.SuspendedAt != nil {
+		return nil, errors.New("invalid refresh token: account suspended")
+	}
+
+	authMethods := splitAuthMethods(current.AuthMethods)
+	next, stored, err := s.newRefreshToken(user.ID, current.FamilyID, authMethods)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.Rotate(current, stored); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenReused) {
+			return nil, s.reused(current)
+		}
+		return nil, err
+	}
+
+	return s.pair(user, current.FamilyID, next, authMethods)
+}
+
+// reused revokes the session of a refresh token that was presented after it
+// had been rotated
+func (s *tokenService) reused(token *model.RefreshToken) error {
+	log.Printf("Refresh token reuse detected for user %d, revoking session %s", token.UserID, token.FamilyID)
+	if err := s.revokeSession(token.FamilyID); err != nil {
+		return err
+	}
+	return errors.New("invalid refresh token: token reuse detected, session revoked")
+}
+
+// Logout revokes the access token of claims and the session it belongs to
+func (s *tokenService) Logout(claims *auth.Claims) error {
+	if claims.ExpiresAt != nil {
+		if err := s.denylist.RevokeToken(claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+			return fmt.Errorf("failed to revoke token: %w", err)
+		}
+	}
+	if claims.SessionID == "" {
+		return nil
+	}
+	return s.revokeSession(claims.SessionID)
+}
+
+// RevokeUserSessions signs a user out everywhere, e.g. after a password reset
+func (s *tokenService) RevokeUserSessions(userID uint) error {
+	families, err := s.tokenRepo.RevokeByUser(userID)
+	if err != nil {
+		return err
+	}
+	for _, familyID := range families {
+		if err := s.denylist.RevokeSession(familyID, s.accessTTL); err != nil {
+			return fmt.Errorf("failed to revoke session: %w", err)
+		}
+	}
+	return nil
+}
+
+// revokeSession revokes the refresh tokens of a session and, through the
+// denylist, the access tokens already issued for it
+func (s *tokenService) revokeSession(familyID string) error {
+	if err := s.tokenRepo.RevokeFamily(familyID); err != nil {
+		return err
+	}
+	if err := s.denylist.RevokeSession(familyID, s.accessTTL); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the signature and expiry of an access token and that it
+// has not been revoked. It fails closed when the denylist cannot be read.
+func (s *tokenService) Validate(accessToken string) (*auth.Claims, error) {
+	claims, err := s.verifier.Verify(context.Background(), accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.denylist.IsRevoked(claims.ID, claims.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if revoked {
+		return nil, errors.New("token has been revoked")
+	}
+
+	// A suspended or deleted user is signed out at once, also when a session
+	// was opened after the revocation that came with the suspension
+	user, err := s.userRepo.FindbyId(claims.UserID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errors.New("invalid token: user not found")
+		}
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+	if user.SuspendedAt != nil {
+		return nil, errors.New("invalid token: account suspended")
+	}
+	return claims, nil
+}
+
+func (s *tokenService) newRefreshToken(userID uint, familyID string, authMethods []string) (string, *model.RefreshToken, error) {
+	token, err := auth.NewOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &model.RefreshToken{
+		UserID:      userID,
+		FamilyID:    familyID,
+		TokenHash:   auth.HashToken(token),
+		ExpiresAt:   time.Now().Add(s.refreshTTL),
+		AuthMethods: strings.Join(authMethods, ","),
+	}, nil
+}
+
+// pair issues an access token for a session with the permissions the role
+// of user has now. A role that requires MFA gets no permissions in a session
+// that started without it.
+func (s *tokenService) pair(user *model.User, familyID, refreshToken string, authMethods []string) (*TokenPair, error) {
+	permissions, err := s.permissions.PermissionsForRole(user.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up permissions: %w", err)
+	}
+	if s.mfaPolicy.Requires(user.Role) && !hasAuthMethod(authMethods, token.AuthMethodMFA) {
+		permissions = nil
+	}
+	accessToken, err := auth.GenerateToken(s.keys, user.ID, user.Email, user.Role, permissions, familyID, authMethods, s.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.accessTTL.Seconds()),
+	}, nil
+}
+
+// splitAuthMethods parses the amr values stored with a refresh token
+func splitAuthMethods(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func hasAuthMethod(authMethods []string, method string) bool {
+	for _, m := range authMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ploezy/ecommerce-platform/pkg/authz"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
+	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
+	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
+	"gorm.io/gorm"
+)
+
+type fakeUserRepository struct {
+	users map[uint]*model.User
+}
+
+func (r *fakeUserRepository) Create(user *model.User) error {
+	user.ID = uint(len(r.users) + 1)
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepository) Update(user *model.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepository) FindByEmail(email string) (*model.User, error) {
+	for _, user := range r.users {
+		if user.Email == email && !user.DeletedAt.Valid {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (r *fakeUserRepository) FindbyId(id uint) (*model.User, error) {
+	if user, ok := r.users[id]; ok && !user.DeletedAt.Valid {
+		return user, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (r *fakeUserRepository) FindByIDWithDeleted(id uint) (*model.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+// List filters on status and search, ordered by ID
+func (r *fakeUserRepository) List(filter repository.UserFilter) ([]model.User, int64, error) {
+	var ids []uint
+	for id := range r.users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var matched []model.User
+	for _, id := range ids {
+		user := r.users[id]
+		status := user.Status()
+		if filter.Status == "" && status == model.UserStatusDeleted || filter.Status != "" && status != filter.Status {
+			continue
+		}
+		if !strings.Contains(user.Email+" "+user.FirstName+" "+user.LastName, filter.Search) {
+			continue
+		}
+		matched = append(matched, *user)
+	}
+	total := int64(len(matched))
+	if filter.Offset >= len(matched) {
+		return nil, total, nil
+	}
+	matched = matched[filter.Offset:]
+	if len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, total, nil
+}
+
+func (r *fakeUserRepository) Delete(user *model.User) error {
+	user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (r *fakeUserRepository) Restore(user *model.User) error {
+	user.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+type fakeRefreshTokenRepository struct {
+	tokens []*model.RefreshToken
+}
+
+func (r *fakeRefreshTokenRepository) Create(token *model.RefreshToken) error {
+	token.ID = uint(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) FindByHash(hash string) (*model.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("refresh token not found")
+}
+
+func (r *fakeRefreshTokenRepository) Rotate(current, next *model.RefreshToken) error {
+	stored := r.tokens[current.ID-1]
+	if stored.RotatedAt != nil || stored.RevokedAt != nil {
+		return repository.ErrRefreshTokenReused
+	}
+	now := time.Now()
+	stored.RotatedAt = &now
+	return r.Create(next)
+}
+
+func (r *fakeRefreshTokenRepository) RevokeByUser(userID uint) ([]string, error) {
+	var families []string
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			families = append(families, token.FamilyID)
+		}
+	}
+	for _, familyID := range families {
+		r.RevokeFamily(familyID)
+	}
+	return families, nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeFamily(familyID string) error {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// fakePermissionSource grants the admin role a permission
+type fakePermissionSource map[string][]string
+
+func (p fakePermissionSource) PermissionsForRole(role string) ([]string, error) {
+	return p[role], nil
+}
+
+var testPermissions = fakePermissionSource{model.RoleAdmin: {authz.UserWrite}}
+
+type fakeTokenDenylist struct {
+	revoked map[string]bool
+}
+
+func (d *fakeTokenDenylist) RevokeToken(tokenID string, ttl time.Duration) error {
+	d.revoked["jti:"+tokenID] = true
+	return nil
+}
+
+func (d *fakeTokenDenylist) RevokeSession(sessionID string, ttl time.Duration) error {
+	d.revoked["sid:"+sessionID] = true
+	return nil
+}
+
+func (d *fakeTokenDenylist) IsRevoked(tokenID, sessionID string) (bool, error) {
+	return d.revoked["jti:"+tokenID] || d.revoked["sid:"+sessionID], nil
+}
+
+func newTestTokenService(t *testing.T) (TokenService, *model.User) {
+	keys, err := auth.GenerateKeyRing()
+	if err != nil {
+		t.Fatalf("GenerateKeyRing() error = %v", err)
+	}
+	user := &model.User{ID: 7, Email: "user@example.com", Role: model.RoleCustomer}
+	tokens := NewTokenService(
+		&fakeUserRepository{users: map[uint]*model.User{user.ID: user}},
+		&fakeRefreshTokenRepository{},
+		&fakeTokenDenylist{revoked: map[string]bool{}},
+		testPermissions,
+		keys,
+		15*time.Minute,
+		24*time.Hour,
+		MFAPolicy{},
+	)
+	return tokens, user
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	tokens, user := newTestTokenService(t)
+
+	issued, err := tokens.Issue(user, []string{token.AuthMethodPassword})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	refreshed, err := tokens.Refresh(issued.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if refreshed.RefreshToken == issued.RefreshToken {
+		t.Error("Refresh() returned the same refresh token")
+	}
+
+	claims, err := tokens.Validate(refreshed.AccessToken)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if claims.UserID != user.ID || claims.ID == "" || claims.SessionID == "" {
+		t.Errorf("Validate() claims = %+v", claims)
+	}
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	tokens, user := newTestTokenService(t)
+
+	issued, _ := tokens.Issue(user, []string{token.AuthMethodPassword})
+	refreshed, err := tokens.Refresh(issued.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	// Replaying the rotated token revokes the session it belongs to
+	if _, err := tokens.Refresh(issued.RefreshToken); err == nil || !strings.Contains(err.Error(), "reuse") {
+		t.Fatalf("Refresh() with a rotated token error = %v, want reuse detected", err)
+	}
+	if _, err := tokens.Refresh(refreshed.RefreshToken); err == nil {
+		t.Error("Refresh() with the latest token of a revoked session succeeded")
+	}
+	if _, err := tokens.Validate(refreshed.AccessToken); err == nil {
+		t.Error("Validate() accepted an access token of a revoked session")
+	}
+
+	// Other sessions of the user are not affected
+	other, _ := tokens.Issue(user, []string{token.AuthMethodPassword})
+	if _, err := tokens.Validate(other.AccessToken); err != nil {
+		t.Errorf("Validate() of another session error = %v", err)
+	}
+}
+
+func TestLogoutRevokesTokens(t *testing.T) {
+	tokens, user := newTestTokenService(t)
+
+	issued, _ := tokens.Issue(user, []string{token.AuthMethodPassword})
+	claims, err := tokens.Validate(issued.AccessToken)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := tokens.Logout(claims); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	if _, err := tokens.Validate(issued.AccessToken); err == nil {
+		t.Error("Validate() accepted a logged out access token")
+	}
+	if _, err := tokens.Refresh(issued.RefreshToken); err == nil {
+		t.Error("Refresh() accepted a logged out refresh token")
+	}
+}
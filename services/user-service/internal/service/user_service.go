@@ -2,26 +2,58 @@ package service
 
 import (
 	"errors"
+	"log"
+	"strings"
 
+	"github.com/ploezy/ecommerce-platform/pkg/token"
 	"github.com/ploezy/ecommerce-platform/user-service/internal/model"
 	"github.com/ploezy/ecommerce-platform/user-service/internal/repository"
-	"github.com/ploezy/ecommerce-platform/user-service/pkg/auth"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService interface {
 	Register(email, password, firstName, lastName string) (*model.User, error)
-	Login(email, password, jwtSecret string) (string, *model.User, error)
+	// Login checks the credentials of a client at ip. Failed logins are
+	// throttled and eventually lock the account. Users with MFA get a
+	// challenge to complete with LoginMFA instead of tokens.
+	Login(email, password, ip string) (*LoginResult, error)
+	LoginMFA(challengeToken, code, ip string) (*LoginResult, error)
 	GetByID(id uint) (*model.User, error)
 	GetByEmail(email string) (*model.User, error)
 }
 
+// errAccountSuspended refuses logins of users suspended by an admin
+var errAccountSuspended = errors.New("account suspended: contact support")
+
+// LoginResult is a session, or the MFA challenge to complete to get one
+type LoginResult struct {
+	User         *model.User
+	Tokens       *TokenPair
+	MFAChallenge *MFAChallenge
+	// MFAEnrollmentRequired is set when the policy requires MFA for the user
+	// but none is enabled. The session has no permissions until it is.
+	MFAEnrollmentRequired bool
+}
+
 type userService struct {
-	repo repository.UserRepository
+	repo    repository.UserRepository
+	tokens  TokenService
+	account AccountService
+	guard   LoginGuard
+	mfa     MFAService
+	// requireVerifiedEmail refuses logins until the email address is verified
+	requireVerifiedEmail bool
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
-	return &userService{repo: repo}
+func NewUserService(repo repository.UserRepository, tokens TokenService, account AccountService, guard LoginGuard, mfa MFAService, requireVerifiedEmail bool) UserService {
+	return &userService{
+		repo:                 repo,
+		tokens:               tokens,
+		account:              account,
+		guard:                guard,
+		mfa:                  mfa,
+		requireVerifiedEmail: requireVerifiedEmail,
+	}
 }
 
 func (s *userService) Register(email, password, firstName, lastName string) (*model.User, error) {
@@ -43,37 +75,111 @@ func (s *userService) Register(email, password, firstName, lastName string) (*mo
 		Password:  string(hashedPassword),
 		FirstName: firstName,
 		LastName:  lastName,
-		Role:      "customer",
+		Role:      model.RoleCustomer,
 	}
 
 	err = s.repo.Create(user)
 	if err != nil {
 		return nil, err
 	}
+
+	// The user can ask for another link when this one is lost
+	if err := s.account.SendVerification(user); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+	}
 	return user, nil
 }
 
-func (s *userService) Login(email, password, jwtSecret string) (string, *model.User, error) {
+func (s *userService) Login(email, password, ip string) (*LoginResult, error) {
+	// Refuse locked accounts before their password can be guessed
+	if err := s.guard.Check(email, ip); err != nil {
+		return nil, err
+	}
+
 	// Find user
 	user, err := s.repo.FindByEmail(email)
 	if err != nil {
-		return "", nil, errors.New("invalid email or password")
+		return nil, s.loginFailed(email, ip)
 	}
 	// check password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return "", nil, errors.New("invalid email or password")
+		return nil, s.loginFailed(email, ip)
+	}
+	if s.requireVerifiedEmail && !user.EmailVerified {
+		return nil, errors.New("email not verified: follow the link sent to your email address")
+	}
+	if user.SuspendedAt != nil {
+		return nil, errAccountSuspended
 	}
 
-	// Generate JWT token
-	token, err := auth.GenerateToken(user.ID, user.Email, user.Role, jwtSecret)
+	// The failures are only cleared once the second factor is in too, so that
+	// codes can't be guessed by starting over with the password
+	mfaEnabled, err := s.mfa.Enabled(user.ID)
 	if err != nil {
-		return "", nil, err
+		return nil, err
+	}
+	if mfaEnabled {
+		challenge, err := s.mfa.Challenge(user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{User: user, MFAChallenge: challenge}, nil
 	}
 
-	return token, user, nil
+	result, err := s.startSession(user, ip, []string{token.AuthMethodPassword})
+	if err != nil {
+		return nil, err
+	}
+	result.MFAEnrollmentRequired = s.mfa.Required(user.Role)
+	return result, nil
+}
 
+// LoginMFA completes a login with the code of the authenticator, or a
+// recovery code. Wrong codes count as failed logins.
+func (s *userService) LoginMFA(challengeToken, code, ip string) (*LoginResult, error) {
+	user, err := s.mfa.ChallengeUser(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if user.SuspendedAt != nil {
+		return nil, errAccountSuspended
+	}
+	if err := s.guard.Check(user.Email, ip); err != nil {
+		return nil, err
+	}
+
+	if err := s.mfa.CompleteChallenge(challengeToken, user, code); err != nil {
+		if strings.Contains(err.Error(), "invalid code") {
+			if err := s.guard.Failed(user.Email, ip); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	return s.startSession(user, ip, []string{token.AuthMethodPassword, token.AuthMethodOTP, token.AuthMethodMFA})
+}
+
+// startSession issues the tokens of a login that passed every factor
+func (s *userService) startSession(user *model.User, ip string, authMethods []string) (*LoginResult, error) {
+	if err := s.guard.Succeeded(user, ip); err != nil {
+		return nil, err
+	}
+	tokens, err := s.tokens.Issue(user, authMethods)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{User: user, Tokens: tokens}, nil
 }
+
+// loginFailed counts a failed login and returns the error for it
+func (s *userService) loginFailed(email, ip string) error {
+	if err := s.guard.Failed(email, ip); err != nil {
+		return err
+	}
+	return errors.New("invalid email or password")
+}
+
 func (s *userService) GetByID(id uint) (*model.User, error) {
 	return s.repo.FindbyId(id)
 }
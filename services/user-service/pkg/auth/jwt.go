@@ -1,43 +1,50 @@
 package auth
 
 import (
-	"errors"
+	"crypto/rand"
+	"encoding/hex"
 	"time"
-	
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
 )
 
-type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	jwt.RegisteredClaims
-}
+// Claims are the claims of an access token, shared with the services that
+// verify them
+type Claims = token.Claims
+
+// GenerateToken issues an access token signed with the active key of keys that
+// expires after ttl. authMethods become the amr claim. Every token gets a unique ID (jti) so that it can be
+// revoked before it expires.
+func GenerateToken(keys *KeyRing, userID uint, email, role string, permissions []string, sessionID string, authMethods []string, ttl time.Duration) (string, error) {
+	tokenID, err := NewTokenID()
+	if err != nil {
+		return "", err
+	}
 
-func GenerateToken (userID uint, email, role, secret string) (string, error){
-	claims := Claims{
-		UserID: userID,
-		Email: email,
-		Role: role,
+	now := time.Now()
+	claims := &Claims{
+		UserID:      userID,
+		Email:       email,
+		Role:        role,
+		Permissions: permissions,
+		SessionID:   sessionID,
+		AuthMethods: authMethods,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt: jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+			Issuer:    token.Issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
-	} 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256,claims)
-	return token.SignedString([]byte(secret))
+	}
+	return keys.Sign(claims)
 }
 
-func ValidateToken(tokenString, secret string) (*Claims,error)  {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
-
-	if err != nil {
-		return nil,err
+// NewTokenID returns a random 128-bit ID for tokens and token families
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
-	if claims,ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims,nil
-	}
-	return nil,errors.New("invalid token")
-}
\ No newline at end of file
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ploezy/ecommerce-platform/pkg/token"
+)
+
+// minRSABits is the smallest RSA signing key accepted
+const minRSABits = 2048
+
+// SigningKey is a private key that signs access tokens
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	JWK     token.JWK
+}
+
+// KeyRing holds the signing keys of user-service. The first key signs new
+// tokens; the others are only published, so that tokens signed before a
+// rotation stay valid and keys about to become active are already cached by
+// the services that verify tokens.
+type KeyRing struct {
+	keys []*SigningKey
+}
+
+// LoadKeyRing reads PEM encoded RSA or Ed25519 private keys (PKCS#8, or
+// PKCS#1 for RSA). The first file holds the active key.
+func LoadKeyRing(paths []string) (*KeyRing, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no signing keys configured")
+	}
+
+	ring := &KeyRing{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %w", err)
+		}
+		private, err := ParsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing key %s: %w", path, err)
+		}
+		if err := ring.add(private); err != nil {
+			return nil, fmt.Errorf("invalid signing key %s: %w", path, err)
+		}
+	}
+	return ring, nil
+}
+
+// GenerateKeyRing creates a key ring with a new Ed25519 key. Tokens it signs
+// become invalid when the process exits; use it for local development only.
+func GenerateKeyRing() (*KeyRing, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	ring := &KeyRing{}
+	if err := ring.add(private); err != nil {
+		return nil, err
+	}
+	log.Printf("Generated ephemeral signing key %s, tokens will not survive a restart", ring.keys[0].ID)
+	return ring, nil
+}
+
+// ParsePrivateKey decodes a PEM encoded RSA or Ed25519 private key
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSABits)
+		}
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", key)
+	}
+}
+
+func (r *KeyRing) add(private crypto.Signer) error {
+	jwk, err := token.NewJWK(private.Public())
+	if err != nil {
+		return err
+	}
+	for _, key := range r.keys {
+		if key.ID == jwk.Kid {
+			return fmt.Errorf("duplicate signing key %s", jwk.Kid)
+		}
+	}
+	r.keys = append(r.keys, &SigningKey{
+		ID:      jwk.Kid,
+		Method:  jwt.GetSigningMethod(jwk.Alg),
+		Private: private,
+		JWK:     jwk,
+	})
+	return nil
+}
+
+// Active returns the key that signs new tokens
+func (r *KeyRing) Active() *SigningKey {
+	return r.keys[0]
+}
+
+// Sign signs claims with the active key and names it in the kid header
+func (r *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	key := r.Active()
+	t := jwt.NewWithClaims(key.Method, claims)
+	t.Header["kid"] = key.ID
+	return t.SignedString(key.Private)
+}
+
+// JWKS returns the public keys of the ring
+func (r *KeyRing) JWKS() token.JWKSet {
+	set := token.JWKSet{Keys: make([]token.JWK, 0, len(r.keys))}
+	for _, key := range r.keys {
+		set.Keys = append(set.Keys, key.JWK)
+	}
+	return set
+}
+
+// Key implements token.KeySet so that user-service verifies its own tokens
+// without fetching its JWKS
+func (r *KeyRing) Key(ctx context.Context, kid string) (token.VerificationKey, error) {
+	for _, key := range r.keys {
+		if key.ID == kid {
+			return key.JWK.VerificationKey()
+		}
+	}
+	return token.VerificationKey{}, fmt.Errorf("unknown signing key %q", kid)
+}
@@ -6,7 +6,7 @@ import (
 	"testing"
 	"time"
 
-	pb "github.com/ploezy/ecommerce-platform/user-service/proto/user"
+	pb "github.com/ploezy/ecommerce-platform/proto/user"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )